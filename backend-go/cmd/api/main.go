@@ -1,49 +1,37 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	// Feature AUTH (register, login, logout)
-	authApp "backend-go/features/auth/application"
-	authInfra "backend-go/features/auth/infrastructure"
 	authPres "backend-go/features/auth/presentation"
 
-	// Shared Security Services (Crypto, JWT)
-	"backend-go/shared/security"
-
 	// Feature USERS (getUser, update)
-	userApp "backend-go/features/users/application"
-	userInfra "backend-go/features/users/infrastructure"
 	userPres "backend-go/features/users/presentation"
 
 	// Feature PROFILE (getProfile, follow, unfollow)
-	profileApp "backend-go/features/profile/application"
-	profileInfra "backend-go/features/profile/infrastructure"
 	profilePres "backend-go/features/profile/presentation"
 
-	bookingApp "backend-go/features/bookings/application"
-	bookingInfra "backend-go/features/bookings/infrastructure"
 	bookingPres "backend-go/features/bookings/presentation"
-	classApp "backend-go/features/classes/application"
-	classInfra "backend-go/features/classes/infrastructure"
 	classPres "backend-go/features/classes/presentation"
-	clubApp "backend-go/features/clubs/application"
-	clubInfra "backend-go/features/clubs/infrastructure"
 	clubPres "backend-go/features/clubs/presentation"
-	paymentApp "backend-go/features/payments/application"
-	paymentInfra "backend-go/features/payments/infrastructure"
+	passPres "backend-go/features/passes/presentation"
 	paymentPres "backend-go/features/payments/presentation"
-	"backend-go/features/pista/application"
-	"backend-go/features/pista/infrastructure"
 	"backend-go/features/pista/presentation"
-	roleApp "backend-go/features/roles/application"
-	roleInfra "backend-go/features/roles/infrastructure"
+	pricingPres "backend-go/features/pricing/presentation"
 	rolePres "backend-go/features/roles/presentation"
+	schedulerPres "backend-go/features/scheduler/presentation"
+	schedulePres "backend-go/features/schedules/presentation"
+	walletPres "backend-go/features/wallet/presentation"
+	"backend-go/internal/bootstrap"
 	"backend-go/internal/database"
 	"backend-go/internal/scheduler"
-	"backend-go/shared/availability"
+	"backend-go/shared/health"
 	sharedMiddleware "backend-go/shared/middleware"
 
 	"github.com/gofiber/fiber/v2"
@@ -83,43 +71,30 @@ func main() {
 		BodyLimit: 10 * 1024 * 1024, // 10 MB para subida de avatares
 	})
 
-	// Middleware CORS - V2: Soporte para cookies con withCredentials
+	// Middleware CORS - V2: Soporte para cookies con withCredentials.
+	// Idempotency-Key permite reintentar pagos desde el navegador; Idempotent-Replayed marca las respuestas repetidas
 	app.Use(cors.New(cors.Config{
 		AllowOrigins:     "http://localhost:5173,http://localhost:3000", // Frontend Vite y alternativas
 		AllowMethods:     "GET,POST,PUT,DELETE,PATCH,OPTIONS",
-		AllowHeaders:     "Origin,Content-Type,Accept,Authorization",
+		AllowHeaders:     "Origin,Content-Type,Accept,Authorization,Idempotency-Key",
+		ExposeHeaders:    "Idempotent-Replayed",
 		AllowCredentials: true, // V2: Permite envío de cookies
 	}))
 
 	// ============================================================
-	// SHARED SECURITY SERVICES (Argon2, JWT)
+	// SERVICIOS DE APLICACIÓN (compartidos con el CLI cmd/polimanage)
 	// ============================================================
-	cryptoService := security.NewArgon2CryptoService()
-
-	// Leer JWT secret desde variable de entorno
-	jwtSecret := os.Getenv("JWT_SECRET")
-	if jwtSecret == "" {
-		log.Fatal("❌ JWT_SECRET no está configurado en las variables de entorno")
+	container, err := bootstrap.NewContainer(database.DB)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
 	}
-	jwtService := security.NewJWTService(jwtSecret)
+	jwtService := container.JWTService
+	log.Printf("💳 Proveedor de pagos: %s", container.PaymentGatewayName)
 
 	// ============================================================
 	// MÓDULO 1: FEATURE AUTH (CtrlAuth: register, login, logout)
 	// ============================================================
-	// Infraestructura - Servicios específicos de Auth (DiceBear)
-	avatarService := authInfra.NewDiceBearService()
-
-	// Repository compartido
-	userRepo := userInfra.NewUserRepository(database.DB)
-
-	// V2: Repository para RefreshSessions
-	sessionRepo := authInfra.NewRefreshSessionRepository(database.DB)
-
-	// Aplicación - AuthService (V2: Incluye sessionRepo)
-	authService := authApp.NewAuthService(userRepo, sessionRepo, cryptoService, jwtService, avatarService)
-
-	// Presentación - AuthHandler
-	authHandler := authPres.NewAuthHandler(authService)
+	authHandler := authPres.NewAuthHandler(container.AuthService)
 
 	// Rutas públicas Auth
 	authPres.RegisterAuthRoutes(app, authHandler)
@@ -127,26 +102,13 @@ func main() {
 	// ============================================================
 	// MÓDULO 2: FEATURE USERS (CtrlUser: getUser, update, updatePassword)
 	// ============================================================
-	// Aplicación - UserService (usa CryptoService para UpdatePassword)
-	userService := userApp.NewUserService(userRepo, cryptoService)
-
-	// Presentación - UserHandler
-	userHandler := userPres.NewUserHandler(userService)
-
-	// Rutas Users
+	userHandler := userPres.NewUserHandler(container.UserService)
 	userPres.RegisterRoutes(app, userHandler, jwtService)
 
 	// ============================================================
 	// MÓDULO 3: FEATURE PROFILE (CtrlProfile: getProfile, follow, unfollow)
 	// ============================================================
-	// Repository Profile
-	profileRepo := profileInfra.NewProfileRepository(database.DB)
-
-	// Aplicación - ProfileService (necesita cryptoService)
-	profileService := profileApp.NewProfileService(profileRepo, cryptoService)
-
-	// Presentación - ProfileHandler
-	profileHandler := profilePres.NewProfileHandler(profileService)
+	profileHandler := profilePres.NewProfileHandler(container.ProfileService)
 
 	// Rutas Profile (protegidas con JWT)
 	profilePres.RegisterProfileRoutes(app, profileHandler, jwtService)
@@ -154,62 +116,59 @@ func main() {
 	// ============================================================
 	// MÓDULO ROLES (Catálogo de Roles)
 	// ============================================================
-	roleRepo := roleInfra.NewRoleRepository(database.DB)
-	roleService := roleApp.NewRoleService(roleRepo)
-	roleHandler := rolePres.NewRoleHandler(roleService)
+	roleHandler := rolePres.NewRoleHandler(container.RoleService)
 	rolePres.RegisterRoutes(app, roleHandler, jwtService)
 
 	// ============================================================
 	// OTROS MÓDULOS (Pistas, Bookings, Classes, Clubs, Payments)
 	// ============================================================
-	pistaRepo := infrastructure.NewPistaRepository(database.DB)
-	pistaService := application.NewPistaService(pistaRepo)
-	pistaHandler := presentation.NewPistaHandler(pistaService)
+	pistaHandler := presentation.NewPistaHandler(container.PistaService)
 	presentation.RegisterRoutes(app, pistaHandler, jwtService)
 
-	// Servicio de disponibilidad compartido (pistas no pueden tener booking Y clase al mismo tiempo)
-	bookingRepo := bookingInfra.NewBookingRepository(database.DB)
-	classRepo := classInfra.NewClassRepository(database.DB)
-	availabilityService := availability.NewAvailabilityService(database.DB)
+	// Módulo Schedules (horario de apertura, festivos y bloqueos)
+	scheduleHandler := schedulePres.NewScheduleHandler(container.ScheduleService)
+	schedulePres.RegisterRoutes(app, scheduleHandler, jwtService)
 
-	// Módulo Bookings (Reservas)
-	bookingService := bookingApp.NewBookingService(bookingRepo, database.DB)
-	bookingHandler := bookingPres.NewBookingHandler(bookingService)
-	bookingPres.RegisterRoutes(app, bookingHandler, jwtService)
+	// Módulo Pricing (reglas de precio, códigos promocionales y presupuestos)
+	pricingHandler := pricingPres.NewPricingHandler(container.PricingService)
+	promoCodeHandler := pricingPres.NewPromoCodeHandler(container.PromoCodeService)
+	pricingPres.RegisterRoutes(app, pricingHandler, promoCodeHandler, jwtService)
 
-	// Módulo Classes (Clases Grupales)
-	classService := classApp.NewClassService(classRepo, availabilityService)
-	classHandler := classPres.NewClassHandler(classService)
+	// Módulo Payments (pagos, webhooks y facturas)
+	paymentHandler := paymentPres.NewPaymentHandler(container.PaymentService)
+	webhookHandler := paymentPres.NewWebhookHandler(container.WebhookService)
+	invoiceHandler := paymentPres.NewInvoiceHandler(container.InvoiceService)
+	paymentPres.RegisterRoutes(app, paymentHandler, webhookHandler, invoiceHandler, jwtService, container.IdempotencyStore)
 
-	// Módulo Enrollments (Inscripciones a Clases) - Dentro de Classes
-	enrollmentRepo := classInfra.NewEnrollmentRepository(database.DB)
-	classProvider := classApp.NewClassProvider(classService)
-	classUserProvider := userApp.NewClassUserProvider(userRepo)
-	enrollmentService := classApp.NewEnrollmentService(enrollmentRepo, classProvider, classUserProvider)
-	enrollmentHandler := classPres.NewEnrollmentHandler(enrollmentService)
+	// Módulo Wallet (monedero prepago)
+	walletHandler := walletPres.NewWalletHandler(container.WalletService)
+	walletPres.RegisterRoutes(app, walletHandler, jwtService, container.IdempotencyStore)
+
+	// Módulo Bookings (Reservas, series e invitados)
+	bookingHandler := bookingPres.NewBookingHandler(container.BookingService)
+	bookingSeriesHandler := bookingPres.NewBookingSeriesHandler(container.BookingSeriesService)
+	bookingParticipantHandler := bookingPres.NewBookingParticipantHandler(container.BookingParticipantService)
+	bookingPres.RegisterRoutes(app, bookingHandler, bookingSeriesHandler, bookingParticipantHandler, jwtService)
+
+	// Módulo Classes (Clases Grupales e Inscripciones)
+	classHandler := classPres.NewClassHandler(container.ClassService)
+	enrollmentHandler := classPres.NewEnrollmentHandler(container.EnrollmentService)
 
 	// Registrar rutas con enrollmentHandler
 	classPres.RegisterRoutes(app, classHandler, enrollmentHandler, jwtService)
 	classPres.RegisterEnrollmentRoutes(app.Group("/api/enrollments"), enrollmentHandler, jwtService)
 
-	// Módulo Clubs (Clubs deportivos y membresías)
-	clubRepo := clubInfra.NewClubRepository(database.DB)
-	clubMembershipRepo := clubInfra.NewClubMembershipRepository(database.DB)
-	clubService := clubApp.NewClubService(clubRepo, clubMembershipRepo)
-	clubMembershipService := clubApp.NewClubMembershipService(clubMembershipRepo)
-	clubUserProvider := userApp.NewClubUserProvider(userRepo)
-
-	// Módulo Payments (Pagos con Mock Provider)
-	paymentGateway := paymentInfra.NewMockPaymentProvider()
-	paymentRepo := paymentInfra.NewPaymentRepository(database.DB)
-	paymentService := paymentApp.NewPaymentService(paymentRepo, paymentGateway)
-	paymentHandler := paymentPres.NewPaymentHandler(paymentService)
-	paymentPres.RegisterRoutes(app, paymentHandler, jwtService)
-
-	// Servicio de renovación de membresías (integra Clubs + Payments)
-	renewalService := clubApp.NewRenewalService(clubMembershipRepo, clubRepo, paymentService)
-	clubHandler := clubPres.NewClubHandler(clubService, clubMembershipService, renewalService, clubUserProvider)
-	clubPres.RegisterRoutes(app, clubHandler, jwtService)
+	// Plantillas de clases recurrentes - generan las sesiones con antelación (ver scheduler)
+	classTemplateHandler := classPres.NewClassTemplateHandler(container.ClassTemplateService)
+	classPres.RegisterTemplateRoutes(app, classTemplateHandler, jwtService)
+
+	// Módulo Clubs (Clubs deportivos, membresías y renovaciones)
+	clubHandler := clubPres.NewClubHandler(container.ClubService, container.ClubMembershipService, container.RenewalService, container.ClubUserProvider)
+	clubPres.RegisterRoutes(app, clubHandler, jwtService, container.IdempotencyStore)
+
+	// Módulo Passes (bonos de clases y horas de pista)
+	passHandler := passPres.NewPassHandler(container.PassService)
+	passPres.RegisterRoutes(app, passHandler, jwtService, container.IdempotencyStore)
 
 	// ============================================================
 	// RUTAS PROTEGIDAS CON JWT MIDDLEWARE
@@ -227,6 +186,12 @@ func main() {
 	os.MkdirAll("./static/avatars", 0755)
 	app.Static("/static", "./static")
 
+	// ============================================================
+	// HEALTH - Liveness y readiness (reflejan la parada ordenada)
+	// ============================================================
+	healthChecker := health.NewChecker(database.DB)
+	health.RegisterRoutes(app, healthChecker)
+
 	// ============================================================
 	// SWAGGER - Documentación de API
 	// ============================================================
@@ -235,41 +200,16 @@ func main() {
 	// ============================================================
 	// SCHEDULER - Tareas automáticas en segundo plano
 	// ============================================================
-	taskScheduler := scheduler.NewScheduler(10 * time.Minute) // Ejecutar cada 10 minutos
-
-	// Tarea 1: Actualizar estados de reservas
-	taskScheduler.AddTask(scheduler.ScheduledTask{
-		Name:     "Actualizar estados de reservas",
-		Interval: 10 * time.Minute,
-		Execute: func() error {
-			count, err := bookingService.AutoUpdateBookingStatuses()
-			if err != nil {
-				log.Printf("[Scheduler] Error actualizando estados de reservas: %v", err)
-				return err
-			}
-			if count > 0 {
-				log.Printf("[Scheduler] %d reservas actualizadas automáticamente", count)
-			}
-			return nil
-		},
-	})
+	// Tareas definidas en internal/bootstrap (también ejecutables con `polimanage run-task`);
+	// cada una con su intervalo o expresión cron, timeout e historial en scheduler_runs
+	taskScheduler, err := bootstrap.NewScheduler(container)
+	if err != nil {
+		log.Fatalf("❌ Error configurando el scheduler: %v", err)
+	}
 
-	// Tarea 2: Actualizar estados de clases
-	taskScheduler.AddTask(scheduler.ScheduledTask{
-		Name:     "Actualizar estados de clases",
-		Interval: 10 * time.Minute,
-		Execute: func() error {
-			count, err := classService.AutoUpdateClassStatuses()
-			if err != nil {
-				log.Printf("[Scheduler] Error actualizando estados de clases: %v", err)
-				return err
-			}
-			if count > 0 {
-				log.Printf("[Scheduler] %d clases actualizadas automáticamente", count)
-			}
-			return nil
-		},
-	})
+	// Administración: estado, historial y ejecución manual - Solo ADMIN
+	schedulerHandler := schedulerPres.NewSchedulerHandler(taskScheduler)
+	schedulerPres.RegisterRoutes(app, schedulerHandler, jwtService)
 
 	// Iniciar el scheduler
 	taskScheduler.Start()
@@ -281,6 +221,66 @@ func main() {
 		})
 	})
 
-	log.Println("🚀 Servidor corriendo en http://localhost:8080")
-	log.Fatal(app.Listen(":8080"))
+	// ============================================================
+	// CICLO DE VIDA - Arranque y parada ordenada (SIGINT/SIGTERM)
+	// ============================================================
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	listenErr := make(chan error, 1)
+	go func() {
+		log.Println("🚀 Servidor corriendo en http://localhost:8080")
+		listenErr <- app.Listen(":8080")
+	}()
+
+	select {
+	case err := <-listenErr:
+		log.Fatal(err)
+	case <-ctx.Done():
+	}
+	stop() // Una segunda señal termina el proceso de inmediato
+
+	shutdown(app, taskScheduler, healthChecker)
+}
+
+// shutdown detiene el proceso en orden: deja de anunciarse como listo, drena las peticiones en
+// curso, espera a las tareas del scheduler y cierra el pool de la base de datos.
+// SHUTDOWN_TIMEOUT (por defecto 30s) limita la espera total; SHUTDOWN_DRAIN_DELAY (por defecto
+// 0s) da tiempo al balanceador a ver el readiness en 503 antes de cerrar el listener.
+func shutdown(app *fiber.App, taskScheduler *scheduler.Scheduler, healthChecker *health.Checker) {
+	timeout := durationFromEnv("SHUTDOWN_TIMEOUT", 30*time.Second)
+	drainDelay := durationFromEnv("SHUTDOWN_DRAIN_DELAY", 0)
+
+	log.Printf("🛑 Señal de parada recibida: deteniendo (timeout %v)...", timeout)
+	healthChecker.MarkShuttingDown()
+	time.Sleep(drainDelay)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := app.ShutdownWithContext(ctx); err != nil {
+		log.Printf("⚠️  Error drenando conexiones HTTP: %v", err)
+	}
+	if err := taskScheduler.Shutdown(ctx); err != nil {
+		log.Printf("⚠️  Tareas del scheduler sin terminar: %v", err)
+	}
+	if err := database.Close(); err != nil {
+		log.Printf("⚠️  Error cerrando la base de datos: %v", err)
+	}
+
+	log.Println("👋 Servidor detenido")
+}
+
+// durationFromEnv lee una duración (p. ej. "30s") de una variable de entorno
+func durationFromEnv(name string, fallback time.Duration) time.Duration {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < 0 {
+		log.Printf("⚠️  %s inválido (%q), usando %v", name, value, fallback)
+		return fallback
+	}
+	return duration
 }
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"backend-go/internal/database"
+
+	"github.com/joho/godotenv"
+)
+
+// migrate gestiona las migraciones versionadas de la base de datos (internal/database).
+// Usa las mismas variables DB_* que la API.
+func main() {
+	log.SetFlags(0)
+
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, "Uso: migrate <comando>\n\n"+database.MigrateUsage)
+		os.Exit(2)
+	}
+
+	if err := godotenv.Load(); err != nil {
+		log.Println("No se encontró archivo .env, asegúrate de tener las variables configuradas.")
+	}
+
+	if err := database.Open(); err != nil {
+		log.Fatal("❌ Error conectando a la base de datos: ", err)
+	}
+
+	if err := database.RunMigrateCommand(os.Args[1:]); err != nil {
+		if errors.Is(err, database.ErrMigrateUsage) {
+			fmt.Fprintf(os.Stderr, "%v\n\n%s", err, database.MigrateUsage)
+			os.Exit(2)
+		}
+		log.Fatal("❌ ", err)
+	}
+}
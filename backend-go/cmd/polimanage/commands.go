@@ -0,0 +1,262 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	userDomain "backend-go/features/users/domain"
+	"backend-go/internal/bootstrap"
+	"backend-go/internal/database"
+	"backend-go/internal/scheduler"
+)
+
+// adminRoleID es el rol ADMIN creado por la migración 0003_seed_roles
+const adminRoleID = 1
+
+// ============================================================
+// BASE DE DATOS
+// ============================================================
+
+func runMigrate(_ *bootstrap.Container, args []string) error {
+	return database.RunMigrateCommand(args)
+}
+
+func runSeed(_ *bootstrap.Container, _ []string) error {
+	return database.SeedData()
+}
+
+func runExport(_ *bootstrap.Container, args []string) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	out := flags.String("out", "", "Fichero de salida (por defecto stdout)")
+	if err := flags.Parse(args); err != nil {
+		return errUsage
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		file, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+
+	export, err := database.ExportData(w)
+	if err != nil {
+		return err
+	}
+
+	for _, table := range export.Tables {
+		log.Printf("📤 %s: %d filas", table.Name, table.Count)
+	}
+	log.Printf("✅ Exportación completada (esquema versión %d)", export.SchemaVersion)
+	return nil
+}
+
+func runImport(_ *bootstrap.Container, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	in := flags.String("in", "", "Fichero generado por `polimanage export`")
+	if err := flags.Parse(args); err != nil || *in == "" {
+		return errUsage
+	}
+
+	file, err := os.Open(*in)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	export, err := database.ImportData(file)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("✅ Importación completada (%d tablas, exportada el %s)",
+		len(export.Tables), export.ExportedAt.Format("2006-01-02 15:04:05"))
+	return nil
+}
+
+// ============================================================
+// USUARIOS
+// ============================================================
+
+func runCreateAdmin(c *bootstrap.Container, args []string) error {
+	flags := flag.NewFlagSet("create-admin", flag.ContinueOnError)
+	email := flags.String("email", "", "Email del administrador")
+	name := flags.String("name", "", "Nombre completo")
+	password := flags.String("password", "", "Contraseña (por defecto se lee de stdin)")
+	if err := flags.Parse(args); err != nil || *email == "" || *name == "" {
+		return errUsage
+	}
+
+	plain, err := passwordFromFlagOrStdin(*password)
+	if err != nil {
+		return err
+	}
+
+	// UserService.Create hashea la contraseña recibida en PasswordHash
+	user := &userDomain.User{
+		RoleID:       adminRoleID,
+		Email:        strings.ToLower(strings.TrimSpace(*email)),
+		FullName:     strings.TrimSpace(*name),
+		PasswordHash: plain,
+	}
+	if err := c.UserService.Create(user); err != nil {
+		return err
+	}
+
+	log.Printf("✅ Administrador creado: %s (slug %s, id %s)", user.Email, user.Slug, user.ID)
+	return nil
+}
+
+func runResetPassword(c *bootstrap.Container, args []string) error {
+	flags := flag.NewFlagSet("reset-password", flag.ContinueOnError)
+	email, slug := userFlags(flags)
+	password := flags.String("password", "", "Nueva contraseña (por defecto se lee de stdin)")
+	if err := flags.Parse(args); err != nil {
+		return errUsage
+	}
+
+	user, err := findUser(c, *email, *slug)
+	if err != nil {
+		return err
+	}
+
+	plain, err := passwordFromFlagOrStdin(*password)
+	if err != nil {
+		return err
+	}
+
+	if err := c.UserService.UpdatePassword(user.ID, plain); err != nil {
+		return err
+	}
+	// Igual que al cambiar la contraseña desde el perfil: se invalidan todas las sesiones
+	if err := c.AuthService.LogoutAllDevices(user.ID); err != nil {
+		return fmt.Errorf("contraseña cambiada pero no se pudieron cerrar las sesiones: %w", err)
+	}
+
+	log.Printf("✅ Contraseña de %s cambiada y sesiones cerradas", user.Email)
+	return nil
+}
+
+func runRevokeSessions(c *bootstrap.Container, args []string) error {
+	flags := flag.NewFlagSet("revoke-sessions", flag.ContinueOnError)
+	email, slug := userFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return errUsage
+	}
+
+	user, err := findUser(c, *email, *slug)
+	if err != nil {
+		return err
+	}
+
+	if err := c.AuthService.LogoutAllDevices(user.ID); err != nil {
+		return err
+	}
+
+	log.Printf("✅ Sesiones de %s cerradas en todos los dispositivos", user.Email)
+	return nil
+}
+
+func userFlags(flags *flag.FlagSet) (email, slug *string) {
+	return flags.String("email", "", "Email del usuario"), flags.String("slug", "", "Slug del usuario")
+}
+
+// findUser resuelve el usuario por email o por slug (exactamente uno de los dos)
+func findUser(c *bootstrap.Container, email, slug string) (*userDomain.User, error) {
+	switch {
+	case email != "" && slug == "":
+		return c.UserRepo.GetByEmail(strings.ToLower(strings.TrimSpace(email)))
+	case slug != "" && email == "":
+		return c.UserRepo.GetBySlug(strings.TrimSpace(slug))
+	}
+	return nil, fmt.Errorf("%w: indica -email o -slug", errUsage)
+}
+
+// passwordFromFlagOrStdin devuelve la contraseña del flag o, si está vacío, la primera línea de stdin
+func passwordFromFlagOrStdin(password string) (string, error) {
+	if password != "" {
+		return password, nil
+	}
+
+	fmt.Fprint(os.Stderr, "Contraseña: ")
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+
+	password = strings.TrimRight(line, "\r\n")
+	if password == "" {
+		return "", fmt.Errorf("contraseña vacía")
+	}
+	return password, nil
+}
+
+// ============================================================
+// TAREAS PROGRAMADAS
+// ============================================================
+
+func runListTasks(c *bootstrap.Container, _ []string) error {
+	taskScheduler, err := bootstrap.NewScheduler(c)
+	if err != nil {
+		return err
+	}
+
+	for i, task := range taskScheduler.Tasks() {
+		lastRun := "nunca"
+		if runs, err := taskScheduler.History(task.ID, 1); err == nil && len(runs) > 0 {
+			lastRun = fmt.Sprintf("%s %s (%s)", runs[0].StartedAt.Format("2006-01-02 15:04:05"), runs[0].Status, runs[0].Instance)
+		}
+		fmt.Printf("%2d  %-20s %-42s %-22s última: %s\n", i+1, task.ID, task.Name, task.Schedule, lastRun)
+	}
+	return nil
+}
+
+func runTask(c *bootstrap.Container, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: indica el número, el ID o el nombre de la tarea (ver `polimanage tasks`)", errUsage)
+	}
+
+	taskScheduler, err := bootstrap.NewScheduler(c)
+	if err != nil {
+		return err
+	}
+	tasks := taskScheduler.Tasks()
+	selector := strings.Join(args, " ")
+
+	index := -1
+	if n, err := strconv.Atoi(selector); err == nil && n >= 1 && n <= len(tasks) {
+		index = n - 1
+	} else {
+		for i, task := range tasks {
+			if task.ID == selector || strings.EqualFold(task.Name, selector) {
+				index = i
+				break
+			}
+		}
+	}
+	if index < 0 {
+		return fmt.Errorf("tarea no encontrada: %s (ver `polimanage tasks`)", selector)
+	}
+
+	// Se ejecuta a través del scheduler para que quede en el historial como MANUAL
+	task := tasks[index]
+	log.Printf("▶️  Ejecutando tarea: %s", task.Name)
+	run, err := taskScheduler.RunNow(task.ID)
+	if err != nil {
+		return err
+	}
+	if run.Status != scheduler.RunStatusSuccess {
+		return fmt.Errorf("tarea %s: %s: %s", task.Name, run.Status, *run.Error)
+	}
+	log.Printf("✅ Tarea %s completada (%d filas, %v)", task.Name, run.AffectedRows, run.Duration())
+	return nil
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"backend-go/internal/bootstrap"
+	"backend-go/internal/database"
+
+	"github.com/joho/godotenv"
+)
+
+const usage = `Uso: polimanage <comando> [opciones]
+
+CLI de operaciones de PoliManage. Usa las mismas variables de entorno (.env) que la API y
+los mismos servicios de aplicación.
+
+Base de datos:
+  migrate <up|down [n]|to <versión>|status>   Gestiona las migraciones versionadas
+  seed                                         Inserta los datos de demostración (solo si no hay usuarios)
+  export [-out fichero]                        Exporta todas las tablas a JSON (por defecto a stdout)
+  import -in fichero                           Importa una exportación en una base de datos vacía
+
+Usuarios:
+  create-admin -email E -name N [-password P]  Crea un usuario ADMIN
+  reset-password (-email E | -slug S) [-password P]
+                                               Cambia la contraseña y cierra todas sus sesiones
+  revoke-sessions (-email E | -slug S)         Cierra todas las sesiones del usuario
+
+Tareas programadas:
+  tasks                                        Lista las tareas, su planificación y su última ejecución
+  run-task <número|id|nombre>                  Ejecuta una tarea una sola vez (queda en el historial)
+
+Sin -password, la contraseña se lee de la entrada estándar (recomendado: no queda en el historial).
+`
+
+// errUsage indica que los argumentos del comando son incorrectos
+var errUsage = errors.New("uso incorrecto")
+
+// command es un subcomando del CLI. needsServices indica si necesita el contenedor de servicios
+// (y por tanto JWT_SECRET y el resto de configuración de la API).
+type command struct {
+	needsServices bool
+	run           func(c *bootstrap.Container, args []string) error
+}
+
+var commands = map[string]command{
+	"migrate":         {run: runMigrate},
+	"seed":            {run: runSeed},
+	"export":          {run: runExport},
+	"import":          {run: runImport},
+	"create-admin":    {needsServices: true, run: runCreateAdmin},
+	"reset-password":  {needsServices: true, run: runResetPassword},
+	"revoke-sessions": {needsServices: true, run: runRevokeSessions},
+	"tasks":           {needsServices: true, run: runListTasks},
+	"run-task":        {needsServices: true, run: runTask},
+}
+
+func main() {
+	log.SetFlags(log.LstdFlags)
+
+	if len(os.Args) < 2 || os.Args[1] == "help" || os.Args[1] == "-h" || os.Args[1] == "--help" {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Comando desconocido: %s\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	if err := godotenv.Load(); err != nil {
+		log.Println("No se encontró archivo .env, asegúrate de tener las variables configuradas.")
+	}
+
+	if err := database.Open(); err != nil {
+		log.Fatal("❌ Error conectando a la base de datos: ", err)
+	}
+
+	var container *bootstrap.Container
+	if cmd.needsServices {
+		var err error
+		container, err = bootstrap.NewContainer(database.DB)
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+	}
+
+	if err := cmd.run(container, os.Args[2:]); err != nil {
+		if errors.Is(err, errUsage) || errors.Is(err, database.ErrMigrateUsage) {
+			fmt.Fprintf(os.Stderr, "%v\n\n%s", err, usage)
+			os.Exit(2)
+		}
+		log.Fatal("❌ ", err)
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"os"
+
+	paymentInfra "backend-go/features/payments/infrastructure"
+)
+
+// Servidor stub local que imita los endpoints de Stripe usados por el adaptador
+// (customers, payment_intents, refunds). Respuestas deterministas según el cliente:
+//
+//	cus_stub_visa               -> cobro correcto
+//	cus_stub_declined           -> tarjeta rechazada (HTTP 402)
+//	cus_stub_requires_action    -> PaymentIntent en requires_action (3DS); completar con
+//	                               POST /v1/payment_intents/{id}/confirm o cancelar con .../cancel
+//	cus_stub_no_payment_method  -> sin método de pago por defecto
+//
+// Uso: STRIPE_STUB_ADDR=:12111 go run ./cmd/stripe-stub
+// y arrancar la API con PAYMENT_PROVIDER=stripe STRIPE_SECRET_KEY=sk_test_stub STRIPE_API_BASE=http://localhost:12111
+//
+// Webhooks (opcional): STRIPE_STUB_WEBHOOK_URL=http://localhost:8080/api/payments/webhooks/stripe
+// y STRIPE_STUB_WEBHOOK_SECRET con el mismo valor que STRIPE_WEBHOOK_SECRET en la API
+func main() {
+	addr := os.Getenv("STRIPE_STUB_ADDR")
+	if addr == "" {
+		addr = ":12111"
+	}
+
+	server := paymentInfra.NewStripeStubServer()
+	if webhookURL := os.Getenv("STRIPE_STUB_WEBHOOK_URL"); webhookURL != "" {
+		server.EnableWebhooks(webhookURL, os.Getenv("STRIPE_STUB_WEBHOOK_SECRET"))
+		log.Printf("📨 Webhooks firmados hacia %s", webhookURL)
+	}
+
+	log.Printf("🧪 Stripe stub escuchando en %s", addr)
+	log.Fatal(http.ListenAndServe(addr, server.Handler()))
+}
@@ -24,7 +24,8 @@ echo "   This server is responsible for all database migrations"
 sleep 2
 
 echo "🚀 Starting Go server..."
-echo "   GORM will handle database migrations automatically on startup"
+echo "   Pending versioned migrations are applied on startup (DB_MIGRATE_ON_START=false to disable)"
+echo "   Manage them manually with: ./migrate up | down [n] | to <version> | status"
 
-# Ejecutar la aplicación Go (las migraciones se ejecutan en database.Connect())
+# Ejecutar la aplicación Go (las migraciones pendientes se aplican en database.Connect())
 exec ./main
@@ -0,0 +1,251 @@
+package application
+
+import (
+	"backend-go/features/bookings/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// openMatchesDefaultDays es el horizonte del listado de partidos abiertos sin fecha
+const openMatchesDefaultDays = 14
+
+// BookingParticipantService gestiona los jugadores invitados a una reserva
+type BookingParticipantService struct {
+	repo        domain.BookingParticipantRepository
+	bookingRepo domain.BookingRepository
+}
+
+func NewBookingParticipantService(repo domain.BookingParticipantRepository, bookingRepo domain.BookingRepository) *BookingParticipantService {
+	return &BookingParticipantService{
+		repo:        repo,
+		bookingRepo: bookingRepo,
+	}
+}
+
+// GetMyBookings obtiene las reservas en las que juega el usuario (como titular o participante)
+func (s *BookingParticipantService) GetMyBookings(userID uuid.UUID) ([]domain.Booking, error) {
+	return s.bookingRepo.FindByUser(userID)
+}
+
+// GetMyInvitations obtiene las invitaciones del usuario (status vacío = todas)
+func (s *BookingParticipantService) GetMyInvitations(userID uuid.UUID, status string) ([]domain.BookingParticipant, error) {
+	return s.repo.FindByUser(userID, status)
+}
+
+// GetParticipants obtiene la reserva y sus invitados. Solo pueden verlos el titular, los
+// invitados y el personal.
+func (s *BookingParticipantService) GetParticipants(bookingID int, userID uuid.UUID, isStaff bool) (*domain.Booking, []domain.BookingParticipant, error) {
+	booking, err := s.bookingRepo.FindByID(bookingID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	participants, err := s.repo.FindByBooking(bookingID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Los jugadores de un partido abierto son visibles para cualquiera que quiera unirse
+	if isStaff || booking.UserID == userID || booking.IsOpenMatch {
+		return booking, participants, nil
+	}
+	for _, participant := range participants {
+		if participant.UserID == userID {
+			return booking, participants, nil
+		}
+	}
+	return nil, nil, domain.ErrNotBookingParticipant
+}
+
+// Invite invita a un jugador registrado (por slug o email) a la reserva. Solo el titular o el
+// personal, mientras la reserva no haya empezado y quede plaza según el tipo de pista.
+func (s *BookingParticipantService) Invite(bookingID int, userID uuid.UUID, isStaff bool, slug, email string) (*domain.BookingParticipant, error) {
+	if slug == "" && email == "" {
+		return nil, domain.ErrInvalidInvitee
+	}
+
+	booking, err := s.bookingRepo.FindByID(bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if !isStaff && booking.UserID != userID {
+		return nil, domain.ErrNotBookingOrganizer
+	}
+	if err := checkOpenForPlayers(booking); err != nil {
+		return nil, err
+	}
+
+	inviteeID, err := s.repo.FindUserBySlugOrEmail(slug, email)
+	if err != nil {
+		return nil, err
+	}
+	if inviteeID == booking.UserID {
+		return nil, domain.ErrCannotInviteOrganizer
+	}
+
+	participant := &domain.BookingParticipant{
+		BookingID:   bookingID,
+		UserID:      inviteeID,
+		InvitedByID: userID,
+		Status:      domain.ParticipantStatusPending,
+	}
+	if err := s.repo.CreateIfRoom(participant, domain.MaxPlayers(booking.PistaType)); err != nil {
+		return nil, err
+	}
+
+	return s.repo.FindByID(participant.ID)
+}
+
+// Respond acepta o rechaza una invitación. Solo puede hacerlo el jugador invitado.
+func (s *BookingParticipantService) Respond(participantID int, userID uuid.UUID, accept bool) (*domain.BookingParticipant, error) {
+	participant, err := s.repo.FindByID(participantID)
+	if err != nil {
+		return nil, err
+	}
+	if participant.UserID != userID {
+		return nil, domain.ErrNotInvitee
+	}
+	if participant.Status != domain.ParticipantStatusPending {
+		return nil, domain.ErrInvitationAnswered
+	}
+	if err := checkOpenForPlayers(participant.Booking); err != nil {
+		return nil, err
+	}
+
+	status := domain.ParticipantStatusDeclined
+	if accept {
+		status = domain.ParticipantStatusAccepted
+	}
+	if err := s.repo.UpdateStatus(participantID, status, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return s.repo.FindByID(participantID)
+}
+
+// Remove retira a un jugador de la reserva: el titular (o el personal) a cualquier invitado y
+// cada jugador a sí mismo (abandonar la reserva)
+func (s *BookingParticipantService) Remove(bookingID, participantID int, userID uuid.UUID, isStaff bool) error {
+	participant, err := s.repo.FindByID(participantID)
+	if err != nil {
+		return err
+	}
+	if participant.BookingID != bookingID {
+		return domain.ErrParticipantNotFound
+	}
+	if !isStaff && participant.UserID != userID && participant.Booking.UserID != userID {
+		return domain.ErrNotBookingOrganizer
+	}
+
+	return s.repo.Delete(participantID)
+}
+
+// GetOpenMatches obtiene los partidos abiertos con plazas libres desde ahora (o del día indicado),
+// opcionalmente filtrados por tipo de pista
+func (s *BookingParticipantService) GetOpenMatches(date *time.Time, pistaType string) ([]domain.OpenMatch, error) {
+	now := time.Now()
+	from, to := now, now.AddDate(0, 0, openMatchesDefaultDays)
+	if date != nil {
+		from = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+		to = from.Add(24 * time.Hour)
+		if from.Before(now) {
+			from = now
+		}
+	}
+
+	matches, err := s.bookingRepo.FindOpenMatches(from, to, pistaType)
+	if err != nil {
+		return nil, err
+	}
+
+	available := make([]domain.OpenMatch, 0, len(matches))
+	for _, match := range matches {
+		match.MaxPlayers = domain.MaxPlayers(match.Booking.PistaType)
+		if match.FreeSpots() > 0 {
+			available = append(available, match)
+		}
+	}
+
+	return available, nil
+}
+
+// SetOpenMatch abre la reserva a otros jugadores con un rango de nivel opcional, o la cierra.
+// Solo el titular o el personal, mientras la reserva no haya empezado.
+func (s *BookingParticipantService) SetOpenMatch(bookingID int, userID uuid.UUID, isStaff, isOpen bool, minLevel, maxLevel *float64) (*domain.Booking, error) {
+	if !isOpen {
+		minLevel, maxLevel = nil, nil
+	}
+	if err := domain.ValidateLevelRange(minLevel, maxLevel); err != nil {
+		return nil, err
+	}
+
+	booking, err := s.bookingRepo.FindByID(bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if !isStaff && booking.UserID != userID {
+		return nil, domain.ErrNotBookingOrganizer
+	}
+	if err := checkOpenForPlayers(booking); err != nil {
+		return nil, err
+	}
+
+	if err := s.bookingRepo.UpdateOpenMatch(bookingID, isOpen, minLevel, maxLevel); err != nil {
+		return nil, err
+	}
+
+	return s.bookingRepo.FindByID(bookingID)
+}
+
+// Join une al usuario a un partido abierto si su nivel está dentro del rango y queda plaza.
+// El jugador entra como participante aceptado; para abandonar el partido se usa Remove.
+func (s *BookingParticipantService) Join(bookingID int, userID uuid.UUID) (*domain.BookingParticipant, error) {
+	booking, err := s.bookingRepo.FindByID(bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if !booking.IsOpenMatch {
+		return nil, domain.ErrNotOpenMatch
+	}
+	if booking.UserID == userID {
+		return nil, domain.ErrAlreadyInMatch
+	}
+	if err := checkOpenForPlayers(booking); err != nil {
+		return nil, err
+	}
+
+	level, err := s.repo.FindUserLevel(userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := booking.AcceptsLevel(level); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	participant := &domain.BookingParticipant{
+		BookingID:   bookingID,
+		UserID:      userID,
+		InvitedByID: userID,
+		Status:      domain.ParticipantStatusAccepted,
+		RespondedAt: &now,
+	}
+	// Si ya tenía una invitación pendiente debe aceptarla en lugar de unirse (ErrAlreadyInvited)
+	if err := s.repo.CreateIfRoom(participant, domain.MaxPlayers(booking.PistaType)); err != nil {
+		return nil, err
+	}
+
+	return s.repo.FindByID(participant.ID)
+}
+
+// checkOpenForPlayers comprueba que la reserva admite cambios de participantes
+func checkOpenForPlayers(booking *domain.Booking) error {
+	if booking == nil ||
+		booking.Status == domain.StatusCancelled ||
+		booking.Status == domain.StatusCompleted ||
+		!booking.StartTime.After(time.Now()) {
+		return domain.ErrBookingNotOpenForPlayer
+	}
+	return nil
+}
@@ -0,0 +1,316 @@
+package application
+
+import (
+	"backend-go/features/bookings/domain"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BookingSeriesService gestiona las reservas recurrentes (series semanales/quincenales)
+// apoyándose en las validaciones de BookingService para cada ocurrencia
+type BookingSeriesService struct {
+	repo           domain.BookingSeriesRepository
+	bookingRepo    domain.BookingRepository
+	bookingService *BookingService
+	location       *time.Location // Zona horaria de la instalación: las series conservan la hora local
+}
+
+func NewBookingSeriesService(
+	repo domain.BookingSeriesRepository,
+	bookingRepo domain.BookingRepository,
+	bookingService *BookingService,
+	location *time.Location,
+) *BookingSeriesService {
+	return &BookingSeriesService{
+		repo:           repo,
+		bookingRepo:    bookingRepo,
+		bookingService: bookingService,
+		location:       location,
+	}
+}
+
+// SeriesResult agrupa la serie resultante y las ocurrencias que no se pudieron reservar
+type SeriesResult struct {
+	Series    *domain.BookingSeries
+	Conflicts []domain.SeriesConflict
+}
+
+// OccurrenceChanges contiene los nuevos datos de una ocurrencia editada
+type OccurrenceChanges struct {
+	PistaID   int
+	StartTime time.Time
+	EndTime   time.Time
+	Notes     *string
+}
+
+// GetSeries obtiene una serie con todas sus ocurrencias. Solo la ve su titular o el personal.
+func (s *BookingSeriesService) GetSeries(id int, userID uuid.UUID, isStaff bool) (*domain.BookingSeries, error) {
+	series, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if !isStaff && series.UserID != userID {
+		return nil, domain.ErrNotSeriesOwner
+	}
+	return series, nil
+}
+
+// CheckSeries calcula las ocurrencias de la serie y valida cada una sin crear nada
+func (s *BookingSeriesService) CheckSeries(series *domain.BookingSeries) ([]domain.SeriesOccurrence, []domain.SeriesConflict, error) {
+	occurrences, err := series.GenerateOccurrences(s.location)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	free := []domain.SeriesOccurrence{}
+	conflicts := []domain.SeriesConflict{}
+	for _, occurrence := range occurrences {
+		if err := s.bookingService.ValidateSlot(series.PistaID, occurrence.StartTime, occurrence.EndTime, nil); err != nil {
+			conflicts = append(conflicts, domain.SeriesConflict{
+				StartTime: occurrence.StartTime,
+				EndTime:   occurrence.EndTime,
+				Reason:    err.Error(),
+			})
+			continue
+		}
+		free = append(free, occurrence)
+	}
+
+	return free, conflicts, nil
+}
+
+// CreateSeries crea una serie y todas sus ocurrencias.
+// Si alguna ocurrencia tiene conflicto se rechaza la serie completa, salvo que
+// skipConflicts sea true, en cuyo caso solo se crean las ocurrencias libres.
+func (s *BookingSeriesService) CreateSeries(series *domain.BookingSeries, skipConflicts bool) (*SeriesResult, error) {
+	free, conflicts, err := s.CheckSeries(series)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SeriesResult{Series: series, Conflicts: conflicts}
+	if len(conflicts) > 0 && !skipConflicts {
+		return result, domain.ErrSeriesHasConflicts
+	}
+	if len(free) == 0 {
+		return result, domain.ErrNoAvailableOccurrences
+	}
+
+	bookings := make([]domain.Booking, len(free))
+	for i, occurrence := range free {
+		// Cada ocurrencia tiene su propio precio (franjas, fines de semana, promociones)
+		price, err := s.bookingService.priceCalculator.CalculatePrice(
+			series.PistaID, series.UserID, occurrence.StartTime, occurrence.EndTime,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error al calcular el precio de la reserva: %w", err)
+		}
+
+		bookings[i] = domain.Booking{
+			UserID:             series.UserID,
+			PistaID:            series.PistaID,
+			StartTime:          occurrence.StartTime,
+			EndTime:            occurrence.EndTime,
+			PriceSnapshotCents: price,
+			Status:             domain.StatusPending,
+			PaymentStatus:      domain.PaymentStatusUnpaid,
+			Notes:              series.Notes,
+		}
+	}
+
+	series.Status = domain.SeriesStatusActive
+	if err := s.repo.CreateWithBookings(series, bookings); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// UpdateOccurrences edita una ocurrencia de la serie aplicando el mismo desplazamiento
+// horario, duración y pista al resto de ocurrencias incluidas en el alcance. Las ocurrencias
+// sin pagar se vuelven a tarifar como en UpdateBooking.
+// Si alguna ocurrencia afectada entra en conflicto no se modifica ninguna.
+func (s *BookingSeriesService) UpdateOccurrences(bookingID int, userID uuid.UUID, isStaff bool, scope string, changes OccurrenceChanges) (*SeriesResult, error) {
+	if err := domain.ValidateScope(scope); err != nil {
+		return nil, err
+	}
+
+	booking, targets, err := s.resolveScope(bookingID, userID, isStaff, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	offset := changes.StartTime.Sub(booking.StartTime)
+	duration := changes.EndTime.Sub(changes.StartTime)
+
+	conflicts := []domain.SeriesConflict{}
+	for i := range targets {
+		target := &targets[i]
+		newStart := target.StartTime.Add(offset)
+		newEnd := newStart.Add(duration)
+
+		if err := s.bookingService.ValidateSlot(changes.PistaID, newStart, newEnd, &target.ID); err != nil {
+			targetID := target.ID
+			conflicts = append(conflicts, domain.SeriesConflict{
+				BookingID: &targetID,
+				StartTime: newStart,
+				EndTime:   newEnd,
+				Reason:    err.Error(),
+			})
+			continue
+		}
+
+		target.PistaID = changes.PistaID
+		target.StartTime = newStart
+		target.EndTime = newEnd
+		target.Notes = changes.Notes
+		if err := s.bookingService.reprice(target); err != nil {
+			targetID := target.ID
+			conflicts = append(conflicts, domain.SeriesConflict{
+				BookingID: &targetID,
+				StartTime: newStart,
+				EndTime:   newEnd,
+				Reason:    err.Error(),
+			})
+		}
+	}
+
+	series, err := s.repo.FindByID(*booking.SeriesID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SeriesResult{Series: series, Conflicts: conflicts}
+	if len(conflicts) > 0 {
+		return result, domain.ErrSeriesHasConflicts
+	}
+
+	if err := s.repo.UpdateBookings(targets); err != nil {
+		return nil, err
+	}
+
+	// Al editar toda la serie, la regla base también cambia
+	if scope == domain.ScopeAll {
+		series.PistaID = changes.PistaID
+		series.StartTime = series.StartTime.Add(offset)
+		series.EndTime = series.StartTime.Add(duration)
+		series.Notes = changes.Notes
+		if err := s.repo.Update(series); err != nil {
+			return nil, err
+		}
+	}
+
+	result.Series, err = s.repo.FindByID(series.ID)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// CancelOccurrences cancela una ocurrencia, esta y las siguientes o la serie completa.
+// Cada cancelación reembolsa por separado, así que no pueden ir en una única transacción: si
+// alguna falla se siguen cancelando las demás, las fallidas se devuelven como conflicto con
+// ErrSeriesPartlyCancelled y la regla de la serie no cambia hasta que se cancelen todas.
+func (s *BookingSeriesService) CancelOccurrences(bookingID int, userID uuid.UUID, isStaff bool, scope string) (*SeriesResult, error) {
+	if err := domain.ValidateScope(scope); err != nil {
+		return nil, err
+	}
+
+	booking, targets, err := s.resolveScope(bookingID, userID, isStaff, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	failed := []domain.SeriesConflict{}
+	for _, target := range targets {
+		if err := s.bookingService.CancelBooking(target.ID); err != nil {
+			targetID := target.ID
+			failed = append(failed, domain.SeriesConflict{
+				BookingID: &targetID,
+				StartTime: target.StartTime,
+				EndTime:   target.EndTime,
+				Reason:    err.Error(),
+			})
+		}
+	}
+
+	series, err := s.repo.FindByID(*booking.SeriesID)
+	if err != nil {
+		return nil, err
+	}
+	if len(failed) > 0 {
+		return &SeriesResult{Series: series, Conflicts: failed}, domain.ErrSeriesPartlyCancelled
+	}
+
+	switch scope {
+	case domain.ScopeAll:
+		series.Status = domain.SeriesStatusCancelled
+	case domain.ScopeThisAndFollowing:
+		// La recurrencia termina el día anterior a la ocurrencia cancelada
+		until := booking.StartTime.AddDate(0, 0, -1)
+		series.UntilDate = &until
+		series.Occurrences = nil
+		if !booking.StartTime.After(series.StartTime) {
+			series.Status = domain.SeriesStatusCancelled
+		}
+	}
+
+	if scope != domain.ScopeThis {
+		if err := s.repo.Update(series); err != nil {
+			return nil, err
+		}
+	}
+
+	series, err = s.repo.FindByID(series.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &SeriesResult{Series: series, Conflicts: failed}, nil
+}
+
+// resolveScope devuelve la ocurrencia de referencia y las ocurrencias afectadas por el alcance.
+// Solo se incluyen ocurrencias activas (no canceladas ni completadas) que aún no han empezado.
+// Todas las ocurrencias de una serie son del titular de la serie: solo él o el personal la gestionan.
+func (s *BookingSeriesService) resolveScope(bookingID int, userID uuid.UUID, isStaff bool, scope string) (*domain.Booking, []domain.Booking, error) {
+	booking, err := s.bookingRepo.FindByID(bookingID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if booking.SeriesID == nil {
+		return nil, nil, domain.ErrBookingNotInSeries
+	}
+	if !isStaff && booking.UserID != userID {
+		return nil, nil, domain.ErrNotSeriesOwner
+	}
+	if booking.Status == domain.StatusCancelled || booking.Status == domain.StatusCompleted {
+		return nil, nil, fmt.Errorf("la reserva está en estado %s y no se puede modificar", booking.Status)
+	}
+
+	if scope == domain.ScopeThis {
+		return booking, []domain.Booking{*booking}, nil
+	}
+
+	siblings, err := s.bookingRepo.FindBySeries(*booking.SeriesID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	targets := []domain.Booking{}
+	for _, sibling := range siblings {
+		if sibling.Status == domain.StatusCancelled || sibling.Status == domain.StatusCompleted {
+			continue
+		}
+		if sibling.StartTime.Before(now) {
+			continue
+		}
+		if scope == domain.ScopeThisAndFollowing && sibling.StartTime.Before(booking.StartTime) {
+			continue
+		}
+		targets = append(targets, sibling)
+	}
+
+	return booking, targets, nil
+}
@@ -2,26 +2,66 @@ package application
 
 import (
 	"backend-go/features/bookings/domain"
-	"backend-go/shared/database"
+	"backend-go/shared/availability"
+	"context"
 	"errors"
 	"fmt"
 	"time"
 
-	"gorm.io/gorm"
+	"github.com/google/uuid"
 )
 
+// PriceCalculator define la interfaz para calcular el precio de una reserva (módulo pricing)
+type PriceCalculator interface {
+	CalculatePrice(pistaID int, userID uuid.UUID, startTime, endTime time.Time) (int, error)
+}
+
+// RefundProcessor define la interfaz para reembolsar el pago de una reserva cancelada (módulo payments)
+type RefundProcessor interface {
+	// RefundBookingCancellation aplica la política de cancelación y devuelve el importe reembolsado
+	RefundBookingCancellation(bookingID int, startTime time.Time) (int, error)
+	// RefundUnpaidBooking devuelve íntegras las partes ya pagadas de una reserva con pago
+	// dividido que se cancela sin haberse completado el pago
+	RefundUnpaidBooking(bookingID int) (int, error)
+}
+
+// PassRedeemer define la interfaz para pagar reservas con bonos de horas de pista (módulo passes)
+type PassRedeemer interface {
+	// RedeemBooking consume los créditos de un bono del usuario. Devuelve false si no tiene
+	// ningún bono aplicable (la reserva se paga entonces por el checkout normal).
+	RedeemBooking(bookingID int, userID uuid.UUID, startTime, endTime time.Time) (bool, error)
+	// RestoreBooking devuelve los créditos al bono. Devuelve false si la reserva no se pagó con bono.
+	RestoreBooking(bookingID int) (bool, error)
+}
+
 type BookingService struct {
-	repo domain.BookingRepository
-	db   *gorm.DB
+	repo                domain.BookingRepository
+	availabilityService *availability.AvailabilityService
+	priceCalculator     PriceCalculator
+	refundProcessor     RefundProcessor
+	passRedeemer        PassRedeemer
 }
 
-func NewBookingService(repo domain.BookingRepository, db *gorm.DB) *BookingService {
+func NewBookingService(
+	repo domain.BookingRepository,
+	availabilityService *availability.AvailabilityService,
+	priceCalculator PriceCalculator,
+	refundProcessor RefundProcessor,
+) *BookingService {
 	return &BookingService{
-		repo: repo,
-		db:   db,
+		repo:                repo,
+		availabilityService: availabilityService,
+		priceCalculator:     priceCalculator,
+		refundProcessor:     refundProcessor,
 	}
 }
 
+// SetPassRedeemer habilita el pago automático de reservas con bonos. Se inyecta después de
+// construir los servicios porque el módulo passes depende de payments.
+func (s *BookingService) SetPassRedeemer(redeemer PassRedeemer) {
+	s.passRedeemer = redeemer
+}
+
 // GetAllBookings obtiene todas las reservas
 func (s *BookingService) GetAllBookings() ([]domain.Booking, error) {
 	return s.repo.FindAll()
@@ -37,73 +77,136 @@ func (s *BookingService) GetBookingsByPistaAndDate(pistaID int, date time.Time)
 	return s.repo.FindByPistaAndDate(pistaID, date)
 }
 
-// CreateBooking crea una nueva reserva con validaciones de negocio
+// GetAvailabilityGrid devuelve la rejilla libre/ocupado (reservas y clases) de las pistas
+// indicadas entre dos fechas, limitada al horario de apertura de cada pista
+func (s *BookingService) GetAvailabilityGrid(pistaIDs []int, from, to time.Time, slotMinutes int) ([]availability.PistaGrid, error) {
+	return s.availabilityService.GetAvailabilityGrid(availability.GridQuery{
+		PistaIDs:    pistaIDs,
+		From:        from,
+		To:          to,
+		SlotMinutes: slotMinutes,
+	})
+}
+
+// CreateBooking crea una nueva reserva con validaciones de negocio. Si el usuario tiene un bono
+// de horas de pista vigente, se pagan con él y la reserva queda confirmada.
 func (s *BookingService) CreateBooking(booking *domain.Booking) error {
-	// VALIDACIÓN 1: Horario comercial (09:00 - 23:00)
-	if err := s.validateBusinessHours(booking.StartTime, booking.EndTime); err != nil {
+	if err := s.ValidateSlot(booking.PistaID, booking.StartTime, booking.EndTime, nil); err != nil {
 		return err
 	}
 
-	// VALIDACIÓN 2: Duración mínima y máxima
-	duration := booking.EndTime.Sub(booking.StartTime)
-	if duration < 1*time.Hour {
-		return fmt.Errorf("la duración mínima de una reserva es 1 hora (duración actual: %.0f minutos)", duration.Minutes())
+	// CALCULAR PRECIO: precio por hora x duración con reglas de precio (franjas, socios, promos)
+	price, err := s.priceCalculator.CalculatePrice(booking.PistaID, booking.UserID, booking.StartTime, booking.EndTime)
+	if err != nil {
+		return fmt.Errorf("error al calcular el precio de la reserva: %w", err)
 	}
-	if duration > 3*time.Hour {
-		return fmt.Errorf("la duración máxima de una reserva es 3 horas (duración solicitada: %.0f horas)", duration.Hours())
+	booking.PriceSnapshotCents = price
+
+	// Valores por defecto
+	if booking.Status == "" {
+		booking.Status = domain.StatusPending
+	}
+	if booking.PaymentStatus == "" {
+		booking.PaymentStatus = domain.PaymentStatusUnpaid
 	}
 
-	// VALIDACIÓN 3: Fecha no puede ser en el pasado
-	now := time.Now()
-	if booking.StartTime.Before(now) {
-		return fmt.Errorf("no se pueden crear reservas en el pasado (fecha solicitada: %s)", booking.StartTime.Format("02/01/2006 15:04"))
+	// Comprobación final e inserción en una transacción serializable: dos peticiones
+	// concurrentes sobre el mismo hueco nunca pueden confirmarse a la vez
+	if err := s.repo.CreateIfAvailable(booking); err != nil {
+		return err
 	}
 
-	// VALIDACIÓN 4: Verificar solapamiento (solo en la misma pista)
-	hasOverlap, err := s.repo.CheckOverlap(booking.PistaID, booking.StartTime, booking.EndTime, nil)
+	return s.redeemPass(booking)
+}
+
+// redeemPass paga la reserva recién creada con un bono del usuario, si tiene alguno aplicable.
+// Si el consumo falla la reserva se elimina para no dejarla a medias.
+func (s *BookingService) redeemPass(booking *domain.Booking) error {
+	if s.passRedeemer == nil || booking.PaymentStatus != domain.PaymentStatusUnpaid {
+		return nil
+	}
+
+	redeemed, err := s.passRedeemer.RedeemBooking(booking.ID, booking.UserID, booking.StartTime, booking.EndTime)
 	if err != nil {
-		return fmt.Errorf("error al verificar disponibilidad: %w", err)
+		return errors.Join(fmt.Errorf("error al pagar la reserva con bono: %w", err), s.repo.Delete(booking.ID))
 	}
-	if hasOverlap {
-		return fmt.Errorf("la pista ya está reservada en ese horario (%s - %s)",
-			booking.StartTime.Format("15:04"), booking.EndTime.Format("15:04"))
+	if !redeemed {
+		return nil
 	}
 
-	// CALCULAR PRECIO: Obtener precio base de la pista
-	pistaPrice, err := s.getPistaBasePrice(booking.PistaID)
-	if err != nil {
-		return fmt.Errorf("error al obtener precio de pista: %w", err)
+	booking.PaymentStatus = domain.PaymentStatusPaid
+	booking.Status = domain.StatusConfirmed
+	if err := s.repo.Update(booking); err != nil {
+		_, restoreErr := s.passRedeemer.RestoreBooking(booking.ID)
+		return errors.Join(err, restoreErr, s.repo.Delete(booking.ID))
 	}
-	booking.PriceSnapshotCents = pistaPrice
+	return nil
+}
 
-	// Valores por defecto
-	if booking.Status == "" {
-		booking.Status = domain.StatusPending
+// ValidateSlot aplica las reglas de negocio de una franja reservable:
+// horario comercial, duración, fecha futura y ausencia de reservas o clases en la pista
+func (s *BookingService) ValidateSlot(pistaID int, startTime, endTime time.Time, excludeID *int) error {
+	// VALIDACIÓN 1: Horario de apertura de la pista (horario semanal, festivos y bloqueos)
+	if err := s.validateBusinessHours(pistaID, startTime, endTime); err != nil {
+		return err
 	}
-	if booking.PaymentStatus == "" {
-		booking.PaymentStatus = domain.PaymentStatusUnpaid
+
+	// VALIDACIÓN 2: Duración mínima y máxima
+	duration := endTime.Sub(startTime)
+	if duration < 1*time.Hour {
+		return fmt.Errorf("la duración mínima de una reserva es 1 hora (duración actual: %.0f minutos)", duration.Minutes())
+	}
+	if duration > 3*time.Hour {
+		return fmt.Errorf("la duración máxima de una reserva es 3 horas (duración solicitada: %.0f horas)", duration.Hours())
+	}
+
+	// VALIDACIÓN 3: Fecha no puede ser en el pasado
+	now := time.Now()
+	if startTime.Before(now) {
+		return fmt.Errorf("no se pueden crear reservas en el pasado (fecha solicitada: %s)", startTime.Format("02/01/2006 15:04"))
+	}
+
+	// VALIDACIÓN 4: Verificar disponibilidad (reservas y clases en la misma pista)
+	if err := s.availabilityService.CheckPistaAvailable(pistaID, startTime, endTime, excludeID, nil); err != nil {
+		return fmt.Errorf("%w (%s - %s)", err, startTime.Format("15:04"), endTime.Format("15:04"))
 	}
 
-	return s.repo.Create(booking)
+	return nil
 }
 
 // UpdateBooking actualiza una reserva existente
 func (s *BookingService) UpdateBooking(booking *domain.Booking) error {
-	// Validar horario comercial
-	if err := s.validateBusinessHours(booking.StartTime, booking.EndTime); err != nil {
+	// Validar horario de apertura
+	if err := s.validateBusinessHours(booking.PistaID, booking.StartTime, booking.EndTime); err != nil {
 		return err
 	}
 
-	// Verificar solapamiento excluyendo la reserva actual (solo en la misma pista)
-	hasOverlap, err := s.repo.CheckOverlap(booking.PistaID, booking.StartTime, booking.EndTime, &booking.ID)
-	if err != nil {
-		return fmt.Errorf("error al verificar solapamiento: %w", err)
+	if err := s.reprice(booking); err != nil {
+		return err
 	}
-	if hasOverlap {
-		return fmt.Errorf("ya existe una reserva activa en ese horario para la pista ID %d", booking.PistaID)
+
+	// Verificar disponibilidad y guardar de forma atómica, excluyendo la reserva actual
+	return s.repo.UpdateIfAvailable(booking)
+}
+
+// reprice actualiza el precio de una reserva movida de pista u horario. Las pagadas conservan
+// su precio; con el pago dividido el precio ya está repartido entre los jugadores: se mantiene
+// y la reserva no puede empezar antes del plazo de pago.
+func (s *BookingService) reprice(booking *domain.Booking) error {
+	split := booking.SplitDeadline != nil && booking.PaymentStatus == domain.PaymentStatusUnpaid
+	if split && booking.StartTime.Before(*booking.SplitDeadline) {
+		return errors.New("la reserva tiene el pago dividido y no puede empezar antes del plazo de pago")
+	}
+	if booking.PaymentStatus == domain.PaymentStatusPaid || split {
+		return nil
 	}
 
-	return s.repo.Update(booking)
+	price, err := s.priceCalculator.CalculatePrice(booking.PistaID, booking.UserID, booking.StartTime, booking.EndTime)
+	if err != nil {
+		return err
+	}
+	booking.PriceSnapshotCents = price
+	return nil
 }
 
 // DeleteBooking elimina una reserva (soft delete)
@@ -118,47 +221,78 @@ func (s *BookingService) CancelBooking(id int) error {
 		return err
 	}
 
-	booking.Status = domain.StatusCancelled
-	return s.repo.Update(booking)
-}
-
-// validateBusinessHours valida que la reserva esté dentro del horario comercial (09:00 - 23:00)
-func (s *BookingService) validateBusinessHours(startTime, endTime time.Time) error {
-	startHour := startTime.Hour()
-	endHour := endTime.Hour()
-	endMinute := endTime.Minute()
+	if booking.Status == domain.StatusCancelled {
+		return errors.New("la reserva ya está cancelada")
+	}
+	if booking.Status == domain.StatusCompleted {
+		return errors.New("no se puede cancelar una reserva completada")
+	}
 
-	// Validar hora de inicio
-	if startHour < domain.OpeningHour {
-		return fmt.Errorf("la hora de inicio no puede ser antes de las %02d:00", domain.OpeningHour)
+	// Reembolsar según la política de cancelación (antes de cancelar: si el reembolso
+	// falla la reserva sigue activa y la cancelación se puede reintentar). Las reservas
+	// pagadas con bono devuelven los créditos al bono.
+	if booking.PaymentStatus == domain.PaymentStatusPaid && s.passRedeemer != nil {
+		restored, err := s.passRedeemer.RestoreBooking(booking.ID)
+		if err != nil {
+			return fmt.Errorf("error al devolver los créditos del bono: %w", err)
+		}
+		if restored {
+			booking.PaymentStatus = domain.PaymentStatusRefunded
+		}
+	}
+	if booking.PaymentStatus == domain.PaymentStatusPaid {
+		refunded, err := s.refundProcessor.RefundBookingCancellation(booking.ID, booking.StartTime)
+		if err != nil {
+			return fmt.Errorf("error al reembolsar la reserva: %w", err)
+		}
+		// Lo cobrado es el precio menos el descuento del código promocional
+		if refunded >= booking.PriceSnapshotCents-booking.DiscountCents {
+			booking.PaymentStatus = domain.PaymentStatusRefunded
+		} else if refunded > 0 {
+			booking.PaymentStatus = domain.PaymentStatusPartiallyRefunded
+		}
 	}
 
-	// Validar hora de fin (permitir exactamente las 23:00 o antes)
-	if endHour > domain.ClosingHour || (endHour == domain.ClosingHour && endMinute > 0) {
-		return fmt.Errorf("la hora de fin no puede ser después de las %02d:00", domain.ClosingHour)
+	if booking.PaymentStatus == domain.PaymentStatusUnpaid && booking.SplitDeadline != nil {
+		refunded, err := s.refundProcessor.RefundUnpaidBooking(booking.ID)
+		if err != nil {
+			return fmt.Errorf("error al devolver las partes pagadas de la reserva: %w", err)
+		}
+		if refunded > 0 {
+			booking.PaymentStatus = domain.PaymentStatusRefunded
+		}
 	}
 
+	booking.Status = domain.StatusCancelled
+	return s.repo.Update(booking)
+}
+
+// validateBusinessHours valida que la reserva esté dentro del horario comercial (09:00 - 23:00)
+func (s *BookingService) validateBusinessHours(pistaID int, startTime, endTime time.Time) error {
 	// Validar que end > start
 	if !endTime.After(startTime) {
 		return errors.New("la hora de fin debe ser posterior a la hora de inicio")
 	}
 
-	return nil
+	// Horario semanal de la pista, días de cierre y bloqueos (módulo schedules)
+	return s.availabilityService.CheckSchedule(pistaID, startTime, endTime)
 }
 
-// AutoUpdateBookingStatuses actualiza automáticamente los estados de las reservas según reglas de negocio
-func (s *BookingService) AutoUpdateBookingStatuses() (int, error) {
+// AutoUpdateBookingStatuses actualiza automáticamente los estados de las reservas según reglas de negocio.
+// Las consultas usan ctx: el plazo o la parada del scheduler interrumpen la ejecución.
+func (s *BookingService) AutoUpdateBookingStatuses(ctx context.Context) (int, error) {
+	repo := s.repo.WithContext(ctx)
 	now := time.Now()
 	updatedCount := 0
 
 	// 1. Completar reservas CONFIRMADAS que ya finalizaron
-	confirmedBookings, err := s.repo.FindConfirmedBookingsEndedBefore(now)
+	confirmedBookings, err := repo.FindConfirmedBookingsEndedBefore(now)
 	if err != nil {
 		return 0, fmt.Errorf("error al buscar reservas confirmadas finalizadas: %w", err)
 	}
 
 	for _, booking := range confirmedBookings {
-		if err := s.repo.UpdateStatus(booking.ID, domain.StatusCompleted); err != nil {
+		if err := repo.UpdateStatus(booking.ID, domain.StatusCompleted); err != nil {
 			return updatedCount, fmt.Errorf("error al completar reserva %d: %w", booking.ID, err)
 		}
 		updatedCount++
@@ -166,13 +300,13 @@ func (s *BookingService) AutoUpdateBookingStatuses() (int, error) {
 
 	// 2. Cancelar reservas PENDIENTES cuya hora de inicio ya pasó
 	// (Asumimos que si no pagaron antes de que empiece, se cancela automáticamente)
-	pendingBookings, err := s.repo.FindPendingBookingsStartedBefore(now)
+	pendingBookings, err := repo.FindPendingBookingsStartedBefore(now)
 	if err != nil {
 		return updatedCount, fmt.Errorf("error al buscar reservas pendientes expiradas: %w", err)
 	}
 
 	for _, booking := range pendingBookings {
-		if err := s.repo.UpdateStatus(booking.ID, domain.StatusCancelled); err != nil {
+		if err := repo.UpdateStatus(booking.ID, domain.StatusCancelled); err != nil {
 			return updatedCount, fmt.Errorf("error al cancelar reserva pendiente %d: %w", booking.ID, err)
 		}
 		updatedCount++
@@ -180,17 +314,3 @@ func (s *BookingService) AutoUpdateBookingStatuses() (int, error) {
 
 	return updatedCount, nil
 }
-
-// getPistaBasePrice obtiene el precio base de una pista desde la BD
-func (s *BookingService) getPistaBasePrice(pistaID int) (int, error) {
-	var pista database.Pista
-	if err := s.db.First(&pista, pistaID).Error; err != nil {
-		return 0, fmt.Errorf("pista no encontrada: %w", err)
-	}
-
-	if !pista.IsActive {
-		return 0, errors.New("la pista no está activa")
-	}
-
-	return pista.BasePriceCents, nil
-}
@@ -14,9 +14,16 @@ type Booking struct {
 	StartTime          time.Time
 	EndTime            time.Time
 	PriceSnapshotCents int
+	DiscountCents      int     // Descuento del código promocional aplicado al pagar
+	PromoCode          *string // Código promocional aplicado (nil si no hay)
 	Status             string
 	PaymentStatus      string
 	Notes              *string
+	SeriesID           *int       // Serie recurrente (nil si es una reserva suelta)
+	SplitDeadline      *time.Time // Pago dividido entre jugadores: plazo para completarlo (nil = sin dividir)
+	IsOpenMatch        bool       // Partido abierto: otros jugadores pueden unirse hasta completarlo
+	MinLevel           *float64   // Partido abierto: nivel mínimo (nil = sin mínimo)
+	MaxLevel           *float64   // Partido abierto: nivel máximo (nil = sin máximo)
 	CreatedAt          time.Time
 	UpdatedAt          time.Time
 
@@ -36,12 +43,8 @@ const (
 
 // Estados de pago
 const (
-	PaymentStatusUnpaid = "UNPAID"
-	PaymentStatusPaid   = "PAID"
-)
-
-// Horarios de apertura (hardcodeados según MVP)
-const (
-	OpeningHour = 9  // 09:00
-	ClosingHour = 23 // 23:00
+	PaymentStatusUnpaid            = "UNPAID"
+	PaymentStatusPaid              = "PAID"
+	PaymentStatusRefunded          = "REFUNDED"
+	PaymentStatusPartiallyRefunded = "PARTIALLY_REFUNDED"
 )
@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Errores de participantes e invitaciones
+var (
+	ErrParticipantNotFound     = errors.New("invitación no encontrada")
+	ErrInviteeNotFound         = errors.New("no existe ningún usuario registrado con ese slug o email")
+	ErrInvalidInvitee          = errors.New("indica el slug o el email del jugador a invitar")
+	ErrCannotInviteOrganizer   = errors.New("el titular de la reserva ya es participante")
+	ErrAlreadyInvited          = errors.New("el jugador ya está invitado a la reserva")
+	ErrBookingFull             = errors.New("la reserva ya tiene el número máximo de jugadores para esta pista")
+	ErrNotBookingOrganizer     = errors.New("solo el titular de la reserva puede gestionar sus participantes")
+	ErrNotBookingParticipant   = errors.New("no participas en esta reserva")
+	ErrNotInvitee              = errors.New("solo el jugador invitado puede responder a la invitación")
+	ErrInvitationAnswered      = errors.New("la invitación ya fue respondida")
+	ErrBookingNotOpenForPlayer = errors.New("la reserva ya no admite cambios de participantes (cancelada, completada o ya empezada)")
+)
+
+// Estados de la invitación de un participante
+const (
+	ParticipantStatusPending  = "PENDING"
+	ParticipantStatusAccepted = "ACCEPTED"
+	ParticipantStatusDeclined = "DECLINED"
+)
+
+// DefaultMaxPlayers se aplica a los tipos de pista sin máximo configurado
+const DefaultMaxPlayers = 4
+
+// MaxPlayersByPistaType es el número máximo de jugadores por reserva (titular incluido) según
+// el tipo de pista
+var MaxPlayersByPistaType = map[string]int{
+	"PADEL":         4,
+	"TENIS":         4,
+	"BALONCESTO":    10,
+	"FUTBOL":        14,
+	"POLIDEPORTIVA": 12,
+}
+
+// MaxPlayers devuelve el máximo de jugadores por reserva de un tipo de pista
+func MaxPlayers(pistaType string) int {
+	if max, ok := MaxPlayersByPistaType[pistaType]; ok {
+		return max
+	}
+	return DefaultMaxPlayers
+}
+
+// BookingParticipant representa a un jugador invitado a una reserva
+type BookingParticipant struct {
+	ID          int
+	BookingID   int
+	UserID      uuid.UUID
+	InvitedByID uuid.UUID
+	Status      string
+	RespondedAt *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+
+	// Relaciones expandidas (solo para lectura)
+	UserName  string
+	UserSlug  string
+	AvatarURL *string
+	Booking   *Booking // Solo en "mis invitaciones"
+}
+
+// IsActive indica si la invitación ocupa plaza en la reserva (pendiente o aceptada)
+func (p *BookingParticipant) IsActive() bool {
+	return p.Status == ParticipantStatusPending || p.Status == ParticipantStatusAccepted
+}
+
+// BookingParticipantRepository define las operaciones de persistencia de participantes
+type BookingParticipantRepository interface {
+	FindByID(id int) (*BookingParticipant, error)
+	FindByBooking(bookingID int) ([]BookingParticipant, error)
+	// FindByUser obtiene las invitaciones de un usuario (status vacío = todas), con su reserva
+	FindByUser(userID uuid.UUID, status string) ([]BookingParticipant, error)
+	// FindUserBySlugOrEmail resuelve el usuario a invitar (ErrInviteeNotFound si no existe)
+	FindUserBySlugOrEmail(slug, email string) (uuid.UUID, error)
+	// FindUserLevel obtiene el nivel de juego del usuario (nil si no lo ha indicado)
+	FindUserLevel(userID uuid.UUID) (*float64, error)
+	// CreateIfRoom bloquea la reserva y crea la invitación solo si, contando al titular y a las
+	// invitaciones pendientes o aceptadas, no se supera maxPlayers
+	CreateIfRoom(participant *BookingParticipant, maxPlayers int) error
+	UpdateStatus(id int, status string, respondedAt time.Time) error
+	Delete(id int) error
+}
@@ -1,15 +1,32 @@
 package domain
 
-import "time"
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
 
 // BookingRepository define las operaciones de persistencia para reservas
 type BookingRepository interface {
+	// WithContext devuelve el repositorio con sus consultas ligadas a ctx (plazo y cancelación)
+	WithContext(ctx context.Context) BookingRepository
+
 	FindAll() ([]Booking, error)
 	FindByID(id int) (*Booking, error)
 	FindByPistaAndDate(pistaID int, date time.Time) ([]Booking, error)
 	FindByPistaAndTimeRange(pistaID int, startTime, endTime time.Time) ([]Booking, error)
+	FindBySeries(seriesID int) ([]Booking, error)
+	// FindByUser obtiene las reservas de un usuario: como titular o como participante que aceptó la invitación
+	FindByUser(userID uuid.UUID) ([]Booking, error)
+	// FindOpenMatches obtiene los partidos abiertos no cancelados que empiezan en [from, to), con
+	// el número de jugadores (titular e invitaciones pendientes o aceptadas). pistaType vacío = todos
+	FindOpenMatches(from, to time.Time, pistaType string) ([]OpenMatch, error)
 	Create(booking *Booking) error
 	Update(booking *Booking) error
+	// CreateIfAvailable/UpdateIfAvailable comprueban reservas y clases y escriben de forma atómica
+	CreateIfAvailable(booking *Booking) error
+	UpdateIfAvailable(booking *Booking) error
 	Delete(id int) error
 	CheckOverlap(pistaID int, startTime, endTime time.Time, excludeID *int) (bool, error)
 
@@ -17,4 +34,6 @@ type BookingRepository interface {
 	FindConfirmedBookingsEndedBefore(endTime time.Time) ([]Booking, error)
 	FindPendingBookingsStartedBefore(startTime time.Time) ([]Booking, error)
 	UpdateStatus(id int, newStatus string) error
+	// UpdateOpenMatch abre o cierra la reserva como partido abierto con su rango de nivel
+	UpdateOpenMatch(id int, isOpen bool, minLevel, maxLevel *float64) error
 }
@@ -0,0 +1,162 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Errores de series recurrentes
+var (
+	ErrSeriesNotFound         = errors.New("serie de reservas no encontrada")
+	ErrInvalidFrequency       = errors.New("frecuencia inválida (usar WEEKLY o BIWEEKLY)")
+	ErrInvalidRecurrenceEnd   = errors.New("la serie debe indicar una fecha de fin o un número de ocurrencias (solo uno de los dos)")
+	ErrTooManyOccurrences     = errors.New("la serie supera el número máximo de ocurrencias permitidas")
+	ErrInvalidSeriesScope     = errors.New("alcance inválido (usar THIS, THIS_AND_FOLLOWING o ALL)")
+	ErrBookingNotInSeries     = errors.New("la reserva no pertenece a ninguna serie")
+	ErrSeriesHasConflicts     = errors.New("algunas ocurrencias de la serie tienen conflictos")
+	ErrNoAvailableOccurrences = errors.New("ninguna ocurrencia de la serie está disponible")
+	ErrNotSeriesOwner         = errors.New("solo el titular de la serie o el personal pueden gestionarla")
+	ErrSeriesPartlyCancelled  = errors.New("algunas ocurrencias no se pudieron cancelar; repite la cancelación para reintentarlas")
+)
+
+// Frecuencias de recurrencia
+const (
+	FrequencyWeekly   = "WEEKLY"
+	FrequencyBiweekly = "BIWEEKLY"
+)
+
+// Estados de la serie
+const (
+	SeriesStatusActive    = "ACTIVE"
+	SeriesStatusCancelled = "CANCELLED"
+)
+
+// Alcance de una edición/cancelación sobre una serie
+const (
+	ScopeThis             = "THIS"
+	ScopeThisAndFollowing = "THIS_AND_FOLLOWING"
+	ScopeAll              = "ALL"
+)
+
+// MaxSeriesOccurrences limita el tamaño de una serie (aprox. una temporada completa semanal)
+const MaxSeriesOccurrences = 52
+
+// BookingSeries representa una reserva recurrente en el dominio
+type BookingSeries struct {
+	ID          int
+	UserID      uuid.UUID
+	PistaID     int
+	Frequency   string
+	StartTime   time.Time // Inicio de la primera ocurrencia
+	EndTime     time.Time // Fin de la primera ocurrencia
+	UntilDate   *time.Time
+	Occurrences *int
+	Status      string
+	Notes       *string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+
+	// Relaciones expandidas (solo para lectura)
+	UserName  string
+	PistaName string
+	Bookings  []Booking
+}
+
+// SeriesOccurrence representa una ocurrencia calculada de la serie
+type SeriesOccurrence struct {
+	StartTime time.Time
+	EndTime   time.Time
+}
+
+// SeriesConflict describe una ocurrencia que no se puede reservar y el motivo
+type SeriesConflict struct {
+	BookingID *int // Solo en ediciones de ocurrencias existentes
+	StartTime time.Time
+	EndTime   time.Time
+	Reason    string
+}
+
+// ValidateRule verifica que la regla de recurrencia sea coherente
+func (s *BookingSeries) ValidateRule() error {
+	if s.Frequency != FrequencyWeekly && s.Frequency != FrequencyBiweekly {
+		return ErrInvalidFrequency
+	}
+	if (s.UntilDate == nil) == (s.Occurrences == nil) {
+		return ErrInvalidRecurrenceEnd
+	}
+	if s.Occurrences != nil && (*s.Occurrences < 1 || *s.Occurrences > MaxSeriesOccurrences) {
+		return ErrTooManyOccurrences
+	}
+	if s.UntilDate != nil && s.UntilDate.Before(s.StartTime) {
+		return ErrInvalidRecurrenceEnd
+	}
+	return nil
+}
+
+// Step devuelve el número de días entre dos ocurrencias consecutivas
+func (s *BookingSeries) Step() int {
+	if s.Frequency == FrequencyBiweekly {
+		return 14
+	}
+	return 7
+}
+
+// GenerateOccurrences calcula todas las ocurrencias de la serie a partir de la regla. Los días se
+// avanzan en la zona horaria de la instalación (location) para conservar la hora local: una serie
+// de "martes a las 19:00" sigue a las 19:00 tras el cambio de horario. Las ocurrencias se
+// devuelven en UTC.
+func (s *BookingSeries) GenerateOccurrences(location *time.Location) ([]SeriesOccurrence, error) {
+	if err := s.ValidateRule(); err != nil {
+		return nil, err
+	}
+
+	duration := s.EndTime.Sub(s.StartTime)
+	firstStart := s.StartTime.In(location)
+	occurrences := []SeriesOccurrence{}
+
+	for i := 0; ; i++ {
+		start := firstStart.AddDate(0, 0, i*s.Step()).UTC()
+
+		if s.Occurrences != nil && i >= *s.Occurrences {
+			break
+		}
+		if s.UntilDate != nil && start.After(endOfDay(*s.UntilDate)) {
+			break
+		}
+		if i >= MaxSeriesOccurrences {
+			return nil, ErrTooManyOccurrences
+		}
+
+		occurrences = append(occurrences, SeriesOccurrence{
+			StartTime: start,
+			EndTime:   start.Add(duration),
+		})
+	}
+
+	return occurrences, nil
+}
+
+// ValidateScope verifica que el alcance de una operación sobre la serie sea válido
+func ValidateScope(scope string) error {
+	switch scope {
+	case ScopeThis, ScopeThisAndFollowing, ScopeAll:
+		return nil
+	}
+	return ErrInvalidSeriesScope
+}
+
+// endOfDay devuelve el último instante del día de la fecha dada
+func endOfDay(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 23, 59, 59, 0, date.Location())
+}
+
+// BookingSeriesRepository define las operaciones de persistencia para series de reservas
+type BookingSeriesRepository interface {
+	FindByID(id int) (*BookingSeries, error)
+	FindByUser(userID uuid.UUID) ([]BookingSeries, error)
+	CreateWithBookings(series *BookingSeries, bookings []Booking) error
+	Update(series *BookingSeries) error
+	UpdateBookings(bookings []Booking) error
+}
@@ -0,0 +1,61 @@
+package domain
+
+import "errors"
+
+// Errores de partidos abiertos
+var (
+	ErrInvalidLevelRange  = errors.New("el rango de nivel debe estar entre 1.0 y 7.0 y el mínimo no puede superar al máximo")
+	ErrNotOpenMatch       = errors.New("la reserva no es un partido abierto")
+	ErrSkillLevelRequired = errors.New("indica tu nivel de juego en el perfil para unirte a este partido")
+	ErrLevelOutOfRange    = errors.New("tu nivel de juego no está dentro del rango del partido")
+	ErrAlreadyInMatch     = errors.New("ya juegas en este partido")
+)
+
+// Rango de niveles de juego admitidos (escala habitual de pádel)
+const (
+	MinSkillLevel = 1.0
+	MaxSkillLevel = 7.0
+)
+
+// OpenMatch es una reserva abierta a otros jugadores con su ocupación actual
+type OpenMatch struct {
+	Booking    Booking
+	Players    int // Titular e invitaciones pendientes o aceptadas
+	MaxPlayers int
+}
+
+// FreeSpots devuelve las plazas libres del partido
+func (m *OpenMatch) FreeSpots() int {
+	if free := m.MaxPlayers - m.Players; free > 0 {
+		return free
+	}
+	return 0
+}
+
+// ValidateLevelRange comprueba un rango de nivel opcional (nil = sin límite)
+func ValidateLevelRange(minLevel, maxLevel *float64) error {
+	for _, level := range []*float64{minLevel, maxLevel} {
+		if level != nil && (*level < MinSkillLevel || *level > MaxSkillLevel) {
+			return ErrInvalidLevelRange
+		}
+	}
+	if minLevel != nil && maxLevel != nil && *minLevel > *maxLevel {
+		return ErrInvalidLevelRange
+	}
+	return nil
+}
+
+// AcceptsLevel indica si un jugador con ese nivel (nil = sin indicar) puede unirse al partido.
+// Los partidos sin rango admiten a cualquiera.
+func (b *Booking) AcceptsLevel(level *float64) error {
+	if b.MinLevel == nil && b.MaxLevel == nil {
+		return nil
+	}
+	if level == nil {
+		return ErrSkillLevelRequired
+	}
+	if (b.MinLevel != nil && *level < *b.MinLevel) || (b.MaxLevel != nil && *level > *b.MaxLevel) {
+		return ErrLevelOutOfRange
+	}
+	return nil
+}
@@ -16,13 +16,24 @@ func ToEntity(model *database.Booking) *domain.Booking {
 		StartTime:          model.StartTime,
 		EndTime:            model.EndTime,
 		PriceSnapshotCents: model.PriceSnapshotCents,
+		DiscountCents:      model.DiscountCents,
+		PromoCode:          model.PromoCode,
 		Status:             model.Status,
 		PaymentStatus:      model.PaymentStatus,
 		Notes:              model.Notes,
+		SplitDeadline:      model.SplitDeadline,
+		IsOpenMatch:        model.IsOpenMatch,
+		MinLevel:           model.MinLevel,
+		MaxLevel:           model.MaxLevel,
 		CreatedAt:          model.CreatedAt,
 		UpdatedAt:          model.UpdatedAt,
 	}
 
+	if model.SeriesID != nil {
+		seriesID := int(*model.SeriesID)
+		booking.SeriesID = &seriesID
+	}
+
 	// Cargar relaciones expandidas si están disponibles
 	if model.User.ID != (uuid.UUID{}) {
 		booking.UserName = model.User.FullName
@@ -43,14 +54,24 @@ func FromEntity(booking *domain.Booking) *database.Booking {
 		StartTime:          booking.StartTime,
 		EndTime:            booking.EndTime,
 		PriceSnapshotCents: booking.PriceSnapshotCents,
+		DiscountCents:      booking.DiscountCents,
+		PromoCode:          booking.PromoCode,
 		Status:             booking.Status,
 		PaymentStatus:      booking.PaymentStatus,
 		Notes:              booking.Notes,
+		SplitDeadline:      booking.SplitDeadline,
+		IsOpenMatch:        booking.IsOpenMatch,
+		MinLevel:           booking.MinLevel,
+		MaxLevel:           booking.MaxLevel,
 	}
 
 	if booking.ID != 0 {
 		model.ID = uint(booking.ID)
 	}
+	if booking.SeriesID != nil {
+		seriesID := uint(*booking.SeriesID)
+		model.SeriesID = &seriesID
+	}
 
 	return model
 }
@@ -0,0 +1,220 @@
+package infrastructure
+
+import (
+	"backend-go/features/bookings/domain"
+	"backend-go/shared/database"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BookingParticipantRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewBookingParticipantRepository(db *gorm.DB) domain.BookingParticipantRepository {
+	return &BookingParticipantRepositoryImpl{db: db}
+}
+
+// FindByID obtiene una invitación con su reserva
+func (r *BookingParticipantRepositoryImpl) FindByID(id int) (*domain.BookingParticipant, error) {
+	var model database.BookingParticipant
+	if err := r.db.
+		Preload("User").
+		Preload("Booking.User").
+		Preload("Booking.Pista").
+		First(&model, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrParticipantNotFound
+		}
+		return nil, err
+	}
+
+	return ParticipantToEntity(&model), nil
+}
+
+// FindByBooking obtiene los invitados de una reserva en orden de invitación
+func (r *BookingParticipantRepositoryImpl) FindByBooking(bookingID int) ([]domain.BookingParticipant, error) {
+	var models []database.BookingParticipant
+	if err := r.db.
+		Preload("User").
+		Where("booking_id = ?", bookingID).
+		Order("created_at ASC").
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	participants := make([]domain.BookingParticipant, len(models))
+	for i, model := range models {
+		participants[i] = *ParticipantToEntity(&model)
+	}
+
+	return participants, nil
+}
+
+// FindByUser obtiene las invitaciones de un usuario con los datos de la reserva
+func (r *BookingParticipantRepositoryImpl) FindByUser(userID uuid.UUID, status string) ([]domain.BookingParticipant, error) {
+	query := r.db.
+		Preload("User").
+		Preload("Booking.User").
+		Preload("Booking.Pista").
+		Where("user_id = ?", userID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var models []database.BookingParticipant
+	if err := query.Order("created_at DESC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	participants := make([]domain.BookingParticipant, len(models))
+	for i, model := range models {
+		participants[i] = *ParticipantToEntity(&model)
+	}
+
+	return participants, nil
+}
+
+// FindUserBySlugOrEmail resuelve el usuario a invitar por su slug o su email
+func (r *BookingParticipantRepositoryImpl) FindUserBySlugOrEmail(slug, email string) (uuid.UUID, error) {
+	query := r.db.Model(&database.User{}).Select("id").Where("is_active = ?", true)
+	if slug != "" {
+		query = query.Where("slug = ?", strings.TrimSpace(slug))
+	} else {
+		query = query.Where("LOWER(email) = ?", strings.ToLower(strings.TrimSpace(email)))
+	}
+
+	var user database.User
+	if err := query.First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return uuid.Nil, domain.ErrInviteeNotFound
+		}
+		return uuid.Nil, err
+	}
+
+	return user.ID, nil
+}
+
+// FindUserLevel obtiene el nivel de juego declarado por el usuario en su perfil
+func (r *BookingParticipantRepositoryImpl) FindUserLevel(userID uuid.UUID) (*float64, error) {
+	var user database.User
+	if err := r.db.Select("id", "skill_level").First(&user, "id = ?", userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrInviteeNotFound
+		}
+		return nil, err
+	}
+
+	return user.SkillLevel, nil
+}
+
+// CreateIfRoom crea la invitación con la reserva bloqueada: dos invitaciones simultáneas a la
+// última plaza se serializan y la segunda ve la reserva completa
+func (r *BookingParticipantRepositoryImpl) CreateIfRoom(participant *domain.BookingParticipant, maxPlayers int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var booking database.Booking
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&booking, participant.BookingID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("reserva no encontrada")
+			}
+			return err
+		}
+
+		var existing database.BookingParticipant
+		err := tx.Where("booking_id = ? AND user_id = ?", participant.BookingID, participant.UserID).First(&existing).Error
+		if err == nil && existing.Status != domain.ParticipantStatusDeclined {
+			return domain.ErrAlreadyInvited
+		}
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		// El titular ocupa siempre una plaza
+		var active int64
+		if err := tx.Model(&database.BookingParticipant{}).
+			Where("booking_id = ? AND status IN ?", participant.BookingID,
+				[]string{domain.ParticipantStatusPending, domain.ParticipantStatusAccepted}).
+			Count(&active).Error; err != nil {
+			return err
+		}
+		if int(active)+1 >= maxPlayers {
+			return domain.ErrBookingFull
+		}
+
+		// Un jugador que rechazó puede volver a ser invitado: se reutiliza su fila
+		model := ParticipantToModel(participant)
+		if existing.ID != 0 {
+			model.ID = existing.ID
+			model.CreatedAt = existing.CreatedAt
+			if err := tx.Save(model).Error; err != nil {
+				return err
+			}
+		} else if err := tx.Create(model).Error; err != nil {
+			return err
+		}
+
+		participant.ID = int(model.ID)
+		participant.CreatedAt = model.CreatedAt
+		participant.UpdatedAt = model.UpdatedAt
+		return nil
+	})
+}
+
+// UpdateStatus registra la respuesta del invitado
+func (r *BookingParticipantRepositoryImpl) UpdateStatus(id int, status string, respondedAt time.Time) error {
+	return r.db.Model(&database.BookingParticipant{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":       status,
+		"responded_at": respondedAt,
+		"updated_at":   time.Now(),
+	}).Error
+}
+
+// Delete elimina la invitación (el titular retira al jugador o el jugador abandona la reserva)
+func (r *BookingParticipantRepositoryImpl) Delete(id int) error {
+	return r.db.Delete(&database.BookingParticipant{}, id).Error
+}
+
+// ParticipantToEntity convierte un modelo de GORM a una entidad de dominio
+func ParticipantToEntity(model *database.BookingParticipant) *domain.BookingParticipant {
+	participant := &domain.BookingParticipant{
+		ID:          int(model.ID),
+		BookingID:   int(model.BookingID),
+		UserID:      model.UserID,
+		InvitedByID: model.InvitedByID,
+		Status:      model.Status,
+		RespondedAt: model.RespondedAt,
+		CreatedAt:   model.CreatedAt,
+		UpdatedAt:   model.UpdatedAt,
+	}
+
+	if model.User.ID != (uuid.UUID{}) {
+		participant.UserName = model.User.FullName
+		participant.UserSlug = model.User.Slug
+		participant.AvatarURL = model.User.AvatarURL
+	}
+	if model.Booking.ID != 0 {
+		participant.Booking = ToEntity(&model.Booking)
+	}
+
+	return participant
+}
+
+// ParticipantToModel convierte una entidad de dominio a un modelo de GORM
+func ParticipantToModel(participant *domain.BookingParticipant) *database.BookingParticipant {
+	model := &database.BookingParticipant{
+		BookingID:   uint(participant.BookingID),
+		UserID:      participant.UserID,
+		InvitedByID: participant.InvitedByID,
+		Status:      participant.Status,
+		RespondedAt: participant.RespondedAt,
+	}
+	if participant.ID != 0 {
+		model.ID = uint(participant.ID)
+	}
+	return model
+}
@@ -2,11 +2,14 @@ package infrastructure
 
 import (
 	"backend-go/features/bookings/domain"
+	"backend-go/shared/availability"
 	"backend-go/shared/database"
+	"context"
 	"errors"
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
@@ -18,6 +21,11 @@ func NewBookingRepository(db *gorm.DB) domain.BookingRepository {
 	return &BookingRepositoryImpl{db: db}
 }
 
+// WithContext devuelve una copia del repositorio cuyas consultas usan ctx
+func (r *BookingRepositoryImpl) WithContext(ctx context.Context) domain.BookingRepository {
+	return &BookingRepositoryImpl{db: r.db.WithContext(ctx)}
+}
+
 // FindAll obtiene todas las reservas con relaciones
 func (r *BookingRepositoryImpl) FindAll() ([]domain.Booking, error) {
 	var models []database.Booking
@@ -46,6 +54,85 @@ func (r *BookingRepositoryImpl) FindByID(id int) (*domain.Booking, error) {
 	return ToEntity(&model), nil
 }
 
+// FindByUser obtiene las reservas en las que juega un usuario: las suyas y aquellas a las que
+// aceptó la invitación del titular
+func (r *BookingRepositoryImpl) FindByUser(userID uuid.UUID) ([]domain.Booking, error) {
+	var models []database.Booking
+	if err := r.db.
+		Preload("User").
+		Preload("Pista").
+		Where("user_id = ? OR id IN (?)", userID,
+			r.db.Model(&database.BookingParticipant{}).Select("booking_id").
+				Where("user_id = ? AND status = ?", userID, domain.ParticipantStatusAccepted)).
+		Order("start_time DESC").
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	bookings := make([]domain.Booking, len(models))
+	for i, model := range models {
+		bookings[i] = *ToEntity(&model)
+	}
+
+	return bookings, nil
+}
+
+// FindOpenMatches obtiene los partidos abiertos que empiezan en [from, to) con su número de
+// jugadores: el titular más las invitaciones pendientes o aceptadas
+func (r *BookingRepositoryImpl) FindOpenMatches(from, to time.Time, pistaType string) ([]domain.OpenMatch, error) {
+	query := r.db.
+		Preload("User").
+		Preload("Pista").
+		Where("is_open_match = ?", true).
+		Where("status IN ?", []string{domain.StatusPending, domain.StatusConfirmed}).
+		Where("start_time >= ? AND start_time < ?", from, to)
+	if pistaType != "" {
+		query = query.Where("pista_id IN (?)",
+			r.db.Model(&database.Pista{}).Select("id").Where("type = ?", pistaType))
+	}
+
+	var models []database.Booking
+	if err := query.Order("start_time ASC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	if len(models) == 0 {
+		return []domain.OpenMatch{}, nil
+	}
+
+	bookingIDs := make([]uint, len(models))
+	for i, model := range models {
+		bookingIDs[i] = model.ID
+	}
+
+	var counts []struct {
+		BookingID uint
+		Active    int
+	}
+	if err := r.db.Model(&database.BookingParticipant{}).
+		Select("booking_id, COUNT(*) AS active").
+		Where("booking_id IN ? AND status IN ?", bookingIDs,
+			[]string{domain.ParticipantStatusPending, domain.ParticipantStatusAccepted}).
+		Group("booking_id").
+		Scan(&counts).Error; err != nil {
+		return nil, err
+	}
+
+	activeByBooking := make(map[uint]int, len(counts))
+	for _, count := range counts {
+		activeByBooking[count.BookingID] = count.Active
+	}
+
+	matches := make([]domain.OpenMatch, len(models))
+	for i, model := range models {
+		matches[i] = domain.OpenMatch{
+			Booking: *ToEntity(&model),
+			Players: activeByBooking[model.ID] + 1,
+		}
+	}
+
+	return matches, nil
+}
+
 // FindByPistaAndDate obtiene reservas de una pista en un día específico
 func (r *BookingRepositoryImpl) FindByPistaAndDate(pistaID int, date time.Time) ([]domain.Booking, error) {
 	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
@@ -91,13 +178,33 @@ func (r *BookingRepositoryImpl) FindByPistaAndTimeRange(pistaID int, startTime,
 	return bookings, nil
 }
 
+// FindBySeries obtiene todas las reservas de una serie ordenadas cronológicamente
+func (r *BookingRepositoryImpl) FindBySeries(seriesID int) ([]domain.Booking, error) {
+	var models []database.Booking
+	if err := r.db.
+		Preload("User").
+		Preload("Pista").
+		Where("series_id = ?", seriesID).
+		Order("start_time ASC").
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	bookings := make([]domain.Booking, len(models))
+	for i, model := range models {
+		bookings[i] = *ToEntity(&model)
+	}
+
+	return bookings, nil
+}
+
 // Create crea una nueva reserva
 func (r *BookingRepositoryImpl) Create(booking *domain.Booking) error {
 	model := FromEntity(booking)
 
 	if err := r.db.Create(model).Error; err != nil {
-		// Capturar error de constraint único (código 23505 de PostgreSQL)
-		if strings.Contains(err.Error(), "idx_booking_overlap") || strings.Contains(err.Error(), "23505") {
+		// Capturar error de constraint único/exclusión de PostgreSQL
+		if isOverlapError(err) {
 			return errors.New("ya existe una reserva en ese horario para esta pista")
 		}
 		return err
@@ -115,7 +222,58 @@ func (r *BookingRepositoryImpl) Update(booking *domain.Booking) error {
 	model := FromEntity(booking)
 
 	if err := r.db.Save(model).Error; err != nil {
-		if strings.Contains(err.Error(), "idx_booking_overlap") || strings.Contains(err.Error(), "23505") {
+		if isOverlapError(err) {
+			return errors.New("ya existe una reserva en ese horario para esta pista")
+		}
+		return err
+	}
+
+	booking.UpdatedAt = model.UpdatedAt
+	return nil
+}
+
+// CreateIfAvailable crea la reserva solo si la pista está libre de reservas y clases.
+// Comprobación e inserción se ejecutan en una única transacción serializable.
+func (r *BookingRepositoryImpl) CreateIfAvailable(booking *domain.Booking) error {
+	model := FromEntity(booking)
+
+	err := availability.NewAvailabilityService(r.db).RunSerializable(func(tx *gorm.DB) error {
+		if err := availability.NewAvailabilityService(tx).CheckPistaAvailable(
+			booking.PistaID, booking.StartTime, booking.EndTime, nil, nil,
+		); err != nil {
+			return err
+		}
+		return tx.Create(model).Error
+	})
+	if err != nil {
+		if isOverlapError(err) {
+			return errors.New("ya existe una reserva en ese horario para esta pista")
+		}
+		return err
+	}
+
+	booking.ID = int(model.ID)
+	booking.CreatedAt = model.CreatedAt
+	booking.UpdatedAt = model.UpdatedAt
+
+	return nil
+}
+
+// UpdateIfAvailable actualiza la reserva solo si el nuevo horario está libre de otras
+// reservas y clases, en una única transacción serializable
+func (r *BookingRepositoryImpl) UpdateIfAvailable(booking *domain.Booking) error {
+	model := FromEntity(booking)
+
+	err := availability.NewAvailabilityService(r.db).RunSerializable(func(tx *gorm.DB) error {
+		if err := availability.NewAvailabilityService(tx).CheckPistaAvailable(
+			booking.PistaID, booking.StartTime, booking.EndTime, &booking.ID, nil,
+		); err != nil {
+			return err
+		}
+		return tx.Omit("created_at").Save(model).Error
+	})
+	if err != nil {
+		if isOverlapError(err) {
 			return errors.New("ya existe una reserva en ese horario para esta pista")
 		}
 		return err
@@ -193,3 +351,23 @@ func (r *BookingRepositoryImpl) UpdateStatus(id int, newStatus string) error {
 		Update("status", newStatus).
 		Error
 }
+
+// UpdateOpenMatch abre o cierra la reserva como partido abierto con su rango de nivel
+func (r *BookingRepositoryImpl) UpdateOpenMatch(id int, isOpen bool, minLevel, maxLevel *float64) error {
+	return r.db.Model(&database.Booking{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"is_open_match": isOpen,
+		"min_level":     minLevel,
+		"max_level":     maxLevel,
+		"updated_at":    time.Now(),
+	}).Error
+}
+
+// isOverlapError detecta las violaciones del índice único (23505) o de la
+// exclusion constraint de rangos (23P01) que impiden reservas solapadas
+func isOverlapError(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "idx_booking_overlap") ||
+		strings.Contains(msg, "excl_booking_overlap") ||
+		strings.Contains(msg, "23505") ||
+		strings.Contains(msg, "23P01")
+}
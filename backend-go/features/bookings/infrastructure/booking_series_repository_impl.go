@@ -0,0 +1,182 @@
+package infrastructure
+
+import (
+	"backend-go/features/bookings/domain"
+	"backend-go/shared/availability"
+	"backend-go/shared/database"
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type BookingSeriesRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewBookingSeriesRepository(db *gorm.DB) domain.BookingSeriesRepository {
+	return &BookingSeriesRepositoryImpl{db: db}
+}
+
+// FindByID obtiene una serie con todas sus reservas
+func (r *BookingSeriesRepositoryImpl) FindByID(id int) (*domain.BookingSeries, error) {
+	var model database.BookingSeries
+	if err := r.db.
+		Preload("User").
+		Preload("Pista").
+		Preload("Bookings", func(db *gorm.DB) *gorm.DB { return db.Order("start_time ASC") }).
+		First(&model, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrSeriesNotFound
+		}
+		return nil, err
+	}
+
+	return SeriesToEntity(&model), nil
+}
+
+// FindByUser obtiene las series de un usuario
+func (r *BookingSeriesRepositoryImpl) FindByUser(userID uuid.UUID) ([]domain.BookingSeries, error) {
+	var models []database.BookingSeries
+	if err := r.db.
+		Preload("Pista").
+		Where("user_id = ?", userID).
+		Order("start_time DESC").
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	series := make([]domain.BookingSeries, len(models))
+	for i, model := range models {
+		series[i] = *SeriesToEntity(&model)
+	}
+
+	return series, nil
+}
+
+// CreateWithBookings crea la serie y todas sus ocurrencias en una única transacción
+// serializable, comprobando de nuevo cada ocurrencia contra reservas y clases
+func (r *BookingSeriesRepositoryImpl) CreateWithBookings(series *domain.BookingSeries, bookings []domain.Booking) error {
+	return availability.NewAvailabilityService(r.db).RunSerializable(func(tx *gorm.DB) error {
+		txAvailability := availability.NewAvailabilityService(tx)
+		model := SeriesFromEntity(series)
+		if err := tx.Create(model).Error; err != nil {
+			return err
+		}
+
+		seriesID := int(model.ID)
+		for i := range bookings {
+			bookings[i].SeriesID = &seriesID
+			if err := txAvailability.CheckPistaAvailable(
+				bookings[i].PistaID, bookings[i].StartTime, bookings[i].EndTime, nil, nil,
+			); err != nil {
+				return errors.New(err.Error() + " (" + bookings[i].StartTime.Format("02/01/2006 15:04") + ")")
+			}
+
+			bookingModel := FromEntity(&bookings[i])
+			if err := tx.Create(bookingModel).Error; err != nil {
+				if isOverlapError(err) {
+					return errors.New("ya existe una reserva en ese horario para esta pista (" +
+						bookings[i].StartTime.Format("02/01/2006 15:04") + ")")
+				}
+				return err
+			}
+			bookings[i].ID = int(bookingModel.ID)
+			bookings[i].CreatedAt = bookingModel.CreatedAt
+			bookings[i].UpdatedAt = bookingModel.UpdatedAt
+		}
+
+		series.ID = seriesID
+		series.CreatedAt = model.CreatedAt
+		series.UpdatedAt = model.UpdatedAt
+		series.Bookings = bookings
+		return nil
+	})
+}
+
+// Update actualiza los datos de la serie
+func (r *BookingSeriesRepositoryImpl) Update(series *domain.BookingSeries) error {
+	model := SeriesFromEntity(series)
+	if err := r.db.Omit("created_at").Save(model).Error; err != nil {
+		return err
+	}
+
+	series.UpdatedAt = model.UpdatedAt
+	return nil
+}
+
+// UpdateBookings actualiza varias ocurrencias de forma atómica (todas o ninguna)
+// en una transacción serializable, comprobando reservas y clases de cada una
+func (r *BookingSeriesRepositoryImpl) UpdateBookings(bookings []domain.Booking) error {
+	return availability.NewAvailabilityService(r.db).RunSerializable(func(tx *gorm.DB) error {
+		txAvailability := availability.NewAvailabilityService(tx)
+		for i := range bookings {
+			if err := txAvailability.CheckPistaAvailable(
+				bookings[i].PistaID, bookings[i].StartTime, bookings[i].EndTime, &bookings[i].ID, nil,
+			); err != nil {
+				return errors.New(err.Error() + " (" + bookings[i].StartTime.Format("02/01/2006 15:04") + ")")
+			}
+
+			model := FromEntity(&bookings[i])
+			if err := tx.Omit("created_at").Save(model).Error; err != nil {
+				if isOverlapError(err) {
+					return errors.New("ya existe una reserva en ese horario para esta pista (" +
+						bookings[i].StartTime.Format("02/01/2006 15:04") + ")")
+				}
+				return err
+			}
+			bookings[i].UpdatedAt = model.UpdatedAt
+		}
+		return nil
+	})
+}
+
+// SeriesToEntity convierte un modelo de GORM a una entidad de dominio
+func SeriesToEntity(model *database.BookingSeries) *domain.BookingSeries {
+	series := &domain.BookingSeries{
+		ID:          int(model.ID),
+		UserID:      model.UserID,
+		PistaID:     int(model.PistaID),
+		Frequency:   model.Frequency,
+		StartTime:   model.StartTime,
+		EndTime:     model.EndTime,
+		UntilDate:   model.UntilDate,
+		Occurrences: model.Occurrences,
+		Status:      model.Status,
+		Notes:       model.Notes,
+		CreatedAt:   model.CreatedAt,
+		UpdatedAt:   model.UpdatedAt,
+	}
+
+	if model.User.ID != (uuid.UUID{}) {
+		series.UserName = model.User.FullName
+	}
+	if model.Pista.ID != 0 {
+		series.PistaName = model.Pista.Name
+	}
+
+	if len(model.Bookings) > 0 {
+		series.Bookings = make([]domain.Booking, len(model.Bookings))
+		for i, booking := range model.Bookings {
+			series.Bookings[i] = *ToEntity(&booking)
+		}
+	}
+
+	return series
+}
+
+// SeriesFromEntity convierte una entidad de dominio a un modelo de GORM
+func SeriesFromEntity(series *domain.BookingSeries) *database.BookingSeries {
+	return &database.BookingSeries{
+		ID:          uint(series.ID),
+		UserID:      series.UserID,
+		PistaID:     uint(series.PistaID),
+		Frequency:   series.Frequency,
+		StartTime:   series.StartTime,
+		EndTime:     series.EndTime,
+		UntilDate:   series.UntilDate,
+		Occurrences: series.Occurrences,
+		Status:      series.Status,
+		Notes:       series.Notes,
+	}
+}
@@ -3,7 +3,9 @@ package presentation
 import (
 	"backend-go/features/bookings/application"
 	"backend-go/features/bookings/domain"
+	"backend-go/shared/availability"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -92,6 +94,60 @@ func (h *BookingHandler) GetByPistaAndDate(c *fiber.Ctx) error {
 	return c.JSON(responses)
 }
 
+// GetAvailability maneja GET /bookings/availability
+// @Summary Rejilla de disponibilidad (reservas y clases) de una o varias pistas
+// @Tags bookings
+// @Produce json
+// @Param pistaIds query string false "IDs de pista separados por comas (vacío = todas)"
+// @Param from query string true "Fecha inicial (YYYY-MM-DD)"
+// @Param to query string false "Fecha final inclusive (YYYY-MM-DD)"
+// @Param slot query int false "Minutos por hueco (15-180, por defecto 30)"
+// @Success 200 {array} PistaAvailabilityResponse
+// @Router /api/bookings/availability [get]
+func (h *BookingHandler) GetAvailability(c *fiber.Ctx) error {
+	var req AvailabilityGridRequest
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Parámetros inválidos"})
+	}
+
+	from, err := time.Parse("2006-01-02", req.From)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Formato de fecha inválido en 'from' (usar YYYY-MM-DD)"})
+	}
+	to := from
+	if req.To != "" {
+		to, err = time.Parse("2006-01-02", req.To)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": "Formato de fecha inválido en 'to' (usar YYYY-MM-DD)"})
+		}
+	}
+
+	pistaIDs := []int{}
+	for _, raw := range strings.Split(req.PistaIDs, ",") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		pistaID, err := strconv.Atoi(raw)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": "ID de pista inválido: " + raw})
+		}
+		pistaIDs = append(pistaIDs, pistaID)
+	}
+
+	grids, err := h.service.GetAvailabilityGrid(pistaIDs, from, to, req.Slot)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	responses := make([]PistaAvailabilityResponse, len(grids))
+	for i, grid := range grids {
+		responses[i] = ToAvailabilityResponse(grid)
+	}
+
+	return c.JSON(responses)
+}
+
 // Create maneja POST /bookings
 // @Summary Crear una nueva reserva
 // @Tags bookings
@@ -107,27 +163,9 @@ func (h *BookingHandler) Create(c *fiber.Ctx) error {
 	}
 
 	// UserID Assignment
-	var userID uuid.UUID
-	if req.UserID != "" {
-		// Si se proporciona UserID en el request, usarlo (para admins creando reservas para otros)
-		parsedID, err := uuid.Parse(req.UserID)
-		if err != nil {
-			return c.Status(400).JSON(fiber.Map{"error": "UserID inválido"})
-		}
-		userID = parsedID
-	} else {
-		// Si no se proporciona UserID, usar el del usuario autenticado (JWT context)
-		jwtUserID := c.Locals("userID")
-		if jwtUserID == nil {
-			return c.Status(401).JSON(fiber.Map{"error": "No autenticado"})
-		}
-
-		// El JWT middleware guarda userID como uuid.UUID directamente
-		var ok bool
-		userID, ok = jwtUserID.(uuid.UUID)
-		if !ok {
-			return c.Status(500).JSON(fiber.Map{"error": "Error de autenticación"})
-		}
+	userID, ferr := resolveUserID(c, req.UserID)
+	if ferr != nil {
+		return c.Status(ferr.Code).JSON(fiber.Map{"error": ferr.Message})
 	}
 
 	// Mapear Request -> Domain Entity
@@ -137,7 +175,7 @@ func (h *BookingHandler) Create(c *fiber.Ctx) error {
 		StartTime: req.StartTime.UTC(), // Convertir a UTC
 		EndTime:   req.EndTime.UTC(),
 		Notes:     req.Notes,
-		// PriceSnapshotCents se calculará en el servicio consultando la pista
+		// PriceSnapshotCents se calculará en el servicio aplicando las reglas de precio
 	}
 
 	if err := h.service.CreateBooking(booking); err != nil {
@@ -241,6 +279,55 @@ func (h *BookingHandler) Cancel(c *fiber.Ctx) error {
 	return c.JSON(ToResponse(booking))
 }
 
+// resolveUserID determina el usuario de la reserva: el del usuario autenticado o el indicado
+// en el request. Solo ADMIN/GESTOR pueden reservar para otros (la reserva puede pagarse
+// con los bonos del usuario indicado).
+func resolveUserID(c *fiber.Ctx, requestedUserID string) (uuid.UUID, *fiber.Error) {
+	// Usuario autenticado (JWT context)
+	jwtUserID := c.Locals("userID")
+	if jwtUserID == nil {
+		return uuid.Nil, fiber.NewError(401, "No autenticado")
+	}
+
+	// El JWT middleware guarda userID como uuid.UUID directamente
+	userID, ok := jwtUserID.(uuid.UUID)
+	if !ok {
+		return uuid.Nil, fiber.NewError(500, "Error de autenticación")
+	}
+
+	if requestedUserID == "" {
+		return userID, nil
+	}
+	parsedID, err := uuid.Parse(requestedUserID)
+	if err != nil {
+		return uuid.Nil, fiber.NewError(400, "UserID inválido")
+	}
+	if parsedID != userID && !isStaff(c) {
+		return uuid.Nil, fiber.NewError(403, "Solo el personal puede crear reservas para otros usuarios")
+	}
+	return parsedID, nil
+}
+
+// ToAvailabilityResponse convierte la rejilla de una pista a un DTO de respuesta
+func ToAvailabilityResponse(grid availability.PistaGrid) PistaAvailabilityResponse {
+	response := PistaAvailabilityResponse{
+		PistaID:   grid.PistaID,
+		PistaName: grid.PistaName,
+		PistaType: grid.PistaType,
+		Slots:     make([]AvailabilitySlotResponse, len(grid.Slots)),
+	}
+	for i, slot := range grid.Slots {
+		response.Slots[i] = AvailabilitySlotResponse{
+			StartTime: slot.StartTime,
+			EndTime:   slot.EndTime,
+			Status:    slot.Status,
+			BookingID: slot.BookingID,
+			ClassID:   slot.ClassID,
+		}
+	}
+	return response
+}
+
 // ToResponse convierte una entidad de dominio a un DTO de respuesta
 func ToResponse(booking *domain.Booking) BookingResponse {
 	return BookingResponse{
@@ -254,9 +341,15 @@ func ToResponse(booking *domain.Booking) BookingResponse {
 		EndTime:            booking.EndTime,
 		PriceSnapshotCents: booking.PriceSnapshotCents,
 		PriceSnapshotEuros: float64(booking.PriceSnapshotCents) / 100.0, // Convertir a euros
+		DiscountCents:      booking.DiscountCents,
+		PromoCode:          booking.PromoCode,
 		Status:             booking.Status,
 		PaymentStatus:      booking.PaymentStatus,
 		Notes:              booking.Notes,
+		SplitDeadline:      booking.SplitDeadline,
+		IsOpenMatch:        booking.IsOpenMatch,
+		MinLevel:           booking.MinLevel,
+		MaxLevel:           booking.MaxLevel,
 		CreatedAt:          booking.CreatedAt,
 		UpdatedAt:          booking.UpdatedAt,
 	}
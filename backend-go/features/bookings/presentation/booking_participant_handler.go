@@ -0,0 +1,372 @@
+package presentation
+
+import (
+	"backend-go/features/bookings/application"
+	"backend-go/features/bookings/domain"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type BookingParticipantHandler struct {
+	service *application.BookingParticipantService
+}
+
+func NewBookingParticipantHandler(service *application.BookingParticipantService) *BookingParticipantHandler {
+	return &BookingParticipantHandler{service: service}
+}
+
+// GetMyBookings maneja GET /bookings/me
+// @Summary Mis reservas (como titular o como participante que aceptó la invitación)
+// @Tags bookings
+// @Produce json
+// @Success 200 {array} BookingResponse
+// @Router /api/bookings/me [get]
+func (h *BookingParticipantHandler) GetMyBookings(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uuid.UUID)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"error": "No autenticado"})
+	}
+
+	bookings, err := h.service.GetMyBookings(userID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	responses := make([]BookingResponse, len(bookings))
+	for i := range bookings {
+		responses[i] = ToResponse(&bookings[i])
+	}
+
+	return c.JSON(responses)
+}
+
+// GetMyInvitations maneja GET /bookings/invitations
+// @Summary Mis invitaciones a reservas
+// @Tags bookings
+// @Produce json
+// @Param status query string false "PENDING, ACCEPTED o DECLINED (por defecto todas)"
+// @Success 200 {array} ParticipantResponse
+// @Router /api/bookings/invitations [get]
+func (h *BookingParticipantHandler) GetMyInvitations(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uuid.UUID)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"error": "No autenticado"})
+	}
+
+	invitations, err := h.service.GetMyInvitations(userID, strings.ToUpper(c.Query("status")))
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	responses := make([]ParticipantResponse, len(invitations))
+	for i := range invitations {
+		responses[i] = ToParticipantResponse(&invitations[i])
+	}
+
+	return c.JSON(responses)
+}
+
+// GetParticipants maneja GET /bookings/:id/participants
+// @Summary Jugadores de una reserva (titular, invitados o ADMIN/GESTOR)
+// @Tags bookings
+// @Produce json
+// @Param id path int true "ID de la reserva"
+// @Success 200 {object} BookingParticipantsResponse
+// @Failure 403 {object} map[string]string
+// @Router /api/bookings/{id}/participants [get]
+func (h *BookingParticipantHandler) GetParticipants(c *fiber.Ctx) error {
+	bookingID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "ID inválido"})
+	}
+	userID, ok := c.Locals("userID").(uuid.UUID)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"error": "No autenticado"})
+	}
+
+	booking, participants, err := h.service.GetParticipants(bookingID, userID, isStaff(c))
+	if err != nil {
+		return c.Status(participantErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(ToBookingParticipantsResponse(booking, participants))
+}
+
+// Invite maneja POST /bookings/:id/participants
+// @Summary Invitar a un jugador registrado a una reserva (por slug o email)
+// @Description Solo el titular de la reserva o ADMIN/GESTOR. El máximo de jugadores depende del tipo de pista
+// @Description (PADEL y TENIS 4, BALONCESTO 10, POLIDEPORTIVA 12, FUTBOL 14; titular incluido).
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de la reserva"
+// @Param invitation body InviteParticipantRequest true "Slug o email del jugador"
+// @Success 201 {object} ParticipantResponse
+// @Failure 409 {object} map[string]string "Ya invitado o reserva completa"
+// @Router /api/bookings/{id}/participants [post]
+func (h *BookingParticipantHandler) Invite(c *fiber.Ctx) error {
+	bookingID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "ID inválido"})
+	}
+	userID, ok := c.Locals("userID").(uuid.UUID)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"error": "No autenticado"})
+	}
+
+	var req InviteParticipantRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Datos inválidos"})
+	}
+
+	participant, err := h.service.Invite(bookingID, userID, isStaff(c), req.Slug, req.Email)
+	if err != nil {
+		return c.Status(participantErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.Status(201).JSON(ToParticipantResponse(participant))
+}
+
+// Accept maneja POST /bookings/invitations/:id/accept
+// @Summary Aceptar una invitación a una reserva
+// @Tags bookings
+// @Produce json
+// @Param id path int true "ID de la invitación"
+// @Success 200 {object} ParticipantResponse
+// @Router /api/bookings/invitations/{id}/accept [post]
+func (h *BookingParticipantHandler) Accept(c *fiber.Ctx) error {
+	return h.respond(c, true)
+}
+
+// Decline maneja POST /bookings/invitations/:id/decline
+// @Summary Rechazar una invitación a una reserva
+// @Tags bookings
+// @Produce json
+// @Param id path int true "ID de la invitación"
+// @Success 200 {object} ParticipantResponse
+// @Router /api/bookings/invitations/{id}/decline [post]
+func (h *BookingParticipantHandler) Decline(c *fiber.Ctx) error {
+	return h.respond(c, false)
+}
+
+func (h *BookingParticipantHandler) respond(c *fiber.Ctx, accept bool) error {
+	participantID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "ID inválido"})
+	}
+	userID, ok := c.Locals("userID").(uuid.UUID)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"error": "No autenticado"})
+	}
+
+	participant, err := h.service.Respond(participantID, userID, accept)
+	if err != nil {
+		return c.Status(participantErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(ToParticipantResponse(participant))
+}
+
+// Remove maneja DELETE /bookings/:id/participants/:participantId
+// @Summary Retirar a un jugador de la reserva (titular o ADMIN/GESTOR) o abandonarla (el propio jugador)
+// @Tags bookings
+// @Param id path int true "ID de la reserva"
+// @Param participantId path int true "ID de la invitación"
+// @Success 204
+// @Router /api/bookings/{id}/participants/{participantId} [delete]
+func (h *BookingParticipantHandler) Remove(c *fiber.Ctx) error {
+	bookingID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "ID inválido"})
+	}
+	participantID, err := strconv.Atoi(c.Params("participantId"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "ID inválido"})
+	}
+	userID, ok := c.Locals("userID").(uuid.UUID)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"error": "No autenticado"})
+	}
+
+	if err := h.service.Remove(bookingID, participantID, userID, isStaff(c)); err != nil {
+		return c.Status(participantErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.Status(204).Send(nil)
+}
+
+// GetOpenMatches maneja GET /bookings/open-matches
+// @Summary Partidos abiertos con plazas libres
+// @Description Sin fecha devuelve los partidos de los próximos 14 días.
+// @Tags bookings
+// @Produce json
+// @Param date query string false "Fecha (YYYY-MM-DD)"
+// @Param sport query string false "Tipo de pista (PADEL, TENIS, BALONCESTO, FUTBOL, POLIDEPORTIVA)"
+// @Success 200 {array} OpenMatchResponse
+// @Router /api/bookings/open-matches [get]
+func (h *BookingParticipantHandler) GetOpenMatches(c *fiber.Ctx) error {
+	var date *time.Time
+	if dateStr := c.Query("date"); dateStr != "" {
+		parsed, err := time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": "Formato de fecha inválido (usar YYYY-MM-DD)"})
+		}
+		date = &parsed
+	}
+
+	matches, err := h.service.GetOpenMatches(date, strings.ToUpper(strings.TrimSpace(c.Query("sport"))))
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	responses := make([]OpenMatchResponse, len(matches))
+	for i := range matches {
+		responses[i] = ToOpenMatchResponse(&matches[i])
+	}
+
+	return c.JSON(responses)
+}
+
+// SetOpenMatch maneja PUT /bookings/:id/open-match
+// @Summary Abrir o cerrar una reserva como partido abierto (titular o ADMIN/GESTOR)
+// @Description Con isOpen=false se cierra el partido y se descarta el rango de nivel. Los jugadores ya unidos se mantienen.
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de la reserva"
+// @Param openMatch body SetOpenMatchRequest true "Partido abierto y rango de nivel"
+// @Success 200 {object} BookingResponse
+// @Failure 403 {object} map[string]string
+// @Router /api/bookings/{id}/open-match [put]
+func (h *BookingParticipantHandler) SetOpenMatch(c *fiber.Ctx) error {
+	bookingID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "ID inválido"})
+	}
+	userID, ok := c.Locals("userID").(uuid.UUID)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"error": "No autenticado"})
+	}
+
+	var req SetOpenMatchRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Datos inválidos"})
+	}
+
+	booking, err := h.service.SetOpenMatch(bookingID, userID, isStaff(c), req.IsOpen, req.MinLevel, req.MaxLevel)
+	if err != nil {
+		return c.Status(participantErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(ToResponse(booking))
+}
+
+// Join maneja POST /bookings/:id/join
+// @Summary Unirse a un partido abierto
+// @Description El nivel de juego del perfil debe estar dentro del rango del partido. Para abandonarlo se usa
+// @Description DELETE /api/bookings/{id}/participants/{participantId}.
+// @Tags bookings
+// @Produce json
+// @Param id path int true "ID de la reserva"
+// @Success 201 {object} ParticipantResponse
+// @Failure 409 {object} map[string]string "Partido completo o ya unido"
+// @Router /api/bookings/{id}/join [post]
+func (h *BookingParticipantHandler) Join(c *fiber.Ctx) error {
+	bookingID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "ID inválido"})
+	}
+	userID, ok := c.Locals("userID").(uuid.UUID)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"error": "No autenticado"})
+	}
+
+	participant, err := h.service.Join(bookingID, userID)
+	if err != nil {
+		return c.Status(participantErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.Status(201).JSON(ToParticipantResponse(participant))
+}
+
+// ToOpenMatchResponse convierte un partido abierto a un DTO de respuesta
+func ToOpenMatchResponse(match *domain.OpenMatch) OpenMatchResponse {
+	return OpenMatchResponse{
+		BookingResponse: ToResponse(&match.Booking),
+		Players:         match.Players,
+		MaxPlayers:      match.MaxPlayers,
+		FreeSpots:       match.FreeSpots(),
+	}
+}
+
+// ToParticipantResponse convierte una entidad de dominio a un DTO de respuesta
+func ToParticipantResponse(participant *domain.BookingParticipant) ParticipantResponse {
+	response := ParticipantResponse{
+		ID:          participant.ID,
+		BookingID:   participant.BookingID,
+		UserID:      participant.UserID.String(),
+		UserName:    participant.UserName,
+		UserSlug:    participant.UserSlug,
+		AvatarURL:   participant.AvatarURL,
+		InvitedByID: participant.InvitedByID.String(),
+		Status:      participant.Status,
+		RespondedAt: participant.RespondedAt,
+		CreatedAt:   participant.CreatedAt,
+	}
+	if participant.Booking != nil {
+		booking := ToResponse(participant.Booking)
+		response.Booking = &booking
+	}
+	return response
+}
+
+// ToBookingParticipantsResponse construye el DTO con los jugadores de una reserva
+func ToBookingParticipantsResponse(booking *domain.Booking, participants []domain.BookingParticipant) BookingParticipantsResponse {
+	maxPlayers := domain.MaxPlayers(booking.PistaType)
+	response := BookingParticipantsResponse{
+		BookingID:    booking.ID,
+		OrganizerID:  booking.UserID.String(),
+		Organizer:    booking.UserName,
+		PistaType:    booking.PistaType,
+		MaxPlayers:   maxPlayers,
+		FreeSpots:    maxPlayers - 1,
+		Participants: make([]ParticipantResponse, len(participants)),
+	}
+	for i := range participants {
+		response.Participants[i] = ToParticipantResponse(&participants[i])
+		if participants[i].IsActive() {
+			response.FreeSpots--
+		}
+	}
+	return response
+}
+
+// participantErrorStatus traduce los errores de participantes a códigos HTTP
+func participantErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrParticipantNotFound), errors.Is(err, domain.ErrInviteeNotFound):
+		return 404
+	case errors.Is(err, domain.ErrNotBookingOrganizer),
+		errors.Is(err, domain.ErrNotBookingParticipant),
+		errors.Is(err, domain.ErrNotInvitee),
+		errors.Is(err, domain.ErrLevelOutOfRange):
+		return 403
+	case errors.Is(err, domain.ErrAlreadyInvited),
+		errors.Is(err, domain.ErrBookingFull),
+		errors.Is(err, domain.ErrInvitationAnswered),
+		errors.Is(err, domain.ErrAlreadyInMatch):
+		return 409
+	}
+	return 400
+}
+
+// isStaff indica si el usuario autenticado es ADMIN o GESTOR
+func isStaff(c *fiber.Ctx) bool {
+	roleName, _ := c.Locals("roleName").(string)
+	return roleName == "ADMIN" || roleName == "GESTOR"
+}
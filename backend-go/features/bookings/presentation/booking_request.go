@@ -21,3 +21,51 @@ type UpdateBookingRequest struct {
 	PaymentStatus string    `json:"paymentStatus"`
 	Notes         *string   `json:"notes"`
 }
+
+// CreateBookingSeriesRequest DTO para crear una serie de reservas recurrentes
+type CreateBookingSeriesRequest struct {
+	UserID        string     `json:"userId"` // UUID como string
+	PistaID       int        `json:"pistaId" validate:"required,min=1"`
+	StartTime     time.Time  `json:"startTime" validate:"required"` // Primera ocurrencia
+	EndTime       time.Time  `json:"endTime" validate:"required"`
+	Frequency     string     `json:"frequency" validate:"required,oneof=WEEKLY BIWEEKLY"`
+	UntilDate     *time.Time `json:"untilDate"`   // Fin de la recurrencia (inclusive)
+	Occurrences   *int       `json:"occurrences"` // Alternativa a untilDate
+	Notes         *string    `json:"notes"`
+	SkipConflicts bool       `json:"skipConflicts"` // Crear solo las ocurrencias libres
+}
+
+// UpdateSeriesOccurrenceRequest DTO para editar una ocurrencia de una serie
+type UpdateSeriesOccurrenceRequest struct {
+	Scope     string    `json:"scope" validate:"required,oneof=THIS THIS_AND_FOLLOWING ALL"`
+	PistaID   int       `json:"pistaId" validate:"required,min=1"`
+	StartTime time.Time `json:"startTime" validate:"required"`
+	EndTime   time.Time `json:"endTime" validate:"required"`
+	Notes     *string   `json:"notes"`
+}
+
+// CancelSeriesOccurrenceRequest DTO para cancelar ocurrencias de una serie
+type CancelSeriesOccurrenceRequest struct {
+	Scope string `json:"scope" validate:"required,oneof=THIS THIS_AND_FOLLOWING ALL"`
+}
+
+// AvailabilityGridRequest parámetros de la rejilla de disponibilidad (query string)
+type AvailabilityGridRequest struct {
+	PistaIDs string `query:"pistaIds"` // IDs separados por comas (vacío = todas las pistas activas)
+	From     string `query:"from"`     // YYYY-MM-DD
+	To       string `query:"to"`       // YYYY-MM-DD (inclusive, por defecto igual a from)
+	Slot     int    `query:"slot"`     // Minutos por hueco (por defecto 30)
+}
+
+// InviteParticipantRequest DTO para invitar a un jugador a una reserva (slug o email)
+type InviteParticipantRequest struct {
+	Slug  string `json:"slug"`
+	Email string `json:"email"`
+}
+
+// SetOpenMatchRequest DTO para abrir o cerrar una reserva como partido abierto
+type SetOpenMatchRequest struct {
+	IsOpen   bool     `json:"isOpen"`
+	MinLevel *float64 `json:"minLevel"` // Nivel mínimo (1.0 - 7.0, opcional)
+	MaxLevel *float64 `json:"maxLevel"` // Nivel máximo (1.0 - 7.0, opcional)
+}
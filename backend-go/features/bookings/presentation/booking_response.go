@@ -4,19 +4,118 @@ import "time"
 
 // BookingResponse DTO para retornar información de una reserva
 type BookingResponse struct {
-	ID                 int       `json:"id"`
-	UserID             string    `json:"userId"` // UUID como string
-	UserName           string    `json:"userName"`
-	PistaID            int       `json:"pistaId"`
-	PistaName          string    `json:"pistaName"`
-	PistaType          string    `json:"pistaType"`
-	StartTime          time.Time `json:"startTime"`
-	EndTime            time.Time `json:"endTime"`
-	PriceSnapshotCents int       `json:"priceSnapshotCents"`
-	PriceSnapshotEuros float64   `json:"priceSnapshotEuros"` // Convertido a euros para UI
-	Status             string    `json:"status"`
-	PaymentStatus      string    `json:"paymentStatus"`
-	Notes              *string   `json:"notes"`
-	CreatedAt          time.Time `json:"createdAt"`
-	UpdatedAt          time.Time `json:"updatedAt"`
+	ID                 int        `json:"id"`
+	UserID             string     `json:"userId"` // UUID como string
+	UserName           string     `json:"userName"`
+	PistaID            int        `json:"pistaId"`
+	PistaName          string     `json:"pistaName"`
+	PistaType          string     `json:"pistaType"`
+	StartTime          time.Time  `json:"startTime"`
+	EndTime            time.Time  `json:"endTime"`
+	PriceSnapshotCents int        `json:"priceSnapshotCents"`
+	PriceSnapshotEuros float64    `json:"priceSnapshotEuros"` // Convertido a euros para UI
+	DiscountCents      int        `json:"discountCents"`      // Descuento del código promocional
+	PromoCode          *string    `json:"promoCode"`
+	Status             string     `json:"status"`
+	PaymentStatus      string     `json:"paymentStatus"`
+	Notes              *string    `json:"notes"`
+	SplitDeadline      *time.Time `json:"splitDeadline,omitempty"` // Pago dividido: plazo para completar el pago
+	IsOpenMatch        bool       `json:"isOpenMatch"`
+	MinLevel           *float64   `json:"minLevel,omitempty"`
+	MaxLevel           *float64   `json:"maxLevel,omitempty"`
+	CreatedAt          time.Time  `json:"createdAt"`
+	UpdatedAt          time.Time  `json:"updatedAt"`
+}
+
+// BookingSeriesResponse DTO para retornar una serie de reservas recurrentes
+type BookingSeriesResponse struct {
+	ID          int                      `json:"id"`
+	UserID      string                   `json:"userId"` // UUID como string
+	UserName    string                   `json:"userName"`
+	PistaID     int                      `json:"pistaId"`
+	PistaName   string                   `json:"pistaName"`
+	Frequency   string                   `json:"frequency"`
+	StartTime   time.Time                `json:"startTime"`
+	EndTime     time.Time                `json:"endTime"`
+	UntilDate   *time.Time               `json:"untilDate,omitempty"`
+	Occurrences *int                     `json:"occurrences,omitempty"`
+	Status      string                   `json:"status"`
+	Notes       *string                  `json:"notes"`
+	Bookings    []BookingResponse        `json:"bookings"`
+	Conflicts   []SeriesConflictResponse `json:"conflicts,omitempty"` // Ocurrencias no reservadas
+	CreatedAt   time.Time                `json:"createdAt"`
+	UpdatedAt   time.Time                `json:"updatedAt"`
+}
+
+// SeriesConflictResponse DTO para una ocurrencia con conflicto
+type SeriesConflictResponse struct {
+	BookingID *int      `json:"bookingId,omitempty"`
+	Date      string    `json:"date"` // YYYY-MM-DD
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime"`
+	Reason    string    `json:"reason"`
+}
+
+// SeriesCheckResponse DTO con el resultado de comprobar una serie antes de crearla
+type SeriesCheckResponse struct {
+	Available []SeriesOccurrenceResponse `json:"available"`
+	Conflicts []SeriesConflictResponse   `json:"conflicts"`
+}
+
+// SeriesOccurrenceResponse DTO para una ocurrencia calculada de la serie
+type SeriesOccurrenceResponse struct {
+	Date      string    `json:"date"` // YYYY-MM-DD
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime"`
+}
+
+// AvailabilitySlotResponse DTO de un hueco de la rejilla de disponibilidad
+type AvailabilitySlotResponse struct {
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime"`
+	Status    string    `json:"status"` // FREE, BOOKED, CLASS, CLOSED, PAST
+	BookingID *uint     `json:"bookingId,omitempty"`
+	ClassID   *uint     `json:"classId,omitempty"`
+}
+
+// PistaAvailabilityResponse DTO con la rejilla de disponibilidad de una pista
+type PistaAvailabilityResponse struct {
+	PistaID   uint                       `json:"pistaId"`
+	PistaName string                     `json:"pistaName"`
+	PistaType string                     `json:"pistaType"`
+	Slots     []AvailabilitySlotResponse `json:"slots"`
+}
+
+// ParticipantResponse DTO de un jugador invitado a una reserva
+type ParticipantResponse struct {
+	ID          int              `json:"id"`
+	BookingID   int              `json:"bookingId"`
+	UserID      string           `json:"userId"` // UUID como string
+	UserName    string           `json:"userName"`
+	UserSlug    string           `json:"userSlug"`
+	AvatarURL   *string          `json:"avatarUrl,omitempty"`
+	InvitedByID string           `json:"invitedById"`
+	Status      string           `json:"status"` // PENDING, ACCEPTED, DECLINED
+	RespondedAt *time.Time       `json:"respondedAt,omitempty"`
+	Booking     *BookingResponse `json:"booking,omitempty"` // Solo en "mis invitaciones"
+	CreatedAt   time.Time        `json:"createdAt"`
+}
+
+// BookingParticipantsResponse DTO con los jugadores de una reserva
+type BookingParticipantsResponse struct {
+	BookingID    int                   `json:"bookingId"`
+	OrganizerID  string                `json:"organizerId"`
+	Organizer    string                `json:"organizer"`
+	PistaType    string                `json:"pistaType"`
+	MaxPlayers   int                   `json:"maxPlayers"` // Titular incluido
+	FreeSpots    int                   `json:"freeSpots"`  // Plazas sin invitación pendiente ni aceptada
+	Participants []ParticipantResponse `json:"participants"`
+}
+
+// OpenMatchResponse DTO de un partido abierto con sus plazas libres
+type OpenMatchResponse struct {
+	BookingResponse
+	Players    int `json:"players"`    // Titular e invitaciones pendientes o aceptadas
+	MaxPlayers int `json:"maxPlayers"` // Titular incluido
+	FreeSpots  int `json:"freeSpots"`
 }
@@ -11,16 +11,41 @@ import (
 // BOOKING ROUTES
 // Admin: GET / (todas las reservas), GET /:id
 // Autenticado: POST / (crear), PUT /:id (modificar), DELETE /:id, POST /:id/cancel
-// Público: GET /pista/:pistaId/date/:date (ver disponibilidad)
+// Series (autenticado): POST /series/check, POST /series, GET /series/:id,
+//   PUT /:id/series (editar ocurrencias), POST /:id/series/cancel
+// Participantes (autenticado): GET /me (mis reservas, también como invitado), GET /invitations,
+//   POST /invitations/:id/accept|decline, GET|POST /:id/participants, DELETE /:id/participants/:participantId
+// Partidos abiertos: GET /open-matches (público), PUT /:id/open-match, POST /:id/join (autenticado)
+// Público: GET /pista/:pistaId/date/:date, GET /availability (rejilla de disponibilidad)
 // ======================================================================================
 
 // RegisterRoutes registra las rutas del módulo de bookings
-func RegisterRoutes(app *fiber.App, handler *BookingHandler, jwtService security.JWTService) {
+func RegisterRoutes(app *fiber.App, handler *BookingHandler, seriesHandler *BookingSeriesHandler, participantHandler *BookingParticipantHandler, jwtService security.JWTService) {
 	// Grupo base
 	bookings := app.Group("/api/bookings")
 
 	// Rutas públicas - Ver disponibilidad (sin middleware)
 	bookings.Get("/pista/:pistaId/date/:date", handler.GetByPistaAndDate)
+	bookings.Get("/availability", handler.GetAvailability)
+	bookings.Get("/open-matches", participantHandler.GetOpenMatches)
+
+	// Rutas protegidas - Series recurrentes (antes de /:id para evitar colisiones)
+	bookings.Post("/series/check", middleware.JWTMiddleware(jwtService), seriesHandler.Check)
+	bookings.Post("/series", middleware.JWTMiddleware(jwtService), seriesHandler.Create)
+	bookings.Get("/series/:id", middleware.JWTMiddleware(jwtService), seriesHandler.GetByID)
+	bookings.Put("/:id/series", middleware.JWTMiddleware(jwtService), seriesHandler.UpdateOccurrence)
+	bookings.Post("/:id/series/cancel", middleware.JWTMiddleware(jwtService), seriesHandler.CancelOccurrence)
+
+	// Rutas protegidas - Participantes e invitaciones (antes de /:id para evitar colisiones)
+	bookings.Get("/me", middleware.JWTMiddleware(jwtService), participantHandler.GetMyBookings)
+	bookings.Get("/invitations", middleware.JWTMiddleware(jwtService), participantHandler.GetMyInvitations)
+	bookings.Post("/invitations/:id/accept", middleware.JWTMiddleware(jwtService), participantHandler.Accept)
+	bookings.Post("/invitations/:id/decline", middleware.JWTMiddleware(jwtService), participantHandler.Decline)
+	bookings.Get("/:id/participants", middleware.JWTMiddleware(jwtService), participantHandler.GetParticipants)
+	bookings.Post("/:id/participants", middleware.JWTMiddleware(jwtService), participantHandler.Invite)
+	bookings.Delete("/:id/participants/:participantId", middleware.JWTMiddleware(jwtService), participantHandler.Remove)
+	bookings.Put("/:id/open-match", middleware.JWTMiddleware(jwtService), participantHandler.SetOpenMatch)
+	bookings.Post("/:id/join", middleware.JWTMiddleware(jwtService), participantHandler.Join)
 
 	// Rutas protegidas - Solo ADMIN y GESTOR
 	bookings.Get("/", middleware.JWTMiddleware(jwtService), middleware.RequireRoleByName("ADMIN", "GESTOR"), handler.GetAll)
@@ -0,0 +1,288 @@
+package presentation
+
+import (
+	"backend-go/features/bookings/application"
+	"backend-go/features/bookings/domain"
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type BookingSeriesHandler struct {
+	service *application.BookingSeriesService
+}
+
+func NewBookingSeriesHandler(service *application.BookingSeriesService) *BookingSeriesHandler {
+	return &BookingSeriesHandler{service: service}
+}
+
+// Check maneja POST /bookings/series/check
+// @Summary Comprobar la disponibilidad de una serie de reservas sin crearla
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Param series body CreateBookingSeriesRequest true "Regla de recurrencia"
+// @Success 200 {object} SeriesCheckResponse
+// @Router /api/bookings/series/check [post]
+func (h *BookingSeriesHandler) Check(c *fiber.Ctx) error {
+	series, _, ferr := parseSeriesRequest(c)
+	if ferr != nil {
+		return c.Status(ferr.Code).JSON(fiber.Map{"error": ferr.Message})
+	}
+
+	available, conflicts, err := h.service.CheckSeries(series)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	response := SeriesCheckResponse{
+		Available: make([]SeriesOccurrenceResponse, len(available)),
+		Conflicts: ToConflictResponses(conflicts),
+	}
+	for i, occurrence := range available {
+		response.Available[i] = SeriesOccurrenceResponse{
+			Date:      occurrence.StartTime.Format("2006-01-02"),
+			StartTime: occurrence.StartTime,
+			EndTime:   occurrence.EndTime,
+		}
+	}
+
+	return c.JSON(response)
+}
+
+// Create maneja POST /bookings/series
+// @Summary Crear una serie de reservas recurrentes (semanal o quincenal)
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Param series body CreateBookingSeriesRequest true "Regla de recurrencia"
+// @Success 201 {object} BookingSeriesResponse
+// @Failure 409 {object} map[string]interface{} "Ocurrencias con conflicto"
+// @Router /api/bookings/series [post]
+func (h *BookingSeriesHandler) Create(c *fiber.Ctx) error {
+	series, skipConflicts, ferr := parseSeriesRequest(c)
+	if ferr != nil {
+		return c.Status(ferr.Code).JSON(fiber.Map{"error": ferr.Message})
+	}
+
+	result, err := h.service.CreateSeries(series, skipConflicts)
+	if err != nil {
+		if errors.Is(err, domain.ErrSeriesHasConflicts) || errors.Is(err, domain.ErrNoAvailableOccurrences) {
+			return c.Status(409).JSON(fiber.Map{
+				"error":     err.Error(),
+				"conflicts": ToConflictResponses(result.Conflicts),
+			})
+		}
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	response := SeriesToResponse(result.Series)
+	response.Conflicts = ToConflictResponses(result.Conflicts)
+	return c.Status(201).JSON(response)
+}
+
+// GetByID maneja GET /bookings/series/:id
+// @Summary Obtener una serie de reservas con sus ocurrencias
+// @Description Solo el titular de la serie o ADMIN/GESTOR
+// @Tags bookings
+// @Produce json
+// @Param id path int true "ID de la serie"
+// @Success 200 {object} BookingSeriesResponse
+// @Router /api/bookings/series/{id} [get]
+func (h *BookingSeriesHandler) GetByID(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "ID inválido"})
+	}
+
+	userID, ok := c.Locals("userID").(uuid.UUID)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"error": "No autenticado"})
+	}
+
+	series, err := h.service.GetSeries(id, userID, isStaff(c))
+	if err != nil {
+		if errors.Is(err, domain.ErrSeriesNotFound) {
+			return c.Status(404).JSON(fiber.Map{"error": err.Error()})
+		}
+		if errors.Is(err, domain.ErrNotSeriesOwner) {
+			return c.Status(403).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(SeriesToResponse(series))
+}
+
+// UpdateOccurrence maneja PUT /bookings/:id/series
+// @Summary Editar una ocurrencia, esta y las siguientes o toda la serie
+// @Description Solo el titular de la serie o ADMIN/GESTOR
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de la reserva (ocurrencia)"
+// @Param changes body UpdateSeriesOccurrenceRequest true "Nuevos datos y alcance"
+// @Success 200 {object} BookingSeriesResponse
+// @Failure 409 {object} map[string]interface{} "Ocurrencias con conflicto"
+// @Router /api/bookings/{id}/series [put]
+func (h *BookingSeriesHandler) UpdateOccurrence(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "ID inválido"})
+	}
+
+	userID, ok := c.Locals("userID").(uuid.UUID)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"error": "No autenticado"})
+	}
+
+	var req UpdateSeriesOccurrenceRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Datos inválidos"})
+	}
+
+	result, err := h.service.UpdateOccurrences(id, userID, isStaff(c), req.Scope, application.OccurrenceChanges{
+		PistaID:   req.PistaID,
+		StartTime: req.StartTime.UTC(),
+		EndTime:   req.EndTime.UTC(),
+		Notes:     req.Notes,
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrSeriesHasConflicts) {
+			return c.Status(409).JSON(fiber.Map{
+				"error":     err.Error(),
+				"conflicts": ToConflictResponses(result.Conflicts),
+			})
+		}
+		return c.Status(seriesErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(SeriesToResponse(result.Series))
+}
+
+// CancelOccurrence maneja POST /bookings/:id/series/cancel
+// @Summary Cancelar una ocurrencia, esta y las siguientes o toda la serie
+// @Description Solo el titular de la serie o ADMIN/GESTOR
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de la reserva (ocurrencia)"
+// @Param scope body CancelSeriesOccurrenceRequest true "Alcance de la cancelación"
+// @Success 200 {object} BookingSeriesResponse
+// @Failure 409 {object} map[string]interface{} "Ocurrencias que no se pudieron cancelar (las demás sí)"
+// @Router /api/bookings/{id}/series/cancel [post]
+func (h *BookingSeriesHandler) CancelOccurrence(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "ID inválido"})
+	}
+
+	userID, ok := c.Locals("userID").(uuid.UUID)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"error": "No autenticado"})
+	}
+
+	var req CancelSeriesOccurrenceRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Datos inválidos"})
+	}
+
+	result, err := h.service.CancelOccurrences(id, userID, isStaff(c), req.Scope)
+	if err != nil {
+		if errors.Is(err, domain.ErrSeriesPartlyCancelled) {
+			return c.Status(409).JSON(fiber.Map{
+				"error":     err.Error(),
+				"conflicts": ToConflictResponses(result.Conflicts),
+				"series":    SeriesToResponse(result.Series),
+			})
+		}
+		return c.Status(seriesErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(SeriesToResponse(result.Series))
+}
+
+// seriesErrorStatus traduce los errores al gestionar ocurrencias de una serie a un código HTTP
+func seriesErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrNotSeriesOwner):
+		return 403
+	case errors.Is(err, domain.ErrSeriesNotFound):
+		return 404
+	}
+	return 400
+}
+
+// parseSeriesRequest mapea el request de creación a la entidad de dominio
+// y devuelve si se deben omitir las ocurrencias con conflicto
+func parseSeriesRequest(c *fiber.Ctx) (*domain.BookingSeries, bool, *fiber.Error) {
+	var req CreateBookingSeriesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return nil, false, fiber.NewError(400, "Datos inválidos")
+	}
+
+	userID, ferr := resolveUserID(c, req.UserID)
+	if ferr != nil {
+		return nil, false, ferr
+	}
+
+	series := &domain.BookingSeries{
+		UserID:      userID,
+		PistaID:     req.PistaID,
+		Frequency:   req.Frequency,
+		StartTime:   req.StartTime.UTC(),
+		EndTime:     req.EndTime.UTC(),
+		Occurrences: req.Occurrences,
+		Notes:       req.Notes,
+	}
+	if req.UntilDate != nil {
+		until := req.UntilDate.UTC()
+		series.UntilDate = &until
+	}
+
+	return series, req.SkipConflicts, nil
+}
+
+// SeriesToResponse convierte una serie de dominio a un DTO de respuesta
+func SeriesToResponse(series *domain.BookingSeries) BookingSeriesResponse {
+	response := BookingSeriesResponse{
+		ID:          series.ID,
+		UserID:      series.UserID.String(),
+		UserName:    series.UserName,
+		PistaID:     series.PistaID,
+		PistaName:   series.PistaName,
+		Frequency:   series.Frequency,
+		StartTime:   series.StartTime,
+		EndTime:     series.EndTime,
+		UntilDate:   series.UntilDate,
+		Occurrences: series.Occurrences,
+		Status:      series.Status,
+		Notes:       series.Notes,
+		Bookings:    make([]BookingResponse, len(series.Bookings)),
+		CreatedAt:   series.CreatedAt,
+		UpdatedAt:   series.UpdatedAt,
+	}
+
+	for i, booking := range series.Bookings {
+		response.Bookings[i] = ToResponse(&booking)
+	}
+
+	return response
+}
+
+// ToConflictResponses convierte los conflictos de dominio a DTOs
+func ToConflictResponses(conflicts []domain.SeriesConflict) []SeriesConflictResponse {
+	responses := make([]SeriesConflictResponse, len(conflicts))
+	for i, conflict := range conflicts {
+		responses[i] = SeriesConflictResponse{
+			BookingID: conflict.BookingID,
+			Date:      conflict.StartTime.Format("2006-01-02"),
+			StartTime: conflict.StartTime,
+			EndTime:   conflict.EndTime,
+			Reason:    conflict.Reason,
+		}
+	}
+	return responses
+}
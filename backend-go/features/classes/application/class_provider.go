@@ -19,6 +19,7 @@ func (p *ClassProviderImpl) GetClassBySlug(slug string) (ClassInfo, error) {
 		ID:          class.ID,
 		Status:      class.Status,
 		MaxCapacity: class.MaxCapacity,
+		StartTime:   class.StartTime,
 	}, nil
 }
 
@@ -32,5 +33,6 @@ func (p *ClassProviderImpl) GetClassByID(id int) (ClassInfo, error) {
 		ID:          class.ID,
 		Status:      class.Status,
 		MaxCapacity: class.MaxCapacity,
+		StartTime:   class.StartTime,
 	}, nil
 }
@@ -4,6 +4,7 @@ import (
 	"backend-go/features/classes/domain"
 	"backend-go/shared/availability"
 	"backend-go/shared/pagination"
+	"context"
 	"errors"
 	"fmt"
 	"time"
@@ -11,9 +12,15 @@ import (
 	"github.com/google/uuid"
 )
 
+// WaitlistPromoter promociona la lista de espera de una clase cuando quedan plazas libres
+type WaitlistPromoter interface {
+	PromoteWaitlist(classID int) (int, error)
+}
+
 type ClassService struct {
 	repo                domain.ClassRepository
 	availabilityService *availability.AvailabilityService
+	waitlistPromoter    WaitlistPromoter
 }
 
 func NewClassService(repo domain.ClassRepository, availabilityService *availability.AvailabilityService) *ClassService {
@@ -23,6 +30,12 @@ func NewClassService(repo domain.ClassRepository, availabilityService *availabil
 	}
 }
 
+// SetWaitlistPromoter configura quién promociona la lista de espera al ampliar el aforo.
+// Se inyecta tras la construcción porque el EnrollmentService depende a su vez de ClassService.
+func (s *ClassService) SetWaitlistPromoter(promoter WaitlistPromoter) {
+	s.waitlistPromoter = promoter
+}
+
 // GetAllClasses obtiene todas las clases
 func (s *ClassService) GetAllClasses() ([]domain.Class, error) {
 	return s.repo.FindAll()
@@ -60,6 +73,11 @@ func (s *ClassService) CreateClass(class *domain.Class, userRoleID int) error {
 		return errors.New("solo administradores y staff pueden ser instructores de clases")
 	}
 
+	return s.create(class)
+}
+
+// create valida y crea una clase (también usado para las sesiones generadas por plantillas)
+func (s *ClassService) create(class *domain.Class) error {
 	// VALIDACIÓN 2: Duración mínima
 	duration := class.EndTime.Sub(class.StartTime)
 	if duration < 30*time.Minute {
@@ -69,7 +87,12 @@ func (s *ClassService) CreateClass(class *domain.Class, userRoleID int) error {
 		return errors.New("la duración máxima de una clase es 3 horas")
 	}
 
-	// VALIDACIÓN 3: Verificar disponibilidad (NO conflictos con bookings o clases existentes)
+	// VALIDACIÓN 3: Horario de apertura de la pista (horario semanal, festivos y bloqueos)
+	if err := s.availabilityService.CheckSchedule(class.PistaID, class.StartTime, class.EndTime); err != nil {
+		return err
+	}
+
+	// VALIDACIÓN 3b: Verificar disponibilidad (NO conflictos con bookings o clases existentes)
 	if err := s.availabilityService.CheckPistaAvailable(
 		class.PistaID,
 		class.StartTime,
@@ -104,7 +127,8 @@ func (s *ClassService) CreateClass(class *domain.Class, userRoleID int) error {
 		class.Status = domain.ClassStatusOpen
 	}
 
-	return s.repo.Create(class)
+	// Comprobación final e inserción atómicas frente a reservas concurrentes
+	return s.repo.CreateIfAvailable(class)
 }
 
 // UpdateClass actualiza una clase existente
@@ -119,7 +143,17 @@ func (s *ClassService) UpdateClass(class *domain.Class) error {
 		return errors.New("la capacidad mínima es 1 alumno")
 	}
 
-	return s.repo.Update(class)
+	if err := s.repo.Update(class); err != nil {
+		return err
+	}
+
+	// Si se amplió el aforo, ofertar las nuevas plazas a la lista de espera
+	if s.waitlistPromoter != nil {
+		if _, err := s.waitlistPromoter.PromoteWaitlist(class.ID); err != nil {
+			return fmt.Errorf("clase actualizada, pero falló la promoción de la lista de espera: %w", err)
+		}
+	}
+	return nil
 }
 
 // DeleteClass elimina una clase (soft delete)
@@ -249,24 +283,33 @@ func (s *ClassService) EnrollUserBySlug(classSlug string, userSlug string) error
 	return s.repo.CreateEnrollment(enrollment)
 }
 
+// withContext devuelve una copia del servicio cuyas consultas usan ctx
+func (s *ClassService) withContext(ctx context.Context) *ClassService {
+	scoped := *s
+	scoped.repo = s.repo.WithContext(ctx)
+	return &scoped
+}
+
 // UnenrollUser elimina la inscripción de un usuario de una clase
 func (s *ClassService) UnenrollUser(enrollmentID int) error {
 	return s.repo.DeleteEnrollment(enrollmentID)
 }
 
-// AutoUpdateClassStatuses actualiza automáticamente los estados de las clases según reglas de negocio
-func (s *ClassService) AutoUpdateClassStatuses() (int, error) {
+// AutoUpdateClassStatuses actualiza automáticamente los estados de las clases según reglas de negocio.
+// Las consultas usan ctx: el plazo o la parada del scheduler interrumpen la ejecución.
+func (s *ClassService) AutoUpdateClassStatuses(ctx context.Context) (int, error) {
+	repo := s.repo.WithContext(ctx)
 	now := time.Now()
 	updatedCount := 0
 
 	// Completar clases ABIERTAS o EN PROGRESO que ya finalizaron
-	openClasses, err := s.repo.FindOpenClassesEndedBefore(now)
+	openClasses, err := repo.FindOpenClassesEndedBefore(now)
 	if err != nil {
 		return 0, fmt.Errorf("error al buscar clases abiertas finalizadas: %w", err)
 	}
 
 	for _, class := range openClasses {
-		if err := s.repo.UpdateStatus(class.ID, domain.ClassStatusCompleted); err != nil {
+		if err := repo.UpdateStatus(class.ID, domain.ClassStatusCompleted); err != nil {
 			return updatedCount, fmt.Errorf("error al completar clase %d: %w", class.ID, err)
 		}
 		updatedCount++
@@ -0,0 +1,280 @@
+package application
+
+import (
+	"backend-go/features/classes/domain"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ClassTemplateService gestiona las plantillas de clases recurrentes y genera sus sesiones
+// con antelación, apoyándose en las validaciones de ClassService para cada sesión
+type ClassTemplateService struct {
+	repo         domain.ClassTemplateRepository
+	classService *ClassService
+	location     *time.Location // Zona horaria del club en la que se interpretan las horas de inicio
+}
+
+func NewClassTemplateService(repo domain.ClassTemplateRepository, classService *ClassService, location *time.Location) *ClassTemplateService {
+	return &ClassTemplateService{
+		repo:         repo,
+		classService: classService,
+		location:     location,
+	}
+}
+
+// TemplateResult agrupa la plantilla y el resultado de generar o actualizar sus sesiones
+type TemplateResult struct {
+	Template  *domain.ClassTemplate
+	Created   []domain.Class            // Sesiones nuevas
+	Updated   int                       // Sesiones futuras actualizadas con los cambios de la plantilla
+	Cancelled int                       // Sesiones en días que la plantilla ya no incluye
+	Conflicts []domain.TemplateConflict // Sesiones que no se pudieron generar o actualizar
+}
+
+// GetAllTemplates obtiene todas las plantillas de clase
+func (s *ClassTemplateService) GetAllTemplates() ([]domain.ClassTemplate, error) {
+	return s.repo.FindAll()
+}
+
+// GetTemplateByID obtiene una plantilla con los conflictos de su última generación
+func (s *ClassTemplateService) GetTemplateByID(id int) (*domain.ClassTemplate, error) {
+	return s.repo.FindByID(id)
+}
+
+// CreateTemplate crea una plantilla y genera sus primeras sesiones
+func (s *ClassTemplateService) CreateTemplate(template *domain.ClassTemplate) (*TemplateResult, error) {
+	if err := template.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(template); err != nil {
+		return nil, err
+	}
+
+	return s.generate(template, time.Now().UTC(), []domain.TemplateConflict{})
+}
+
+// UpdateTemplate actualiza una plantilla y propaga los cambios a las sesiones futuras que
+// aún no han empezado. Las sesiones pasadas, en curso o canceladas no se modifican.
+func (s *ClassTemplateService) UpdateTemplate(template *domain.ClassTemplate) (*TemplateResult, error) {
+	existing, err := s.repo.FindByID(template.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := template.Validate(); err != nil {
+		return nil, err
+	}
+
+	template.CreatedAt = existing.CreatedAt
+	template.LastGeneratedAt = existing.LastGeneratedAt
+	if err := s.repo.Update(template); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	sessions, err := s.repo.FindUpcomingSessions(template.ID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, cancelled := 0, 0
+	conflicts := []domain.TemplateConflict{}
+	for i := range sessions {
+		session := &sessions[i]
+
+		// Día que la plantilla ya no incluye: se cancela la sesión si nadie se ha inscrito
+		if !template.RunsOn(session.StartTime, s.location) {
+			if hasActiveEnrollments(session) {
+				conflicts = append(conflicts, sessionConflict(session, session.StartTime, session.EndTime,
+					"la plantilla ya no incluye este día pero la sesión tiene inscripciones; cancélala manualmente"))
+				continue
+			}
+			if err := s.classService.CancelClass(session.ID); err != nil {
+				return nil, err
+			}
+			cancelled++
+			continue
+		}
+
+		if conflict := s.applyTemplate(template, session); conflict != nil {
+			conflicts = append(conflicts, *conflict)
+		}
+		if err := s.classService.UpdateClass(session); err != nil {
+			return nil, err
+		}
+		updated++
+	}
+
+	result, err := s.generate(template, now, conflicts)
+	if err != nil {
+		return nil, err
+	}
+	result.Updated = updated
+	result.Cancelled = cancelled
+	return result, nil
+}
+
+// DeleteTemplate elimina una plantilla; las sesiones ya generadas se conservan
+func (s *ClassTemplateService) DeleteTemplate(id int) error {
+	return s.repo.Delete(id)
+}
+
+// GenerateSessions genera las sesiones pendientes de una plantilla hasta su horizonte
+func (s *ClassTemplateService) GenerateSessions(id int) (*TemplateResult, error) {
+	template, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.generate(template, time.Now().UTC(), []domain.TemplateConflict{})
+}
+
+// GenerateAll genera las sesiones pendientes de todas las plantillas activas (scheduler).
+// Devuelve el número de sesiones creadas y de conflictos detectados. Las consultas usan ctx.
+func (s *ClassTemplateService) GenerateAll(ctx context.Context) (int, int, error) {
+	s = s.withContext(ctx)
+	templates, err := s.repo.FindActive()
+	if err != nil {
+		return 0, 0, fmt.Errorf("error al buscar plantillas activas: %w", err)
+	}
+
+	created, conflicts := 0, 0
+	now := time.Now().UTC()
+	for i := range templates {
+		result, err := s.generate(&templates[i], now, []domain.TemplateConflict{})
+		if err != nil {
+			return created, conflicts, fmt.Errorf("error al generar sesiones de la plantilla %d: %w", templates[i].ID, err)
+		}
+		created += len(result.Created)
+		conflicts += len(result.Conflicts)
+	}
+
+	return created, conflicts, nil
+}
+
+// withContext devuelve una copia del servicio cuyas consultas (también las de ClassService) usan ctx
+func (s *ClassTemplateService) withContext(ctx context.Context) *ClassTemplateService {
+	scoped := *s
+	scoped.repo = s.repo.WithContext(ctx)
+	scoped.classService = s.classService.withContext(ctx)
+	return &scoped
+}
+
+// generate crea las sesiones de la plantilla entre ahora y su horizonte que aún no existan.
+// Cada sesión pasa por las mismas validaciones que una clase suelta (horario de apertura,
+// disponibilidad de la pista...); las que fallan se registran como conflicto.
+func (s *ClassTemplateService) generate(template *domain.ClassTemplate, now time.Time, conflicts []domain.TemplateConflict) (*TemplateResult, error) {
+	result := &TemplateResult{Template: template, Created: []domain.Class{}, Conflicts: conflicts}
+
+	if template.IsActive {
+		existing, err := s.repo.FindSessionStarts(template.ID, now.AddDate(0, 0, -1))
+		if err != nil {
+			return nil, err
+		}
+		generatedDays := make(map[string]bool, len(existing))
+		for _, start := range existing {
+			generatedDays[start.In(s.location).Format("2006-01-02")] = true
+		}
+
+		for _, session := range template.SessionsBetween(now, template.GenerationHorizon(now), s.location) {
+			if generatedDays[session.StartTime.In(s.location).Format("2006-01-02")] {
+				continue
+			}
+
+			class := template.NewSession(session)
+			if err := s.classService.create(class); err != nil {
+				result.Conflicts = append(result.Conflicts, domain.TemplateConflict{
+					StartTime: session.StartTime,
+					EndTime:   session.EndTime,
+					Reason:    err.Error(),
+				})
+				continue
+			}
+			result.Created = append(result.Created, *class)
+		}
+	}
+
+	for i := range result.Conflicts {
+		result.Conflicts[i].DetectedAt = now
+	}
+	if err := s.repo.SaveGeneration(template.ID, now, result.Conflicts); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.repo.FindByID(template.ID)
+	if err != nil {
+		return nil, err
+	}
+	result.Template = updated
+	return result, nil
+}
+
+// applyTemplate copia los datos de la plantilla a una sesión existente. Si el nuevo
+// horario o pista no están disponibles se conserva el horario anterior y se devuelve el conflicto.
+func (s *ClassTemplateService) applyTemplate(template *domain.ClassTemplate, session *domain.Class) *domain.TemplateConflict {
+	var conflict *domain.TemplateConflict
+
+	slot := template.SessionOn(session.StartTime, s.location)
+	if !slot.StartTime.Equal(session.StartTime) || !slot.EndTime.Equal(session.EndTime) || template.PistaID != session.PistaID {
+		if err := s.checkSlot(template.PistaID, slot, session.ID); err != nil {
+			c := sessionConflict(session, slot.StartTime, slot.EndTime, err.Error())
+			conflict = &c
+		} else {
+			session.PistaID = template.PistaID
+			session.StartTime = slot.StartTime
+			session.EndTime = slot.EndTime
+		}
+	}
+
+	// El aforo no puede quedar por debajo de las plazas ya ocupadas
+	capacity := template.MaxCapacity
+	if session.EnrolledCount > capacity {
+		capacity = session.EnrolledCount
+		if conflict == nil {
+			c := sessionConflict(session, session.StartTime, session.EndTime,
+				fmt.Sprintf("la sesión tiene %d plazas ocupadas; se mantiene ese aforo", session.EnrolledCount))
+			conflict = &c
+		}
+	}
+
+	session.InstructorID = template.InstructorID
+	session.Title = template.Title
+	session.Description = template.Description
+	session.MaxCapacity = capacity
+	session.PriceCents = template.PriceCents
+
+	return conflict
+}
+
+// checkSlot comprueba el horario de apertura y la disponibilidad de la pista para una sesión existente
+func (s *ClassTemplateService) checkSlot(pistaID int, slot domain.TemplateSession, classID int) error {
+	if slot.StartTime.Before(time.Now()) {
+		return errors.New("no se puede mover una sesión a una hora que ya ha pasado")
+	}
+
+	availabilityService := s.classService.availabilityService
+	if err := availabilityService.CheckSchedule(pistaID, slot.StartTime, slot.EndTime); err != nil {
+		return err
+	}
+	return availabilityService.CheckPistaAvailable(pistaID, slot.StartTime, slot.EndTime, nil, &classID)
+}
+
+// hasActiveEnrollments indica si la sesión tiene inscritos o lista de espera vigentes
+func hasActiveEnrollments(session *domain.Class) bool {
+	for _, enrollment := range session.Enrollments {
+		if enrollment.Status != domain.EnrollmentStatusCancelled && enrollment.Status != domain.EnrollmentStatusExpired {
+			return true
+		}
+	}
+	return false
+}
+
+func sessionConflict(session *domain.Class, start, end time.Time, reason string) domain.TemplateConflict {
+	classID := session.ID
+	return domain.TemplateConflict{
+		ClassID:   &classID,
+		StartTime: start,
+		EndTime:   end,
+		Reason:    reason,
+	}
+}
@@ -1,8 +1,10 @@
 package application
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"backend-go/features/classes/domain"
 
@@ -20,11 +22,27 @@ type UserProvider interface {
 	GetUserBySlug(slug string) (UserInfo, error)
 }
 
+// RefundProcessor define la interfaz para reembolsar el pago de una inscripción cancelada (módulo payments)
+type RefundProcessor interface {
+	// RefundEnrollmentCancellation aplica la política de cancelación y devuelve el importe reembolsado
+	RefundEnrollmentCancellation(enrollmentID int, classStartTime time.Time) (int, error)
+}
+
+// PassRedeemer define la interfaz para pagar inscripciones con bonos de clases (módulo passes)
+type PassRedeemer interface {
+	// RedeemEnrollment consume un crédito de un bono del usuario. Devuelve false si no tiene
+	// ningún bono aplicable (la inscripción se paga entonces por el flujo normal).
+	RedeemEnrollment(enrollmentID int, userID uuid.UUID, classStartTime time.Time) (bool, error)
+	// RestoreEnrollment devuelve el crédito al bono. Devuelve false si no se pagó con bono.
+	RestoreEnrollment(enrollmentID int) (bool, error)
+}
+
 // ClassInfo representa la información necesaria de una clase
 type ClassInfo struct {
 	ID          int
 	Status      string
 	MaxCapacity int
+	StartTime   time.Time
 }
 
 // UserInfo representa la información necesaria de un usuario
@@ -34,92 +52,309 @@ type UserInfo struct {
 }
 
 type EnrollmentService struct {
-	repo          domain.EnrollmentRepository
-	classProvider ClassProvider
-	userProvider  UserProvider
+	repo            domain.EnrollmentRepository
+	classProvider   ClassProvider
+	userProvider    UserProvider
+	refundProcessor RefundProcessor
+	passRedeemer    PassRedeemer
 }
 
 func NewEnrollmentService(
 	repo domain.EnrollmentRepository,
 	classProvider ClassProvider,
 	userProvider UserProvider,
+	refundProcessor RefundProcessor,
 ) *EnrollmentService {
 	return &EnrollmentService{
-		repo:          repo,
-		classProvider: classProvider,
-		userProvider:  userProvider,
+		repo:            repo,
+		classProvider:   classProvider,
+		userProvider:    userProvider,
+		refundProcessor: refundProcessor,
 	}
 }
 
+// SetPassRedeemer habilita el pago automático de inscripciones con bonos. Se inyecta después
+// de construir los servicios porque el módulo passes depende de payments.
+func (s *EnrollmentService) SetPassRedeemer(redeemer PassRedeemer) {
+	s.passRedeemer = redeemer
+}
+
 // GetEnrollmentsByClass obtiene todas las inscripciones de una clase
 func (s *EnrollmentService) GetEnrollmentsByClass(classID int) ([]domain.Enrollment, error) {
-	return s.repo.FindByClass(classID)
+	enrollments, err := s.repo.FindByClass(classID)
+	if err != nil {
+		return nil, err
+	}
+	return enrollments, s.fillWaitlistPositions(enrollments)
 }
 
-// GetEnrollmentsByUser obtiene todas las inscripciones de un usuario
+// GetEnrollmentsByUser obtiene todas las inscripciones de un usuario (con su posición en lista de espera)
 func (s *EnrollmentService) GetEnrollmentsByUser(userID uuid.UUID) ([]domain.Enrollment, error) {
-	return s.repo.FindByUser(userID)
+	enrollments, err := s.repo.FindByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	return enrollments, s.fillWaitlistPositions(enrollments)
 }
 
-// EnrollUserBySlug inscribe a un usuario en una clase usando slugs
-func (s *EnrollmentService) EnrollUserBySlug(classSlug string, userSlug string) error {
+// EnrollUserBySlug inscribe a un usuario en una clase usando slugs. Solo el propio usuario
+// o el personal (isStaff) pueden inscribirlo: la inscripción puede consumir sus bonos.
+func (s *EnrollmentService) EnrollUserBySlug(classSlug string, userSlug string, requesterID uuid.UUID, isStaff bool) (*domain.Enrollment, error) {
 	// Obtener clase
 	classInfo, err := s.classProvider.GetClassBySlug(classSlug)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Obtener usuario
 	userInfo, err := s.userProvider.GetUserBySlug(userSlug)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if !isStaff && userInfo.ID != requesterID {
+		return nil, domain.ErrEnrollOtherUser
 	}
 
 	return s.EnrollUser(classInfo.ID, userInfo.ID)
 }
 
-// EnrollUser inscribe a un usuario en una clase
-func (s *EnrollmentService) EnrollUser(classID int, userID uuid.UUID) error {
+// EnrollUser inscribe a un usuario en una clase. Si la clase está completa,
+// el usuario queda en lista de espera (estado WAITLIST) con su posición.
+// Con plaza confirmada, si el usuario tiene un bono de clases vigente se paga con él.
+func (s *EnrollmentService) EnrollUser(classID int, userID uuid.UUID) (*domain.Enrollment, error) {
 	// VALIDACIÓN 1: Verificar que la clase existe y obtener info
 	classInfo, err := s.classProvider.GetClassByID(classID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// VALIDACIÓN 2: Verificar que la clase esté abierta
 	if classInfo.Status != "OPEN" {
-		return fmt.Errorf("la clase no está abierta para inscripciones (estado: %s)", classInfo.Status)
+		return nil, fmt.Errorf("la clase no está abierta para inscripciones (estado: %s)", classInfo.Status)
 	}
 
-	// VALIDACIÓN 3: Verificar que el usuario no esté ya inscrito
+	// VALIDACIÓN 3: Verificar que el usuario no esté ya inscrito (o en lista de espera)
 	exists, err := s.repo.CheckExists(classID, userID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if exists {
-		return errors.New("el usuario ya está inscrito en esta clase")
+		return nil, errors.New("el usuario ya está inscrito en esta clase")
+	}
+
+	// Crear inscripción: confirmada si hay plaza, en lista de espera si la clase está completa
+	enrollment := &domain.Enrollment{
+		ClassID: classID,
+		UserID:  userID,
+	}
+	if err := s.repo.Enroll(enrollment, classInfo.MaxCapacity); err != nil {
+		return nil, err
+	}
+
+	if enrollment.Status == domain.EnrollmentStatusWaitlist {
+		if enrollment.WaitlistPosition, err = s.repo.WaitlistPosition(enrollment); err != nil {
+			return nil, err
+		}
 	}
 
-	// VALIDACIÓN 4: Verificar capacidad disponible
-	count, err := s.repo.Count(classID)
+	if enrollment.Status == domain.EnrollmentStatusConfirmed {
+		if enrollment.PaidWithPass, err = s.redeemPass(enrollment, classInfo.StartTime); err != nil {
+			// Sin pagar no se conserva la plaza
+			return nil, errors.Join(err, s.repo.Delete(enrollment.ID))
+		}
+	}
+
+	return enrollment, nil
+}
+
+// redeemPass paga la inscripción con un bono del usuario, si tiene alguno aplicable
+func (s *EnrollmentService) redeemPass(enrollment *domain.Enrollment, classStartTime time.Time) (bool, error) {
+	if s.passRedeemer == nil {
+		return false, nil
+	}
+	redeemed, err := s.passRedeemer.RedeemEnrollment(enrollment.ID, enrollment.UserID, classStartTime)
+	if err != nil {
+		return false, fmt.Errorf("error al pagar la inscripción con bono: %w", err)
+	}
+	return redeemed, nil
+}
+
+// UnenrollUser da de baja a un usuario de una clase: reembolsa según la política de
+// cancelación (o devuelve el crédito al bono con el que se pagó) y marca la inscripción como CANCELLED (se conserva para el histórico de pagos).
+// Si la inscripción ocupaba plaza, se promociona al primero de la lista de espera.
+func (s *EnrollmentService) UnenrollUser(enrollmentID int) error {
+	enrollment, err := s.repo.FindByID(enrollmentID)
 	if err != nil {
 		return err
 	}
-	if count >= classInfo.MaxCapacity {
-		return fmt.Errorf("la clase está completa (capacidad: %d/%d)", count, classInfo.MaxCapacity)
+	if enrollment.Status == domain.EnrollmentStatusCancelled || enrollment.Status == domain.EnrollmentStatusExpired {
+		return errors.New("la inscripción ya está cancelada")
 	}
 
-	// Crear inscripción
-	enrollment := &domain.Enrollment{
-		ClassID: classID,
-		UserID:  userID,
-		Status:  domain.EnrollmentStatusConfirmed,
+	classInfo, err := s.classProvider.GetClassByID(enrollment.ClassID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.refundProcessor.RefundEnrollmentCancellation(enrollmentID, classInfo.StartTime); err != nil {
+		return fmt.Errorf("error al reembolsar la inscripción: %w", err)
+	}
+	if s.passRedeemer != nil {
+		if _, err := s.passRedeemer.RestoreEnrollment(enrollmentID); err != nil {
+			return fmt.Errorf("error al devolver el crédito del bono: %w", err)
+		}
+	}
+
+	if err := s.repo.UpdateStatus(enrollmentID, domain.EnrollmentStatusCancelled); err != nil {
+		return err
+	}
+
+	if enrollment.HoldsSpot() {
+		if _, err := s.PromoteWaitlist(enrollment.ClassID); err != nil {
+			return fmt.Errorf("baja realizada, pero falló la promoción de la lista de espera: %w", err)
+		}
 	}
+	return nil
+}
 
-	return s.repo.Create(enrollment)
+// CancelUnpaidEnrollment da de baja una inscripción cuyo pago rechazó el proveedor (webhook),
+// liberando la plaza para la lista de espera. Es idempotente: una inscripción ya cancelada o
+// caducada no se modifica.
+func (s *EnrollmentService) CancelUnpaidEnrollment(enrollmentID int) error {
+	enrollment, err := s.repo.FindByID(enrollmentID)
+	if err != nil {
+		return err
+	}
+	if enrollment.Status == domain.EnrollmentStatusCancelled || enrollment.Status == domain.EnrollmentStatusExpired {
+		return nil
+	}
+	return s.UnenrollUser(enrollmentID)
 }
 
-// UnenrollUser da de baja a un usuario de una clase
-func (s *EnrollmentService) UnenrollUser(enrollmentID int) error {
-	return s.repo.Delete(enrollmentID)
+// ConfirmOffer confirma la plaza ofertada a un usuario promocionado desde la lista de espera
+func (s *EnrollmentService) ConfirmOffer(enrollmentID int, userID uuid.UUID, isStaff bool) (*domain.Enrollment, error) {
+	enrollment, err := s.repo.FindByID(enrollmentID)
+	if err != nil {
+		return nil, err
+	}
+	if !isStaff && enrollment.UserID != userID {
+		return nil, errors.New("no puedes confirmar la inscripción de otro usuario")
+	}
+	if enrollment.Status != domain.EnrollmentStatusOffered {
+		return nil, domain.ErrEnrollmentNotOffered
+	}
+
+	if enrollment.OfferExpiresAt != nil && time.Now().After(*enrollment.OfferExpiresAt) {
+		// La oferta caducó: liberar la plaza para el siguiente de la lista (si la tarea programada
+		// no lo hizo ya)
+		if err := s.repo.ResolveOffer(enrollmentID, domain.EnrollmentStatusExpired); err != nil {
+			if errors.Is(err, domain.ErrEnrollmentNotOffered) {
+				return nil, domain.ErrOfferExpired
+			}
+			return nil, err
+		}
+		if _, err := s.PromoteWaitlist(enrollment.ClassID); err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrOfferExpired
+	}
+
+	classInfo, err := s.classProvider.GetClassByID(enrollment.ClassID)
+	if err != nil {
+		return nil, err
+	}
+	if enrollment.PaidWithPass, err = s.redeemPass(enrollment, classInfo.StartTime); err != nil {
+		return nil, err
+	}
+
+	// Condicional: si la oferta caducó entretanto (tarea programada), no se confirma
+	if err := s.repo.ResolveOffer(enrollmentID, domain.EnrollmentStatusConfirmed); err != nil {
+		if enrollment.PaidWithPass {
+			_, restoreErr := s.passRedeemer.RestoreEnrollment(enrollmentID)
+			return nil, errors.Join(err, restoreErr)
+		}
+		return nil, err
+	}
+	enrollment.Status = domain.EnrollmentStatusConfirmed
+	enrollment.OfferExpiresAt = nil
+	return enrollment, nil
+}
+
+// PromoteWaitlist oferta las plazas libres de una clase a los primeros de su lista de espera.
+// Se invoca tras una baja, al ampliar el aforo o al caducar una oferta. Devuelve cuántos se promocionaron.
+func (s *EnrollmentService) PromoteWaitlist(classID int) (int, error) {
+	classInfo, err := s.classProvider.GetClassByID(classID)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	if classInfo.Status != "OPEN" || !classInfo.StartTime.After(now) {
+		return 0, nil
+	}
+
+	// La oferta caduca tras la ventana de confirmación, y nunca después del inicio de la clase
+	expiresAt := now.Add(domain.WaitlistOfferWindow)
+	if classInfo.StartTime.Before(expiresAt) {
+		expiresAt = classInfo.StartTime
+	}
+
+	promoted, err := s.repo.PromoteWaitlist(classID, classInfo.MaxCapacity, expiresAt)
+	if err != nil {
+		return 0, err
+	}
+	return len(promoted), nil
+}
+
+// ExpireWaitlistOffers caduca las plazas ofertadas no confirmadas a tiempo y promociona
+// al siguiente de cada lista de espera (tarea programada). Las consultas usan ctx.
+func (s *EnrollmentService) ExpireWaitlistOffers(ctx context.Context) (int, error) {
+	s = s.withContext(ctx)
+	expired, err := s.repo.FindExpiredOffers(time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	classIDs := map[int]bool{}
+	for _, enrollment := range expired {
+		if err := s.repo.ResolveOffer(enrollment.ID, domain.EnrollmentStatusExpired); err != nil {
+			if errors.Is(err, domain.ErrEnrollmentNotOffered) {
+				continue // El usuario la confirmó (o ya caducó) mientras tanto: la plaza no queda libre
+			}
+			return count, fmt.Errorf("error al caducar la inscripción %d: %w", enrollment.ID, err)
+		}
+		count++
+		classIDs[enrollment.ClassID] = true
+	}
+
+	for classID := range classIDs {
+		if _, err := s.PromoteWaitlist(classID); err != nil {
+			return count, fmt.Errorf("error al promocionar la lista de espera de la clase %d: %w", classID, err)
+		}
+	}
+
+	return count, nil
+}
+
+// withContext devuelve una copia del servicio cuyas consultas usan ctx
+func (s *EnrollmentService) withContext(ctx context.Context) *EnrollmentService {
+	scoped := *s
+	scoped.repo = s.repo.WithContext(ctx)
+	return &scoped
+}
+
+// fillWaitlistPositions calcula la posición de las inscripciones en lista de espera
+func (s *EnrollmentService) fillWaitlistPositions(enrollments []domain.Enrollment) error {
+	for i := range enrollments {
+		if enrollments[i].Status != domain.EnrollmentStatusWaitlist {
+			continue
+		}
+		position, err := s.repo.WaitlistPosition(&enrollments[i])
+		if err != nil {
+			return err
+		}
+		enrollments[i].WaitlistPosition = position
+	}
+	return nil
 }
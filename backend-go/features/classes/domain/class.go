@@ -2,6 +2,7 @@ package domain
 
 import (
 	"backend-go/shared/pagination"
+	"context"
 	"time"
 
 	"github.com/google/uuid"
@@ -20,6 +21,7 @@ type Class struct {
 	MaxCapacity  int
 	PriceCents   int
 	Status       string
+	TemplateID   *int // Plantilla que generó la sesión (nil = clase suelta)
 	CreatedAt    time.Time
 	UpdatedAt    time.Time
 
@@ -55,6 +57,9 @@ const (
 
 // ClassRepository define el contrato de persistencia para clases
 type ClassRepository interface {
+	// WithContext devuelve el repositorio con sus consultas ligadas a ctx (plazo y cancelación)
+	WithContext(ctx context.Context) ClassRepository
+
 	FindAll() ([]Class, error)
 	FindByID(id int) (*Class, error)
 	FindBySlug(slug string) (*Class, error)
@@ -62,6 +67,7 @@ type ClassRepository interface {
 	FindByPistaAndTimeRange(pistaID int, startTime, endTime time.Time) ([]Class, error)
 	FindAllPaginated(params pagination.PaginationParams) ([]Class, *pagination.PaginationMeta, error)
 	Create(class *Class) error
+	CreateIfAvailable(class *Class) error // Comprueba reservas y clases e inserta de forma atómica
 	Update(class *Class) error
 	Delete(id int) error
 	DeleteBySlug(slug string) error
@@ -0,0 +1,190 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Errores de plantillas de clase
+var (
+	ErrTemplateNotFound        = errors.New("plantilla de clase no encontrada")
+	ErrInvalidTemplateWeekdays = errors.New("la plantilla debe indicar al menos un día de la semana válido (0 = domingo ... 6 = sábado)")
+	ErrInvalidTemplateStart    = errors.New("hora de inicio inválida (formato HH:MM)")
+	ErrInvalidTemplateDuration = errors.New("la duración de una clase debe estar entre 30 minutos y 3 horas")
+	ErrInvalidTemplateCapacity = errors.New("la capacidad debe estar entre 1 y 20 alumnos")
+	ErrInvalidTemplatePrice    = errors.New("el precio no puede ser negativo")
+	ErrInvalidTemplateValidity = errors.New("la fecha de fin de la plantilla debe ser posterior a la de inicio")
+	ErrInvalidWeeksAhead       = errors.New("la antelación de generación debe estar entre 1 y 12 semanas")
+)
+
+// Antelación con la que se generan las sesiones de una plantilla
+const (
+	DefaultTemplateWeeksAhead = 4
+	MaxTemplateWeeksAhead     = 12
+)
+
+// ClassTemplate representa una clase recurrente a partir de la cual se generan sesiones (Class)
+type ClassTemplate struct {
+	ID              int
+	Title           string
+	Description     *string
+	PistaID         int
+	InstructorID    uuid.UUID
+	MaxCapacity     int
+	PriceCents      int
+	Weekdays        []int // 0 = domingo ... 6 = sábado
+	StartMinute     int   // Hora de inicio (minutos desde medianoche, hora local del club)
+	DurationMinutes int
+	ValidFrom       time.Time
+	ValidUntil      *time.Time // nil = sin fecha de fin
+	WeeksAhead      int        // Semanas de antelación con las que se generan sesiones
+	IsActive        bool
+	LastGeneratedAt *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+
+	// Relaciones expandidas (solo para lectura)
+	PistaName      string
+	InstructorName string
+	Conflicts      []TemplateConflict // Conflictos de la última generación
+}
+
+// TemplateSession representa una sesión calculada de la plantilla
+type TemplateSession struct {
+	StartTime time.Time
+	EndTime   time.Time
+}
+
+// TemplateConflict describe una sesión que no se pudo generar o actualizar y el motivo
+type TemplateConflict struct {
+	ClassID    *int // Solo en sesiones ya existentes
+	StartTime  time.Time
+	EndTime    time.Time
+	Reason     string
+	DetectedAt time.Time
+}
+
+// Validate verifica que la plantilla sea coherente (mismas reglas que una clase suelta)
+func (t *ClassTemplate) Validate() error {
+	if len(t.Weekdays) == 0 {
+		return ErrInvalidTemplateWeekdays
+	}
+	for _, weekday := range t.Weekdays {
+		if weekday < 0 || weekday > 6 {
+			return ErrInvalidTemplateWeekdays
+		}
+	}
+	if t.StartMinute < 0 || t.StartMinute >= 24*60 {
+		return ErrInvalidTemplateStart
+	}
+	if t.DurationMinutes < 30 || t.DurationMinutes > 180 {
+		return ErrInvalidTemplateDuration
+	}
+	if t.MaxCapacity < 1 || t.MaxCapacity > 20 {
+		return ErrInvalidTemplateCapacity
+	}
+	if t.PriceCents < 0 {
+		return ErrInvalidTemplatePrice
+	}
+	if t.ValidUntil != nil && t.ValidUntil.Before(t.ValidFrom) {
+		return ErrInvalidTemplateValidity
+	}
+	if t.WeeksAhead < 1 || t.WeeksAhead > MaxTemplateWeeksAhead {
+		return ErrInvalidWeeksAhead
+	}
+	return nil
+}
+
+// RunsOn indica si la plantilla tiene sesión el día indicado (día de la semana y vigencia).
+// Los días se cuentan en la zona horaria del club (location).
+func (t *ClassTemplate) RunsOn(day time.Time, location *time.Location) bool {
+	day = startOfDay(day, location)
+	if day.Before(startOfDay(t.ValidFrom, location)) {
+		return false
+	}
+	if t.ValidUntil != nil && day.After(startOfDay(*t.ValidUntil, location)) {
+		return false
+	}
+	for _, weekday := range t.Weekdays {
+		if int(day.Weekday()) == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// SessionOn devuelve el horario de la sesión de la plantilla en el día indicado. La hora de
+// inicio es hora local del club, así que se mantiene en los cambios de horario de verano.
+func (t *ClassTemplate) SessionOn(day time.Time, location *time.Location) TemplateSession {
+	day = startOfDay(day, location)
+	start := time.Date(day.Year(), day.Month(), day.Day(), 0, t.StartMinute, 0, 0, location)
+	return TemplateSession{
+		StartTime: start.UTC(),
+		EndTime:   start.Add(time.Duration(t.DurationMinutes) * time.Minute).UTC(),
+	}
+}
+
+// SessionsBetween calcula las sesiones de la plantilla que empiezan en [from, to)
+func (t *ClassTemplate) SessionsBetween(from, to time.Time, location *time.Location) []TemplateSession {
+	sessions := []TemplateSession{}
+	for day := startOfDay(from, location); day.Before(to); day = day.AddDate(0, 0, 1) {
+		if !t.RunsOn(day, location) {
+			continue
+		}
+		session := t.SessionOn(day, location)
+		if session.StartTime.Before(from) || !session.StartTime.Before(to) {
+			continue
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions
+}
+
+// GenerationHorizon devuelve hasta cuándo deben existir sesiones generadas
+func (t *ClassTemplate) GenerationHorizon(now time.Time) time.Time {
+	return now.AddDate(0, 0, 7*t.WeeksAhead)
+}
+
+// NewSession construye la clase (sesión) de la plantilla para el horario indicado
+func (t *ClassTemplate) NewSession(session TemplateSession) *Class {
+	templateID := t.ID
+	return &Class{
+		PistaID:      t.PistaID,
+		InstructorID: t.InstructorID,
+		Title:        t.Title,
+		Description:  t.Description,
+		StartTime:    session.StartTime,
+		EndTime:      session.EndTime,
+		MaxCapacity:  t.MaxCapacity,
+		PriceCents:   t.PriceCents,
+		Status:       ClassStatusOpen,
+		TemplateID:   &templateID,
+	}
+}
+
+// startOfDay devuelve la medianoche del día de la fecha dada en la zona horaria indicada
+func startOfDay(date time.Time, location *time.Location) time.Time {
+	date = date.In(location)
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, location)
+}
+
+// ClassTemplateRepository define las operaciones de persistencia de plantillas de clase
+type ClassTemplateRepository interface {
+	// WithContext devuelve el repositorio con sus consultas ligadas a ctx (plazo y cancelación)
+	WithContext(ctx context.Context) ClassTemplateRepository
+
+	FindAll() ([]ClassTemplate, error)
+	FindActive() ([]ClassTemplate, error)
+	FindByID(id int) (*ClassTemplate, error)
+	Create(template *ClassTemplate) error
+	Update(template *ClassTemplate) error
+	Delete(id int) error // Las sesiones ya generadas se conservan como clases sueltas
+
+	// Sesiones generadas
+	FindSessionStarts(templateID int, from time.Time) ([]time.Time, error) // Incluye canceladas y eliminadas
+	FindUpcomingSessions(templateID int, after time.Time) ([]Class, error) // Solo sesiones abiertas
+	SaveGeneration(templateID int, generatedAt time.Time, conflicts []TemplateConflict) error
+}
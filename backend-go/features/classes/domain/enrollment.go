@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"context"
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,6 +16,11 @@ type Enrollment struct {
 	Status       string
 	RegisteredAt time.Time
 
+	// Lista de espera
+	OfferExpiresAt   *time.Time // Solo OFFERED: límite para confirmar la plaza
+	WaitlistPosition int        // Solo WAITLIST: posición (1 = siguiente en ser promocionado)
+	PaidWithPass     bool       // Solo al inscribirse o confirmar: la plaza se pagó con un bono
+
 	// Relaciones expandidas
 	UserName   string
 	UserEmail  string
@@ -28,15 +35,48 @@ const (
 	EnrollmentStatusConfirmed = "CONFIRMED"
 	EnrollmentStatusCancelled = "CANCELLED"
 	EnrollmentStatusWaitlist  = "WAITLIST"
+	EnrollmentStatusOffered   = "OFFERED" // Promocionado desde la lista de espera, pendiente de confirmar
+	EnrollmentStatusExpired   = "EXPIRED" // La oferta de plaza caducó sin confirmar
 )
 
+// WaitlistOfferWindow es el tiempo que tiene un usuario promocionado para confirmar su plaza
+// (nunca más allá del inicio de la clase)
+const WaitlistOfferWindow = 12 * time.Hour
+
+// Errores de lista de espera
+var (
+	ErrEnrollmentNotOffered = errors.New("la inscripción no tiene una plaza ofertada pendiente de confirmar")
+	ErrOfferExpired         = errors.New("la oferta de plaza ha caducado")
+)
+
+// ErrEnrollOtherUser se devuelve cuando un usuario sin rol de personal intenta inscribir a otro
+var ErrEnrollOtherUser = errors.New("solo el personal puede inscribir a otros usuarios")
+
+// HoldsSpot indica si la inscripción ocupa plaza en la clase
+func (e *Enrollment) HoldsSpot() bool {
+	return e.Status == EnrollmentStatusConfirmed || e.Status == EnrollmentStatusOffered
+}
+
 // EnrollmentRepository define el contrato de persistencia para inscripciones
 type EnrollmentRepository interface {
+	// WithContext devuelve el repositorio con sus consultas ligadas a ctx (plazo y cancelación)
+	WithContext(ctx context.Context) EnrollmentRepository
+
 	FindByID(id int) (*Enrollment, error)
 	FindByClass(classID int) ([]Enrollment, error)
 	FindByUser(userID uuid.UUID) ([]Enrollment, error)
 	Create(enrollment *Enrollment) error
 	Delete(id int) error
+	UpdateStatus(id int, status string) error
+	// ResolveOffer pasa una plaza ofertada a CONFIRMED o EXPIRED solo si sigue OFFERED; devuelve
+	// ErrEnrollmentNotOffered si otra petición (confirmación o caducidad) ya la resolvió
+	ResolveOffer(id int, status string) error
 	CheckExists(classID int, userID uuid.UUID) (bool, error)
-	Count(classID int) (int, error)
+	Count(classID int) (int, error) // Plazas ocupadas (CONFIRMED + OFFERED)
+
+	// Lista de espera. Enroll y PromoteWaitlist bloquean la clase para serializar la asignación de plazas.
+	Enroll(enrollment *Enrollment, capacity int) error // CONFIRMED si hay plaza y nadie esperando, si no WAITLIST
+	PromoteWaitlist(classID int, capacity int, offerExpiresAt time.Time) ([]Enrollment, error)
+	FindExpiredOffers(now time.Time) ([]Enrollment, error)
+	WaitlistPosition(enrollment *Enrollment) (int, error)
 }
@@ -2,8 +2,10 @@ package infrastructure
 
 import (
 	"backend-go/features/classes/domain"
+	"backend-go/shared/availability"
 	"backend-go/shared/database"
 	"backend-go/shared/pagination"
+	"context"
 	"errors"
 	"fmt"
 	"regexp"
@@ -22,6 +24,11 @@ func NewClassRepository(db *gorm.DB) domain.ClassRepository {
 	return &ClassRepositoryImpl{db: db}
 }
 
+// WithContext devuelve una copia del repositorio cuyas consultas usan ctx
+func (r *ClassRepositoryImpl) WithContext(ctx context.Context) domain.ClassRepository {
+	return &ClassRepositoryImpl{db: r.db.WithContext(ctx)}
+}
+
 // FindAll obtiene todas las clases con relaciones
 func (r *ClassRepositoryImpl) FindAll() ([]domain.Class, error) {
 	var models []database.Class
@@ -144,6 +151,38 @@ func (r *ClassRepositoryImpl) Create(class *domain.Class) error {
 	return nil
 }
 
+// CreateIfAvailable crea la clase solo si la pista está libre de reservas y clases,
+// en una única transacción serializable (misma garantía que las reservas)
+func (r *ClassRepositoryImpl) CreateIfAvailable(class *domain.Class) error {
+	model := FromEntity(class)
+
+	err := availability.NewAvailabilityService(r.db).RunSerializable(func(tx *gorm.DB) error {
+		if err := availability.NewAvailabilityService(tx).CheckPistaAvailable(
+			class.PistaID, class.StartTime, class.EndTime, nil, nil,
+		); err != nil {
+			return err
+		}
+		return tx.Create(model).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	// Recargar el modelo con las relaciones para obtener los nombres
+	if err := r.db.Preload("Pista").Preload("Instructor").First(model, model.ID).Error; err != nil {
+		return err
+	}
+
+	class.ID = int(model.ID)
+	class.Slug = model.Slug
+	class.CreatedAt = model.CreatedAt
+	class.UpdatedAt = model.UpdatedAt
+	class.PistaName = model.Pista.Name
+	class.InstructorName = model.Instructor.FullName
+
+	return nil
+}
+
 // Update actualiza una clase
 func (r *ClassRepositoryImpl) Update(class *domain.Class) error {
 	model := FromEntity(class)
@@ -210,6 +249,7 @@ func (r *ClassRepositoryImpl) CheckEnrollmentExists(classID int, userID uuid.UUI
 	var count int64
 	if err := r.db.Model(&database.ClassEnrollment{}).
 		Where("class_id = ? AND user_id = ?", classID, userID).
+		Where("status NOT IN ?", []string{domain.EnrollmentStatusCancelled, domain.EnrollmentStatusExpired}).
 		Count(&count).Error; err != nil {
 		return false, err
 	}
@@ -217,11 +257,12 @@ func (r *ClassRepositoryImpl) CheckEnrollmentExists(classID int, userID uuid.UUI
 	return count > 0, nil
 }
 
-// CountEnrollments cuenta las inscripciones de una clase
+// CountEnrollments cuenta las plazas ocupadas de una clase (confirmadas y ofertadas)
 func (r *ClassRepositoryImpl) CountEnrollments(classID int) (int, error) {
 	var count int64
 	if err := r.db.Model(&database.ClassEnrollment{}).
 		Where("class_id = ?", classID).
+		Where("status IN ?", []string{domain.EnrollmentStatusConfirmed, domain.EnrollmentStatusOffered}).
 		Count(&count).Error; err != nil {
 		return 0, err
 	}
@@ -258,6 +299,10 @@ func ToEntity(model *database.Class) *domain.Class {
 		CreatedAt:    model.CreatedAt,
 		UpdatedAt:    model.UpdatedAt,
 	}
+	if model.TemplateID != nil {
+		templateID := int(*model.TemplateID)
+		class.TemplateID = &templateID
+	}
 
 	// Relaciones expandidas
 	if model.Pista.ID != 0 {
@@ -274,7 +319,12 @@ func ToEntity(model *database.Class) *domain.Class {
 			class.Enrollments[i] = *ToEnrollmentEntity(&enrollment)
 		}
 	}
-	class.EnrolledCount = len(model.Enrollments)
+	// Solo cuentan las plazas ocupadas (no lista de espera ni bajas)
+	for _, enrollment := range model.Enrollments {
+		if enrollment.Status == domain.EnrollmentStatusConfirmed || enrollment.Status == domain.EnrollmentStatusOffered {
+			class.EnrolledCount++
+		}
+	}
 
 	return class
 }
@@ -295,6 +345,10 @@ func FromEntity(class *domain.Class) *database.Class {
 		CreatedAt:    class.CreatedAt,
 		UpdatedAt:    class.UpdatedAt,
 	}
+	if class.TemplateID != nil {
+		templateID := uint(*class.TemplateID)
+		model.TemplateID = &templateID
+	}
 
 	// Generar slug si no existe
 	if class.Slug != "" {
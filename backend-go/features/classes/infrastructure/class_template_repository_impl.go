@@ -0,0 +1,270 @@
+package infrastructure
+
+import (
+	"backend-go/features/classes/domain"
+	"backend-go/shared/database"
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ClassTemplateRepositoryImpl implementa domain.ClassTemplateRepository usando GORM
+type ClassTemplateRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewClassTemplateRepository crea una nueva instancia del repositorio
+func NewClassTemplateRepository(db *gorm.DB) domain.ClassTemplateRepository {
+	return &ClassTemplateRepositoryImpl{db: db}
+}
+
+// WithContext devuelve una copia del repositorio cuyas consultas usan ctx
+func (r *ClassTemplateRepositoryImpl) WithContext(ctx context.Context) domain.ClassTemplateRepository {
+	return &ClassTemplateRepositoryImpl{db: r.db.WithContext(ctx)}
+}
+
+// FindAll obtiene todas las plantillas de clase
+func (r *ClassTemplateRepositoryImpl) FindAll() ([]domain.ClassTemplate, error) {
+	var models []database.ClassTemplate
+	if err := r.db.
+		Preload("Pista").
+		Preload("Instructor").
+		Order("id ASC").
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return toTemplateEntities(models), nil
+}
+
+// FindActive obtiene las plantillas activas
+func (r *ClassTemplateRepositoryImpl) FindActive() ([]domain.ClassTemplate, error) {
+	var models []database.ClassTemplate
+	if err := r.db.Where("is_active = ?", true).Order("id ASC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return toTemplateEntities(models), nil
+}
+
+// FindByID obtiene una plantilla por ID con los conflictos de la última generación
+func (r *ClassTemplateRepositoryImpl) FindByID(id int) (*domain.ClassTemplate, error) {
+	var model database.ClassTemplate
+	if err := r.db.
+		Preload("Pista").
+		Preload("Instructor").
+		Preload("Conflicts", func(db *gorm.DB) *gorm.DB { return db.Order("start_time ASC") }).
+		First(&model, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrTemplateNotFound
+		}
+		return nil, err
+	}
+	return TemplateToEntity(&model), nil
+}
+
+// Create crea una plantilla de clase
+func (r *ClassTemplateRepositoryImpl) Create(template *domain.ClassTemplate) error {
+	model := TemplateFromEntity(template)
+	if err := r.db.Omit("Pista", "Instructor", "Conflicts").Create(model).Error; err != nil {
+		return err
+	}
+
+	template.ID = int(model.ID)
+	template.CreatedAt = model.CreatedAt
+	template.UpdatedAt = model.UpdatedAt
+	return nil
+}
+
+// Update actualiza una plantilla de clase
+func (r *ClassTemplateRepositoryImpl) Update(template *domain.ClassTemplate) error {
+	model := TemplateFromEntity(template)
+	if err := r.db.Omit("created_at", "Pista", "Instructor", "Conflicts").Save(model).Error; err != nil {
+		return err
+	}
+
+	template.UpdatedAt = model.UpdatedAt
+	return nil
+}
+
+// Delete elimina una plantilla; sus sesiones quedan como clases sueltas
+func (r *ClassTemplateRepositoryImpl) Delete(id int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&database.Class{}).
+			Where("template_id = ?", id).
+			Update("template_id", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("template_id = ?", id).Delete(&database.ClassTemplateConflict{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Delete(&database.ClassTemplate{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrTemplateNotFound
+		}
+		return nil
+	})
+}
+
+// FindSessionStarts obtiene el inicio de todas las sesiones de la plantilla desde una fecha,
+// incluidas las canceladas o eliminadas para no volver a generarlas
+func (r *ClassTemplateRepositoryImpl) FindSessionStarts(templateID int, from time.Time) ([]time.Time, error) {
+	var starts []time.Time
+	if err := r.db.Unscoped().Model(&database.Class{}).
+		Where("template_id = ?", templateID).
+		Where("start_time >= ?", from).
+		Pluck("start_time", &starts).Error; err != nil {
+		return nil, err
+	}
+	return starts, nil
+}
+
+// FindUpcomingSessions obtiene las sesiones abiertas de la plantilla que aún no han empezado
+func (r *ClassTemplateRepositoryImpl) FindUpcomingSessions(templateID int, after time.Time) ([]domain.Class, error) {
+	var models []database.Class
+	if err := r.db.
+		Preload("Enrollments").
+		Where("template_id = ?", templateID).
+		Where("status = ?", domain.ClassStatusOpen).
+		Where("start_time > ?", after).
+		Order("start_time ASC").
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	classes := make([]domain.Class, len(models))
+	for i := range models {
+		classes[i] = *ToEntity(&models[i])
+	}
+	return classes, nil
+}
+
+// SaveGeneration registra la última generación de la plantilla y reemplaza sus conflictos
+func (r *ClassTemplateRepositoryImpl) SaveGeneration(templateID int, generatedAt time.Time, conflicts []domain.TemplateConflict) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&database.ClassTemplate{}).
+			Where("id = ?", templateID).
+			Update("last_generated_at", generatedAt).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("template_id = ?", templateID).Delete(&database.ClassTemplateConflict{}).Error; err != nil {
+			return err
+		}
+		if len(conflicts) == 0 {
+			return nil
+		}
+
+		models := make([]database.ClassTemplateConflict, len(conflicts))
+		for i, conflict := range conflicts {
+			models[i] = database.ClassTemplateConflict{
+				TemplateID: uint(templateID),
+				StartTime:  conflict.StartTime,
+				EndTime:    conflict.EndTime,
+				Reason:     conflict.Reason,
+				DetectedAt: generatedAt,
+			}
+			if conflict.ClassID != nil {
+				classID := uint(*conflict.ClassID)
+				models[i].ClassID = &classID
+			}
+		}
+		return tx.Create(&models).Error
+	})
+}
+
+// TemplateToEntity convierte database.ClassTemplate a domain.ClassTemplate
+func TemplateToEntity(model *database.ClassTemplate) *domain.ClassTemplate {
+	template := &domain.ClassTemplate{
+		ID:              int(model.ID),
+		Title:           model.Title,
+		Description:     model.Description,
+		PistaID:         int(model.PistaID),
+		InstructorID:    model.InstructorID,
+		MaxCapacity:     model.Capacity,
+		PriceCents:      model.PriceCents,
+		Weekdays:        parseWeekdays(model.Weekdays),
+		StartMinute:     model.StartMinute,
+		DurationMinutes: model.DurationMinutes,
+		ValidFrom:       model.ValidFrom,
+		ValidUntil:      model.ValidUntil,
+		WeeksAhead:      model.WeeksAhead,
+		IsActive:        model.IsActive,
+		LastGeneratedAt: model.LastGeneratedAt,
+		CreatedAt:       model.CreatedAt,
+		UpdatedAt:       model.UpdatedAt,
+		PistaName:       model.Pista.Name,
+		InstructorName:  model.Instructor.FullName,
+	}
+
+	template.Conflicts = make([]domain.TemplateConflict, len(model.Conflicts))
+	for i, conflict := range model.Conflicts {
+		template.Conflicts[i] = domain.TemplateConflict{
+			StartTime:  conflict.StartTime,
+			EndTime:    conflict.EndTime,
+			Reason:     conflict.Reason,
+			DetectedAt: conflict.DetectedAt,
+		}
+		if conflict.ClassID != nil {
+			classID := int(*conflict.ClassID)
+			template.Conflicts[i].ClassID = &classID
+		}
+	}
+
+	return template
+}
+
+// TemplateFromEntity convierte domain.ClassTemplate a database.ClassTemplate
+func TemplateFromEntity(template *domain.ClassTemplate) *database.ClassTemplate {
+	return &database.ClassTemplate{
+		ID:              uint(template.ID),
+		Title:           template.Title,
+		Description:     template.Description,
+		PistaID:         uint(template.PistaID),
+		InstructorID:    template.InstructorID,
+		Capacity:        template.MaxCapacity,
+		PriceCents:      template.PriceCents,
+		Weekdays:        formatWeekdays(template.Weekdays),
+		StartMinute:     template.StartMinute,
+		DurationMinutes: template.DurationMinutes,
+		ValidFrom:       template.ValidFrom,
+		ValidUntil:      template.ValidUntil,
+		WeeksAhead:      template.WeeksAhead,
+		IsActive:        template.IsActive,
+		LastGeneratedAt: template.LastGeneratedAt,
+		CreatedAt:       template.CreatedAt,
+	}
+}
+
+func toTemplateEntities(models []database.ClassTemplate) []domain.ClassTemplate {
+	templates := make([]domain.ClassTemplate, len(models))
+	for i := range models {
+		templates[i] = *TemplateToEntity(&models[i])
+	}
+	return templates
+}
+
+// parseWeekdays convierte "1,3" a []int (se ignoran valores no numéricos)
+func parseWeekdays(value string) []int {
+	weekdays := []int{}
+	for _, raw := range strings.Split(value, ",") {
+		if weekday, err := strconv.Atoi(strings.TrimSpace(raw)); err == nil {
+			weekdays = append(weekdays, weekday)
+		}
+	}
+	return weekdays
+}
+
+// formatWeekdays convierte []int a "1,3"
+func formatWeekdays(weekdays []int) string {
+	parts := make([]string, len(weekdays))
+	for i, weekday := range weekdays {
+		parts[i] = strconv.Itoa(weekday)
+	}
+	return strings.Join(parts, ",")
+}
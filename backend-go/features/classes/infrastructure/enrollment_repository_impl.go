@@ -3,10 +3,13 @@ package infrastructure
 import (
 	"backend-go/features/classes/domain"
 	"backend-go/shared/database"
+	"context"
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type EnrollmentRepositoryImpl struct {
@@ -17,6 +20,11 @@ func NewEnrollmentRepository(db *gorm.DB) domain.EnrollmentRepository {
 	return &EnrollmentRepositoryImpl{db: db}
 }
 
+// WithContext devuelve una copia del repositorio cuyas consultas usan ctx
+func (r *EnrollmentRepositoryImpl) WithContext(ctx context.Context) domain.EnrollmentRepository {
+	return &EnrollmentRepositoryImpl{db: r.db.WithContext(ctx)}
+}
+
 // FindByID obtiene una inscripción por ID
 func (r *EnrollmentRepositoryImpl) FindByID(id int) (*domain.Enrollment, error) {
 	var model database.ClassEnrollment
@@ -68,15 +76,145 @@ func (r *EnrollmentRepositoryImpl) FindByUser(userID uuid.UUID) ([]domain.Enroll
 	return enrollments, nil
 }
 
-// Create crea una nueva inscripción
+// Create crea una nueva inscripción. Si el usuario tenía una inscripción cancelada
+// o caducada en la clase, se reactiva (class_id + user_id es único)
 func (r *EnrollmentRepositoryImpl) Create(enrollment *domain.Enrollment) error {
 	model := EnrollmentFromEntity(enrollment)
+	if err := r.save(r.db, model); err != nil {
+		return err
+	}
+	return r.reload(enrollment, model)
+}
+
+// Enroll inscribe con la clase bloqueada: CONFIRMED si hay plaza libre y nadie en lista de
+// espera, WAITLIST en caso contrario. Así dos inscripciones simultáneas no superan el aforo.
+func (r *EnrollmentRepositoryImpl) Enroll(enrollment *domain.Enrollment, capacity int) error {
+	model := EnrollmentFromEntity(enrollment)
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockClass(tx, enrollment.ClassID); err != nil {
+			return err
+		}
 
-	if err := r.db.Create(model).Error; err != nil {
+		occupied, err := countByStatus(tx, enrollment.ClassID, domain.EnrollmentStatusConfirmed, domain.EnrollmentStatusOffered)
+		if err != nil {
+			return err
+		}
+		waiting, err := countByStatus(tx, enrollment.ClassID, domain.EnrollmentStatusWaitlist)
+		if err != nil {
+			return err
+		}
+
+		model.Status = domain.EnrollmentStatusConfirmed
+		if occupied >= capacity || waiting > 0 {
+			model.Status = domain.EnrollmentStatusWaitlist
+		}
+		return r.save(tx, model)
+	})
+	if err != nil {
 		return err
 	}
 
-	// Recargar para obtener las relaciones
+	enrollment.Status = model.Status
+	return r.reload(enrollment, model)
+}
+
+// PromoteWaitlist oferta las plazas libres a los primeros de la lista de espera (por orden de registro)
+func (r *EnrollmentRepositoryImpl) PromoteWaitlist(classID int, capacity int, offerExpiresAt time.Time) ([]domain.Enrollment, error) {
+	var promoted []database.ClassEnrollment
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockClass(tx, classID); err != nil {
+			return err
+		}
+
+		occupied, err := countByStatus(tx, classID, domain.EnrollmentStatusConfirmed, domain.EnrollmentStatusOffered)
+		if err != nil {
+			return err
+		}
+		free := capacity - occupied
+		if free <= 0 {
+			return nil
+		}
+
+		if err := tx.Where("class_id = ? AND status = ?", classID, domain.EnrollmentStatusWaitlist).
+			Order("registered_at ASC, id ASC").
+			Limit(free).
+			Find(&promoted).Error; err != nil {
+			return err
+		}
+
+		for i := range promoted {
+			promoted[i].Status = domain.EnrollmentStatusOffered
+			promoted[i].OfferExpiresAt = &offerExpiresAt
+			if err := tx.Model(&database.ClassEnrollment{}).Where("id = ?", promoted[i].ID).Updates(map[string]interface{}{
+				"status":           domain.EnrollmentStatusOffered,
+				"offer_expires_at": offerExpiresAt,
+			}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	enrollments := make([]domain.Enrollment, len(promoted))
+	for i := range promoted {
+		enrollments[i] = *EnrollmentToEntity(&promoted[i])
+	}
+	return enrollments, nil
+}
+
+// FindExpiredOffers obtiene las plazas ofertadas cuyo plazo de confirmación ya pasó
+func (r *EnrollmentRepositoryImpl) FindExpiredOffers(now time.Time) ([]domain.Enrollment, error) {
+	var models []database.ClassEnrollment
+	if err := r.db.
+		Where("status = ? AND offer_expires_at < ?", domain.EnrollmentStatusOffered, now).
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	enrollments := make([]domain.Enrollment, len(models))
+	for i := range models {
+		enrollments[i] = *EnrollmentToEntity(&models[i])
+	}
+	return enrollments, nil
+}
+
+// WaitlistPosition calcula la posición en la lista de espera (1 = siguiente en ser promocionado)
+func (r *EnrollmentRepositoryImpl) WaitlistPosition(enrollment *domain.Enrollment) (int, error) {
+	var ahead int64
+	err := r.db.Model(&database.ClassEnrollment{}).
+		Where("class_id = ? AND status = ?", enrollment.ClassID, domain.EnrollmentStatusWaitlist).
+		Where("(registered_at, id) < (?, ?)", enrollment.RegisteredAt, enrollment.ID).
+		Count(&ahead).Error
+	return int(ahead) + 1, err
+}
+
+// save inserta la inscripción o reactiva una anterior cancelada/caducada del mismo usuario
+func (r *EnrollmentRepositoryImpl) save(tx *gorm.DB, model *database.ClassEnrollment) error {
+	var previous database.ClassEnrollment
+	err := tx.Where("class_id = ? AND user_id = ? AND status IN ?", model.ClassID, model.UserID,
+		[]string{domain.EnrollmentStatusCancelled, domain.EnrollmentStatusExpired}).First(&previous).Error
+	switch {
+	case err == nil:
+		model.ID = previous.ID
+		return tx.Model(&previous).Updates(map[string]interface{}{
+			"status":           model.Status,
+			"registered_at":    model.RegisteredAt,
+			"offer_expires_at": nil,
+		}).Error
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return tx.Create(model).Error
+	default:
+		return err
+	}
+}
+
+// reload recarga la inscripción con sus relaciones
+func (r *EnrollmentRepositoryImpl) reload(enrollment *domain.Enrollment, model *database.ClassEnrollment) error {
 	if err := r.db.
 		Preload("User").
 		Preload("Class").
@@ -95,37 +233,70 @@ func (r *EnrollmentRepositoryImpl) Create(enrollment *domain.Enrollment) error {
 	return nil
 }
 
+// lockClass bloquea la fila de la clase hasta el fin de la transacción
+func lockClass(tx *gorm.DB, classID int) error {
+	var class database.Class
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&class, classID).Error
+}
+
+// countByStatus cuenta las inscripciones de una clase en los estados indicados
+func countByStatus(tx *gorm.DB, classID int, statuses ...string) (int, error) {
+	var count int64
+	err := tx.Model(&database.ClassEnrollment{}).
+		Where("class_id = ? AND status IN ?", classID, statuses).
+		Count(&count).Error
+	return int(count), err
+}
+
 // Delete elimina una inscripción
 func (r *EnrollmentRepositoryImpl) Delete(id int) error {
 	return r.db.Delete(&database.ClassEnrollment{}, id).Error
 }
 
-// CheckExists verifica si existe una inscripción
+// UpdateStatus cambia el estado de una inscripción
+func (r *EnrollmentRepositoryImpl) UpdateStatus(id int, status string) error {
+	return r.db.Model(&database.ClassEnrollment{}).Where("id = ?", id).Update("status", status).Error
+}
+
+// ResolveOffer actualiza el estado de una plaza ofertada de forma condicional, para que una
+// confirmación y una caducidad simultáneas no se apliquen las dos
+func (r *EnrollmentRepositoryImpl) ResolveOffer(id int, status string) error {
+	result := r.db.Model(&database.ClassEnrollment{}).
+		Where("id = ? AND status = ?", id, domain.EnrollmentStatusOffered).
+		Update("status", status)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrEnrollmentNotOffered
+	}
+	return nil
+}
+
+// CheckExists verifica si existe una inscripción activa
 func (r *EnrollmentRepositoryImpl) CheckExists(classID int, userID uuid.UUID) (bool, error) {
 	var count int64
 	err := r.db.Model(&database.ClassEnrollment{}).
 		Where("class_id = ? AND user_id = ?", classID, userID).
+		Where("status NOT IN ?", []string{domain.EnrollmentStatusCancelled, domain.EnrollmentStatusExpired}).
 		Count(&count).Error
 	return count > 0, err
 }
 
-// Count cuenta las inscripciones de una clase
+// Count cuenta las plazas ocupadas de una clase (confirmadas y ofertadas)
 func (r *EnrollmentRepositoryImpl) Count(classID int) (int, error) {
-	var count int64
-	err := r.db.Model(&database.ClassEnrollment{}).
-		Where("class_id = ?", classID).
-		Count(&count).Error
-	return int(count), err
+	return countByStatus(r.db, classID, domain.EnrollmentStatusConfirmed, domain.EnrollmentStatusOffered)
 }
 
 // EnrollmentToEntity convierte un modelo GORM a entidad de dominio
 func EnrollmentToEntity(model *database.ClassEnrollment) *domain.Enrollment {
 	enrollment := &domain.Enrollment{
-		ID:           int(model.ID),
-		ClassID:      int(model.ClassID),
-		UserID:       model.UserID,
-		Status:       model.Status,
-		RegisteredAt: model.RegisteredAt,
+		ID:             int(model.ID),
+		ClassID:        int(model.ClassID),
+		UserID:         model.UserID,
+		Status:         model.Status,
+		RegisteredAt:   model.RegisteredAt,
+		OfferExpiresAt: model.OfferExpiresAt,
 	}
 
 	// Relaciones expandidas
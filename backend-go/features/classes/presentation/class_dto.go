@@ -45,6 +45,7 @@ type ClassResponse struct {
 	Status         string               `json:"status"`
 	EnrolledCount  int                  `json:"enrolledCount"`
 	Enrollments    []EnrollmentResponse `json:"enrollments,omitempty"`
+	TemplateID     *int                 `json:"templateId,omitempty"` // Sesión generada por una plantilla
 	CreatedAt      time.Time            `json:"createdAt"`
 	UpdatedAt      time.Time            `json:"updatedAt"`
 }
@@ -302,6 +302,7 @@ func ToResponse(class *domain.Class) ClassResponse {
 		PriceEuros:     float64(class.PriceCents) / 100.0,
 		Status:         class.Status,
 		EnrolledCount:  class.EnrolledCount,
+		TemplateID:     class.TemplateID,
 		CreatedAt:      class.CreatedAt,
 		UpdatedAt:      class.UpdatedAt,
 	}
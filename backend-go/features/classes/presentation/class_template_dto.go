@@ -0,0 +1,169 @@
+package presentation
+
+import (
+	"backend-go/features/classes/application"
+	"backend-go/features/classes/domain"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ClassTemplateRequest representa los datos para crear o actualizar una plantilla de clase
+type ClassTemplateRequest struct {
+	PistaID         int        `json:"pistaId" validate:"required"`
+	InstructorID    string     `json:"instructorId" validate:"required"` // UUID como string
+	Title           string     `json:"title" validate:"required,min=3"`
+	Description     *string    `json:"description"`
+	MaxCapacity     int        `json:"maxCapacity" validate:"required,min=1,max=20"`
+	PriceCents      int        `json:"priceCents" validate:"min=0"`
+	Weekdays        []int      `json:"weekdays" validate:"required"`        // 0 = domingo ... 6 = sábado
+	StartsAt        string     `json:"startsAt" validate:"required"`        // HH:MM (hora local del club)
+	DurationMinutes int        `json:"durationMinutes" validate:"required"` // 30 - 180
+	ValidFrom       *time.Time `json:"validFrom"`                           // Por defecto hoy
+	ValidUntil      *time.Time `json:"validUntil"`                          // nil = sin fecha de fin
+	WeeksAhead      int        `json:"weeksAhead"`                          // Por defecto 4 semanas
+	IsActive        *bool      `json:"isActive"`                            // Por defecto true
+}
+
+// ClassTemplateResponse representa la respuesta de una plantilla de clase
+type ClassTemplateResponse struct {
+	ID              int                        `json:"id"`
+	PistaID         int                        `json:"pistaId"`
+	PistaName       string                     `json:"pistaName"`
+	InstructorID    string                     `json:"instructorId"` // UUID como string
+	InstructorName  string                     `json:"instructorName"`
+	Title           string                     `json:"title"`
+	Description     *string                    `json:"description"`
+	MaxCapacity     int                        `json:"maxCapacity"`
+	PriceCents      int                        `json:"priceCents"`
+	Weekdays        []int                      `json:"weekdays"`
+	StartsAt        string                     `json:"startsAt"` // HH:MM (hora local del club)
+	DurationMinutes int                        `json:"durationMinutes"`
+	ValidFrom       time.Time                  `json:"validFrom"`
+	ValidUntil      *time.Time                 `json:"validUntil"`
+	WeeksAhead      int                        `json:"weeksAhead"`
+	IsActive        bool                       `json:"isActive"`
+	LastGeneratedAt *time.Time                 `json:"lastGeneratedAt"`
+	Conflicts       []TemplateConflictResponse `json:"conflicts,omitempty"` // Conflictos de la última generación
+	CreatedAt       time.Time                  `json:"createdAt"`
+	UpdatedAt       time.Time                  `json:"updatedAt"`
+}
+
+// TemplateConflictResponse representa una sesión que no se pudo generar o actualizar
+type TemplateConflictResponse struct {
+	ClassID    *int      `json:"classId,omitempty"`
+	Date       string    `json:"date"` // YYYY-MM-DD
+	StartTime  time.Time `json:"startTime"`
+	EndTime    time.Time `json:"endTime"`
+	Reason     string    `json:"reason"`
+	DetectedAt time.Time `json:"detectedAt"`
+}
+
+// TemplateResultResponse representa el resultado de crear, actualizar o generar una plantilla
+type TemplateResultResponse struct {
+	Template  ClassTemplateResponse      `json:"template"`
+	Created   []ClassResponse            `json:"created"`
+	Updated   int                        `json:"updated"`
+	Cancelled int                        `json:"cancelled"`
+	Conflicts []TemplateConflictResponse `json:"conflicts"`
+}
+
+// TemplateRequestToDomain convierte ClassTemplateRequest a domain.ClassTemplate
+func TemplateRequestToDomain(req *ClassTemplateRequest) (*domain.ClassTemplate, error) {
+	instructorID, err := uuid.Parse(req.InstructorID)
+	if err != nil {
+		return nil, errors.New("InstructorID inválido")
+	}
+
+	startsAt, err := time.Parse("15:04", req.StartsAt)
+	if err != nil {
+		return nil, domain.ErrInvalidTemplateStart
+	}
+
+	template := &domain.ClassTemplate{
+		PistaID:         req.PistaID,
+		InstructorID:    instructorID,
+		Title:           req.Title,
+		Description:     req.Description,
+		MaxCapacity:     req.MaxCapacity,
+		PriceCents:      req.PriceCents,
+		Weekdays:        req.Weekdays,
+		StartMinute:     startsAt.Hour()*60 + startsAt.Minute(),
+		DurationMinutes: req.DurationMinutes,
+		ValidFrom:       time.Now().UTC(),
+		ValidUntil:      req.ValidUntil,
+		WeeksAhead:      req.WeeksAhead,
+		IsActive:        req.IsActive == nil || *req.IsActive,
+	}
+	if req.ValidFrom != nil {
+		template.ValidFrom = req.ValidFrom.UTC()
+	}
+	if template.ValidUntil != nil {
+		validUntil := template.ValidUntil.UTC()
+		template.ValidUntil = &validUntil
+	}
+	if template.WeeksAhead == 0 {
+		template.WeeksAhead = domain.DefaultTemplateWeeksAhead
+	}
+
+	return template, nil
+}
+
+// ToTemplateResponse convierte domain.ClassTemplate a ClassTemplateResponse
+func ToTemplateResponse(template *domain.ClassTemplate) ClassTemplateResponse {
+	return ClassTemplateResponse{
+		ID:              template.ID,
+		PistaID:         template.PistaID,
+		PistaName:       template.PistaName,
+		InstructorID:    template.InstructorID.String(),
+		InstructorName:  template.InstructorName,
+		Title:           template.Title,
+		Description:     template.Description,
+		MaxCapacity:     template.MaxCapacity,
+		PriceCents:      template.PriceCents,
+		Weekdays:        template.Weekdays,
+		StartsAt:        fmt.Sprintf("%02d:%02d", template.StartMinute/60, template.StartMinute%60),
+		DurationMinutes: template.DurationMinutes,
+		ValidFrom:       template.ValidFrom,
+		ValidUntil:      template.ValidUntil,
+		WeeksAhead:      template.WeeksAhead,
+		IsActive:        template.IsActive,
+		LastGeneratedAt: template.LastGeneratedAt,
+		Conflicts:       ToTemplateConflictResponses(template.Conflicts),
+		CreatedAt:       template.CreatedAt,
+		UpdatedAt:       template.UpdatedAt,
+	}
+}
+
+// ToTemplateConflictResponses convierte los conflictos de dominio a DTOs
+func ToTemplateConflictResponses(conflicts []domain.TemplateConflict) []TemplateConflictResponse {
+	responses := make([]TemplateConflictResponse, len(conflicts))
+	for i, conflict := range conflicts {
+		responses[i] = TemplateConflictResponse{
+			ClassID:    conflict.ClassID,
+			Date:       conflict.StartTime.Format("2006-01-02"),
+			StartTime:  conflict.StartTime,
+			EndTime:    conflict.EndTime,
+			Reason:     conflict.Reason,
+			DetectedAt: conflict.DetectedAt,
+		}
+	}
+	return responses
+}
+
+// ToTemplateResultResponse convierte application.TemplateResult a TemplateResultResponse
+func ToTemplateResultResponse(result *application.TemplateResult) TemplateResultResponse {
+	response := TemplateResultResponse{
+		Template:  ToTemplateResponse(result.Template),
+		Created:   make([]ClassResponse, len(result.Created)),
+		Updated:   result.Updated,
+		Cancelled: result.Cancelled,
+		Conflicts: ToTemplateConflictResponses(result.Conflicts),
+	}
+	for i := range result.Created {
+		response.Created[i] = ToResponse(&result.Created[i])
+	}
+	return response
+}
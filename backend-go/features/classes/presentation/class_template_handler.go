@@ -0,0 +1,187 @@
+package presentation
+
+import (
+	"backend-go/features/classes/application"
+	"backend-go/features/classes/domain"
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ClassTemplateHandler struct {
+	service *application.ClassTemplateService
+}
+
+func NewClassTemplateHandler(service *application.ClassTemplateService) *ClassTemplateHandler {
+	return &ClassTemplateHandler{service: service}
+}
+
+// GetAll maneja GET /class-templates
+// @Summary Listar plantillas de clases recurrentes
+// @Tags class-templates
+// @Produce json
+// @Success 200 {array} ClassTemplateResponse
+// @Router /api/class-templates [get]
+func (h *ClassTemplateHandler) GetAll(c *fiber.Ctx) error {
+	templates, err := h.service.GetAllTemplates()
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	responses := make([]ClassTemplateResponse, len(templates))
+	for i := range templates {
+		responses[i] = ToTemplateResponse(&templates[i])
+	}
+
+	return c.JSON(responses)
+}
+
+// GetByID maneja GET /class-templates/:id
+// @Summary Obtener una plantilla con los conflictos de su última generación
+// @Tags class-templates
+// @Produce json
+// @Param id path int true "ID de la plantilla"
+// @Success 200 {object} ClassTemplateResponse
+// @Router /api/class-templates/{id} [get]
+func (h *ClassTemplateHandler) GetByID(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "ID inválido"})
+	}
+
+	template, err := h.service.GetTemplateByID(id)
+	if err != nil {
+		return templateError(c, err)
+	}
+
+	return c.JSON(ToTemplateResponse(template))
+}
+
+// Create maneja POST /class-templates
+// @Summary Crear una plantilla de clase recurrente y generar sus primeras sesiones
+// @Tags class-templates
+// @Accept json
+// @Produce json
+// @Param template body ClassTemplateRequest true "Datos de la plantilla"
+// @Success 201 {object} TemplateResultResponse
+// @Router /api/class-templates [post]
+func (h *ClassTemplateHandler) Create(c *fiber.Ctx) error {
+	var req ClassTemplateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Datos inválidos"})
+	}
+
+	template, err := TemplateRequestToDomain(&req)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	result, err := h.service.CreateTemplate(template)
+	if err != nil {
+		return templateError(c, err)
+	}
+
+	return c.Status(201).JSON(ToTemplateResultResponse(result))
+}
+
+// Update maneja PUT /class-templates/:id
+// @Summary Actualizar una plantilla y propagar los cambios a las sesiones futuras
+// @Description Solo se modifican las sesiones abiertas que aún no han empezado.
+// @Tags class-templates
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de la plantilla"
+// @Param template body ClassTemplateRequest true "Datos de la plantilla"
+// @Success 200 {object} TemplateResultResponse
+// @Router /api/class-templates/{id} [put]
+func (h *ClassTemplateHandler) Update(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "ID inválido"})
+	}
+
+	var req ClassTemplateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Datos inválidos"})
+	}
+
+	template, err := TemplateRequestToDomain(&req)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	template.ID = id
+
+	// Sin validFrom se conserva el inicio de vigencia actual
+	if req.ValidFrom == nil {
+		existing, err := h.service.GetTemplateByID(id)
+		if err != nil {
+			return templateError(c, err)
+		}
+		template.ValidFrom = existing.ValidFrom
+	}
+
+	result, err := h.service.UpdateTemplate(template)
+	if err != nil {
+		return templateError(c, err)
+	}
+
+	return c.JSON(ToTemplateResultResponse(result))
+}
+
+// Delete maneja DELETE /class-templates/:id
+// @Summary Eliminar una plantilla (las sesiones ya generadas se conservan)
+// @Tags class-templates
+// @Param id path int true "ID de la plantilla"
+// @Success 204
+// @Router /api/class-templates/{id} [delete]
+func (h *ClassTemplateHandler) Delete(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "ID inválido"})
+	}
+
+	if err := h.service.DeleteTemplate(id); err != nil {
+		return templateError(c, err)
+	}
+
+	return c.Status(204).Send(nil)
+}
+
+// Generate maneja POST /class-templates/:id/generate
+// @Summary Generar ahora las sesiones pendientes de una plantilla
+// @Tags class-templates
+// @Produce json
+// @Param id path int true "ID de la plantilla"
+// @Success 200 {object} TemplateResultResponse
+// @Router /api/class-templates/{id}/generate [post]
+func (h *ClassTemplateHandler) Generate(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "ID inválido"})
+	}
+
+	result, err := h.service.GenerateSessions(id)
+	if err != nil {
+		return templateError(c, err)
+	}
+
+	return c.JSON(ToTemplateResultResponse(result))
+}
+
+// templateError traduce los errores de plantillas a códigos HTTP
+func templateError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrTemplateNotFound):
+		return c.Status(404).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrInvalidTemplateWeekdays),
+		errors.Is(err, domain.ErrInvalidTemplateStart),
+		errors.Is(err, domain.ErrInvalidTemplateDuration),
+		errors.Is(err, domain.ErrInvalidTemplateCapacity),
+		errors.Is(err, domain.ErrInvalidTemplatePrice),
+		errors.Is(err, domain.ErrInvalidTemplateValidity),
+		errors.Is(err, domain.ErrInvalidWeeksAhead):
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+}
@@ -0,0 +1,25 @@
+package presentation
+
+import (
+	"backend-go/shared/middleware"
+	"backend-go/shared/security"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ======================================================================================
+// CLASS TEMPLATE ROUTES - Solo ADMIN, GESTOR y MONITOR
+// GET /, GET /:id, POST /, PUT /:id, DELETE /:id, POST /:id/generate
+// ======================================================================================
+
+func RegisterTemplateRoutes(app *fiber.App, handler *ClassTemplateHandler, jwtService security.JWTService) {
+	templates := app.Group("/api/class-templates")
+	templates.Use(middleware.JWTMiddleware(jwtService), middleware.RequireRoleByName("ADMIN", "GESTOR", "MONITOR"))
+
+	templates.Get("/", handler.GetAll)
+	templates.Get("/:id", handler.GetByID)
+	templates.Post("/", handler.Create)
+	templates.Put("/:id", handler.Update)
+	templates.Delete("/:id", handler.Delete)
+	templates.Post("/:id/generate", handler.Generate) // Generar sesiones sin esperar al scheduler
+}
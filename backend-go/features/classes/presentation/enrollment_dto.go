@@ -18,4 +18,11 @@ type EnrollmentResponse struct {
 	Status       string    `json:"status"`
 	RegisteredAt time.Time `json:"registeredAt"`
 	EnrolledAt   time.Time `json:"enrolledAt"` // Alias para compatibilidad
+
+	// Lista de espera
+	WaitlistPosition *int       `json:"waitlistPosition,omitempty"` // Solo WAITLIST
+	OfferExpiresAt   *time.Time `json:"offerExpiresAt,omitempty"`   // Solo OFFERED: límite para confirmar
+
+	// Pago con bono (solo en la respuesta de inscribirse o confirmar la plaza)
+	PaidWithPass bool `json:"paidWithPass,omitempty"`
 }
@@ -3,10 +3,13 @@ package presentation
 import (
 	"backend-go/features/classes/application"
 	"backend-go/features/classes/domain"
+	"errors"
+	"fmt"
 	"net/url"
 	"strconv"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 )
 
 type EnrollmentHandler struct {
@@ -45,7 +48,9 @@ func (h *EnrollmentHandler) GetByClass(c *fiber.Ctx, classID int) error {
 // @Produce json
 // @Param slug path string true "Slug de la clase"
 // @Param enrollment body EnrollUserRequest true "Datos de inscripción"
-// @Success 201 {object} map[string]string
+// @Description Si la clase está completa el usuario queda en lista de espera (status WAITLIST, waitlistPosition)
+// @Success 201 {object} map[string]interface{}
+// @Failure 403 {object} map[string]string "Solo el personal puede inscribir a otros usuarios"
 // @Router /api/classes/{slug}/enroll [post]
 func (h *EnrollmentHandler) Enroll(c *fiber.Ctx) error {
 	classSlug, err := url.QueryUnescape(c.Params("slug"))
@@ -58,11 +63,33 @@ func (h *EnrollmentHandler) Enroll(c *fiber.Ctx) error {
 		return c.Status(400).JSON(fiber.Map{"error": "Datos inválidos"})
 	}
 
-	if err := h.service.EnrollUserBySlug(classSlug, req.UserSlug); err != nil {
+	userID, ok := c.Locals("userID").(uuid.UUID)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"error": "No autenticado"})
+	}
+	roleName, _ := c.Locals("roleName").(string)
+	isStaff := roleName == "ADMIN" || roleName == "GESTOR"
+
+	enrollment, err := h.service.EnrollUserBySlug(classSlug, req.UserSlug, userID, isStaff)
+	if errors.Is(err, domain.ErrEnrollOtherUser) {
+		return c.Status(403).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err != nil {
 		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
 	}
 
-	return c.Status(201).JSON(fiber.Map{"message": "Usuario inscrito correctamente"})
+	message := "Usuario inscrito correctamente"
+	if enrollment.PaidWithPass {
+		message = "Usuario inscrito correctamente (pagado con bono)"
+	}
+	if enrollment.Status == domain.EnrollmentStatusWaitlist {
+		message = fmt.Sprintf("Clase completa: usuario añadido a la lista de espera (posición %d)", enrollment.WaitlistPosition)
+	}
+
+	return c.Status(201).JSON(fiber.Map{
+		"message":    message,
+		"enrollment": EnrollmentToResponse(enrollment),
+	})
 }
 
 // Unenroll maneja DELETE /enrollments/:id
@@ -84,17 +111,84 @@ func (h *EnrollmentHandler) Unenroll(c *fiber.Ctx) error {
 	return c.Status(204).Send(nil)
 }
 
+// GetMyEnrollments maneja GET /enrollments/me
+// @Summary Mis inscripciones (incluye posición en lista de espera y plazas ofertadas)
+// @Tags enrollments
+// @Produce json
+// @Success 200 {array} EnrollmentResponse
+// @Router /api/enrollments/me [get]
+func (h *EnrollmentHandler) GetMyEnrollments(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uuid.UUID)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"error": "No autenticado"})
+	}
+
+	enrollments, err := h.service.GetEnrollmentsByUser(userID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	responses := make([]EnrollmentResponse, len(enrollments))
+	for i := range enrollments {
+		responses[i] = EnrollmentToResponse(&enrollments[i])
+	}
+
+	return c.JSON(responses)
+}
+
+// ConfirmOffer maneja POST /enrollments/:id/confirm
+// @Summary Confirmar la plaza ofertada desde la lista de espera
+// @Tags enrollments
+// @Produce json
+// @Param id path int true "ID de la inscripción"
+// @Success 200 {object} EnrollmentResponse
+// @Failure 410 {object} map[string]string
+// @Router /api/enrollments/{id}/confirm [post]
+func (h *EnrollmentHandler) ConfirmOffer(c *fiber.Ctx) error {
+	enrollmentID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "ID inválido"})
+	}
+
+	userID, ok := c.Locals("userID").(uuid.UUID)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"error": "No autenticado"})
+	}
+	roleName, _ := c.Locals("roleName").(string)
+	isStaff := roleName == "ADMIN" || roleName == "GESTOR"
+
+	enrollment, err := h.service.ConfirmOffer(enrollmentID, userID, isStaff)
+	if err != nil {
+		if errors.Is(err, domain.ErrOfferExpired) {
+			return c.Status(410).JSON(fiber.Map{"error": err.Error()})
+		}
+		if errors.Is(err, domain.ErrEnrollmentNotOffered) {
+			return c.Status(409).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(EnrollmentToResponse(enrollment))
+}
+
 // EnrollmentToResponse convierte una entidad de dominio a DTO
 func EnrollmentToResponse(enrollment *domain.Enrollment) EnrollmentResponse {
-	return EnrollmentResponse{
-		ID:           enrollment.ID,
-		ClassID:      enrollment.ClassID,
-		ClassName:    enrollment.ClassName,
-		UserID:       enrollment.UserID.String(),
-		UserName:     enrollment.UserName,
-		UserEmail:    enrollment.UserEmail,
-		Status:       enrollment.Status,
-		RegisteredAt: enrollment.RegisteredAt,
-		EnrolledAt:   enrollment.RegisteredAt,
+	response := EnrollmentResponse{
+		ID:             enrollment.ID,
+		ClassID:        enrollment.ClassID,
+		ClassName:      enrollment.ClassName,
+		UserID:         enrollment.UserID.String(),
+		UserName:       enrollment.UserName,
+		UserEmail:      enrollment.UserEmail,
+		Status:         enrollment.Status,
+		RegisteredAt:   enrollment.RegisteredAt,
+		EnrolledAt:     enrollment.RegisteredAt,
+		OfferExpiresAt: enrollment.OfferExpiresAt,
+		PaidWithPass:   enrollment.PaidWithPass,
+	}
+	if enrollment.Status == domain.EnrollmentStatusWaitlist {
+		position := enrollment.WaitlistPosition
+		response.WaitlistPosition = &position
 	}
+	return response
 }
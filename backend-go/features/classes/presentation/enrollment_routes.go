@@ -15,5 +15,7 @@ func RegisterEnrollmentRoutes(router fiber.Router, handler *EnrollmentHandler, j
 	router.Use(middleware.JWTMiddleware(jwtService))
 
 	// Rutas de inscripciones - Autenticado
-	router.Delete("/:id", handler.Unenroll) // Desinscribirse - Validar usuario en handler
+	router.Get("/me", handler.GetMyEnrollments)       // Mis inscripciones y posición en lista de espera
+	router.Post("/:id/confirm", handler.ConfirmOffer) // Confirmar plaza ofertada desde la lista de espera
+	router.Delete("/:id", handler.Unenroll)           // Desinscribirse - Validar usuario en handler
 }
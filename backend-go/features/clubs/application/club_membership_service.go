@@ -2,6 +2,7 @@ package application
 
 import (
 	"backend-go/features/clubs/domain"
+	paymentApp "backend-go/features/payments/application"
 	"errors"
 	"fmt"
 	"time"
@@ -21,11 +22,15 @@ type UserInfo struct {
 }
 
 type ClubMembershipService struct {
-	repo domain.ClubMembershipRepository
+	repo           domain.ClubMembershipRepository
+	paymentService *paymentApp.PaymentService
 }
 
-func NewClubMembershipService(repo domain.ClubMembershipRepository) *ClubMembershipService {
-	return &ClubMembershipService{repo: repo}
+func NewClubMembershipService(repo domain.ClubMembershipRepository, paymentService *paymentApp.PaymentService) *ClubMembershipService {
+	return &ClubMembershipService{
+		repo:           repo,
+		paymentService: paymentService,
+	}
 }
 
 // GetMembershipsByClub obtiene todas las membresías de un club
@@ -107,10 +112,18 @@ func (s *ClubMembershipService) CancelMembership(membershipID int) error {
 		return fmt.Errorf("la membresía ya está cancelada")
 	}
 
+	// Reembolsar la parte no consumida del periodo ya pagado (hasta el próximo cobro)
+	if membership.NextBillingDate != nil {
+		if _, err := s.paymentService.RefundMembershipCancellation(uint(membershipID), *membership.NextBillingDate); err != nil {
+			return fmt.Errorf("error al reembolsar la membresía: %w", err)
+		}
+	}
+
 	membership.Status = domain.MembershipStatusCancelled
 	membership.IsActive = false
 	now := time.Now()
 	membership.EndDate = &now
+	membership.NextBillingDate = nil
 
 	return s.repo.Update(membership)
 }
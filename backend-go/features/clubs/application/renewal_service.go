@@ -3,9 +3,13 @@ package application
 import (
 	"backend-go/features/clubs/domain"
 	paymentApp "backend-go/features/payments/application"
+	paymentDomain "backend-go/features/payments/domain"
+	"context"
 	"errors"
 	"fmt"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 var (
@@ -13,6 +17,9 @@ var (
 	ErrClubNotFound       = errors.New("club no encontrado")
 	ErrMembershipInactive = errors.New("la membresía no está activa")
 	ErrPaymentFailed      = errors.New("el pago falló")
+	ErrNoPaymentMethod    = errors.New("el usuario no tiene un método de pago guardado")
+	ErrRenewalPending     = errors.New("la membresía tiene un cobro de renovación pendiente de confirmar")
+	ErrNotMembershipOwner = errors.New("solo el titular de la membresía o el personal pueden renovarla")
 )
 
 // RenewalService maneja la lógica de renovación de membresías
@@ -35,85 +42,234 @@ func NewRenewalService(
 	}
 }
 
-// RenewMembership procesa la renovación (cobro) de una membresía
-func (s *RenewalService) RenewMembership(membershipID int, customerID string) error {
-	// 1. Obtener la membresía
+// RenewalRunResult resume una ejecución de la renovación automática
+type RenewalRunResult struct {
+	Renewed   int // Cobros correctos
+	Failed    int // Cobros fallidos (PAST_DUE, con reintento programado)
+	Suspended int // Membresías suspendidas al agotar el periodo de gracia
+	Pending   int // Cobros pendientes de confirmar por el proveedor (webhook)
+}
+
+// RenewMembership procesa manualmente la renovación (cobro) de una membresía. Solo la puede
+// renovar su titular (userID) o el personal (isStaff); sin customerID se usa el cliente guardado
+// del titular. Una membresía suspendida por
+// impago se reactiva al pagar. Si el proveedor deja el cobro pendiente, la membresía
+// devuelta tiene PendingPaymentID y se renueva al confirmarse por webhook.
+// Sin idempotencyKey se usa la clave del periodo (RenewalChargeKey).
+func (s *RenewalService) RenewMembership(membershipID int, userID uuid.UUID, isStaff bool, customerID, idempotencyKey string) (*domain.ClubMembership, error) {
+	// 1. Obtener la membresía y comprobar que el usuario puede cobrarla
 	membership, err := s.membershipRepo.FindByID(membershipID)
 	if err != nil {
-		return ErrMembershipNotFound
+		return nil, ErrMembershipNotFound
+	}
+	if !isStaff && membership.UserID != userID {
+		return nil, ErrNotMembershipOwner
 	}
 
-	// 2. Verificar que esté activa
-	if membership.Status != domain.MembershipStatusActive {
-		return ErrMembershipInactive
+	// 2. Verificar que esté activa (o suspendida por impago) y sin otro cobro en curso
+	if !membership.CanRenew() {
+		return nil, ErrMembershipInactive
+	}
+	if membership.PendingPaymentID != nil {
+		return nil, ErrRenewalPending
+	}
+
+	// 3. Método de pago: el indicado o el guardado del usuario
+	if customerID == "" && membership.StripeCustomerID != nil {
+		customerID = *membership.StripeCustomerID
+	}
+	if customerID == "" {
+		return nil, ErrNoPaymentMethod
 	}
 
-	// 3. Obtener el club para conocer el precio
+	// 4. Obtener el club para conocer el precio y cobrar
 	club, err := s.clubRepo.FindByID(membership.ClubID)
 	if err != nil {
-		return ErrClubNotFound
+		return nil, ErrClubNotFound
 	}
 
-	// 4. Procesar el pago a través del PaymentService
-	payment, err := s.paymentService.ProcessClubPayment(
-		membership.UserID,
-		uint(membershipID),
-		club.MonthlyFeeCents,
-		customerID,
-	)
+	if idempotencyKey == "" {
+		idempotencyKey = membership.RenewalChargeKey()
+	}
+	if err := s.charge(membership, club, customerID, idempotencyKey, time.Now()); err != nil {
+		return nil, err
+	}
+	return membership, nil
+}
+
+// GetPendingRenewals obtiene las membresías activas con NextBillingDate <= ahora
+// que no tienen un reintento de cobro programado para más adelante
+func (s *RenewalService) GetPendingRenewals() ([]domain.ClubMembership, error) {
+	return s.membershipRepo.FindDueForRenewal(time.Now())
+}
+
+// AutoRenewMemberships cobra las renovaciones vencidas con el cliente guardado de cada usuario.
+// Los cobros fallidos se reintentan con backoff (PAST_DUE) y, agotado el periodo de gracia,
+// la membresía se suspende. Se ejecuta desde el scheduler; las consultas usan ctx.
+func (s *RenewalService) AutoRenewMemberships(ctx context.Context) (*RenewalRunResult, error) {
+	s = s.withContext(ctx)
+	pendingRenewals, err := s.GetPendingRenewals()
 	if err != nil {
-		// Marcar como PAST_DUE si el pago falla
-		membership.PaymentStatus = domain.PaymentStatusPastDue
-		s.membershipRepo.Update(membership)
-		return fmt.Errorf("%w: %v", ErrPaymentFailed, err)
+		return nil, fmt.Errorf("error al buscar renovaciones pendientes: %w", err)
+	}
+
+	result := &RenewalRunResult{}
+	for i := range pendingRenewals {
+		// Los cobros no se cortan a medias: se para entre membresías
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		membership := &pendingRenewals[i]
+		now := time.Now()
+
+		// Reservar la renovación para no cobrar dos veces si otra ejecución la está procesando
+		claimed, err := s.membershipRepo.ClaimRenewal(membership.ID, now, now.Add(domain.RenewalClaimTimeout))
+		if err != nil {
+			return result, fmt.Errorf("error al reservar la renovación #%d: %w", membership.ID, err)
+		}
+		if !claimed {
+			continue
+		}
+
+		club, err := s.clubRepo.FindByID(membership.ClubID)
+		if err != nil {
+			return result, fmt.Errorf("error al obtener el club de la membresía #%d: %w", membership.ID, err)
+		}
+
+		customerID := ""
+		if membership.StripeCustomerID != nil {
+			customerID = *membership.StripeCustomerID
+		}
+
+		err = s.charge(membership, club, customerID, membership.RenewalChargeKey(), now)
+		switch {
+		case err == nil && membership.PendingPaymentID != nil:
+			result.Pending++
+		case err == nil:
+			result.Renewed++
+		case errors.Is(err, ErrPaymentFailed):
+			if membership.Status == domain.MembershipStatusSuspended {
+				result.Suspended++
+			} else {
+				result.Failed++
+			}
+			fmt.Printf("❌ Error renovando membresía #%d: %v\n", membership.ID, err)
+		default:
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+// withContext devuelve una copia del servicio cuyas consultas (también las de pagos) usan ctx
+func (s *RenewalService) withContext(ctx context.Context) *RenewalService {
+	scoped := *s
+	scoped.membershipRepo = s.membershipRepo.WithContext(ctx)
+	scoped.clubRepo = s.clubRepo.WithContext(ctx)
+	scoped.paymentService = s.paymentService.WithContext(ctx)
+	return &scoped
+}
+
+// charge cobra la cuota del club y actualiza el estado de la membresía según el resultado.
+// Sin cliente de pago el cobro se considera fallido (cuenta para el periodo de gracia).
+// Un cobro pendiente (3DS, SEPA) queda a la espera del webhook del proveedor.
+func (s *RenewalService) charge(membership *domain.ClubMembership, club *domain.Club, customerID, idempotencyKey string, now time.Time) error {
+	var paymentID *int
+	var chargeErr error
+	pending := false
+
+	switch {
+	case club.MonthlyFeeCents <= 0:
+		// Club gratuito: solo se avanza el periodo
+	case customerID == "":
+		chargeErr = ErrNoPaymentMethod
+	default:
+		payment, err := s.paymentService.ProcessClubPayment(
+			membership.UserID,
+			uint(membership.ID),
+			paymentDomain.PaymentMethodCard,
+			customerID,
+			"", // Las renovaciones automáticas no admiten código promocional
+			idempotencyKey,
+		)
+		if err != nil {
+			chargeErr = err
+		} else {
+			id := int(payment.ID)
+			paymentID = &id
+			pending = payment.Status == paymentDomain.StatusPending
+		}
 	}
 
-	// 5. Actualizar la membresía con los datos del pago exitoso
-	now := time.Now()
-	nextBilling := now.AddDate(0, 1, 0) // +1 mes
+	if pending {
+		membership.MarkRenewalPending(*paymentID)
+		if err := s.membershipRepo.Update(membership); err != nil {
+			return fmt.Errorf("error al actualizar membresía: %w", err)
+		}
+		fmt.Printf("⏳ Membresía #%d: cobro #%d pendiente de confirmar por el proveedor\n", membership.ID, *paymentID)
+		return nil
+	}
 
-	membership.PaymentStatus = domain.PaymentStatusUpToDate
-	membership.NextBillingDate = &nextBilling
-	paymentID := int(payment.ID)
-	membership.LastPaymentID = &paymentID
-	membership.UpdatedAt = now
+	if chargeErr != nil {
+		suspended := membership.MarkRenewalFailed(now)
+		if err := s.membershipRepo.Update(membership); err != nil {
+			return fmt.Errorf("error al actualizar membresía: %w", err)
+		}
+		if suspended {
+			return fmt.Errorf("%w (membresía suspendida tras el periodo de gracia): %v", ErrPaymentFailed, chargeErr)
+		}
+		return fmt.Errorf("%w: %v", ErrPaymentFailed, chargeErr)
+	}
 
-	// 6. Guardar cambios
+	membership.MarkRenewed(paymentID, now)
 	if err := s.membershipRepo.Update(membership); err != nil {
 		return fmt.Errorf("error al actualizar membresía: %w", err)
 	}
 
 	fmt.Printf("✅ Membresía #%d renovada exitosamente. Próximo cobro: %s\n",
-		membershipID, nextBilling.Format("2006-01-02"))
+		membership.ID, membership.NextBillingDate.Format("2006-01-02"))
 
 	return nil
 }
 
-// GetPendingRenewals obtiene membresías que requieren renovación
-func (s *RenewalService) GetPendingRenewals() ([]domain.ClubMembership, error) {
-	// TODO: Implementar lógica para obtener membresías con NextBillingDate <= hoy
-	// Por ahora retorna vacío, se puede implementar con un método en el repo
-	return []domain.ClubMembership{}, nil
+// ConfirmRenewalPayment renueva la membresía cuando el proveedor confirma (webhook) el cobro
+// pendiente. Es idempotente: si la membresía ya no espera ese cobro no hace nada.
+func (s *RenewalService) ConfirmRenewalPayment(membershipID, paymentID int) error {
+	membership, err := s.membershipRepo.FindByID(membershipID)
+	if err != nil {
+		return ErrMembershipNotFound
+	}
+	if !membership.AwaitsPayment(paymentID) {
+		return nil
+	}
+
+	membership.MarkRenewed(&paymentID, time.Now())
+	if err := s.membershipRepo.Update(membership); err != nil {
+		return fmt.Errorf("error al actualizar membresía: %w", err)
+	}
+
+	fmt.Printf("✅ Membresía #%d renovada (cobro #%d confirmado). Próximo cobro: %s\n",
+		membership.ID, paymentID, membership.NextBillingDate.Format("2006-01-02"))
+	return nil
 }
 
-// AutoRenewMemberships ejecuta renovaciones automáticas
-// Se puede llamar desde un scheduler/cron job
-func (s *RenewalService) AutoRenewMemberships() (int, error) {
-	pendingRenewals, err := s.GetPendingRenewals()
+// FailRenewalPayment aplica el fallo (webhook) del cobro pendiente: PAST_DUE con reintento
+// o suspensión si se agotó el periodo de gracia. Es idempotente como ConfirmRenewalPayment.
+func (s *RenewalService) FailRenewalPayment(membershipID, paymentID int) error {
+	membership, err := s.membershipRepo.FindByID(membershipID)
 	if err != nil {
-		return 0, err
+		return ErrMembershipNotFound
+	}
+	if !membership.AwaitsPayment(paymentID) {
+		return nil
 	}
 
-	successCount := 0
-	for _, membership := range pendingRenewals {
-		// Aquí necesitarías obtener el customerID del usuario
-		// Por ahora es un placeholder
-		if err := s.RenewMembership(membership.ID, "cus_auto"); err != nil {
-			fmt.Printf("❌ Error renovando membresía #%d: %v\n", membership.ID, err)
-			continue
-		}
-		successCount++
+	suspended := membership.MarkRenewalFailed(time.Now())
+	if err := s.membershipRepo.Update(membership); err != nil {
+		return fmt.Errorf("error al actualizar membresía: %w", err)
 	}
 
-	return successCount, nil
+	fmt.Printf("❌ Membresía #%d: el cobro #%d falló (suspendida: %t)\n", membership.ID, paymentID, suspended)
+	return nil
 }
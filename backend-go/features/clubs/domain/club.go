@@ -2,6 +2,7 @@ package domain
 
 import (
 	"backend-go/shared/pagination"
+	"context"
 	"time"
 
 	"github.com/google/uuid"
@@ -37,6 +38,9 @@ const (
 
 // ClubRepository define el contrato de persistencia para clubs
 type ClubRepository interface {
+	// WithContext devuelve el repositorio con sus consultas ligadas a ctx (plazo y cancelación)
+	WithContext(ctx context.Context) ClubRepository
+
 	FindAll() ([]Club, error)
 	FindAllPaginated(params pagination.PaginationParams) ([]Club, *pagination.PaginationMeta, error)
 	FindByID(id int) (*Club, error)
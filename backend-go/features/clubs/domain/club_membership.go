@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"context"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -8,25 +10,30 @@ import (
 
 // ClubMembership representa la membresía de un usuario a un club
 type ClubMembership struct {
-	ID              int
-	ClubID          int
-	UserID          uuid.UUID
-	Status          string
-	StartDate       time.Time
-	EndDate         *time.Time
-	NextBillingDate *time.Time
-	PaymentStatus   string
-	LastPaymentID   *int
-	IsActive        bool
-	CreatedAt       time.Time
-	UpdatedAt       time.Time
+	ID               int
+	ClubID           int
+	UserID           uuid.UUID
+	Status           string
+	StartDate        time.Time
+	EndDate          *time.Time
+	NextBillingDate  *time.Time
+	PaymentStatus    string
+	LastPaymentID    *int
+	PendingPaymentID *int       // Cobro de renovación pendiente de confirmar por webhook
+	RenewalAttempts  int        // Intentos fallidos de cobro consecutivos
+	NextRetryAt      *time.Time // Próximo reintento de cobro
+	PastDueSince     *time.Time // Inicio del periodo de gracia por impago
+	IsActive         bool
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
 
 	// Relaciones expandidas
-	ClubName  string
-	ClubSlug  string
-	UserName  string
-	UserEmail string
-	UserSlug  string
+	ClubName         string
+	ClubSlug         string
+	UserName         string
+	UserEmail        string
+	UserSlug         string
+	StripeCustomerID *string // Cliente del proveedor de pagos del usuario (cobro automático)
 }
 
 // Estados de membresía
@@ -43,8 +50,106 @@ const (
 	PaymentStatusPastDue  = "PAST_DUE"
 )
 
+// Renovación automática: reintentos con backoff y periodo de gracia antes de suspender
+const (
+	RenewalGracePeriod  = 7 * 24 * time.Hour // Tras este tiempo en PAST_DUE la membresía se suspende
+	RenewalClaimTimeout = 15 * time.Minute   // Bloqueo de una renovación en curso frente a otras ejecuciones
+)
+
+// RenewalRetryBackoff define la espera tras cada intento fallido (el último se repite)
+var RenewalRetryBackoff = []time.Duration{1 * time.Hour, 6 * time.Hour, 24 * time.Hour, 48 * time.Hour}
+
+// NextRenewalRetry calcula el próximo reintento de cobro tras el número de intentos fallidos indicado
+func NextRenewalRetry(attempts int, now time.Time) time.Time {
+	index := attempts - 1
+	if index < 0 {
+		index = 0
+	}
+	if index >= len(RenewalRetryBackoff) {
+		index = len(RenewalRetryBackoff) - 1
+	}
+	return now.Add(RenewalRetryBackoff[index])
+}
+
+// CanRenew indica si la membresía admite un cobro de renovación: activa, o suspendida por impago
+// (pagar la deuda la reactiva)
+func (m *ClubMembership) CanRenew() bool {
+	if m.Status == MembershipStatusActive {
+		return true
+	}
+	return m.Status == MembershipStatusSuspended && m.PaymentStatus == PaymentStatusPastDue
+}
+
+// MarkRenewed registra un cobro correcto y programa el siguiente periodo
+func (m *ClubMembership) MarkRenewed(paymentID *int, now time.Time) {
+	next := now.AddDate(0, 1, 0)
+	if m.NextBillingDate != nil {
+		// Mantener el día de cobro salvo que la membresía lleve más de un periodo sin pagar
+		if anchored := m.NextBillingDate.AddDate(0, 1, 0); anchored.After(now) {
+			next = anchored
+		}
+	}
+
+	m.Status = MembershipStatusActive
+	m.IsActive = true
+	m.PaymentStatus = PaymentStatusUpToDate
+	m.NextBillingDate = &next
+	m.LastPaymentID = paymentID
+	m.PendingPaymentID = nil
+	m.RenewalAttempts = 0
+	m.NextRetryAt = nil
+	m.PastDueSince = nil
+}
+
+// MarkRenewalFailed registra un cobro fallido: pasa a PAST_DUE y programa un reintento con
+// backoff, o suspende la membresía si se agotó el periodo de gracia. Devuelve true si se suspendió.
+func (m *ClubMembership) MarkRenewalFailed(now time.Time) bool {
+	m.PendingPaymentID = nil
+	m.RenewalAttempts++
+	m.PaymentStatus = PaymentStatusPastDue
+	if m.PastDueSince == nil {
+		m.PastDueSince = &now
+	}
+
+	if now.Sub(*m.PastDueSince) >= RenewalGracePeriod {
+		m.Status = MembershipStatusSuspended
+		m.IsActive = false
+		m.NextRetryAt = nil
+		return true
+	}
+
+	retryAt := NextRenewalRetry(m.RenewalAttempts, now)
+	m.NextRetryAt = &retryAt
+	return false
+}
+
+// MarkRenewalPending registra un cobro de renovación que el proveedor confirmará más tarde
+// (3DS, SEPA). El periodo no avanza ni se reintenta hasta que llegue el webhook.
+func (m *ClubMembership) MarkRenewalPending(paymentID int) {
+	m.PendingPaymentID = &paymentID
+	m.NextRetryAt = nil
+}
+
+// RenewalChargeKey es la clave de idempotencia del cobro de renovación: la misma para el mismo
+// periodo e intento, de modo que un reintento tras un timeout no cobra dos veces
+func (m *ClubMembership) RenewalChargeKey() string {
+	period := "none"
+	if m.NextBillingDate != nil {
+		period = m.NextBillingDate.UTC().Format("20060102")
+	}
+	return fmt.Sprintf("renewal:%d:%s:%d", m.ID, period, m.RenewalAttempts)
+}
+
+// AwaitsPayment indica si la membresía espera la confirmación del cobro indicado
+func (m *ClubMembership) AwaitsPayment(paymentID int) bool {
+	return m.PendingPaymentID != nil && *m.PendingPaymentID == paymentID
+}
+
 // ClubMembershipRepository define el contrato de persistencia para membresías
 type ClubMembershipRepository interface {
+	// WithContext devuelve el repositorio con sus consultas ligadas a ctx (plazo y cancelación)
+	WithContext(ctx context.Context) ClubMembershipRepository
+
 	FindByID(id int) (*ClubMembership, error)
 	FindByClub(clubID int) ([]ClubMembership, error)
 	FindByUser(userID uuid.UUID) ([]ClubMembership, error)
@@ -53,4 +158,8 @@ type ClubMembershipRepository interface {
 	Delete(id int) error
 	CheckExists(clubID int, userID uuid.UUID) (bool, error)
 	Count(clubID int) (int, error)
+
+	// Renovación automática
+	FindDueForRenewal(now time.Time) ([]ClubMembership, error)         // Activas con cobro vencido, sin reintento ni cobro pendiente
+	ClaimRenewal(id int, now time.Time, until time.Time) (bool, error) // Reserva la renovación frente a ejecuciones concurrentes
 }
@@ -3,6 +3,8 @@ package infrastructure
 import (
 	"backend-go/features/clubs/domain"
 	"backend-go/shared/database"
+	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -16,6 +18,11 @@ func NewClubMembershipRepository(db *gorm.DB) domain.ClubMembershipRepository {
 	return &ClubMembershipRepositoryImpl{db: db}
 }
 
+// WithContext devuelve una copia del repositorio cuyas consultas usan ctx
+func (r *ClubMembershipRepositoryImpl) WithContext(ctx context.Context) domain.ClubMembershipRepository {
+	return &ClubMembershipRepositoryImpl{db: r.db.WithContext(ctx)}
+}
+
 // FindByID obtiene una membresía por ID
 func (r *ClubMembershipRepositoryImpl) FindByID(id int) (*domain.ClubMembership, error) {
 	var model database.ClubMembership
@@ -126,6 +133,43 @@ func (r *ClubMembershipRepositoryImpl) Count(clubID int) (int, error) {
 	return int(count), err
 }
 
+// FindDueForRenewal obtiene las membresías activas cuyo cobro ha vencido (NextBillingDate <= now),
+// sin un reintento programado para más adelante ni un cobro pendiente de confirmar
+func (r *ClubMembershipRepositoryImpl) FindDueForRenewal(now time.Time) ([]domain.ClubMembership, error) {
+	var models []database.ClubMembership
+	if err := r.db.
+		Preload("User").
+		Preload("Club").
+		Where("status = ? AND is_active = ?", domain.MembershipStatusActive, true).
+		Where("next_billing_date IS NOT NULL AND next_billing_date <= ?", now).
+		Where("next_retry_at IS NULL OR next_retry_at <= ?", now).
+		Where("pending_payment_id IS NULL").
+		Order("next_billing_date ASC").
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	memberships := make([]domain.ClubMembership, len(models))
+	for i, model := range models {
+		memberships[i] = *MembershipToEntity(&model)
+	}
+
+	return memberships, nil
+}
+
+// ClaimRenewal reserva la renovación de una membresía hasta "until" con un UPDATE condicional:
+// si otra ejecución ya la reservó (next_retry_at en el futuro) no se cobra dos veces
+func (r *ClubMembershipRepositoryImpl) ClaimRenewal(id int, now time.Time, until time.Time) (bool, error) {
+	result := r.db.Model(&database.ClubMembership{}).
+		Where("id = ?", id).
+		Where("next_retry_at IS NULL OR next_retry_at <= ?", now).
+		Update("next_retry_at", until)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
 // MembershipToEntity convierte un modelo GORM a entidad de dominio
 func MembershipToEntity(model *database.ClubMembership) *domain.ClubMembership {
 	membership := &domain.ClubMembership{
@@ -137,16 +181,28 @@ func MembershipToEntity(model *database.ClubMembership) *domain.ClubMembership {
 		EndDate:         model.EndDate,
 		NextBillingDate: model.NextBillingDate,
 		PaymentStatus:   model.PaymentStatus,
+		RenewalAttempts: model.RenewalAttempts,
+		NextRetryAt:     model.NextRetryAt,
+		PastDueSince:    model.PastDueSince,
 		IsActive:        model.IsActive,
 		CreatedAt:       model.CreatedAt,
 		UpdatedAt:       model.UpdatedAt,
 	}
+	if model.LastPaymentID != nil {
+		lastPaymentID := int(*model.LastPaymentID)
+		membership.LastPaymentID = &lastPaymentID
+	}
+	if model.PendingPaymentID != nil {
+		pendingPaymentID := int(*model.PendingPaymentID)
+		membership.PendingPaymentID = &pendingPaymentID
+	}
 
 	// Relaciones expandidas
 	if model.User.ID != (uuid.UUID{}) {
 		membership.UserName = model.User.FullName
 		membership.UserEmail = model.User.Email
 		membership.UserSlug = model.User.Slug
+		membership.StripeCustomerID = model.User.StripeCustomerID
 	}
 	if model.Club.ID != 0 {
 		membership.ClubName = model.Club.Name
@@ -158,7 +214,7 @@ func MembershipToEntity(model *database.ClubMembership) *domain.ClubMembership {
 
 // MembershipFromEntity convierte una entidad de dominio a modelo GORM
 func MembershipFromEntity(membership *domain.ClubMembership) *database.ClubMembership {
-	return &database.ClubMembership{
+	model := &database.ClubMembership{
 		ID:              uint(membership.ID),
 		ClubID:          uint(membership.ClubID),
 		UserID:          membership.UserID,
@@ -167,6 +223,19 @@ func MembershipFromEntity(membership *domain.ClubMembership) *database.ClubMembe
 		EndDate:         membership.EndDate,
 		NextBillingDate: membership.NextBillingDate,
 		PaymentStatus:   membership.PaymentStatus,
+		RenewalAttempts: membership.RenewalAttempts,
+		NextRetryAt:     membership.NextRetryAt,
+		PastDueSince:    membership.PastDueSince,
 		IsActive:        membership.IsActive,
+		CreatedAt:       membership.CreatedAt,
+	}
+	if membership.LastPaymentID != nil {
+		lastPaymentID := uint(*membership.LastPaymentID)
+		model.LastPaymentID = &lastPaymentID
+	}
+	if membership.PendingPaymentID != nil {
+		pendingPaymentID := uint(*membership.PendingPaymentID)
+		model.PendingPaymentID = &pendingPaymentID
 	}
+	return model
 }
@@ -4,6 +4,7 @@ import (
 	"backend-go/features/clubs/domain"
 	"backend-go/shared/database"
 	"backend-go/shared/pagination"
+	"context"
 	"errors"
 
 	"gorm.io/gorm"
@@ -17,6 +18,11 @@ func NewClubRepository(db *gorm.DB) domain.ClubRepository {
 	return &ClubRepositoryImpl{db: db}
 }
 
+// WithContext devuelve una copia del repositorio cuyas consultas usan ctx
+func (r *ClubRepositoryImpl) WithContext(ctx context.Context) domain.ClubRepository {
+	return &ClubRepositoryImpl{db: r.db.WithContext(ctx)}
+}
+
 // FindAll obtiene todos los clubs
 func (r *ClubRepositoryImpl) FindAll() ([]domain.Club, error) {
 	var models []database.Club
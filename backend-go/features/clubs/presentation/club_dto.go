@@ -52,7 +52,7 @@ type AddMemberRequest struct {
 
 // RenewMembershipRequest representa los datos para renovar una membresía
 type RenewMembershipRequest struct {
-	CustomerID string `json:"customerId" validate:"required"`
+	CustomerID string `json:"customerId"` // Opcional: por defecto el cliente guardado del usuario
 }
 
 // UpdateBillingDateRequest representa los datos para actualizar fecha de cobro
@@ -62,22 +62,26 @@ type UpdateBillingDateRequest struct {
 
 // ClubMembershipResponse representa la respuesta de una membresía
 type ClubMembershipResponse struct {
-	ID              int        `json:"id"`
-	ClubID          int        `json:"clubId"`
-	ClubSlug        string     `json:"clubSlug"`
-	ClubName        string     `json:"clubName"`
-	UserID          string     `json:"userId"` // UUID como string
-	UserSlug        string     `json:"userSlug"`
-	UserName        string     `json:"userName"`
-	UserEmail       string     `json:"userEmail"`
-	Status          string     `json:"status"`
-	StartDate       time.Time  `json:"startDate"`
-	EndDate         *time.Time `json:"endDate"`
-	NextBillingDate *time.Time `json:"nextBillingDate,omitempty"`
-	PaymentStatus   string     `json:"paymentStatus"`
-	IsActive        bool       `json:"isActive"`
-	CreatedAt       time.Time  `json:"createdAt"`
-	UpdatedAt       time.Time  `json:"updatedAt"`
+	ID               int        `json:"id"`
+	ClubID           int        `json:"clubId"`
+	ClubSlug         string     `json:"clubSlug"`
+	ClubName         string     `json:"clubName"`
+	UserID           string     `json:"userId"` // UUID como string
+	UserSlug         string     `json:"userSlug"`
+	UserName         string     `json:"userName"`
+	UserEmail        string     `json:"userEmail"`
+	Status           string     `json:"status"`
+	StartDate        time.Time  `json:"startDate"`
+	EndDate          *time.Time `json:"endDate"`
+	NextBillingDate  *time.Time `json:"nextBillingDate,omitempty"`
+	PaymentStatus    string     `json:"paymentStatus"`
+	RenewalAttempts  int        `json:"renewalAttempts"`
+	PendingPaymentID *int       `json:"pendingPaymentId,omitempty"`
+	NextRetryAt      *time.Time `json:"nextRetryAt,omitempty"`
+	PastDueSince     *time.Time `json:"pastDueSince,omitempty"`
+	IsActive         bool       `json:"isActive"`
+	CreatedAt        time.Time  `json:"createdAt"`
+	UpdatedAt        time.Time  `json:"updatedAt"`
 }
 
 // MessageResponse representa una respuesta simple con mensaje
@@ -3,11 +3,14 @@ package presentation
 import (
 	"backend-go/features/clubs/application"
 	"backend-go/features/clubs/domain"
+	"backend-go/shared/middleware"
 	"backend-go/shared/pagination"
+	"errors"
 	"net/url"
 	"strconv"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 )
 
 type ClubHandler struct {
@@ -360,22 +363,26 @@ func ClubToResponse(club *domain.Club) ClubResponse {
 // MembershipToResponse convierte una entidad de membresía a DTO
 func MembershipToResponse(membership *domain.ClubMembership) ClubMembershipResponse {
 	return ClubMembershipResponse{
-		ID:              membership.ID,
-		ClubID:          membership.ClubID,
-		ClubSlug:        membership.ClubSlug,
-		ClubName:        membership.ClubName,
-		UserID:          membership.UserID.String(),
-		UserSlug:        membership.UserSlug,
-		UserName:        membership.UserName,
-		UserEmail:       membership.UserEmail,
-		Status:          membership.Status,
-		StartDate:       membership.StartDate,
-		EndDate:         membership.EndDate,
-		NextBillingDate: membership.NextBillingDate,
-		PaymentStatus:   membership.PaymentStatus,
-		IsActive:        membership.IsActive,
-		CreatedAt:       membership.CreatedAt,
-		UpdatedAt:       membership.UpdatedAt,
+		ID:               membership.ID,
+		ClubID:           membership.ClubID,
+		ClubSlug:         membership.ClubSlug,
+		ClubName:         membership.ClubName,
+		UserID:           membership.UserID.String(),
+		UserSlug:         membership.UserSlug,
+		UserName:         membership.UserName,
+		UserEmail:        membership.UserEmail,
+		Status:           membership.Status,
+		StartDate:        membership.StartDate,
+		EndDate:          membership.EndDate,
+		NextBillingDate:  membership.NextBillingDate,
+		PaymentStatus:    membership.PaymentStatus,
+		RenewalAttempts:  membership.RenewalAttempts,
+		PendingPaymentID: membership.PendingPaymentID,
+		NextRetryAt:      membership.NextRetryAt,
+		PastDueSince:     membership.PastDueSince,
+		IsActive:         membership.IsActive,
+		CreatedAt:        membership.CreatedAt,
+		UpdatedAt:        membership.UpdatedAt,
 	}
 }
 
@@ -385,8 +392,11 @@ func MembershipToResponse(membership *domain.ClubMembership) ClubMembershipRespo
 // @Accept json
 // @Produce json
 // @Param id path int true "Membership ID"
-// @Param request body RenewMembershipRequest true "Datos del pago"
+// @Param Idempotency-Key header string false "Clave para reintentar sin cobrar dos veces"
+// @Param request body RenewMembershipRequest false "Cliente de pago (opcional)"
 // @Success 200 {object} MessageResponse
+// @Success 202 {object} MessageResponse "Cobro pendiente de confirmar (webhook)"
+// @Failure 403 {object} map[string]string "Solo el titular o ADMIN/GESTOR"
 // @Router /clubs/memberships/{id}/renew [post]
 func (h *ClubHandler) RenewMembership(c *fiber.Ctx) error {
 	membershipID, err := c.ParamsInt("id")
@@ -395,18 +405,44 @@ func (h *ClubHandler) RenewMembership(c *fiber.Ctx) error {
 	}
 
 	var req RenewMembershipRequest
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Datos de renovación inválidos"})
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": "Datos de renovación inválidos"})
+		}
 	}
 
-	if req.CustomerID == "" {
-		return c.Status(400).JSON(fiber.Map{"error": "CustomerID es requerido"})
+	userID, ok := c.Locals("userID").(uuid.UUID)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"error": "No autenticado"})
 	}
+	roleName, _ := c.Locals("roleName").(string)
+	isStaff := roleName == "ADMIN" || roleName == "GESTOR"
 
-	if err := h.renewalService.RenewMembership(membershipID, req.CustomerID); err != nil {
+	membership, err := h.renewalService.RenewMembership(membershipID, userID, isStaff, req.CustomerID, middleware.IdempotencyKey(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, application.ErrNotMembershipOwner):
+			return c.Status(403).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, application.ErrMembershipNotFound):
+			return c.Status(404).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, application.ErrMembershipInactive), errors.Is(err, application.ErrRenewalPending):
+			return c.Status(409).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, application.ErrNoPaymentMethod):
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, application.ErrPaymentFailed):
+			return c.Status(402).JSON(fiber.Map{"error": err.Error()})
+		}
 		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
 	}
 
+	if membership.PendingPaymentID != nil {
+		return c.Status(202).JSON(fiber.Map{
+			"message":      "Cobro pendiente de confirmar por el proveedor de pagos",
+			"membershipID": membershipID,
+			"paymentID":    *membership.PendingPaymentID,
+		})
+	}
+
 	return c.JSON(fiber.Map{
 		"message":      "Membresía renovada exitosamente",
 		"membershipID": membershipID,
@@ -1,6 +1,7 @@
 package presentation
 
 import (
+	"backend-go/shared/idempotency"
 	"backend-go/shared/middleware"
 	"backend-go/shared/security"
 
@@ -11,10 +12,10 @@ import (
 // CLUB ROUTES
 // Público: GET / (listar clubs), GET /:slug
 // Admin: POST /, PUT /:slug, DELETE /:slug, POST /:slug/members, DELETE memberships
-// Autenticado: Otros endpoints de membresías
+// Autenticado: Otros endpoints de membresías (renew admite la cabecera Idempotency-Key)
 // ======================================================================================
 
-func RegisterRoutes(app *fiber.App, handler *ClubHandler, jwtService security.JWTService) {
+func RegisterRoutes(app *fiber.App, handler *ClubHandler, jwtService security.JWTService, idempotencyStore *idempotency.Store) {
 	// Grupo base
 	clubs := app.Group("/api/clubs")
 
@@ -31,7 +32,7 @@ func RegisterRoutes(app *fiber.App, handler *ClubHandler, jwtService security.JW
 
 	// Rutas protegidas - Autenticado (membresías)
 	clubs.Delete("/memberships/:id", middleware.JWTMiddleware(jwtService), handler.RemoveMember)
-	clubs.Post("/memberships/:id/renew", middleware.JWTMiddleware(jwtService), handler.RenewMembership)
+	clubs.Post("/memberships/:id/renew", middleware.JWTMiddleware(jwtService), middleware.Idempotency(idempotencyStore), handler.RenewMembership)
 	clubs.Post("/memberships/:id/suspend", middleware.JWTMiddleware(jwtService), handler.SuspendMembership)
 	clubs.Post("/memberships/:id/resume", middleware.JWTMiddleware(jwtService), handler.ResumeMembership)
 	clubs.Post("/memberships/:id/cancel", middleware.JWTMiddleware(jwtService), handler.CancelMembership)
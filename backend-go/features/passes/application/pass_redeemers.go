@@ -0,0 +1,22 @@
+package application
+
+import (
+	bookingApp "backend-go/features/bookings/application"
+	classApp "backend-go/features/classes/application"
+	paymentApp "backend-go/features/payments/application"
+)
+
+// NewBookingPassRedeemer crea el adaptador de bonos para el módulo bookings
+func NewBookingPassRedeemer(service *PassService) bookingApp.PassRedeemer {
+	return service
+}
+
+// NewEnrollmentPassRedeemer crea el adaptador de bonos para las inscripciones a clases
+func NewEnrollmentPassRedeemer(service *PassService) classApp.PassRedeemer {
+	return service
+}
+
+// NewPassPaymentHandler crea el adaptador que aplica los webhooks de cobro de bonos
+func NewPassPaymentHandler(service *PassService) paymentApp.PassPaymentHandler {
+	return service
+}
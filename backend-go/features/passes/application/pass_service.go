@@ -0,0 +1,183 @@
+package application
+
+import (
+	"backend-go/features/passes/domain"
+	paymentApp "backend-go/features/payments/application"
+	paymentDomain "backend-go/features/payments/domain"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PassService maneja el catálogo de bonos, su compra y el consumo de créditos
+type PassService struct {
+	repo           domain.PassRepository
+	paymentService *paymentApp.PaymentService
+}
+
+// NewPassService crea una nueva instancia del servicio
+func NewPassService(repo domain.PassRepository, paymentService *paymentApp.PaymentService) *PassService {
+	return &PassService{
+		repo:           repo,
+		paymentService: paymentService,
+	}
+}
+
+// GetProducts obtiene el catálogo de bonos (solo los que están a la venta si onlyActive)
+func (s *PassService) GetProducts(onlyActive bool) ([]domain.PassProduct, error) {
+	return s.repo.FindProducts(onlyActive)
+}
+
+// GetProduct obtiene un bono del catálogo
+func (s *PassService) GetProduct(id int) (*domain.PassProduct, error) {
+	return s.repo.FindProductByID(id)
+}
+
+// CreateProduct añade un bono al catálogo
+func (s *PassService) CreateProduct(product *domain.PassProduct) error {
+	if err := product.Validate(); err != nil {
+		return err
+	}
+	return s.repo.CreateProduct(product)
+}
+
+// UpdateProduct modifica un bono del catálogo. Los bonos ya comprados conservan sus condiciones.
+func (s *PassService) UpdateProduct(product *domain.PassProduct) error {
+	if err := product.Validate(); err != nil {
+		return err
+	}
+	return s.repo.UpdateProduct(product)
+}
+
+// Purchase compra un bono del catálogo para userID. El bono se activa al completarse el cobro;
+// si el proveedor deja el cobro pendiente (3DS, SEPA) se activa al recibir el webhook.
+func (s *PassService) Purchase(productID int, userID uuid.UUID, paymentMethod, customerID, idempotencyKey string) (*domain.Pass, *paymentDomain.Payment, error) {
+	product, err := s.repo.FindProductByID(productID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !product.IsActive {
+		return nil, nil, domain.ErrProductInactive
+	}
+
+	pass := product.NewPass(userID, time.Now())
+	if product.PriceCents == 0 {
+		pass.Status = domain.PassStatusActive
+	}
+	if err := s.repo.Create(pass); err != nil {
+		return nil, nil, err
+	}
+	if product.PriceCents == 0 {
+		return pass, nil, nil
+	}
+
+	payment, err := s.paymentService.ProcessPassPayment(userID, uint(pass.ID), product.PriceCents, paymentMethod, customerID, idempotencyKey)
+	if err != nil {
+		return nil, nil, errors.Join(err, s.repo.UpdateStatus(pass.ID, domain.PassStatusCancelled))
+	}
+
+	if payment.Status == paymentDomain.StatusCompleted {
+		if err := s.repo.UpdateStatus(pass.ID, domain.PassStatusActive); err != nil {
+			return nil, nil, err
+		}
+		pass.Status = domain.PassStatusActive
+	}
+
+	return pass, payment, nil
+}
+
+// GetUserPasses obtiene los bonos de un usuario
+func (s *PassService) GetUserPasses(userID uuid.UUID) ([]domain.Pass, error) {
+	return s.repo.FindByUser(userID)
+}
+
+// GetPass obtiene un bono con sus consumos. Solo el titular o ADMIN/GESTOR.
+func (s *PassService) GetPass(id int, userID uuid.UUID, isStaff bool) (*domain.Pass, []domain.Redemption, error) {
+	pass, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !isStaff && pass.UserID != userID {
+		return nil, nil, domain.ErrPassNotOwned
+	}
+
+	redemptions, err := s.repo.FindRedemptions(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pass, redemptions, nil
+}
+
+// ConfirmPassPayment activa el bono cuyo cobro pendiente se completó (webhook)
+func (s *PassService) ConfirmPassPayment(passID int) error {
+	return s.settlePendingPass(passID, domain.PassStatusActive)
+}
+
+// FailPassPayment cancela el bono cuyo cobro pendiente falló (webhook)
+func (s *PassService) FailPassPayment(passID int) error {
+	return s.settlePendingPass(passID, domain.PassStatusCancelled)
+}
+
+// settlePendingPass aplica el resultado del cobro. Es idempotente: solo cambia bonos pendientes.
+func (s *PassService) settlePendingPass(passID int, status string) error {
+	pass, err := s.repo.FindByID(passID)
+	if err != nil {
+		return err
+	}
+	if pass.Status != domain.PassStatusPendingPayment {
+		return nil
+	}
+	return s.repo.UpdateStatus(passID, status)
+}
+
+// RedeemBooking paga una reserva de pista con un bono del usuario (1 crédito por hora o fracción)
+func (s *PassService) RedeemBooking(bookingID int, userID uuid.UUID, startTime, endTime time.Time) (bool, error) {
+	return s.redeem(domain.Usage{
+		UserID:       userID,
+		ResourceType: domain.ResourcePista,
+		Credits:      domain.PistaCredits(startTime, endTime),
+		SessionStart: startTime,
+		BookingID:    &bookingID,
+	})
+}
+
+// RedeemEnrollment paga una inscripción a clase con un bono del usuario (1 crédito)
+func (s *PassService) RedeemEnrollment(enrollmentID int, userID uuid.UUID, classStartTime time.Time) (bool, error) {
+	return s.redeem(domain.Usage{
+		UserID:       userID,
+		ResourceType: domain.ResourceClass,
+		Credits:      1,
+		SessionStart: classStartTime,
+		EnrollmentID: &enrollmentID,
+	})
+}
+
+// RestoreBooking devuelve al bono los créditos de una reserva cancelada
+func (s *PassService) RestoreBooking(bookingID int) (bool, error) {
+	redemption, err := s.repo.RestoreBooking(bookingID, time.Now())
+	return redemption != nil, err
+}
+
+// RestoreEnrollment devuelve al bono el crédito de una inscripción cancelada
+func (s *PassService) RestoreEnrollment(enrollmentID int) (bool, error) {
+	redemption, err := s.repo.RestoreEnrollment(enrollmentID, time.Now())
+	return redemption != nil, err
+}
+
+// redeem consume los créditos del uso. Devuelve false si el usuario no tiene bono aplicable.
+func (s *PassService) redeem(usage domain.Usage) (bool, error) {
+	if usage.Credits <= 0 {
+		return false, domain.ErrInvalidCredits
+	}
+
+	_, err := s.repo.Consume(usage, time.Now())
+	if errors.Is(err, domain.ErrNoUsablePass) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("error al consumir el bono: %w", err)
+	}
+	return true, nil
+}
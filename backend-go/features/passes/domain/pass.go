@@ -0,0 +1,190 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Errores de dominio
+var (
+	ErrProductNotFound     = errors.New("bono no encontrado en el catálogo")
+	ErrProductInactive     = errors.New("el bono ya no está a la venta")
+	ErrPassNotFound        = errors.New("bono no encontrado")
+	ErrNoUsablePass        = errors.New("no hay ningún bono con créditos suficientes para este recurso")
+	ErrInvalidCredits      = errors.New("los créditos deben ser mayores que cero")
+	ErrInvalidValidity     = errors.New("los días de validez deben ser mayores que cero")
+	ErrInvalidPrice        = errors.New("el precio no puede ser negativo")
+	ErrInvalidResourceType = errors.New("tipo de recurso inválido (CLASS o PISTA)")
+	ErrPassNotOwned        = errors.New("el bono pertenece a otro usuario")
+)
+
+// Tipos de recurso a los que se aplica un bono
+const (
+	ResourceClass = "CLASS" // 1 crédito por inscripción a clase
+	ResourcePista = "PISTA" // 1 crédito por hora de reserva de pista (las fracciones cuentan como hora)
+)
+
+// Estados del bono comprado. Un bono ACTIVE caducado no se puede usar (ver IsUsable).
+const (
+	PassStatusPendingPayment = "PENDING_PAYMENT" // Cobro pendiente de confirmar por webhook
+	PassStatusActive         = "ACTIVE"
+	PassStatusCancelled      = "CANCELLED" // El cobro falló
+)
+
+// PassProduct es un bono del catálogo (p. ej. "Bono 10 clases" o "10 horas de pista")
+type PassProduct struct {
+	ID            int
+	Name          string
+	Description   *string
+	Credits       int
+	ValidityDays  int // Días de validez desde la compra
+	PriceCents    int
+	ResourceTypes []string
+	IsActive      bool
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// Validate verifica los datos del producto y normaliza los tipos de recurso
+func (p *PassProduct) Validate() error {
+	if p.Credits <= 0 {
+		return ErrInvalidCredits
+	}
+	if p.ValidityDays <= 0 {
+		return ErrInvalidValidity
+	}
+	if p.PriceCents < 0 {
+		return ErrInvalidPrice
+	}
+	resources, err := NormalizeResourceTypes(p.ResourceTypes)
+	if err != nil {
+		return err
+	}
+	p.ResourceTypes = resources
+	return nil
+}
+
+// NewPass crea el bono de un usuario a partir del producto, pendiente de pago
+func (p *PassProduct) NewPass(userID uuid.UUID, now time.Time) *Pass {
+	return &Pass{
+		UserID:           userID,
+		ProductID:        p.ID,
+		Name:             p.Name,
+		ResourceTypes:    p.ResourceTypes,
+		CreditsTotal:     p.Credits,
+		CreditsRemaining: p.Credits,
+		PriceCents:       p.PriceCents,
+		Status:           PassStatusPendingPayment,
+		ExpiresAt:        now.AddDate(0, 0, p.ValidityDays),
+	}
+}
+
+// Pass es un bono comprado por un usuario
+type Pass struct {
+	ID               int
+	UserID           uuid.UUID
+	ProductID        int
+	Name             string
+	ResourceTypes    []string
+	CreditsTotal     int
+	CreditsRemaining int
+	PriceCents       int
+	Status           string
+	ExpiresAt        time.Time
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+// Covers indica si el bono se aplica al tipo de recurso
+func (p *Pass) Covers(resourceType string) bool {
+	for _, resource := range p.ResourceTypes {
+		if resource == resourceType {
+			return true
+		}
+	}
+	return false
+}
+
+// IsUsable indica si el bono está pagado y vigente en el instante at
+func (p *Pass) IsUsable(at time.Time) bool {
+	return p.Status == PassStatusActive && at.Before(p.ExpiresAt)
+}
+
+// Redemption es el consumo de créditos de un bono por una reserva o inscripción
+type Redemption struct {
+	ID           int
+	PassID       int
+	ResourceType string
+	BookingID    *int
+	EnrollmentID *int
+	Credits      int
+	RestoredAt   *time.Time
+	CreatedAt    time.Time
+}
+
+// Usage es el uso de un bono que se quiere cobrar: recurso, créditos necesarios y fecha de la
+// sesión (el bono debe estar vigente en ese momento)
+type Usage struct {
+	UserID       uuid.UUID
+	ResourceType string
+	Credits      int
+	SessionStart time.Time
+	BookingID    *int
+	EnrollmentID *int
+}
+
+// PistaCredits calcula los créditos de una reserva de pista: uno por hora o fracción
+func PistaCredits(startTime, endTime time.Time) int {
+	minutes := int(endTime.Sub(startTime).Minutes())
+	if minutes <= 0 {
+		return 0
+	}
+	return (minutes + 59) / 60
+}
+
+// NormalizeResourceTypes valida y deduplica los tipos de recurso (al menos uno)
+func NormalizeResourceTypes(resources []string) ([]string, error) {
+	var normalized []string
+	seen := map[string]bool{}
+	for _, resource := range resources {
+		resource = strings.ToUpper(strings.TrimSpace(resource))
+		if resource != ResourceClass && resource != ResourcePista {
+			return nil, ErrInvalidResourceType
+		}
+		if !seen[resource] {
+			seen[resource] = true
+			normalized = append(normalized, resource)
+		}
+	}
+	if len(normalized) == 0 {
+		return nil, ErrInvalidResourceType
+	}
+	return normalized, nil
+}
+
+// PassRepository define las operaciones de persistencia de bonos
+type PassRepository interface {
+	// Catálogo
+	FindProducts(onlyActive bool) ([]PassProduct, error)
+	FindProductByID(id int) (*PassProduct, error)
+	CreateProduct(product *PassProduct) error
+	UpdateProduct(product *PassProduct) error
+
+	// Bonos comprados
+	Create(pass *Pass) error
+	FindByID(id int) (*Pass, error)
+	FindByUser(userID uuid.UUID) ([]Pass, error)
+	UpdateStatus(id int, status string) error
+	FindRedemptions(passID int) ([]Redemption, error)
+
+	// Consume descuenta los créditos del bono utilizable que antes caduca (bloqueándolo) y
+	// registra el consumo. Devuelve ErrNoUsablePass si ningún bono cubre el uso.
+	Consume(usage Usage, now time.Time) (*Redemption, error)
+	// RestoreBooking y RestoreEnrollment devuelven al bono los créditos del consumo vigente de la
+	// reserva o inscripción. Devuelven nil si no había consumo (no se pagó con bono) o ya se restauró.
+	RestoreBooking(bookingID int, now time.Time) (*Redemption, error)
+	RestoreEnrollment(enrollmentID int, now time.Time) (*Redemption, error)
+}
@@ -0,0 +1,103 @@
+package infrastructure
+
+import (
+	"backend-go/features/passes/domain"
+	"backend-go/shared/database"
+	"strings"
+)
+
+// productToEntity convierte database.PassProduct a domain.PassProduct
+func productToEntity(m *database.PassProduct) *domain.PassProduct {
+	return &domain.PassProduct{
+		ID:            int(m.ID),
+		Name:          m.Name,
+		Description:   m.Description,
+		Credits:       m.Credits,
+		ValidityDays:  m.ValidityDays,
+		PriceCents:    m.PriceCents,
+		ResourceTypes: parseResourceTypes(m.ResourceTypes),
+		IsActive:      m.IsActive,
+		CreatedAt:     m.CreatedAt,
+		UpdatedAt:     m.UpdatedAt,
+	}
+}
+
+// productFromEntity convierte domain.PassProduct a database.PassProduct
+func productFromEntity(product *domain.PassProduct) *database.PassProduct {
+	return &database.PassProduct{
+		ID:            uint(product.ID),
+		Name:          product.Name,
+		Description:   product.Description,
+		Credits:       product.Credits,
+		ValidityDays:  product.ValidityDays,
+		PriceCents:    product.PriceCents,
+		ResourceTypes: strings.Join(product.ResourceTypes, ","),
+		IsActive:      product.IsActive,
+	}
+}
+
+// passToEntity convierte database.Pass a domain.Pass
+func passToEntity(m *database.Pass) *domain.Pass {
+	return &domain.Pass{
+		ID:               int(m.ID),
+		UserID:           m.UserID,
+		ProductID:        int(m.ProductID),
+		Name:             m.Name,
+		ResourceTypes:    parseResourceTypes(m.ResourceTypes),
+		CreditsTotal:     m.CreditsTotal,
+		CreditsRemaining: m.CreditsRemaining,
+		PriceCents:       m.PriceCents,
+		Status:           m.Status,
+		ExpiresAt:        m.ExpiresAt,
+		CreatedAt:        m.CreatedAt,
+		UpdatedAt:        m.UpdatedAt,
+	}
+}
+
+// passFromEntity convierte domain.Pass a database.Pass
+func passFromEntity(pass *domain.Pass) *database.Pass {
+	return &database.Pass{
+		ID:               uint(pass.ID),
+		UserID:           pass.UserID,
+		ProductID:        uint(pass.ProductID),
+		Name:             pass.Name,
+		ResourceTypes:    strings.Join(pass.ResourceTypes, ","),
+		CreditsTotal:     pass.CreditsTotal,
+		CreditsRemaining: pass.CreditsRemaining,
+		PriceCents:       pass.PriceCents,
+		Status:           pass.Status,
+		ExpiresAt:        pass.ExpiresAt,
+	}
+}
+
+// redemptionToEntity convierte database.PassRedemption a domain.Redemption
+func redemptionToEntity(m *database.PassRedemption) *domain.Redemption {
+	redemption := &domain.Redemption{
+		ID:           int(m.ID),
+		PassID:       int(m.PassID),
+		ResourceType: m.ResourceType,
+		Credits:      m.Credits,
+		RestoredAt:   m.RestoredAt,
+		CreatedAt:    m.CreatedAt,
+	}
+	if m.BookingID != nil {
+		bookingID := int(*m.BookingID)
+		redemption.BookingID = &bookingID
+	}
+	if m.EnrollmentID != nil {
+		enrollmentID := int(*m.EnrollmentID)
+		redemption.EnrollmentID = &enrollmentID
+	}
+	return redemption
+}
+
+// parseResourceTypes convierte "CLASS,PISTA" a []string
+func parseResourceTypes(value string) []string {
+	resources := []string{}
+	for _, raw := range strings.Split(value, ",") {
+		if resource := strings.TrimSpace(raw); resource != "" {
+			resources = append(resources, resource)
+		}
+	}
+	return resources
+}
@@ -0,0 +1,259 @@
+package infrastructure
+
+import (
+	"backend-go/features/passes/domain"
+	"backend-go/shared/database"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PassRepositoryImpl implementa domain.PassRepository usando GORM
+type PassRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewPassRepository crea una nueva instancia del repositorio
+func NewPassRepository(db *gorm.DB) domain.PassRepository {
+	return &PassRepositoryImpl{db: db}
+}
+
+// FindProducts obtiene el catálogo de bonos (solo los que están a la venta si onlyActive)
+func (r *PassRepositoryImpl) FindProducts(onlyActive bool) ([]domain.PassProduct, error) {
+	query := r.db.Order("price_cents ASC, id ASC")
+	if onlyActive {
+		query = query.Where("is_active = ?", true)
+	}
+
+	var models []database.PassProduct
+	if err := query.Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	products := make([]domain.PassProduct, len(models))
+	for i := range models {
+		products[i] = *productToEntity(&models[i])
+	}
+	return products, nil
+}
+
+// FindProductByID obtiene un bono del catálogo por ID
+func (r *PassRepositoryImpl) FindProductByID(id int) (*domain.PassProduct, error) {
+	var model database.PassProduct
+	if err := r.db.First(&model, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrProductNotFound
+		}
+		return nil, err
+	}
+	return productToEntity(&model), nil
+}
+
+// CreateProduct crea un bono en el catálogo
+func (r *PassRepositoryImpl) CreateProduct(product *domain.PassProduct) error {
+	model := productFromEntity(product)
+	if err := r.db.Create(model).Error; err != nil {
+		return err
+	}
+
+	product.ID = int(model.ID)
+	product.CreatedAt = model.CreatedAt
+	product.UpdatedAt = model.UpdatedAt
+	return nil
+}
+
+// UpdateProduct actualiza un bono del catálogo (los bonos ya comprados no cambian)
+func (r *PassRepositoryImpl) UpdateProduct(product *domain.PassProduct) error {
+	model := productFromEntity(product)
+	result := r.db.Model(model).Select("*").Omit("created_at").Updates(model)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrProductNotFound
+	}
+
+	product.UpdatedAt = model.UpdatedAt
+	return nil
+}
+
+// Create guarda un bono comprado
+func (r *PassRepositoryImpl) Create(pass *domain.Pass) error {
+	model := passFromEntity(pass)
+	if err := r.db.Create(model).Error; err != nil {
+		return err
+	}
+
+	pass.ID = int(model.ID)
+	pass.CreatedAt = model.CreatedAt
+	pass.UpdatedAt = model.UpdatedAt
+	return nil
+}
+
+// FindByID obtiene un bono comprado por ID
+func (r *PassRepositoryImpl) FindByID(id int) (*domain.Pass, error) {
+	var model database.Pass
+	if err := r.db.First(&model, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrPassNotFound
+		}
+		return nil, err
+	}
+	return passToEntity(&model), nil
+}
+
+// FindByUser obtiene los bonos de un usuario (los que antes caducan primero)
+func (r *PassRepositoryImpl) FindByUser(userID uuid.UUID) ([]domain.Pass, error) {
+	var models []database.Pass
+	if err := r.db.Where("user_id = ?", userID).Order("expires_at ASC, id ASC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	passes := make([]domain.Pass, len(models))
+	for i := range models {
+		passes[i] = *passToEntity(&models[i])
+	}
+	return passes, nil
+}
+
+// UpdateStatus cambia el estado de un bono (activación tras el cobro o cancelación si falla)
+func (r *PassRepositoryImpl) UpdateStatus(id int, status string) error {
+	result := r.db.Model(&database.Pass{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":     status,
+		"updated_at": time.Now(),
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrPassNotFound
+	}
+	return nil
+}
+
+// FindRedemptions obtiene los consumos de un bono (más recientes primero)
+func (r *PassRepositoryImpl) FindRedemptions(passID int) ([]domain.Redemption, error) {
+	var models []database.PassRedemption
+	if err := r.db.Where("pass_id = ?", passID).Order("created_at DESC, id DESC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	redemptions := make([]domain.Redemption, len(models))
+	for i := range models {
+		redemptions[i] = *redemptionToEntity(&models[i])
+	}
+	return redemptions, nil
+}
+
+// Consume descuenta los créditos del bono utilizable que antes caduca. El bono queda bloqueado
+// (SELECT ... FOR UPDATE) hasta el commit, así que dos consumos simultáneos nunca gastan el
+// mismo crédito; la BD rechaza además un saldo de créditos negativo.
+func (r *PassRepositoryImpl) Consume(usage domain.Usage, now time.Time) (*domain.Redemption, error) {
+	var redemption *domain.Redemption
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var candidates []database.Pass
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ? AND status = ?", usage.UserID, domain.PassStatusActive).
+			Where("expires_at > ? AND expires_at > ?", now, usage.SessionStart).
+			Where("credits_remaining >= ?", usage.Credits).
+			Order("expires_at ASC, id ASC").
+			Find(&candidates).Error; err != nil {
+			return err
+		}
+
+		var selected *database.Pass
+		for i := range candidates {
+			if passToEntity(&candidates[i]).Covers(usage.ResourceType) {
+				selected = &candidates[i]
+				break
+			}
+		}
+		if selected == nil {
+			return domain.ErrNoUsablePass
+		}
+
+		if err := tx.Model(selected).Updates(map[string]interface{}{
+			"credits_remaining": gorm.Expr("credits_remaining - ?", usage.Credits),
+			"updated_at":        now,
+		}).Error; err != nil {
+			return err
+		}
+
+		model := &database.PassRedemption{
+			PassID:       selected.ID,
+			ResourceType: usage.ResourceType,
+			Credits:      usage.Credits,
+			CreatedAt:    now,
+		}
+		if usage.BookingID != nil {
+			bookingID := uint(*usage.BookingID)
+			model.BookingID = &bookingID
+		}
+		if usage.EnrollmentID != nil {
+			enrollmentID := uint(*usage.EnrollmentID)
+			model.EnrollmentID = &enrollmentID
+		}
+		if err := tx.Create(model).Error; err != nil {
+			return err
+		}
+
+		redemption = redemptionToEntity(model)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return redemption, nil
+}
+
+// RestoreBooking devuelve al bono los créditos consumidos por una reserva
+func (r *PassRepositoryImpl) RestoreBooking(bookingID int, now time.Time) (*domain.Redemption, error) {
+	return r.restore("booking_id = ?", bookingID, now)
+}
+
+// RestoreEnrollment devuelve al bono los créditos consumidos por una inscripción
+func (r *PassRepositoryImpl) RestoreEnrollment(enrollmentID int, now time.Time) (*domain.Redemption, error) {
+	return r.restore("enrollment_id = ?", enrollmentID, now)
+}
+
+// restore marca como restaurado el consumo vigente (bloqueándolo) y devuelve los créditos al bono
+func (r *PassRepositoryImpl) restore(condition string, id int, now time.Time) (*domain.Redemption, error) {
+	var redemption *domain.Redemption
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var model database.PassRedemption
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where(condition, id).
+			Where("restored_at IS NULL").
+			First(&model).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		if err := tx.Model(&database.Pass{}).Where("id = ?", model.PassID).Updates(map[string]interface{}{
+			"credits_remaining": gorm.Expr("credits_remaining + ?", model.Credits),
+			"updated_at":        now,
+		}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model).Update("restored_at", now).Error; err != nil {
+			return err
+		}
+
+		model.RestoredAt = &now
+		redemption = redemptionToEntity(&model)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return redemption, nil
+}
@@ -0,0 +1,230 @@
+package presentation
+
+import (
+	"backend-go/features/passes/application"
+	"backend-go/features/passes/domain"
+	paymentDomain "backend-go/features/payments/domain"
+	"backend-go/shared/middleware"
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type PassHandler struct {
+	service *application.PassService
+}
+
+func NewPassHandler(service *application.PassService) *PassHandler {
+	return &PassHandler{service: service}
+}
+
+// GetProducts maneja GET /api/passes/products
+// @Summary Catálogo de bonos a la venta
+// @Description ADMIN/GESTOR pueden incluir los bonos retirados con include_inactive=true.
+// @Tags passes
+// @Produce json
+// @Param include_inactive query bool false "Incluir bonos retirados (ADMIN/GESTOR)"
+// @Success 200 {array} PassProductResponse
+// @Router /api/passes/products [get]
+func (h *PassHandler) GetProducts(c *fiber.Ctx) error {
+	onlyActive := !(isStaff(c) && c.QueryBool("include_inactive"))
+
+	products, err := h.service.GetProducts(onlyActive)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	response := make([]PassProductResponse, len(products))
+	for i := range products {
+		response[i] = ToProductResponse(&products[i])
+	}
+	return c.JSON(response)
+}
+
+// CreateProduct maneja POST /api/passes/products
+// @Summary Crear un bono en el catálogo (ADMIN/GESTOR)
+// @Tags passes
+// @Accept json
+// @Produce json
+// @Param product body PassProductRequest true "Bono"
+// @Success 201 {object} PassProductResponse
+// @Router /api/passes/products [post]
+func (h *PassHandler) CreateProduct(c *fiber.Ctx) error {
+	var req PassProductRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Datos inválidos"})
+	}
+
+	product := ProductRequestToDomain(&req)
+	if err := h.service.CreateProduct(product); err != nil {
+		return passError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(ToProductResponse(product))
+}
+
+// UpdateProduct maneja PUT /api/passes/products/:id
+// @Summary Actualizar un bono del catálogo (ADMIN/GESTOR)
+// @Description Los bonos ya comprados conservan las condiciones con las que se compraron.
+// @Tags passes
+// @Accept json
+// @Produce json
+// @Param id path int true "ID del bono en el catálogo"
+// @Param product body PassProductRequest true "Bono"
+// @Success 200 {object} PassProductResponse
+// @Router /api/passes/products/{id} [put]
+func (h *PassHandler) UpdateProduct(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID inválido"})
+	}
+
+	var req PassProductRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Datos inválidos"})
+	}
+
+	product := ProductRequestToDomain(&req)
+	product.ID = id
+	if err := h.service.UpdateProduct(product); err != nil {
+		return passError(c, err)
+	}
+
+	return c.JSON(ToProductResponse(product))
+}
+
+// Purchase maneja POST /api/passes/purchase
+// @Summary Comprar un bono
+// @Description Con payment_method = "wallet" se paga con el monedero. ADMIN/GESTOR pueden vender un bono a otro usuario (user_id).
+// @Tags passes
+// @Accept json
+// @Produce json
+// @Param Idempotency-Key header string false "Clave para reintentar sin cobrar dos veces"
+// @Param purchase body PurchasePassRequest true "Bono a comprar"
+// @Success 201 {object} PurchaseResponse
+// @Success 202 {object} PurchaseResponse "Cobro pendiente: el bono se activa al confirmarse por webhook"
+// @Failure 402 {object} map[string]string "Saldo insuficiente o cargo rechazado"
+// @Router /api/passes/purchase [post]
+func (h *PassHandler) Purchase(c *fiber.Ctx) error {
+	var req PurchasePassRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Datos inválidos"})
+	}
+
+	userID, ok := c.Locals("userID").(uuid.UUID)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Usuario no autenticado"})
+	}
+	if req.UserID != "" && isStaff(c) {
+		parsed, err := uuid.Parse(req.UserID)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid user ID"})
+		}
+		userID = parsed
+	}
+
+	pass, payment, err := h.service.Purchase(req.ProductID, userID, req.PaymentMethod, req.CustomerID, middleware.IdempotencyKey(c))
+	if err != nil {
+		return passError(c, err)
+	}
+
+	status := fiber.StatusCreated
+	if pass.Status == domain.PassStatusPendingPayment {
+		status = fiber.StatusAccepted
+	}
+	return c.Status(status).JSON(ToPurchaseResponse(pass, payment))
+}
+
+// GetMyPasses maneja GET /api/passes/me
+// @Summary Mis bonos
+// @Tags passes
+// @Produce json
+// @Success 200 {array} PassResponse
+// @Router /api/passes/me [get]
+func (h *PassHandler) GetMyPasses(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uuid.UUID)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Usuario no autenticado"})
+	}
+	return h.userPasses(c, userID)
+}
+
+// GetUserPasses maneja GET /api/passes/users/:user_id
+// @Summary Bonos de un usuario (ADMIN/GESTOR)
+// @Tags passes
+// @Produce json
+// @Param user_id path string true "User ID (UUID)"
+// @Success 200 {array} PassResponse
+// @Router /api/passes/users/{user_id} [get]
+func (h *PassHandler) GetUserPasses(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Params("user_id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid user ID"})
+	}
+	return h.userPasses(c, userID)
+}
+
+// GetPass maneja GET /api/passes/:id
+// @Summary Bono con sus consumos (titular o ADMIN/GESTOR)
+// @Tags passes
+// @Produce json
+// @Param id path int true "ID del bono"
+// @Success 200 {object} PassDetailResponse
+// @Router /api/passes/{id} [get]
+func (h *PassHandler) GetPass(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID inválido"})
+	}
+
+	userID, _ := c.Locals("userID").(uuid.UUID)
+	pass, redemptions, err := h.service.GetPass(id, userID, isStaff(c))
+	if err != nil {
+		return passError(c, err)
+	}
+
+	return c.JSON(ToPassDetailResponse(pass, redemptions))
+}
+
+func (h *PassHandler) userPasses(c *fiber.Ctx, userID uuid.UUID) error {
+	passes, err := h.service.GetUserPasses(userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	response := make([]PassResponse, len(passes))
+	for i := range passes {
+		response[i] = ToPassResponse(&passes[i])
+	}
+	return c.JSON(response)
+}
+
+// passError traduce los errores de bonos (y del cobro de la compra) a códigos HTTP
+func passError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrProductNotFound), errors.Is(err, domain.ErrPassNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrPassNotOwned):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrProductInactive),
+		errors.Is(err, domain.ErrInvalidCredits),
+		errors.Is(err, domain.ErrInvalidValidity),
+		errors.Is(err, domain.ErrInvalidPrice),
+		errors.Is(err, domain.ErrInvalidResourceType),
+		errors.Is(err, paymentDomain.ErrInvalidPaymentMethod),
+		errors.Is(err, paymentDomain.ErrWalletUnavailable),
+		errors.Is(err, paymentDomain.ErrCustomerNotFound):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, paymentDomain.ErrInsufficientFunds), errors.Is(err, paymentDomain.ErrPaymentFailed):
+		return c.Status(fiber.StatusPaymentRequired).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+}
+
+// isStaff indica si el usuario autenticado es ADMIN o GESTOR
+func isStaff(c *fiber.Ctx) bool {
+	roleName, _ := c.Locals("roleName").(string)
+	return roleName == "ADMIN" || roleName == "GESTOR"
+}
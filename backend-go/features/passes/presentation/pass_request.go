@@ -0,0 +1,35 @@
+package presentation
+
+import "backend-go/features/passes/domain"
+
+// PassProductRequest DTO para crear o actualizar un bono del catálogo
+type PassProductRequest struct {
+	Name          string   `json:"name" validate:"required,max=100"`
+	Description   *string  `json:"description"`
+	Credits       int      `json:"credits" validate:"required,gt=0"`       // Clases o horas de pista
+	ValidityDays  int      `json:"validity_days" validate:"required,gt=0"` // Días de validez desde la compra
+	PriceCents    int      `json:"price_cents" validate:"gte=0"`
+	ResourceTypes []string `json:"resource_types" validate:"required"` // CLASS y/o PISTA
+	IsActive      *bool    `json:"is_active"`                          // Por defecto true
+}
+
+// PurchasePassRequest DTO para comprar un bono
+type PurchasePassRequest struct {
+	ProductID     int    `json:"product_id" validate:"required"`
+	UserID        string `json:"user_id"`        // Solo ADMIN/GESTOR: compra para otro usuario (venta en recepción)
+	PaymentMethod string `json:"payment_method"` // Opcional: card (por defecto) o wallet
+	CustomerID    string `json:"customer_id"`    // Obligatorio con card
+}
+
+// ProductRequestToDomain convierte PassProductRequest a domain.PassProduct
+func ProductRequestToDomain(req *PassProductRequest) *domain.PassProduct {
+	return &domain.PassProduct{
+		Name:          req.Name,
+		Description:   req.Description,
+		Credits:       req.Credits,
+		ValidityDays:  req.ValidityDays,
+		PriceCents:    req.PriceCents,
+		ResourceTypes: req.ResourceTypes,
+		IsActive:      req.IsActive == nil || *req.IsActive,
+	}
+}
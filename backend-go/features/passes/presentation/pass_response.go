@@ -0,0 +1,127 @@
+package presentation
+
+import (
+	"backend-go/features/passes/domain"
+	paymentDomain "backend-go/features/payments/domain"
+	"time"
+)
+
+// PassProductResponse representa un bono del catálogo
+type PassProductResponse struct {
+	ID            int      `json:"id"`
+	Name          string   `json:"name"`
+	Description   *string  `json:"description,omitempty"`
+	Credits       int      `json:"credits"`
+	ValidityDays  int      `json:"validity_days"`
+	PriceCents    int      `json:"price_cents"`
+	PriceEuros    float64  `json:"price_euros"`
+	ResourceTypes []string `json:"resource_types"`
+	IsActive      bool     `json:"is_active"`
+}
+
+// ToProductResponse convierte un domain.PassProduct a PassProductResponse
+func ToProductResponse(product *domain.PassProduct) PassProductResponse {
+	return PassProductResponse{
+		ID:            product.ID,
+		Name:          product.Name,
+		Description:   product.Description,
+		Credits:       product.Credits,
+		ValidityDays:  product.ValidityDays,
+		PriceCents:    product.PriceCents,
+		PriceEuros:    float64(product.PriceCents) / 100.0,
+		ResourceTypes: product.ResourceTypes,
+		IsActive:      product.IsActive,
+	}
+}
+
+// PassResponse representa un bono comprado
+type PassResponse struct {
+	ID               int      `json:"id"`
+	UserID           string   `json:"user_id"`
+	ProductID        int      `json:"product_id"`
+	Name             string   `json:"name"`
+	ResourceTypes    []string `json:"resource_types"`
+	CreditsTotal     int      `json:"credits_total"`
+	CreditsRemaining int      `json:"credits_remaining"`
+	PriceCents       int      `json:"price_cents"`
+	Status           string   `json:"status"`
+	Usable           bool     `json:"usable"` // Pagado, vigente y con créditos
+	ExpiresAt        string   `json:"expires_at"`
+	CreatedAt        string   `json:"created_at"`
+}
+
+// ToPassResponse convierte un domain.Pass a PassResponse
+func ToPassResponse(pass *domain.Pass) PassResponse {
+	return PassResponse{
+		ID:               pass.ID,
+		UserID:           pass.UserID.String(),
+		ProductID:        pass.ProductID,
+		Name:             pass.Name,
+		ResourceTypes:    pass.ResourceTypes,
+		CreditsTotal:     pass.CreditsTotal,
+		CreditsRemaining: pass.CreditsRemaining,
+		PriceCents:       pass.PriceCents,
+		Status:           pass.Status,
+		Usable:           pass.IsUsable(time.Now()) && pass.CreditsRemaining > 0,
+		ExpiresAt:        pass.ExpiresAt.Format(time.RFC3339),
+		CreatedAt:        pass.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// RedemptionResponse representa un consumo de créditos
+type RedemptionResponse struct {
+	ID           int     `json:"id"`
+	ResourceType string  `json:"resource_type"`
+	BookingID    *int    `json:"booking_id,omitempty"`
+	EnrollmentID *int    `json:"enrollment_id,omitempty"`
+	Credits      int     `json:"credits"`
+	RestoredAt   *string `json:"restored_at,omitempty"` // Créditos devueltos al cancelar
+	CreatedAt    string  `json:"created_at"`
+}
+
+// PassDetailResponse representa un bono con sus consumos
+type PassDetailResponse struct {
+	PassResponse
+	Redemptions []RedemptionResponse `json:"redemptions"`
+}
+
+// ToPassDetailResponse construye la respuesta de un bono con sus consumos
+func ToPassDetailResponse(pass *domain.Pass, redemptions []domain.Redemption) PassDetailResponse {
+	response := PassDetailResponse{
+		PassResponse: ToPassResponse(pass),
+		Redemptions:  make([]RedemptionResponse, len(redemptions)),
+	}
+	for i, redemption := range redemptions {
+		item := RedemptionResponse{
+			ID:           redemption.ID,
+			ResourceType: redemption.ResourceType,
+			BookingID:    redemption.BookingID,
+			EnrollmentID: redemption.EnrollmentID,
+			Credits:      redemption.Credits,
+			CreatedAt:    redemption.CreatedAt.Format(time.RFC3339),
+		}
+		if redemption.RestoredAt != nil {
+			restoredAt := redemption.RestoredAt.Format(time.RFC3339)
+			item.RestoredAt = &restoredAt
+		}
+		response.Redemptions[i] = item
+	}
+	return response
+}
+
+// PurchaseResponse representa el resultado de comprar un bono
+type PurchaseResponse struct {
+	Pass          PassResponse `json:"pass"`
+	PaymentID     *uint        `json:"payment_id,omitempty"` // Sin pago si el bono es gratuito
+	PaymentStatus string       `json:"payment_status,omitempty"`
+}
+
+// ToPurchaseResponse construye la respuesta de la compra
+func ToPurchaseResponse(pass *domain.Pass, payment *paymentDomain.Payment) PurchaseResponse {
+	response := PurchaseResponse{Pass: ToPassResponse(pass)}
+	if payment != nil {
+		response.PaymentID = &payment.ID
+		response.PaymentStatus = payment.Status
+	}
+	return response
+}
@@ -0,0 +1,35 @@
+package presentation
+
+import (
+	"backend-go/shared/idempotency"
+	"backend-go/shared/middleware"
+	"backend-go/shared/security"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ======================================================================================
+// PASS ROUTES (bonos de clases y horas de pista)
+// Público (JWT opcional): GET /products
+// Autenticado: POST /purchase (admite Idempotency-Key), GET /me, GET /:id
+// Admin/Gestor: POST /products, PUT /products/:id, GET /users/:user_id
+// El consumo es automático al reservar pista o inscribirse en una clase
+// ======================================================================================
+
+// RegisterRoutes registra las rutas de bonos
+func RegisterRoutes(app *fiber.App, handler *PassHandler, jwtService security.JWTService, idempotencyStore *idempotency.Store) {
+	passes := app.Group("/api/passes")
+
+	// Rutas públicas - Catálogo
+	passes.Get("/products", middleware.OptionalJWTMiddleware(jwtService), handler.GetProducts)
+
+	// Rutas protegidas - Solo ADMIN y GESTOR
+	passes.Post("/products", middleware.JWTMiddleware(jwtService), middleware.RequireRoleByName("ADMIN", "GESTOR"), handler.CreateProduct)
+	passes.Put("/products/:id", middleware.JWTMiddleware(jwtService), middleware.RequireRoleByName("ADMIN", "GESTOR"), handler.UpdateProduct)
+	passes.Get("/users/:user_id", middleware.JWTMiddleware(jwtService), middleware.RequireRoleByName("ADMIN", "GESTOR"), handler.GetUserPasses)
+
+	// Rutas protegidas - Autenticado
+	passes.Post("/purchase", middleware.JWTMiddleware(jwtService), middleware.Idempotency(idempotencyStore), handler.Purchase)
+	passes.Get("/me", middleware.JWTMiddleware(jwtService), handler.GetMyPasses)
+	passes.Get("/:id", middleware.JWTMiddleware(jwtService), handler.GetPass)
+}
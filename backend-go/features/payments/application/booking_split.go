@@ -0,0 +1,249 @@
+package application
+
+import (
+	bookingDomain "backend-go/features/bookings/domain"
+	"backend-go/features/payments/domain"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SplitBooking divide el pago de una reserva sin pagar entre el organizador (titular) y los
+// jugadores invitados (por ID o email). Cada uno paga su parte con CheckoutBookingShare; la
+// reserva se confirma al pagarse todas o cuando el organizador cubre lo pendiente con
+// CheckoutBooking. deadline nil = DefaultSplitDeadlineLead antes del inicio de la reserva.
+func (s *PaymentService) SplitBooking(bookingID uint, userID uuid.UUID, isStaff bool, userIDs []uuid.UUID, emails []string, deadline *time.Time) (*domain.BookingSplit, error) {
+	invited, err := s.repo.ResolveUsers(userIDs, emails)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.repo.CreateBookingSplit(bookingID, func(booking *domain.BookingCharge) (*domain.BookingSplit, error) {
+		if !isStaff && booking.UserID != userID {
+			return nil, domain.ErrSplitNotOrganizer
+		}
+		if booking.IsSplit() {
+			return nil, domain.ErrBookingAlreadySplit
+		}
+		if booking.PaymentStatus != bookingDomain.PaymentStatusUnpaid {
+			return nil, domain.ErrBookingAlreadyPaid
+		}
+		if booking.HasPending {
+			return nil, domain.ErrPaymentPending
+		}
+		if booking.Status == bookingDomain.StatusCancelled || booking.Status == bookingDomain.StatusCompleted {
+			return nil, domain.ErrBookingNotPayable
+		}
+
+		// El organizador siempre paga la primera parte; no puede invitarse a sí mismo
+		players := []uuid.UUID{booking.UserID}
+		for _, id := range invited {
+			if id != booking.UserID {
+				players = append(players, id)
+			}
+		}
+		if len(players) < 2 || len(players) > domain.MaxSplitPlayers || booking.AmountCents < len(players) {
+			return nil, domain.ErrInvalidSplit
+		}
+
+		splitDeadline := booking.StartTime.Add(-domain.DefaultSplitDeadlineLead)
+		if deadline != nil {
+			splitDeadline = *deadline
+		}
+		if !splitDeadline.After(time.Now()) || splitDeadline.After(booking.StartTime) {
+			return nil, domain.ErrInvalidSplitDeadline
+		}
+
+		split := &domain.BookingSplit{
+			BookingID:     booking.BookingID,
+			OrganizerID:   booking.UserID,
+			TotalCents:    booking.AmountCents,
+			Deadline:      splitDeadline,
+			BookingStatus: booking.Status,
+			PaymentStatus: booking.PaymentStatus,
+			Shares:        make([]domain.BookingShare, len(players)),
+		}
+		for i, amount := range domain.SplitAmount(booking.AmountCents, len(players)) {
+			split.Shares[i] = domain.BookingShare{
+				BookingID:   booking.BookingID,
+				UserID:      players[i],
+				AmountCents: amount,
+				Status:      domain.ShareStatusPending,
+			}
+		}
+		return split, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Se vuelve a leer para devolver los nombres de los jugadores
+	return s.repo.GetBookingSplit(bookingID)
+}
+
+// GetBookingSplit obtiene el reparto del pago de una reserva. Solo pueden verlo el organizador,
+// los jugadores con parte y el personal.
+func (s *PaymentService) GetBookingSplit(bookingID uint, userID uuid.UUID, isStaff bool) (*domain.BookingSplit, error) {
+	split, err := s.repo.GetBookingSplit(bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if isStaff || split.OrganizerID == userID {
+		return split, nil
+	}
+	for _, share := range split.Shares {
+		if share.UserID == userID {
+			return split, nil
+		}
+	}
+	return nil, domain.ErrShareNotOwned
+}
+
+// GetMyShares obtiene las partes de reservas con pago dividido de un jugador
+func (s *PaymentService) GetMyShares(userID uuid.UUID) ([]domain.BookingShare, error) {
+	return s.repo.GetSharesByUser(userID)
+}
+
+// CheckoutBookingShare cobra la parte de un jugador en una reserva con pago dividido. Igual que
+// CheckoutBooking, el cargo se realiza con la reserva bloqueada y se reembolsa si la transacción
+// falla. Con paymentMethod "wallet" se cobra del monedero del jugador de la parte.
+func (s *PaymentService) CheckoutBookingShare(shareID uint, userID uuid.UUID, isStaff bool, paymentMethod, customerID, idempotencyKey string) (*domain.Payment, error) {
+	gateway, err := s.gatewayFor(paymentMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	var chargedIntentID string
+	var chargedAmount int
+
+	payment, err := s.repo.CheckoutBookingShare(shareID, func(share *domain.ShareCharge) (*domain.Payment, error) {
+		if !isStaff && share.Share.UserID != userID {
+			return nil, domain.ErrShareNotOwned
+		}
+		if share.Share.Status != domain.ShareStatusPending {
+			return nil, domain.ErrShareNotPayable
+		}
+		if share.HasPending {
+			return nil, domain.ErrPaymentPending
+		}
+		if share.Booking.Status == bookingDomain.StatusCancelled || share.Booking.Status == bookingDomain.StatusCompleted {
+			return nil, domain.ErrBookingNotPayable
+		}
+		if share.Booking.SplitDeadline != nil && !time.Now().Before(*share.Booking.SplitDeadline) {
+			return nil, domain.ErrSplitDeadlinePassed
+		}
+
+		switch {
+		case paymentMethod == domain.PaymentMethodWallet:
+			customerID = share.Share.UserID.String()
+		case customerID == "":
+			customerID = share.CustomerID
+		}
+		description := fmt.Sprintf("Parte del pago de la reserva #%d", share.Booking.BookingID)
+
+		charge, err := gateway.Charge(share.Share.AmountCents, customerID, description, idempotencyKey)
+		if err != nil {
+			return nil, err
+		}
+		if charge.Status == domain.StatusCompleted {
+			chargedIntentID = charge.PaymentIntentID
+			chargedAmount = share.Share.AmountCents
+		}
+
+		bookingRef := share.Booking.BookingID
+		shareRef := share.Share.ID
+		payment := &domain.Payment{
+			UserID:                share.Share.UserID,
+			BookingID:             &bookingRef,
+			BookingShareID:        &shareRef,
+			AmountCents:           share.Share.AmountCents,
+			Currency:              "EUR",
+			Status:                charge.Status,
+			Provider:              gateway.Name(),
+			StripePaymentIntentID: &charge.PaymentIntentID,
+			CreatedAt:             time.Now(),
+			UpdatedAt:             time.Now(),
+		}
+		return payment, payment.Validate()
+	})
+	if err != nil {
+		if chargedIntentID != "" {
+			err = refundCharge(gateway, chargedIntentID, chargedAmount, err)
+		}
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+// RefundUnpaidBooking devuelve íntegras las partes ya pagadas de una reserva con pago dividido
+// que se cancela sin haber llegado a confirmarse, y cierra sus partes. Devuelve el importe
+// reembolsado (0 si la reserva no tenía el pago dividido).
+func (s *PaymentService) RefundUnpaidBooking(bookingID uint) (int, error) {
+	payments, err := s.repo.GetAllByBooking(bookingID)
+	if err != nil {
+		return 0, err
+	}
+
+	refunded := 0
+	for i := range payments {
+		if payments[i].BookingShareID == nil || payments[i].RefundableCents() <= 0 {
+			continue
+		}
+		refund, err := s.repo.CreateRefund(payments[i].ID, func(locked *domain.Payment) (*domain.Refund, error) {
+			amount := locked.RefundableCents()
+			if amount <= 0 || locked.StripePaymentIntentID == nil {
+				return nil, errNothingToRefund
+			}
+			return s.refundWithGateway(locked, amount, domain.RefundReasonCancellation)
+		})
+		if errors.Is(err, errNothingToRefund) {
+			continue
+		}
+		if err != nil {
+			return refunded, err
+		}
+		refunded += refund.AmountCents
+	}
+
+	return refunded, s.repo.CancelSplitShares(bookingID)
+}
+
+// ExpireBookingSplits cancela las reservas con pago dividido cuyo plazo venció sin completar el
+// pago y devuelve las partes pagadas. Las devoluciones fallidas se reintentan en la siguiente
+// ejecución. Devuelve el número de reservas procesadas. Las consultas usan ctx.
+func (s *PaymentService) ExpireBookingSplits(ctx context.Context) (int, error) {
+	s = s.WithContext(ctx)
+	now := time.Now()
+	bookingIDs, err := s.repo.FindExpiredSplits(now)
+	if err != nil {
+		return 0, fmt.Errorf("error al buscar pagos divididos vencidos: %w", err)
+	}
+
+	processed := 0
+	var errs []error
+	for _, bookingID := range bookingIDs {
+		// Las devoluciones no se cortan a medias: se para entre reservas
+		if err := ctx.Err(); err != nil {
+			return processed, errors.Join(append(errs, err)...)
+		}
+		cancelled, err := s.repo.CancelExpiredSplit(bookingID, now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error al cancelar la reserva %d: %w", bookingID, err))
+			continue
+		}
+		if !cancelled {
+			continue
+		}
+		if _, err := s.RefundUnpaidBooking(bookingID); err != nil {
+			errs = append(errs, fmt.Errorf("error al devolver las partes de la reserva %d: %w", bookingID, err))
+			continue
+		}
+		processed++
+	}
+
+	return processed, errors.Join(errs...)
+}
@@ -0,0 +1,52 @@
+package application
+
+import (
+	"backend-go/features/payments/domain"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// InvoiceService consulta las facturas de los pagos y genera sus PDF. Las facturas se emiten
+// en el repositorio al cobrar y reembolsar; los pagos anteriores a la facturación reciben la
+// suya la primera vez que se consulta.
+type InvoiceService struct {
+	repo     domain.PaymentRepository
+	renderer domain.InvoiceRenderer
+}
+
+// NewInvoiceService crea una nueva instancia del servicio
+func NewInvoiceService(repo domain.PaymentRepository, renderer domain.InvoiceRenderer) *InvoiceService {
+	return &InvoiceService{
+		repo:     repo,
+		renderer: renderer,
+	}
+}
+
+// GetPaymentInvoice obtiene la factura de un pago con sus rectificativas. Solo el titular del
+// pago o el personal: la propiedad se comprueba antes de emitir la factura de un pago antiguo.
+func (s *InvoiceService) GetPaymentInvoice(paymentID uint, userID uuid.UUID, isStaff bool) (*domain.Invoice, error) {
+	payment, err := s.repo.GetByID(paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if !isStaff && payment.UserID != userID {
+		return nil, domain.ErrInvoiceNotOwned
+	}
+
+	invoice, err := s.repo.GetInvoiceByPayment(paymentID)
+	if errors.Is(err, domain.ErrInvoiceNotFound) {
+		return s.repo.IssueInvoice(paymentID)
+	}
+	return invoice, err
+}
+
+// GetInvoiceByNumber obtiene una factura o rectificativa por su número (F2026-000001, R2026-000001)
+func (s *InvoiceService) GetInvoiceByNumber(number string) (*domain.Invoice, error) {
+	return s.repo.GetInvoiceByNumber(number)
+}
+
+// RenderPDF genera el PDF de la factura
+func (s *InvoiceService) RenderPDF(invoice *domain.Invoice) ([]byte, error) {
+	return s.renderer.Render(invoice)
+}
@@ -1,7 +1,10 @@
 package application
 
 import (
+	bookingDomain "backend-go/features/bookings/domain"
 	"backend-go/features/payments/domain"
+	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -12,25 +15,74 @@ import (
 type PaymentService struct {
 	repo    domain.PaymentRepository
 	gateway domain.PaymentGateway
+	wallet  domain.WalletGateway     // Opcional: pago con el saldo del monedero
+	promos  domain.PromoCodeRedeemer // Opcional: códigos promocionales al cobrar
+	policy  domain.CancellationPolicy
 }
 
 // NewPaymentService crea una nueva instancia del servicio
-func NewPaymentService(repo domain.PaymentRepository, gateway domain.PaymentGateway) *PaymentService {
+func NewPaymentService(repo domain.PaymentRepository, gateway domain.PaymentGateway, policy domain.CancellationPolicy) *PaymentService {
 	return &PaymentService{
 		repo:    repo,
 		gateway: gateway,
+		policy:  policy,
 	}
 }
 
-// ProcessPayment procesa un pago genérico
-func (s *PaymentService) ProcessPayment(userID uuid.UUID, amountCents int, customerID, description string) (*domain.Payment, error) {
+// SetWalletGateway habilita el monedero como método de pago (payment_method = "wallet") y
+// como destino de reembolsos
+func (s *PaymentService) SetWalletGateway(wallet domain.WalletGateway) {
+	s.wallet = wallet
+}
+
+// SetPromoCodeRedeemer habilita los códigos promocionales (promo_code) en los cobros de
+// reservas, clases y cuotas de club
+func (s *PaymentService) SetPromoCodeRedeemer(promos domain.PromoCodeRedeemer) {
+	s.promos = promos
+}
+
+// WithContext devuelve una copia del servicio cuyas consultas usan ctx (tareas programadas)
+func (s *PaymentService) WithContext(ctx context.Context) *PaymentService {
+	scoped := *s
+	scoped.repo = s.repo.WithContext(ctx)
+	return &scoped
+}
+
+// gatewayFor devuelve la fuente de financiación del método de pago ("" = tarjeta)
+func (s *PaymentService) gatewayFor(paymentMethod string) (domain.PaymentGateway, error) {
+	switch paymentMethod {
+	case "", domain.PaymentMethodCard:
+		return s.gateway, nil
+	case domain.PaymentMethodWallet:
+		if s.wallet == nil {
+			return nil, domain.ErrWalletUnavailable
+		}
+		return s.wallet, nil
+	}
+	return nil, domain.ErrInvalidPaymentMethod
+}
+
+// gatewayForProvider devuelve el proveedor que procesó un pago (el único que puede reembolsarlo)
+func (s *PaymentService) gatewayForProvider(provider string) (domain.PaymentGateway, error) {
+	if s.wallet != nil && provider == s.wallet.Name() {
+		return s.wallet, nil
+	}
+	if provider == s.gateway.Name() {
+		return s.gateway, nil
+	}
+	return nil, fmt.Errorf("%w (%s)", domain.ErrProviderMismatch, provider)
+}
+
+// ProcessPayment procesa un pago genérico. idempotencyKey (opcional) se reenvía al proveedor
+// para que un reintento no genere un segundo cargo.
+func (s *PaymentService) ProcessPayment(userID uuid.UUID, amountCents int, customerID, description, idempotencyKey string) (*domain.Payment, error) {
 	// 1. Validar monto
 	if amountCents <= 0 {
 		return nil, domain.ErrInvalidAmount
 	}
 
 	// 2. Procesar el cargo con el gateway
-	paymentIntentID, err := s.gateway.Charge(amountCents, customerID, description)
+	charge, err := s.gateway.Charge(amountCents, customerID, description, idempotencyKey)
 	if err != nil {
 		return nil, err
 	}
@@ -40,9 +92,9 @@ func (s *PaymentService) ProcessPayment(userID uuid.UUID, amountCents int, custo
 		UserID:                userID,
 		AmountCents:           amountCents,
 		Currency:              "EUR",
-		Status:                domain.StatusCompleted,
-		Provider:              domain.ProviderMock,
-		StripePaymentIntentID: &paymentIntentID,
+		Status:                charge.Status,
+		Provider:              s.gateway.Name(),
+		StripePaymentIntentID: &charge.PaymentIntentID,
 		CreatedAt:             time.Now(),
 		UpdatedAt:             time.Now(),
 	}
@@ -55,87 +107,275 @@ func (s *PaymentService) ProcessPayment(userID uuid.UUID, amountCents int, custo
 	return payment, nil
 }
 
-// ProcessBookingPayment procesa un pago para una reserva
-func (s *PaymentService) ProcessBookingPayment(userID uuid.UUID, bookingID uint, amountCents int, customerID string) (*domain.Payment, error) {
-	description := fmt.Sprintf("Pago de reserva #%d", bookingID)
+// CheckoutBooking cobra una reserva usando el precio congelado en PriceSnapshotCents.
+// El cargo, el registro del pago y la confirmación de la reserva ocurren en una única
+// transacción con la reserva bloqueada; si el cargo se realizó pero la transacción falla,
+// se reembolsa para no dejar cobros huérfanos. Si el proveedor deja el cobro pendiente
+// (3DS, SEPA) la reserva se confirma al recibir el webhook. Con paymentMethod "wallet" se
+// cobra del monedero del titular de la reserva. Con promoCode se cobra el precio menos el
+// descuento del código, que queda registrado en la reserva y en el pago. Si el pago está
+// dividido se cobra la suma de las partes pendientes, que quedan cubiertas por el titular.
+func (s *PaymentService) CheckoutBooking(bookingID uint, userID uuid.UUID, isStaff bool, paymentMethod, customerID, promoCode, idempotencyKey string) (*domain.Payment, error) {
+	gateway, err := s.gatewayFor(paymentMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	var chargedIntentID string
+	var chargedAmount int
+	var discount *domain.PromoDiscount
+
+	payment, err := s.repo.CheckoutBooking(bookingID, func(booking *domain.BookingCharge) (*domain.Payment, error) {
+		if !isStaff && booking.UserID != userID {
+			return nil, domain.ErrBookingNotOwned
+		}
+		if booking.PaymentStatus == bookingDomain.PaymentStatusPaid {
+			return nil, domain.ErrBookingAlreadyPaid
+		}
+		if booking.HasPending {
+			return nil, domain.ErrPaymentPending
+		}
+		if booking.Status == bookingDomain.StatusCancelled || booking.Status == bookingDomain.StatusCompleted {
+			return nil, domain.ErrBookingNotPayable
+		}
+		// Pago dividido: el titular cubre lo que falta por pagar antes del plazo
+		if booking.IsSplit() {
+			if promoCode != "" {
+				return nil, domain.ErrSplitPromoCode
+			}
+			if !time.Now().Before(*booking.SplitDeadline) {
+				return nil, domain.ErrSplitDeadlinePassed
+			}
+		}
+		if booking.AmountCents <= 0 {
+			return nil, domain.ErrInvalidAmount
+		}
+
+		var err error
+		if discount, err = s.redeemPromoCode(promoCode, booking.UserID, domain.PromoResourcePista, booking.AmountCents); err != nil {
+			return nil, err
+		}
+		amount := booking.AmountCents
+		if discount != nil {
+			amount -= discount.DiscountCents
+		}
+
+		switch {
+		case paymentMethod == domain.PaymentMethodWallet:
+			customerID = booking.UserID.String()
+		case customerID == "":
+			customerID = booking.CustomerID
+		}
+		description := fmt.Sprintf("Pago de reserva #%d", booking.BookingID)
+
+		charge, err := gateway.Charge(amount, customerID, description, idempotencyKey)
+		if err != nil {
+			return nil, err
+		}
+		// Solo un cobro ya realizado se puede reembolsar si la transacción falla
+		if charge.Status == domain.StatusCompleted {
+			chargedIntentID = charge.PaymentIntentID
+			chargedAmount = amount
+		}
+
+		bookingRef := booking.BookingID
+		payment := &domain.Payment{
+			UserID:                booking.UserID,
+			BookingID:             &bookingRef,
+			AmountCents:           amount,
+			Currency:              "EUR",
+			Status:                charge.Status,
+			Provider:              gateway.Name(),
+			StripePaymentIntentID: &charge.PaymentIntentID,
+			CreatedAt:             time.Now(),
+			UpdatedAt:             time.Now(),
+		}
+		payment.ApplyPromoDiscount(discount)
+		return payment, payment.Validate()
+	})
+	if err != nil {
+		if chargedIntentID != "" {
+			err = refundCharge(gateway, chargedIntentID, chargedAmount, err)
+		}
+		return nil, s.releasePromoCode(discount, err)
+	}
+
+	return payment, nil
+}
+
+// refundCharge reembolsa un cargo ya cobrado cuando no se pudo registrar el pago. Si el
+// reembolso se completa devuelve ErrChargeRefunded (4xx): la Idempotency-Key guarda esa
+// respuesta y un reintento con la misma clave no repite el cargo ya reembolsado en el proveedor.
+func refundCharge(gateway domain.PaymentGateway, intentID string, amountCents int, cause error) error {
+	if _, err := gateway.Refund(intentID, amountCents); err != nil {
+		return fmt.Errorf("%w (el reembolso automático del cargo %s también falló: %v)", cause, intentID, err)
+	}
+	return fmt.Errorf("%w: %v", domain.ErrChargeRefunded, cause)
+}
+
+// ProcessClassPayment procesa un pago para una inscripción a clase de userID, que debe ser su
+// titular; una inscripción ya pagada (o con bono) se rechaza. El importe es siempre el
+// precio de la clase, nunca el del cliente. Con paymentMethod "wallet" se cobra del monedero
+// de userID; con promoCode se cobra el precio menos el descuento.
+func (s *PaymentService) ProcessClassPayment(userID uuid.UUID, enrollmentID uint, paymentMethod, customerID, promoCode, idempotencyKey string) (*domain.Payment, error) {
+	gateway, err := s.gatewayFor(paymentMethod)
+	if err != nil {
+		return nil, err
+	}
+	resource, err := s.repo.GetEnrollmentCharge(enrollmentID)
+	if err != nil {
+		return nil, err
+	}
+	if resource.UserID != userID {
+		return nil, domain.ErrEnrollmentNotOwned
+	}
+	if resource.Paid {
+		return nil, domain.ErrEnrollmentAlreadyPaid
+	}
+	amountCents := resource.AmountCents
+	if amountCents <= 0 {
+		return nil, domain.ErrInvalidAmount
+	}
+	if paymentMethod == domain.PaymentMethodWallet {
+		customerID = userID.String()
+	}
+	description := fmt.Sprintf("Pago de inscripción a clase #%d", enrollmentID)
 
-	paymentIntentID, err := s.gateway.Charge(amountCents, customerID, description)
+	discount, err := s.redeemPromoCode(promoCode, userID, domain.PromoResourceClass, amountCents)
 	if err != nil {
 		return nil, err
 	}
+	if discount != nil {
+		amountCents -= discount.DiscountCents
+	}
+
+	charge, err := gateway.Charge(amountCents, customerID, description, idempotencyKey)
+	if err != nil {
+		return nil, s.releasePromoCode(discount, err)
+	}
 
 	payment := &domain.Payment{
 		UserID:                userID,
-		BookingID:             &bookingID,
+		ClassEnrollmentID:     &enrollmentID,
 		AmountCents:           amountCents,
 		Currency:              "EUR",
-		Status:                domain.StatusCompleted,
-		Provider:              domain.ProviderMock,
-		StripePaymentIntentID: &paymentIntentID,
+		Status:                charge.Status,
+		Provider:              gateway.Name(),
+		StripePaymentIntentID: &charge.PaymentIntentID,
 		CreatedAt:             time.Now(),
 		UpdatedAt:             time.Now(),
 	}
+	payment.ApplyPromoDiscount(discount)
 
 	if err := payment.Validate(); err != nil {
-		return nil, err
+		return nil, s.releasePromoCode(discount, err)
 	}
 
 	if err := s.repo.Create(payment); err != nil {
-		return nil, err
+		// Solo un cobro ya realizado se puede reembolsar
+		if charge.Status == domain.StatusCompleted {
+			err = refundCharge(gateway, charge.PaymentIntentID, amountCents, err)
+		}
+		return nil, s.releasePromoCode(discount, err)
 	}
 
 	return payment, nil
 }
 
-// ProcessClassPayment procesa un pago para una inscripción a clase
-func (s *PaymentService) ProcessClassPayment(userID uuid.UUID, enrollmentID uint, amountCents int, customerID string) (*domain.Payment, error) {
-	description := fmt.Sprintf("Pago de inscripción a clase #%d", enrollmentID)
+// ProcessClubPayment procesa un pago de la membresía a club de userID, que debe ser su
+// titular; se rechaza si el periodo actual ya está pagado. El importe es siempre la cuota
+// mensual del club, nunca la del cliente. Con paymentMethod "wallet" se cobra del monedero
+// de userID; con promoCode se cobra la cuota menos el descuento.
+func (s *PaymentService) ProcessClubPayment(userID uuid.UUID, membershipID uint, paymentMethod, customerID, promoCode, idempotencyKey string) (*domain.Payment, error) {
+	gateway, err := s.gatewayFor(paymentMethod)
+	if err != nil {
+		return nil, err
+	}
+	resource, err := s.repo.GetMembershipCharge(membershipID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if resource.UserID != userID {
+		return nil, domain.ErrMembershipNotOwned
+	}
+	if resource.Paid {
+		return nil, domain.ErrMembershipAlreadyPaid
+	}
+	amountCents := resource.AmountCents
+	if amountCents <= 0 {
+		return nil, domain.ErrInvalidAmount
+	}
+	if paymentMethod == domain.PaymentMethodWallet {
+		customerID = userID.String()
+	}
+	description := fmt.Sprintf("Pago de membresía #%d", membershipID)
 
-	paymentIntentID, err := s.gateway.Charge(amountCents, customerID, description)
+	discount, err := s.redeemPromoCode(promoCode, userID, domain.PromoResourceClub, amountCents)
 	if err != nil {
 		return nil, err
 	}
+	if discount != nil {
+		amountCents -= discount.DiscountCents
+	}
+
+	charge, err := gateway.Charge(amountCents, customerID, description, idempotencyKey)
+	if err != nil {
+		return nil, s.releasePromoCode(discount, err)
+	}
 
 	payment := &domain.Payment{
 		UserID:                userID,
-		ClassEnrollmentID:     &enrollmentID,
+		ClubMembershipID:      &membershipID,
 		AmountCents:           amountCents,
 		Currency:              "EUR",
-		Status:                domain.StatusCompleted,
-		Provider:              domain.ProviderMock,
-		StripePaymentIntentID: &paymentIntentID,
+		Status:                charge.Status,
+		Provider:              gateway.Name(),
+		StripePaymentIntentID: &charge.PaymentIntentID,
 		CreatedAt:             time.Now(),
 		UpdatedAt:             time.Now(),
 	}
+	payment.ApplyPromoDiscount(discount)
 
 	if err := payment.Validate(); err != nil {
-		return nil, err
+		return nil, s.releasePromoCode(discount, err)
 	}
 
 	if err := s.repo.Create(payment); err != nil {
-		return nil, err
+		// Solo un cobro ya realizado se puede reembolsar
+		if charge.Status == domain.StatusCompleted {
+			err = refundCharge(gateway, charge.PaymentIntentID, amountCents, err)
+		}
+		return nil, s.releasePromoCode(discount, err)
 	}
 
 	return payment, nil
 }
 
-// ProcessClubPayment procesa un pago de membresía a club
-func (s *PaymentService) ProcessClubPayment(userID uuid.UUID, membershipID uint, amountCents int, customerID string) (*domain.Payment, error) {
-	description := fmt.Sprintf("Pago de membresía #%d", membershipID)
+// ProcessPassPayment cobra la compra de un bono. Con paymentMethod "wallet" se cobra del
+// monedero de userID.
+func (s *PaymentService) ProcessPassPayment(userID uuid.UUID, passID uint, amountCents int, paymentMethod, customerID, idempotencyKey string) (*domain.Payment, error) {
+	gateway, err := s.gatewayFor(paymentMethod)
+	if err != nil {
+		return nil, err
+	}
+	if paymentMethod == domain.PaymentMethodWallet {
+		customerID = userID.String()
+	}
+	description := fmt.Sprintf("Compra de bono #%d", passID)
 
-	paymentIntentID, err := s.gateway.Charge(amountCents, customerID, description)
+	charge, err := gateway.Charge(amountCents, customerID, description, idempotencyKey)
 	if err != nil {
 		return nil, err
 	}
 
 	payment := &domain.Payment{
 		UserID:                userID,
-		ClubMembershipID:      &membershipID,
+		PassID:                &passID,
 		AmountCents:           amountCents,
 		Currency:              "EUR",
-		Status:                domain.StatusCompleted,
-		Provider:              domain.ProviderMock,
-		StripePaymentIntentID: &paymentIntentID,
+		Status:                charge.Status,
+		Provider:              gateway.Name(),
+		StripePaymentIntentID: &charge.PaymentIntentID,
 		CreatedAt:             time.Now(),
 		UpdatedAt:             time.Now(),
 	}
@@ -151,6 +391,31 @@ func (s *PaymentService) ProcessClubPayment(userID uuid.UUID, membershipID uint,
 	return payment, nil
 }
 
+// redeemPromoCode canjea el código promocional (si lo hay) para un cobro de amountCents
+func (s *PaymentService) redeemPromoCode(code string, userID uuid.UUID, resourceType string, amountCents int) (*domain.PromoDiscount, error) {
+	if code == "" {
+		return nil, nil
+	}
+	if s.promos == nil {
+		return nil, domain.ErrPromoCodesUnavailable
+	}
+	if amountCents <= 0 {
+		return nil, domain.ErrInvalidAmount
+	}
+	return s.promos.Redeem(code, userID, resourceType, amountCents)
+}
+
+// releasePromoCode libera el uso del código si el cobro no llegó a realizarse y devuelve cause
+func (s *PaymentService) releasePromoCode(discount *domain.PromoDiscount, cause error) error {
+	if discount == nil {
+		return cause
+	}
+	if err := s.promos.Release(discount.RedemptionID); err != nil {
+		return errors.Join(cause, err)
+	}
+	return cause
+}
+
 // GetUserPayments obtiene todos los pagos de un usuario
 func (s *PaymentService) GetUserPayments(userID uint) ([]domain.Payment, error) {
 	return s.repo.GetByUser(userID)
@@ -161,30 +426,204 @@ func (s *PaymentService) GetPaymentByID(id uint) (*domain.Payment, error) {
 	return s.repo.GetByID(id)
 }
 
-// RefundPayment procesa un reembolso
-func (s *PaymentService) RefundPayment(paymentID uint) error {
-	payment, err := s.repo.GetByID(paymentID)
+// RefundPayment reembolsa un pago. amountCents = 0 reembolsa todo lo pendiente. Con toWallet,
+// el importe se abona en el monedero del titular en lugar de devolverse a la tarjeta.
+func (s *PaymentService) RefundPayment(paymentID uint, amountCents int, reason string, toWallet bool) (*domain.Refund, error) {
+	if toWallet && s.wallet == nil {
+		return nil, domain.ErrWalletUnavailable
+	}
+	if amountCents < 0 {
+		return nil, domain.ErrInvalidAmount
+	}
+	if reason == "" {
+		reason = domain.RefundReasonManual
+	}
+
+	return s.repo.CreateRefund(paymentID, func(payment *domain.Payment) (*domain.Refund, error) {
+		refundable := payment.RefundableCents()
+		if refundable <= 0 {
+			return nil, domain.ErrPaymentNotRefundable
+		}
+		if payment.StripePaymentIntentID == nil {
+			return nil, fmt.Errorf("no se puede reembolsar un pago sin payment intent")
+		}
+
+		amount := amountCents
+		if amount == 0 {
+			amount = refundable
+		}
+		if amount > refundable {
+			return nil, domain.ErrRefundExceedsPayment
+		}
+
+		if toWallet && payment.Provider != s.wallet.Name() {
+			return s.refundToWallet(payment, amount, reason)
+		}
+		return s.refundWithGateway(payment, amount, reason)
+	})
+}
+
+// GetPaymentRefunds obtiene los reembolsos de un pago
+func (s *PaymentService) GetPaymentRefunds(paymentID uint) ([]domain.Refund, error) {
+	return s.repo.GetRefundsByPayment(paymentID)
+}
+
+// CancellationPolicy devuelve la política de cancelación configurada
+func (s *PaymentService) CancellationPolicy() domain.CancellationPolicy {
+	return s.policy
+}
+
+// RefundBookingCancellation aplica la política de cancelación a los pagos de una reserva (el del
+// titular y, con pago dividido, el de cada parte). Devuelve el importe reembolsado (0 si la
+// reserva no estaba pagada o la política no reembolsa nada).
+func (s *PaymentService) RefundBookingCancellation(bookingID uint, startsAt time.Time) (int, error) {
+	payments, err := s.repo.GetAllByBooking(bookingID)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	if payment.Status == domain.StatusRefunded {
-		return fmt.Errorf("el pago ya está reembolsado")
+	refunded := 0
+	split := false
+	for i := range payments {
+		if payments[i].BookingShareID != nil {
+			split = true
+		}
+		if payments[i].RefundableCents() <= 0 && payments[i].RefundedCents == 0 {
+			continue
+		}
+		amount, err := s.refundCancellation(&payments[i], startsAt)
+		if err != nil {
+			return refunded, err
+		}
+		refunded += amount
 	}
 
-	if payment.StripePaymentIntentID == nil {
-		return fmt.Errorf("no se puede reembolsar un pago sin payment intent")
+	if split {
+		return refunded, s.repo.CancelSplitShares(bookingID)
 	}
+	return refunded, nil
+}
+
+// RefundEnrollmentCancellation aplica la política de cancelación al pago de una inscripción a clase
+func (s *PaymentService) RefundEnrollmentCancellation(enrollmentID uint, startsAt time.Time) (int, error) {
+	payment, err := s.repo.GetByClassEnrollment(enrollmentID)
+	if err != nil {
+		if errors.Is(err, domain.ErrPaymentNotFound) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return s.refundCancellation(payment, startsAt)
+}
+
+// RefundMembershipCancellation reembolsa la parte no consumida del último periodo pagado de una
+// membresía (prorrateo por días entre el cobro y la próxima fecha de facturación)
+func (s *PaymentService) RefundMembershipCancellation(membershipID uint, periodEnd time.Time) (int, error) {
+	payments, err := s.repo.GetByClubMembership(membershipID)
+	if err != nil {
+		return 0, err
+	}
+
+	// El último cobro vigente (GetByClubMembership ordena del más reciente al más antiguo)
+	var last *domain.Payment
+	for i := range payments {
+		if payments[i].RefundableCents() > 0 {
+			last = &payments[i]
+			break
+		}
+	}
+	if last == nil {
+		return 0, nil
+	}
+
+	refund, err := s.repo.CreateRefund(last.ID, func(payment *domain.Payment) (*domain.Refund, error) {
+		amount := domain.ProratedRefund(payment.AmountCents, payment.CreatedAt, periodEnd, time.Now())
+		if refundable := payment.RefundableCents(); amount > refundable {
+			amount = refundable
+		}
+		if amount <= 0 || payment.StripePaymentIntentID == nil {
+			return nil, errNothingToRefund
+		}
+		return s.refundWithGateway(payment, amount, domain.RefundReasonCancellation)
+	})
+	if errors.Is(err, errNothingToRefund) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return refund.AmountCents, nil
+}
 
-	// Procesar reembolso con el gateway
-	_, err = s.gateway.Refund(*payment.StripePaymentIntentID, payment.AmountCents)
+// errNothingToRefund aborta la transacción de reembolso sin que sea un error para el llamador
+var errNothingToRefund = errors.New("nada que reembolsar")
+
+// refundCancellation reembolsa el porcentaje de la política según la antelación. Es idempotente:
+// si el pago ya tiene reembolsos no se vuelve a reembolsar y se devuelve lo ya reembolsado.
+func (s *PaymentService) refundCancellation(payment *domain.Payment, startsAt time.Time) (int, error) {
+	refund, err := s.repo.CreateRefund(payment.ID, func(locked *domain.Payment) (*domain.Refund, error) {
+		if locked.RefundedCents > 0 || locked.RefundableCents() <= 0 || locked.StripePaymentIntentID == nil {
+			return nil, errNothingToRefund
+		}
+
+		percent := s.policy.RefundPercent(startsAt, time.Now())
+		amount := locked.AmountCents * percent / 100
+		if amount <= 0 {
+			return nil, errNothingToRefund
+		}
+		return s.refundWithGateway(locked, amount, domain.RefundReasonCancellation)
+	})
+	if errors.Is(err, errNothingToRefund) {
+		latest, err := s.repo.GetByID(payment.ID)
+		if err != nil {
+			return 0, err
+		}
+		return latest.RefundedCents, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return refund.AmountCents, nil
+}
+
+// refundWithGateway ejecuta el reembolso en el proveedor y construye el registro
+func (s *PaymentService) refundWithGateway(payment *domain.Payment, amountCents int, reason string) (*domain.Refund, error) {
+	// El reembolso debe hacerlo el mismo proveedor que procesó el cobro
+	gateway, err := s.gatewayForProvider(payment.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	providerRefundID, err := gateway.Refund(*payment.StripePaymentIntentID, amountCents)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	destination := domain.RefundDestinationOriginal
+	if payment.Provider == domain.ProviderWallet {
+		destination = domain.RefundDestinationWallet
 	}
+	return &domain.Refund{
+		PaymentID:        payment.ID,
+		AmountCents:      amountCents,
+		Reason:           reason,
+		ProviderRefundID: providerRefundID,
+		Destination:      destination,
+	}, nil
+}
 
-	// Actualizar estado
-	payment.Status = domain.StatusRefunded
-	payment.UpdatedAt = time.Now()
+// refundToWallet abona en el monedero del titular el reembolso de un pago cobrado con tarjeta
+func (s *PaymentService) refundToWallet(payment *domain.Payment, amountCents int, reason string) (*domain.Refund, error) {
+	reference, err := s.wallet.CreditRefund(payment.UserID, amountCents, payment.ID)
+	if err != nil {
+		return nil, err
+	}
 
-	return s.repo.Update(payment)
+	return &domain.Refund{
+		PaymentID:        payment.ID,
+		AmountCents:      amountCents,
+		Reason:           reason,
+		ProviderRefundID: reference,
+		Destination:      domain.RefundDestinationWallet,
+	}, nil
 }
@@ -0,0 +1,42 @@
+package application
+
+import (
+	bookingApp "backend-go/features/bookings/application"
+	classApp "backend-go/features/classes/application"
+	"time"
+)
+
+// BookingRefundProcessor implementa bookingApp.RefundProcessor usando el PaymentService
+type BookingRefundProcessor struct {
+	service *PaymentService
+}
+
+// NewBookingRefundProcessor crea el adaptador de reembolsos para el módulo bookings
+func NewBookingRefundProcessor(service *PaymentService) bookingApp.RefundProcessor {
+	return &BookingRefundProcessor{service: service}
+}
+
+// RefundBookingCancellation aplica la política de cancelación al pago de la reserva
+func (p *BookingRefundProcessor) RefundBookingCancellation(bookingID int, startTime time.Time) (int, error) {
+	return p.service.RefundBookingCancellation(uint(bookingID), startTime)
+}
+
+// RefundUnpaidBooking devuelve las partes ya pagadas de una reserva con pago dividido sin confirmar
+func (p *BookingRefundProcessor) RefundUnpaidBooking(bookingID int) (int, error) {
+	return p.service.RefundUnpaidBooking(uint(bookingID))
+}
+
+// EnrollmentRefundProcessor implementa classApp.RefundProcessor usando el PaymentService
+type EnrollmentRefundProcessor struct {
+	service *PaymentService
+}
+
+// NewEnrollmentRefundProcessor crea el adaptador de reembolsos para las inscripciones a clases
+func NewEnrollmentRefundProcessor(service *PaymentService) classApp.RefundProcessor {
+	return &EnrollmentRefundProcessor{service: service}
+}
+
+// RefundEnrollmentCancellation aplica la política de cancelación al pago de la inscripción
+func (p *EnrollmentRefundProcessor) RefundEnrollmentCancellation(enrollmentID int, classStartTime time.Time) (int, error) {
+	return p.service.RefundEnrollmentCancellation(uint(enrollmentID), classStartTime)
+}
@@ -0,0 +1,159 @@
+package application
+
+import (
+	"backend-go/features/payments/domain"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// EnrollmentPaymentHandler aplica en el módulo de clases el resultado asíncrono del pago de una inscripción
+type EnrollmentPaymentHandler interface {
+	// CancelUnpaidEnrollment da de baja la inscripción cuyo pago falló (libera la plaza)
+	CancelUnpaidEnrollment(enrollmentID int) error
+}
+
+// MembershipPaymentHandler aplica en el módulo de clubs el resultado asíncrono de un cobro de renovación
+type MembershipPaymentHandler interface {
+	ConfirmRenewalPayment(membershipID, paymentID int) error
+	FailRenewalPayment(membershipID, paymentID int) error
+}
+
+// PassPaymentHandler aplica en el módulo de bonos el resultado asíncrono del cobro de un bono
+type PassPaymentHandler interface {
+	ConfirmPassPayment(passID int) error
+	FailPassPayment(passID int) error
+}
+
+// WebhookService recibe los webhooks de los proveedores de pago: verifica la firma, guarda
+// cada evento una sola vez y aplica las transiciones del pago (PENDING -> COMPLETED/FAILED,
+// reembolsos) junto con el estado de la reserva, inscripción, membresía o bono enlazado.
+type WebhookService struct {
+	repo        domain.PaymentRepository
+	verifiers   map[string]domain.WebhookVerifier
+	enrollments EnrollmentPaymentHandler
+	memberships MembershipPaymentHandler
+	passes      PassPaymentHandler
+}
+
+// NewWebhookService crea el servicio con los verificadores de los proveedores configurados
+func NewWebhookService(repo domain.PaymentRepository, verifiers ...domain.WebhookVerifier) *WebhookService {
+	service := &WebhookService{
+		repo:      repo,
+		verifiers: map[string]domain.WebhookVerifier{},
+	}
+	for _, verifier := range verifiers {
+		service.verifiers[verifier.Provider()] = verifier
+	}
+	return service
+}
+
+// SetEnrollmentHandler configura quién cancela las inscripciones con pago fallido.
+// Se inyecta después de construir los servicios porque classes no depende de payments.
+func (s *WebhookService) SetEnrollmentHandler(handler EnrollmentPaymentHandler) {
+	s.enrollments = handler
+}
+
+// SetMembershipHandler configura quién confirma o marca como fallidas las renovaciones pendientes
+func (s *WebhookService) SetMembershipHandler(handler MembershipPaymentHandler) {
+	s.memberships = handler
+}
+
+// SetPassHandler configura quién activa o cancela los bonos con cobro pendiente
+func (s *WebhookService) SetPassHandler(handler PassPaymentHandler) {
+	s.passes = handler
+}
+
+// HandleWebhook procesa un webhook de provider ("stripe", "mock"). header devuelve el valor de
+// una cabecera de la petición. Devuelve el evento y si es un reenvío ya procesado.
+func (s *WebhookService) HandleWebhook(provider string, payload []byte, header func(string) string) (*domain.PaymentEvent, bool, error) {
+	verifier, ok := s.verifiers[strings.ToUpper(provider)]
+	if !ok {
+		return nil, false, domain.ErrUnknownWebhookProvider
+	}
+
+	now := time.Now()
+	event, err := verifier.Parse(payload, header(verifier.SignatureHeader()), now)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// 1. Guardar el evento (idempotente por proveedor + ID del evento)
+	event.Status = domain.EventStatusReceived
+	event.ReceivedAt = now
+	if _, err := s.repo.SaveEvent(event); err != nil {
+		return nil, false, fmt.Errorf("error al guardar el evento %s: %w", event.EventID, err)
+	}
+	if event.IsFinal() {
+		return event, true, nil
+	}
+	retry := event.Status == domain.EventStatusFailed
+
+	// 2. Aplicar la transición al pago y a la reserva enlazada (una transacción)
+	result, err := s.repo.ApplyEvent(event)
+	if err != nil {
+		return nil, false, s.fail(event, err)
+	}
+	if result.Payment == nil {
+		return event, false, nil
+	}
+
+	// 3. Estado de la inscripción o membresía enlazada. Solo se reintenta si la transición es
+	// nueva o el intento anterior falló; los handlers son idempotentes.
+	if result.Changed || retry {
+		if err := s.dispatch(result.Payment); err != nil {
+			return nil, false, s.fail(event, err)
+		}
+	}
+
+	return event, false, nil
+}
+
+// dispatch propaga el nuevo estado del pago a inscripciones, membresías y bonos
+func (s *WebhookService) dispatch(payment *domain.Payment) error {
+	switch {
+	case payment.ClassEnrollmentID != nil && s.enrollments != nil:
+		if payment.Status != domain.StatusFailed {
+			return nil
+		}
+		// Si hay un pago posterior de la inscripción (reintento), ese es el que cuenta
+		latest, err := s.repo.GetByClassEnrollment(*payment.ClassEnrollmentID)
+		if err != nil {
+			return err
+		}
+		if latest.ID != payment.ID {
+			return nil
+		}
+		return s.enrollments.CancelUnpaidEnrollment(int(*payment.ClassEnrollmentID))
+	case payment.ClubMembershipID != nil && s.memberships != nil:
+		membershipID := int(*payment.ClubMembershipID)
+		switch payment.Status {
+		case domain.StatusCompleted:
+			return s.memberships.ConfirmRenewalPayment(membershipID, int(payment.ID))
+		case domain.StatusFailed:
+			return s.memberships.FailRenewalPayment(membershipID, int(payment.ID))
+		}
+	case payment.PassID != nil && s.passes != nil:
+		switch payment.Status {
+		case domain.StatusCompleted:
+			return s.passes.ConfirmPassPayment(int(*payment.PassID))
+		case domain.StatusFailed:
+			return s.passes.FailPassPayment(int(*payment.PassID))
+		}
+	}
+	return nil
+}
+
+// fail marca el evento como fallido para que el reenvío del proveedor lo reintente
+func (s *WebhookService) fail(event *domain.PaymentEvent, cause error) error {
+	if err := s.repo.MarkEventFailed(event.ID, cause.Error()); err != nil {
+		return errors.Join(cause, err)
+	}
+	return cause
+}
+
+// GetPaymentEvents obtiene los eventos de webhook recibidos para un pago
+func (s *WebhookService) GetPaymentEvents(paymentID uint) ([]domain.PaymentEvent, error) {
+	return s.repo.GetEventsByPayment(paymentID)
+}
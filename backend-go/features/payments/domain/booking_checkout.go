@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Errores del checkout de reservas
+var (
+	ErrBookingNotFound    = errors.New("reserva no encontrada")
+	ErrBookingAlreadyPaid = errors.New("la reserva ya está pagada")
+	ErrBookingNotPayable  = errors.New("la reserva no admite pagos en su estado actual")
+	ErrBookingNotOwned    = errors.New("no puedes pagar una reserva de otro usuario")
+	ErrPaymentPending     = errors.New("la reserva tiene un pago pendiente de confirmar")
+)
+
+// BookingCharge contiene los datos de la reserva a cobrar, leídos con la fila bloqueada.
+// El importe sale siempre de PriceSnapshotCents, nunca del cliente; en una reserva con pago
+// dividido es la suma de las partes aún pendientes (lo que cubre el organizador).
+type BookingCharge struct {
+	BookingID     uint
+	UserID        uuid.UUID
+	CustomerID    string // StripeCustomerID del titular (vacío si no tiene)
+	AmountCents   int
+	StartTime     time.Time
+	Status        string
+	PaymentStatus string
+	HasPending    bool       // Ya existe un pago PENDING (pendiente de confirmación por webhook)
+	SplitDeadline *time.Time // Pago dividido: plazo para completar el pago (nil = sin dividir)
+}
+
+// IsSplit indica si el pago de la reserva está dividido entre los jugadores
+func (b *BookingCharge) IsSplit() bool {
+	return b.SplitDeadline != nil
+}
+
+// BookingChargeFunc valida la reserva bloqueada, realiza el cargo y devuelve el pago a registrar
+type BookingChargeFunc func(booking *BookingCharge) (*Payment, error)
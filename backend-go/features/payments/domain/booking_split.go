@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Errores del pago dividido de reservas
+var (
+	ErrBookingAlreadySplit  = errors.New("la reserva ya tiene el pago dividido")
+	ErrBookingNotSplit      = errors.New("la reserva no tiene el pago dividido")
+	ErrSplitNotOrganizer    = errors.New("solo el organizador de la reserva puede dividir el pago")
+	ErrInvalidSplit         = errors.New("invita entre 1 y 7 jugadores registrados distintos del organizador")
+	ErrSplitUserNotFound    = errors.New("no existe ningún usuario registrado con ese ID o email")
+	ErrInvalidSplitDeadline = errors.New("la fecha límite debe ser futura y no posterior al inicio de la reserva")
+	ErrSplitDeadlinePassed  = errors.New("ha vencido el plazo para completar el pago de la reserva")
+	ErrSplitPromoCode       = errors.New("los códigos promocionales no se admiten en reservas con pago dividido")
+	ErrShareNotFound        = errors.New("parte del pago no encontrada")
+	ErrShareNotOwned        = errors.New("solo el jugador invitado puede pagar su parte")
+	ErrShareNotPayable      = errors.New("la parte ya está pagada o cancelada")
+)
+
+// Estados de una parte del pago dividido
+const (
+	ShareStatusPending   = "PENDING"   // Pendiente de pago
+	ShareStatusPaid      = "PAID"      // Pagada por el jugador
+	ShareStatusCovered   = "COVERED"   // Cubierta por el organizador
+	ShareStatusRefunded  = "REFUNDED"  // Pagada y devuelta (reserva cancelada)
+	ShareStatusCancelled = "CANCELLED" // Sin pagar y reserva cancelada
+)
+
+const (
+	// MaxSplitPlayers es el máximo de jugadores entre los que se divide una reserva (con el organizador)
+	MaxSplitPlayers = 8
+	// DefaultSplitDeadlineLead es la antelación al inicio de la reserva del plazo por defecto
+	DefaultSplitDeadlineLead = 2 * time.Hour
+)
+
+// BookingShare es la parte del precio de una reserva que paga un jugador
+type BookingShare struct {
+	ID          uint
+	BookingID   uint
+	UserID      uuid.UUID
+	UserName    string // Solo lectura
+	AmountCents int
+	Status      string
+	PaymentID   *uint
+	PaidAt      *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+
+	// Datos de la reserva (solo lectura, para el listado "mis partes")
+	PistaName        string
+	BookingStartTime time.Time
+	SplitDeadline    *time.Time
+}
+
+// BookingSplit es el reparto del pago de una reserva entre el organizador y los jugadores invitados
+type BookingSplit struct {
+	BookingID     uint
+	OrganizerID   uuid.UUID
+	TotalCents    int
+	Deadline      time.Time
+	BookingStatus string
+	PaymentStatus string
+	Shares        []BookingShare
+}
+
+// OutstandingCents devuelve lo que falta por pagar (partes pendientes)
+func (s *BookingSplit) OutstandingCents() int {
+	outstanding := 0
+	for _, share := range s.Shares {
+		if share.Status == ShareStatusPending {
+			outstanding += share.AmountCents
+		}
+	}
+	return outstanding
+}
+
+// SplitAmount reparte un importe a partes iguales; los céntimos sobrantes van a la primera parte
+// (la del organizador)
+func SplitAmount(totalCents, players int) []int {
+	shares := make([]int, players)
+	for i := range shares {
+		shares[i] = totalCents / players
+	}
+	shares[0] += totalCents % players
+	return shares
+}
+
+// SplitFunc valida la reserva bloqueada y devuelve el reparto a registrar
+type SplitFunc func(booking *BookingCharge) (*BookingSplit, error)
+
+// ShareCharge contiene la parte a cobrar y su reserva, leídas con las filas bloqueadas
+type ShareCharge struct {
+	Share      BookingShare
+	Booking    BookingCharge
+	CustomerID string // StripeCustomerID del jugador de la parte (vacío si no tiene)
+	HasPending bool   // La parte o el organizador (cubriendo lo pendiente) tienen un cobro PENDING
+}
+
+// ShareChargeFunc valida la parte bloqueada, realiza el cargo y devuelve el pago a registrar
+type ShareChargeFunc func(share *ShareCharge) (*Payment, error)
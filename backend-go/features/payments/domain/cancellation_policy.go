@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidCancellationPolicy se devuelve cuando CANCELLATION_POLICY no tiene el formato esperado
+var ErrInvalidCancellationPolicy = errors.New("política de cancelación inválida (formato: \"24:100,6:50,0:0\")")
+
+// RefundTier indica el porcentaje a reembolsar si se cancela con al menos MinHoursBefore de antelación
+type RefundTier struct {
+	MinHoursBefore int
+	Percent        int
+}
+
+// CancellationPolicy define el reembolso según la antelación de la cancelación.
+// Los tramos se evalúan de mayor a menor antelación; el primero que se cumple decide.
+type CancellationPolicy struct {
+	Tiers []RefundTier
+}
+
+// DefaultCancellationPolicy: 100% con más de 24h, 50% entre 6h y 24h, nada después
+func DefaultCancellationPolicy() CancellationPolicy {
+	return CancellationPolicy{Tiers: []RefundTier{
+		{MinHoursBefore: 24, Percent: 100},
+		{MinHoursBefore: 6, Percent: 50},
+		{MinHoursBefore: 0, Percent: 0},
+	}}
+}
+
+// ParseCancellationPolicy interpreta "horas:porcentaje" separados por comas (p. ej. "24:100,6:50,0:0").
+// Una cadena vacía devuelve la política por defecto.
+func ParseCancellationPolicy(value string) (CancellationPolicy, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return DefaultCancellationPolicy(), nil
+	}
+
+	policy := CancellationPolicy{}
+	for _, raw := range strings.Split(value, ",") {
+		parts := strings.Split(strings.TrimSpace(raw), ":")
+		if len(parts) != 2 {
+			return CancellationPolicy{}, ErrInvalidCancellationPolicy
+		}
+		hours, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+		if err != nil || hours < 0 {
+			return CancellationPolicy{}, ErrInvalidCancellationPolicy
+		}
+		percent, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+		if err != nil || percent < 0 || percent > 100 {
+			return CancellationPolicy{}, ErrInvalidCancellationPolicy
+		}
+		policy.Tiers = append(policy.Tiers, RefundTier{MinHoursBefore: hours, Percent: percent})
+	}
+
+	sort.Slice(policy.Tiers, func(i, j int) bool {
+		return policy.Tiers[i].MinHoursBefore > policy.Tiers[j].MinHoursBefore
+	})
+	return policy, nil
+}
+
+// RefundPercent devuelve el porcentaje a reembolsar al cancelar en cancelledAt algo que empieza en startsAt
+func (p CancellationPolicy) RefundPercent(startsAt, cancelledAt time.Time) int {
+	before := startsAt.Sub(cancelledAt)
+	for _, tier := range p.Tiers {
+		if before >= time.Duration(tier.MinHoursBefore)*time.Hour {
+			return tier.Percent
+		}
+	}
+	return 0
+}
+
+// String devuelve la política en el mismo formato que acepta ParseCancellationPolicy
+func (p CancellationPolicy) String() string {
+	parts := make([]string, len(p.Tiers))
+	for i, tier := range p.Tiers {
+		parts[i] = fmt.Sprintf("%d:%d", tier.MinHoursBefore, tier.Percent)
+	}
+	return strings.Join(parts, ",")
+}
+
+// ProratedRefund calcula la parte no consumida de un periodo pagado (membresías).
+// Se prorratea por días completos restantes; fuera del periodo devuelve 0.
+func ProratedRefund(amountCents int, periodStart, periodEnd, cancelledAt time.Time) int {
+	if !periodEnd.After(periodStart) || !cancelledAt.Before(periodEnd) {
+		return 0
+	}
+	if cancelledAt.Before(periodStart) {
+		return amountCents
+	}
+
+	totalDays := int(periodEnd.Sub(periodStart).Hours() / 24)
+	remainingDays := int(periodEnd.Sub(cancelledAt).Hours() / 24)
+	if totalDays <= 0 {
+		return 0
+	}
+	return amountCents * remainingDays / totalDays
+}
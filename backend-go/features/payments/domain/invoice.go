@@ -0,0 +1,295 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Errores de facturación
+var (
+	ErrInvoiceNotFound    = errors.New("factura no encontrada")
+	ErrPaymentNotInvoiced = errors.New("el pago no está cobrado y no tiene factura")
+	ErrInvalidVATRate     = errors.New("tipo de IVA inválido (porcentaje entre 0 y 100)")
+	ErrInvoiceNotOwned    = errors.New("no puedes ver facturas de otros usuarios")
+)
+
+// Series de facturación: cada serie numera por separado dentro de cada ejercicio fiscal
+const (
+	InvoiceSeriesOrdinary   = "F" // Facturas de los cobros
+	InvoiceSeriesRectifying = "R" // Facturas rectificativas de los reembolsos
+)
+
+// Tipos de factura
+const (
+	InvoiceTypeOrdinary   = "ORDINARY"
+	InvoiceTypeRectifying = "RECTIFYING"
+)
+
+// DefaultVATRatePercent es el IVA general en España
+const DefaultVATRatePercent = 21
+
+// InvoiceIssuer son los datos fiscales del emisor que figuran en cada factura
+type InvoiceIssuer struct {
+	Name    string
+	TaxID   string // NIF/CIF
+	Address string
+}
+
+// InvoiceSettings configura la emisión de facturas (INVOICE_* en el entorno)
+type InvoiceSettings struct {
+	Issuer         InvoiceIssuer
+	VATRatePercent int
+	Location       *time.Location // Zona horaria que decide el ejercicio fiscal de la fecha de emisión
+}
+
+// ParseVATRate interpreta el porcentaje de IVA. Una cadena vacía devuelve el IVA general (21%).
+func ParseVATRate(value string) (int, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return DefaultVATRatePercent, nil
+	}
+	rate, err := strconv.Atoi(value)
+	if err != nil || rate < 0 || rate > 100 {
+		return 0, ErrInvalidVATRate
+	}
+	return rate, nil
+}
+
+// InvoiceLine es una línea de la factura. Los precios son con IVA incluido, por lo que la base
+// y la cuota se obtienen desglosando el total. En las rectificativas los importes son negativos.
+type InvoiceLine struct {
+	ID             uint
+	Description    string
+	Quantity       int
+	UnitPriceCents int // Precio unitario con IVA
+	BaseCents      int
+	VATRatePercent int
+	VATCents       int
+	TotalCents     int
+}
+
+// NewInvoiceLine crea una línea desglosando el IVA del importe total
+func NewInvoiceLine(description string, quantity, unitPriceCents, vatRatePercent int) InvoiceLine {
+	total := unitPriceCents * quantity
+	base, vat := SplitVAT(total, vatRatePercent)
+	return InvoiceLine{
+		Description:    description,
+		Quantity:       quantity,
+		UnitPriceCents: unitPriceCents,
+		BaseCents:      base,
+		VATRatePercent: vatRatePercent,
+		VATCents:       vat,
+		TotalCents:     total,
+	}
+}
+
+// SplitVAT desglosa un importe con IVA incluido en base imponible y cuota (redondeo al céntimo;
+// base + cuota = total siempre)
+func SplitVAT(totalCents, vatRatePercent int) (baseCents, vatCents int) {
+	divisor := 100 + vatRatePercent
+	abs := totalCents
+	if abs < 0 {
+		abs = -abs
+	}
+	base := (abs*100*2 + divisor) / (2 * divisor)
+	if totalCents < 0 {
+		base = -base
+	}
+	return base, totalCents - base
+}
+
+// VATBreakdown es el desglose de la factura por tipo de IVA
+type VATBreakdown struct {
+	VATRatePercent int
+	BaseCents      int
+	VATCents       int
+}
+
+// Invoice es una factura (o rectificativa) emitida para un pago. Los datos del emisor y del
+// cliente se copian al emitirla para que la factura no cambie si después cambian sus fichas.
+type Invoice struct {
+	ID              uint
+	PaymentID       uint
+	RefundID        *uint // Solo rectificativas: reembolso que la origina
+	UserID          uuid.UUID
+	Type            string
+	Series          string
+	FiscalYear      int
+	Sequence        int    // Correlativo sin huecos dentro de la serie y el ejercicio
+	Number          string // p. ej. F2026-000042
+	RectifiesID     *uint  // Solo rectificativas: factura rectificada
+	RectifiesNumber string
+	Reason          string // Motivo de la rectificación
+	Issuer          InvoiceIssuer
+	CustomerName    string
+	CustomerEmail   string
+	CustomerTaxID   string
+	Currency        string
+	Lines           []InvoiceLine
+	BaseCents       int
+	VATCents        int
+	TotalCents      int
+	IssuedAt        time.Time
+	Rectifications  []Invoice // Rectificativas emitidas sobre esta factura (solo lectura)
+}
+
+// InvoiceCustomer son los datos del cliente que figuran en la factura
+type InvoiceCustomer struct {
+	Name  string
+	Email string
+	TaxID string // DNI/NIF
+}
+
+// InvoiceConcept describe el concepto facturado según el tipo de pago
+func InvoiceConcept(payment *Payment) string {
+	switch {
+	case payment.BookingShareID != nil && payment.BookingID != nil:
+		return fmt.Sprintf("Parte de la reserva de pista #%d", *payment.BookingID)
+	case payment.BookingID != nil:
+		return fmt.Sprintf("Reserva de pista #%d", *payment.BookingID)
+	case payment.ClassEnrollmentID != nil:
+		return fmt.Sprintf("Inscripción a clase #%d", *payment.ClassEnrollmentID)
+	case payment.ClubMembershipID != nil:
+		return fmt.Sprintf("Cuota de membresía de club #%d", *payment.ClubMembershipID)
+	case payment.PassID != nil:
+		return fmt.Sprintf("Bono #%d", *payment.PassID)
+	}
+	return fmt.Sprintf("Pago #%d", payment.ID)
+}
+
+// NewPaymentInvoice prepara la factura ordinaria de un pago cobrado (sin numerar)
+func NewPaymentInvoice(payment *Payment, customer InvoiceCustomer, settings InvoiceSettings, issuedAt time.Time) *Invoice {
+	invoice := &Invoice{
+		PaymentID: payment.ID,
+		UserID:    payment.UserID,
+		Type:      InvoiceTypeOrdinary,
+		Series:    InvoiceSeriesOrdinary,
+		Currency:  payment.Currency,
+		Lines:     []InvoiceLine{NewInvoiceLine(InvoiceConcept(payment), 1, payment.GrossCents(), settings.VATRatePercent)},
+	}
+	// El descuento del código promocional figura como línea negativa: el total es lo cobrado
+	if payment.DiscountCents > 0 && payment.PromoCode != nil {
+		invoice.Lines = append(invoice.Lines, NewInvoiceLine(
+			fmt.Sprintf("Descuento (código %s)", *payment.PromoCode),
+			1, -payment.DiscountCents, settings.VATRatePercent,
+		))
+	}
+	invoice.prepare(customer, settings, issuedAt)
+	return invoice
+}
+
+// NewRectifyingInvoice prepara la factura rectificativa (importes negativos) de un reembolso,
+// con el mismo tipo de IVA que la factura original
+func NewRectifyingInvoice(original *Invoice, refund *Refund, customer InvoiceCustomer, settings InvoiceSettings, issuedAt time.Time) *Invoice {
+	rate := settings.VATRatePercent
+	if len(original.Lines) > 0 {
+		rate = original.Lines[0].VATRatePercent
+	}
+	refundID := refund.ID
+	originalID := original.ID
+	invoice := &Invoice{
+		PaymentID:       original.PaymentID,
+		RefundID:        &refundID,
+		UserID:          original.UserID,
+		Type:            InvoiceTypeRectifying,
+		Series:          InvoiceSeriesRectifying,
+		RectifiesID:     &originalID,
+		RectifiesNumber: original.Number,
+		Reason:          fmt.Sprintf("%s (factura %s)", refundReasonLabel(refund.Reason), original.Number),
+		Currency:        original.Currency,
+		Lines: []InvoiceLine{NewInvoiceLine(
+			fmt.Sprintf("Devolución: %s", original.Lines[0].Description),
+			1, -refund.AmountCents, rate,
+		)},
+	}
+	invoice.prepare(customer, settings, issuedAt)
+	return invoice
+}
+
+// refundReasonLabel describe el motivo del reembolso en la rectificativa
+func refundReasonLabel(reason string) string {
+	switch reason {
+	case RefundReasonCancellation:
+		return "Devolución por cancelación"
+	case RefundReasonProvider:
+		return "Devolución tramitada en el proveedor de pagos"
+	}
+	return "Devolución"
+}
+
+// prepare copia emisor y cliente, fija la fecha y el ejercicio fiscal y calcula los totales
+func (inv *Invoice) prepare(customer InvoiceCustomer, settings InvoiceSettings, issuedAt time.Time) {
+	if settings.Location != nil {
+		issuedAt = issuedAt.In(settings.Location)
+	}
+	inv.Issuer = settings.Issuer
+	inv.CustomerName = customer.Name
+	inv.CustomerEmail = customer.Email
+	inv.CustomerTaxID = customer.TaxID
+	inv.IssuedAt = issuedAt
+	inv.FiscalYear = issuedAt.Year()
+	if inv.Currency == "" {
+		inv.Currency = "EUR"
+	}
+
+	inv.BaseCents, inv.VATCents, inv.TotalCents = 0, 0, 0
+	for _, line := range inv.Lines {
+		inv.BaseCents += line.BaseCents
+		inv.VATCents += line.VATCents
+		inv.TotalCents += line.TotalCents
+	}
+}
+
+// Assign numera la factura con el siguiente correlativo de su serie y ejercicio
+func (inv *Invoice) Assign(sequence int) {
+	inv.Sequence = sequence
+	inv.Number = FormatInvoiceNumber(inv.Series, inv.FiscalYear, sequence)
+}
+
+// FormatInvoiceNumber compone el número de factura: serie, ejercicio y correlativo (F2026-000042)
+func FormatInvoiceNumber(series string, fiscalYear, sequence int) string {
+	return fmt.Sprintf("%s%d-%06d", series, fiscalYear, sequence)
+}
+
+// VATBreakdown agrupa base y cuota por tipo de IVA
+func (inv *Invoice) VATBreakdown() []VATBreakdown {
+	var breakdown []VATBreakdown
+	for _, line := range inv.Lines {
+		found := false
+		for i := range breakdown {
+			if breakdown[i].VATRatePercent == line.VATRatePercent {
+				breakdown[i].BaseCents += line.BaseCents
+				breakdown[i].VATCents += line.VATCents
+				found = true
+				break
+			}
+		}
+		if !found {
+			breakdown = append(breakdown, VATBreakdown{
+				VATRatePercent: line.VATRatePercent,
+				BaseCents:      line.BaseCents,
+				VATCents:       line.VATCents,
+			})
+		}
+	}
+	return breakdown
+}
+
+// IsInvoiceable indica si el pago se cobró (y por tanto tiene o debe tener factura)
+func (p *Payment) IsInvoiceable() bool {
+	switch p.Status {
+	case StatusCompleted, StatusPartiallyRefunded, StatusRefunded:
+		return true
+	}
+	return false
+}
+
+// InvoiceRenderer genera el documento descargable (PDF) de una factura
+type InvoiceRenderer interface {
+	Render(invoice *Invoice) ([]byte, error)
+}
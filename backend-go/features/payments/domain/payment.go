@@ -15,14 +15,33 @@ var (
 	ErrCustomerNotFound   = errors.New("cliente no encontrado")
 	ErrInvalidPaymentType = errors.New("tipo de pago inválido")
 	ErrMultipleReferences = errors.New("el pago solo puede estar asociado a un concepto")
+	ErrProviderMismatch   = errors.New("el pago fue procesado por otro proveedor de pagos")
+	ErrNoPaymentMethod    = errors.New("el cliente no tiene un método de pago guardado")
+	ErrEnrollmentNotFound = errors.New("inscripción a clase no encontrada")
+	ErrMembershipNotFound = errors.New("membresía no encontrada")
+	ErrChargeRefunded     = errors.New("el cobro se reembolsó porque no se pudo registrar el pago; repítelo con otra Idempotency-Key")
+
+	ErrEnrollmentNotOwned    = errors.New("no puedes pagar una inscripción de otro usuario")
+	ErrMembershipNotOwned    = errors.New("no puedes pagar una membresía de otro usuario")
+	ErrEnrollmentAlreadyPaid = errors.New("la inscripción ya está pagada")
+	ErrMembershipAlreadyPaid = errors.New("la membresía ya está pagada para el periodo actual")
 )
 
+// ResourceCharge contiene los datos de la inscripción o membresía a cobrar. El importe sale
+// siempre del precio de la clase o de la cuota del club, nunca del cliente.
+type ResourceCharge struct {
+	UserID      uuid.UUID // Titular de la inscripción o membresía
+	AmountCents int
+	Paid        bool // Ya tiene un pago vigente (o un bono consumido) que la cubre
+}
+
 // Payment estados
 const (
-	StatusPending   = "PENDING"
-	StatusCompleted = "COMPLETED"
-	StatusFailed    = "FAILED"
-	StatusRefunded  = "REFUNDED"
+	StatusPending           = "PENDING"
+	StatusCompleted         = "COMPLETED"
+	StatusFailed            = "FAILED"
+	StatusRefunded          = "REFUNDED"
+	StatusPartiallyRefunded = "PARTIALLY_REFUNDED"
 )
 
 // Payment providers
@@ -36,6 +55,7 @@ type Payment struct {
 	ID                    uint
 	UserID                uuid.UUID
 	AmountCents           int
+	RefundedCents         int // Suma de los reembolsos realizados
 	Currency              string
 	Status                string
 	Provider              string
@@ -43,6 +63,11 @@ type Payment struct {
 	BookingID             *uint
 	ClassEnrollmentID     *uint
 	ClubMembershipID      *uint
+	PassID                *uint // Compra de un bono
+	BookingShareID        *uint // Pago dividido: parte de la reserva que paga un jugador (con BookingID)
+	DiscountCents         int   // Descuento del código promocional (AmountCents ya lo descuenta)
+	PromoCode             *string
+	PromoRedemptionID     *uint
 	CreatedAt             time.Time
 	UpdatedAt             time.Time
 }
@@ -64,6 +89,9 @@ func (p *Payment) Validate() error {
 	if p.ClubMembershipID != nil {
 		references++
 	}
+	if p.PassID != nil {
+		references++
+	}
 
 	if references == 0 {
 		return ErrInvalidPaymentType
@@ -74,3 +102,29 @@ func (p *Payment) Validate() error {
 
 	return nil
 }
+
+// ApplyPromoDiscount registra en el pago el código canjeado (nil = sin código). AmountCents
+// debe ser ya el importe cobrado.
+func (p *Payment) ApplyPromoDiscount(discount *PromoDiscount) {
+	if discount == nil {
+		return
+	}
+	code := discount.Code
+	redemptionID := discount.RedemptionID
+	p.DiscountCents = discount.DiscountCents
+	p.PromoCode = &code
+	p.PromoRedemptionID = &redemptionID
+}
+
+// GrossCents devuelve el importe antes del descuento del código promocional
+func (p *Payment) GrossCents() int {
+	return p.AmountCents + p.DiscountCents
+}
+
+// RefundableCents devuelve el importe que aún se puede reembolsar
+func (p *Payment) RefundableCents() int {
+	if p.Status != StatusCompleted && p.Status != StatusPartiallyRefunded {
+		return 0
+	}
+	return p.AmountCents - p.RefundedCents
+}
@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// Errores de webhooks
+var (
+	ErrUnknownWebhookProvider  = errors.New("proveedor de webhooks desconocido o sin secreto configurado")
+	ErrInvalidWebhookSignature = errors.New("firma del webhook inválida")
+	ErrInvalidWebhookPayload   = errors.New("contenido del webhook inválido")
+)
+
+// Estados de procesamiento de un evento recibido por webhook
+const (
+	EventStatusReceived  = "RECEIVED"  // Guardado, pendiente de procesar
+	EventStatusProcessed = "PROCESSED" // Aplicado al pago (aunque no cambiara su estado)
+	EventStatusIgnored   = "IGNORED"   // Tipo no soportado o sin pago asociado
+	EventStatusFailed    = "FAILED"    // Error al aplicarlo; el reenvío del proveedor lo reintenta
+)
+
+// Transiciones normalizadas a partir de los tipos de evento de cada proveedor
+const (
+	EventActionSucceeded = "SUCCEEDED" // p. ej. payment_intent.succeeded
+	EventActionFailed    = "FAILED"    // p. ej. payment_intent.payment_failed / canceled
+	EventActionRefunded  = "REFUNDED"  // p. ej. charge.refunded (total o parcial)
+)
+
+// WebhookSignatureTolerance es la antigüedad máxima aceptada de una firma (protección frente a reenvíos)
+const WebhookSignatureTolerance = 5 * time.Minute
+
+// PaymentEvent representa un evento recibido del proveedor de pagos por webhook.
+// Se guardan todos (provider + EventID es único), incluidos los que no se procesan.
+type PaymentEvent struct {
+	ID              uint
+	Provider        string // ProviderStripe, ProviderMock
+	EventID         string // ID del evento en el proveedor (idempotencia)
+	Type            string // Tipo original del proveedor
+	PaymentIntentID string
+	PaymentID       *uint // Pago afectado, si se encontró
+	Payload         []byte
+	Status          string
+	Error           *string
+	ReceivedAt      time.Time
+	ProcessedAt     *time.Time
+
+	// Calculados al verificar el webhook (no se guardan)
+	Action              string // EventAction*; vacío si el tipo no se procesa
+	AmountRefundedCents int    // Total reembolsado según el proveedor (EventActionRefunded)
+	ProviderRefundID    string // Último reembolso del proveedor (EventActionRefunded)
+}
+
+// IsFinal indica si el evento ya se procesó y un reenvío no debe volver a aplicarlo
+func (e *PaymentEvent) IsFinal() bool {
+	return e.Status == EventStatusProcessed || e.Status == EventStatusIgnored
+}
+
+// PaymentEventResult es el resultado de aplicar un evento a su pago
+type PaymentEventResult struct {
+	Event   *PaymentEvent
+	Payment *Payment // nil si el evento no corresponde a ningún pago
+	Changed bool     // true si el evento cambió el estado del pago
+}
+
+// ApplyEvent aplica la transición del evento al pago y devuelve si cambió y el importe
+// reembolsado nuevo (solo EventActionRefunded). Los eventos que llegan fuera de orden o
+// repetidos no hacen retroceder el estado:
+//   - SUCCEEDED: PENDING -> COMPLETED
+//   - FAILED:    PENDING -> FAILED
+//   - REFUNDED:  PENDING/COMPLETED/PARTIALLY_REFUNDED -> PARTIALLY_REFUNDED/REFUNDED
+func (p *Payment) ApplyEvent(event *PaymentEvent) (changed bool, refundedDelta int) {
+	switch event.Action {
+	case EventActionSucceeded:
+		if p.Status == StatusPending {
+			p.Status = StatusCompleted
+			return true, 0
+		}
+	case EventActionFailed:
+		if p.Status == StatusPending {
+			p.Status = StatusFailed
+			return true, 0
+		}
+	case EventActionRefunded:
+		if p.Status != StatusPending && p.Status != StatusCompleted && p.Status != StatusPartiallyRefunded {
+			return false, 0
+		}
+		refunded := event.AmountRefundedCents
+		if refunded > p.AmountCents {
+			refunded = p.AmountCents
+		}
+		if refunded <= p.RefundedCents {
+			return false, 0
+		}
+		refundedDelta = refunded - p.RefundedCents
+		p.RefundedCents = refunded
+		p.Status = StatusPartiallyRefunded
+		if refunded >= p.AmountCents {
+			p.Status = StatusRefunded
+		}
+		return true, refundedDelta
+	}
+	return false, 0
+}
+
+// WebhookVerifier verifica la firma de los webhooks de un proveedor y traduce el evento al dominio
+type WebhookVerifier interface {
+	// Provider identifica el proveedor (ProviderStripe, ProviderMock)
+	Provider() string
+
+	// SignatureHeader es la cabecera HTTP que contiene la firma
+	SignatureHeader() string
+
+	// Parse verifica la firma (ErrInvalidWebhookSignature) y decodifica el evento (ErrInvalidWebhookPayload)
+	Parse(payload []byte, signature string, now time.Time) (*PaymentEvent, error)
+}
@@ -3,15 +3,27 @@ package domain
 // PaymentGateway define la interfaz para proveedores de pago
 // Puede ser implementada por Stripe, PayPal, Mock, etc.
 type PaymentGateway interface {
+	// Name identifica el proveedor (ProviderStripe, ProviderMock); se guarda en cada pago
+	Name() string
+
 	// CreateCustomer crea un cliente en el proveedor de pagos
 	// Retorna el ID del cliente en el sistema del proveedor
 	CreateCustomer(email, name string) (string, error)
 
 	// Charge procesa un cargo
-	// Retorna el ID de la transacción/intento de pago
-	Charge(amountCents int, customerID string, description string) (string, error)
+	// Retorna el ID de la transacción/intento de pago y si quedó cobrado o pendiente de confirmación.
+	// Con idempotencyKey (opcional) el proveedor devuelve el mismo cargo si se repite la llamada.
+	Charge(amountCents int, customerID string, description string, idempotencyKey string) (*ChargeResult, error)
 
 	// Refund procesa un reembolso
 	// Retorna el ID del reembolso
 	Refund(paymentIntentID string, amountCents int) (string, error)
 }
+
+// ChargeResult es el resultado de un cargo en el proveedor
+type ChargeResult struct {
+	PaymentIntentID string
+	// StatusCompleted si el cobro se realizó, StatusPending si el proveedor lo confirmará
+	// más tarde por webhook (autenticación 3DS, adeudos SEPA...)
+	Status string
+}
@@ -1,7 +1,17 @@
 package domain
 
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
 // PaymentRepository define las operaciones de persistencia para pagos
 type PaymentRepository interface {
+	// WithContext devuelve el repositorio con sus consultas ligadas a ctx (plazo y cancelación)
+	WithContext(ctx context.Context) PaymentRepository
+
 	Create(payment *Payment) error
 	GetByID(id uint) (*Payment, error)
 	GetByUser(userID uint) ([]Payment, error)
@@ -9,4 +19,57 @@ type PaymentRepository interface {
 	GetByClassEnrollment(enrollmentID uint) (*Payment, error)
 	GetByClubMembership(membershipID uint) ([]Payment, error)
 	Update(payment *Payment) error
+
+	// CheckoutBooking bloquea la reserva (SELECT ... FOR UPDATE), ejecuta charge y, en la misma
+	// transacción, registra el pago y, si quedó cobrado, marca la reserva como PAID/CONFIRMED
+	CheckoutBooking(bookingID uint, charge BookingChargeFunc) (*Payment, error)
+	// GetEnrollmentCharge devuelve el titular y el precio de la clase de la inscripción, y si ya
+	// está pagada con un pago vigente o un bono (ErrEnrollmentNotFound si no existe)
+	GetEnrollmentCharge(enrollmentID uint) (*ResourceCharge, error)
+	// GetMembershipCharge devuelve el titular y la cuota mensual del club de la membresía, y si el
+	// periodo actual ya está pagado o tiene un cobro pendiente (ErrMembershipNotFound si no existe)
+	GetMembershipCharge(membershipID uint, now time.Time) (*ResourceCharge, error)
+	// GetAllByBooking obtiene todos los pagos de una reserva (el del titular y los de sus partes)
+	GetAllByBooking(bookingID uint) ([]Payment, error)
+
+	// Pago dividido
+	// ResolveUsers devuelve los IDs de los usuarios indicados por ID o email (ErrSplitUserNotFound
+	// si alguno no existe)
+	ResolveUsers(userIDs []uuid.UUID, emails []string) ([]uuid.UUID, error)
+	// CreateBookingSplit bloquea la reserva, ejecuta split y guarda las partes y el plazo
+	CreateBookingSplit(bookingID uint, split SplitFunc) (*BookingSplit, error)
+	GetBookingSplit(bookingID uint) (*BookingSplit, error)
+	GetSharesByUser(userID uuid.UUID) ([]BookingShare, error)
+	// CheckoutBookingShare bloquea la reserva y la parte, ejecuta charge y registra el pago; si
+	// quedó cobrado marca la parte como PAID y, si era la última pendiente, la reserva como PAID/CONFIRMED
+	CheckoutBookingShare(shareID uint, charge ShareChargeFunc) (*Payment, error)
+	// FindExpiredSplits devuelve las reservas con pago dividido sin completar cuyo plazo venció
+	FindExpiredSplits(now time.Time) ([]uint, error)
+	// CancelSplitShares marca las partes pagadas como REFUNDED y las pendientes como CANCELLED
+	CancelSplitShares(bookingID uint) error
+	// CancelExpiredSplit cancela la reserva si sigue sin pagar y con el plazo vencido (false si no)
+	CancelExpiredSplit(bookingID uint, now time.Time) (bool, error)
+
+	// CreateRefund bloquea el pago, ejecuta refund y guarda el reembolso actualizando
+	// RefundedCents y el estado del pago en la misma transacción
+	CreateRefund(paymentID uint, refund RefundFunc) (*Refund, error)
+	GetRefundsByPayment(paymentID uint) ([]Refund, error)
+
+	// Webhooks
+	// SaveEvent guarda el evento si no existía (provider + EventID); si ya existía devuelve false
+	// y rellena event con el estado guardado
+	SaveEvent(event *PaymentEvent) (bool, error)
+	// ApplyEvent bloquea el evento y el pago del PaymentIntent, aplica la transición
+	// (Payment.ApplyEvent) junto con el estado de la reserva enlazada y marca el evento
+	// como procesado, todo en la misma transacción
+	ApplyEvent(event *PaymentEvent) (*PaymentEventResult, error)
+	MarkEventFailed(eventID uint, reason string) error
+	GetEventsByPayment(paymentID uint) ([]PaymentEvent, error)
+
+	// Facturas: la ordinaria se emite al quedar cobrado el pago y la rectificativa con cada
+	// reembolso, en la misma transacción (numeración correlativa por serie y ejercicio)
+	GetInvoiceByPayment(paymentID uint) (*Invoice, error)
+	GetInvoiceByNumber(number string) (*Invoice, error)
+	// IssueInvoice emite las facturas que falten a un pago cobrado (pagos anteriores a la facturación)
+	IssueInvoice(paymentID uint) (*Invoice, error)
 }
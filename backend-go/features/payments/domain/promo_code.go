@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// Conceptos a los que se aplica un código promocional al cobrar
+const (
+	PromoResourcePista = "PISTA"
+	PromoResourceClass = "CLASS"
+	PromoResourceClub  = "CLUB"
+)
+
+// Errores del canje de códigos promocionales
+var (
+	ErrInvalidPromoCode      = errors.New("código promocional no válido")
+	ErrPromoCodesUnavailable = errors.New("los códigos promocionales no están disponibles")
+)
+
+// PromoDiscount es el descuento de un código canjeado para un cobro
+type PromoDiscount struct {
+	RedemptionID  uint
+	Code          string
+	DiscountCents int
+}
+
+// PromoCodeRedeemer canjea códigos promocionales. Lo implementa el módulo pricing; se define
+// aquí porque payments no depende de él.
+type PromoCodeRedeemer interface {
+	// Redeem valida el código para el usuario y el concepto, registra el uso y devuelve el
+	// descuento sobre amountCents. Los motivos de rechazo envuelven ErrInvalidPromoCode.
+	Redeem(code string, userID uuid.UUID, resourceType string, amountCents int) (*PromoDiscount, error)
+	// Release libera el uso de un código cuyo cobro no llegó a realizarse
+	Release(redemptionID uint) error
+}
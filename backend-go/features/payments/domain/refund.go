@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// Errores de reembolso
+var (
+	ErrRefundExceedsPayment = errors.New("el reembolso supera el importe pendiente del pago")
+	ErrPaymentNotRefundable = errors.New("el pago no admite reembolsos en su estado actual")
+)
+
+// Motivos de reembolso
+const (
+	RefundReasonCancellation = "CANCELLATION" // Aplicando la política de cancelación
+	RefundReasonManual       = "MANUAL"       // Reembolso manual de ADMIN/GESTOR
+	RefundReasonProvider     = "PROVIDER"     // Hecho directamente en el proveedor (notificado por webhook)
+)
+
+// Destinos del reembolso
+const (
+	RefundDestinationOriginal = "ORIGINAL" // Al medio con el que se pagó
+	RefundDestinationWallet   = "WALLET"   // Al monedero del titular
+)
+
+// Refund representa un reembolso (total o parcial) asociado a un pago
+type Refund struct {
+	ID               uint
+	PaymentID        uint
+	AmountCents      int
+	Reason           string
+	ProviderRefundID string
+	Destination      string // RefundDestinationOriginal o RefundDestinationWallet
+	CreatedAt        time.Time
+}
+
+// RefundFunc valida el pago bloqueado, ejecuta el reembolso en el proveedor y devuelve el registro a guardar
+type RefundFunc func(payment *Payment) (*Refund, error)
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// ProviderWallet identifica los pagos cobrados del monedero (saldo prepago) del usuario
+const ProviderWallet = "WALLET"
+
+// Métodos de pago que elige el cliente
+const (
+	PaymentMethodCard   = "card"   // Proveedor configurado (Stripe o mock), por defecto
+	PaymentMethodWallet = "wallet" // Saldo del monedero del titular
+)
+
+// Errores del pago con monedero
+var (
+	ErrInvalidPaymentMethod = errors.New("método de pago inválido (card o wallet)")
+	ErrWalletUnavailable    = errors.New("el pago con monedero no está disponible")
+	ErrInsufficientFunds    = errors.New("saldo insuficiente en el monedero")
+)
+
+// WalletLedger es el libro del monedero de los usuarios. Lo implementa el módulo wallet;
+// se define aquí porque payments no depende de él.
+type WalletLedger interface {
+	// Debit cobra del saldo y devuelve la referencia del cargo (idempotente por clave)
+	Debit(userID uuid.UUID, amountCents int, description, idempotencyKey string) (string, error)
+	// RefundDebit devuelve al monedero (parte de) un cargo y devuelve la referencia de la devolución
+	RefundDebit(debitReference string, amountCents int) (string, error)
+	// CreditRefund abona en el monedero el reembolso de un pago cobrado con tarjeta
+	CreditRefund(userID uuid.UUID, amountCents int, paymentID uint) (string, error)
+}
+
+// WalletGateway es el monedero como fuente de financiación con la interfaz de PaymentGateway:
+// Charge cobra del saldo del usuario (customerID = UUID del usuario) y Refund lo devuelve.
+// CreditRefund permite además reembolsar al monedero pagos hechos con tarjeta.
+type WalletGateway interface {
+	PaymentGateway
+	CreditRefund(userID uuid.UUID, amountCents int, paymentID uint) (string, error)
+}
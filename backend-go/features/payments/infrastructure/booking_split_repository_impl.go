@@ -0,0 +1,424 @@
+package infrastructure
+
+import (
+	bookingDomain "backend-go/features/bookings/domain"
+	"backend-go/features/payments/domain"
+	"backend-go/shared/database"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ResolveUsers devuelve los IDs de los usuarios indicados por ID o por email, sin repetidos
+func (r *PaymentRepositoryImpl) ResolveUsers(userIDs []uuid.UUID, emails []string) ([]uuid.UUID, error) {
+	resolved := make([]uuid.UUID, 0, len(userIDs)+len(emails))
+	seen := make(map[uuid.UUID]bool)
+
+	if len(userIDs) > 0 {
+		var users []database.User
+		if err := r.db.Select("id").Where("id IN ?", userIDs).Find(&users).Error; err != nil {
+			return nil, err
+		}
+		found := make(map[uuid.UUID]bool, len(users))
+		for _, user := range users {
+			found[user.ID] = true
+		}
+		for _, id := range userIDs {
+			if !found[id] {
+				return nil, domain.ErrSplitUserNotFound
+			}
+			if !seen[id] {
+				seen[id] = true
+				resolved = append(resolved, id)
+			}
+		}
+	}
+
+	for _, email := range emails {
+		var user database.User
+		if err := r.db.Select("id").Where("LOWER(email) = ?", strings.ToLower(strings.TrimSpace(email))).First(&user).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, domain.ErrSplitUserNotFound
+			}
+			return nil, err
+		}
+		if !seen[user.ID] {
+			seen[user.ID] = true
+			resolved = append(resolved, user.ID)
+		}
+	}
+
+	return resolved, nil
+}
+
+// CreateBookingSplit divide el pago con la reserva bloqueada, de modo que no puede cobrarse
+// ni dividirse dos veces a la vez
+func (r *PaymentRepositoryImpl) CreateBookingSplit(bookingID uint, split domain.SplitFunc) (*domain.BookingSplit, error) {
+	var created *domain.BookingSplit
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var booking database.Booking
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Preload("User").
+			First(&booking, bookingID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domain.ErrBookingNotFound
+			}
+			return err
+		}
+
+		bookingCharge, err := r.bookingCharge(tx, &booking)
+		if err != nil {
+			return err
+		}
+		var pending int64
+		if err := tx.Model(&database.Payment{}).
+			Where("booking_id = ? AND status = ?", booking.ID, domain.StatusPending).
+			Count(&pending).Error; err != nil {
+			return err
+		}
+		bookingCharge.HasPending = pending > 0
+
+		if created, err = split(bookingCharge); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		for i := range created.Shares {
+			dbShare := r.mapper.ShareToDatabase(&created.Shares[i])
+			dbShare.CreatedAt = now
+			dbShare.UpdatedAt = now
+			if err := tx.Create(dbShare).Error; err != nil {
+				return err
+			}
+			created.Shares[i].ID = dbShare.ID
+			created.Shares[i].CreatedAt = now
+			created.Shares[i].UpdatedAt = now
+		}
+
+		return tx.Model(&database.Booking{}).Where("id = ?", booking.ID).Updates(map[string]interface{}{
+			"split_deadline": created.Deadline,
+			"updated_at":     now,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// GetBookingSplit obtiene el reparto del pago de una reserva con el estado de cada parte
+func (r *PaymentRepositoryImpl) GetBookingSplit(bookingID uint) (*domain.BookingSplit, error) {
+	var booking database.Booking
+	if err := r.db.First(&booking, bookingID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrBookingNotFound
+		}
+		return nil, err
+	}
+	if booking.SplitDeadline == nil {
+		return nil, domain.ErrBookingNotSplit
+	}
+
+	var dbShares []database.BookingShare
+	if err := r.db.Preload("User").Where("booking_id = ?", bookingID).Order("id ASC").Find(&dbShares).Error; err != nil {
+		return nil, err
+	}
+
+	split := &domain.BookingSplit{
+		BookingID:     booking.ID,
+		OrganizerID:   booking.UserID,
+		TotalCents:    booking.PriceSnapshotCents,
+		Deadline:      *booking.SplitDeadline,
+		BookingStatus: booking.Status,
+		PaymentStatus: booking.PaymentStatus,
+		Shares:        make([]domain.BookingShare, len(dbShares)),
+	}
+	for i := range dbShares {
+		split.Shares[i] = *r.mapper.ShareToDomain(&dbShares[i])
+	}
+	return split, nil
+}
+
+// GetSharesByUser obtiene las partes de reservas con pago dividido de un jugador
+func (r *PaymentRepositoryImpl) GetSharesByUser(userID uuid.UUID) ([]domain.BookingShare, error) {
+	var dbShares []database.BookingShare
+	if err := r.db.Preload("User").Preload("Booking.Pista").
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&dbShares).Error; err != nil {
+		return nil, err
+	}
+
+	shares := make([]domain.BookingShare, len(dbShares))
+	for i := range dbShares {
+		shares[i] = *r.mapper.ShareToDomain(&dbShares[i])
+	}
+	return shares, nil
+}
+
+// CheckoutBookingShare cobra la parte de un jugador con la reserva y la parte bloqueadas: el
+// pago de una parte y la cobertura del organizador se serializan sobre la misma reserva
+func (r *PaymentRepositoryImpl) CheckoutBookingShare(shareID uint, charge domain.ShareChargeFunc) (*domain.Payment, error) {
+	var payment *domain.Payment
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var dbShare database.BookingShare
+		if err := tx.First(&dbShare, shareID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domain.ErrShareNotFound
+			}
+			return err
+		}
+
+		// Primero la reserva y después la parte: el mismo orden que el resto de cobros
+		var booking database.Booking
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Preload("User").
+			First(&booking, dbShare.BookingID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domain.ErrBookingNotFound
+			}
+			return err
+		}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Preload("User").
+			First(&dbShare, shareID).Error; err != nil {
+			return err
+		}
+
+		bookingCharge, err := r.bookingCharge(tx, &booking)
+		if err != nil {
+			return err
+		}
+		shareCharge := &domain.ShareCharge{
+			Share:   *r.mapper.ShareToDomain(&dbShare),
+			Booking: *bookingCharge,
+		}
+		if dbShare.User.StripeCustomerID != nil {
+			shareCharge.CustomerID = *dbShare.User.StripeCustomerID
+		}
+
+		// Un cobro pendiente de esta parte o del organizador cubriendo lo pendiente
+		var pending int64
+		if err := tx.Model(&database.Payment{}).
+			Where("booking_id = ? AND status = ?", booking.ID, domain.StatusPending).
+			Where("booking_share_id IS NULL OR booking_share_id = ?", shareID).
+			Count(&pending).Error; err != nil {
+			return err
+		}
+		shareCharge.HasPending = pending > 0
+
+		if payment, err = charge(shareCharge); err != nil {
+			return err
+		}
+
+		dbPayment := r.mapper.ToDatabase(payment)
+		if err := tx.Create(dbPayment).Error; err != nil {
+			if isDuplicateBookingPayment(err) {
+				return domain.ErrShareNotPayable
+			}
+			return err
+		}
+		payment.ID = dbPayment.ID
+
+		// Un cobro pendiente (3DS, SEPA) deja la parte pendiente hasta que llegue el webhook
+		if payment.Status != domain.StatusCompleted {
+			return nil
+		}
+		now := time.Now()
+		if _, err := r.issueInvoice(tx, payment, now); err != nil {
+			return err
+		}
+		if err := r.markSharePaid(tx, shareID, payment.ID, now); err != nil {
+			return err
+		}
+		return r.settleSplit(tx, booking.ID, now)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+// FindExpiredSplits devuelve las reservas con pago dividido que hay que cancelar (plazo vencido
+// sin completar el pago ni cobros pendientes) o cuyas partes pagadas siguen sin reembolsar
+// tras cancelarse
+func (r *PaymentRepositoryImpl) FindExpiredSplits(now time.Time) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&database.Booking{}).
+		Where("split_deadline IS NOT NULL").
+		Where(r.db.
+			Where("split_deadline <= ? AND status = ? AND payment_status = ? AND NOT EXISTS (?)",
+				now, bookingDomain.StatusPending, bookingDomain.PaymentStatusUnpaid,
+				r.db.Model(&database.Payment{}).Select("1").
+					Where("payments.booking_id = bookings.id AND payments.status = ?", domain.StatusPending)).
+			Or("status = ? AND EXISTS (?)",
+				bookingDomain.StatusCancelled,
+				r.db.Model(&database.BookingShare{}).Select("1").
+					Where("booking_shares.booking_id = bookings.id AND booking_shares.status = ?", domain.ShareStatusPaid))).
+		Order("split_deadline ASC").
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// CancelExpiredSplit cancela la reserva, con la fila bloqueada, si sigue sin completar el pago
+// y el plazo venció. Devuelve true si la reserva queda cancelada (también si ya lo estaba).
+func (r *PaymentRepositoryImpl) CancelExpiredSplit(bookingID uint, now time.Time) (bool, error) {
+	cancelled := false
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var booking database.Booking
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&booking, bookingID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domain.ErrBookingNotFound
+			}
+			return err
+		}
+		if booking.Status == bookingDomain.StatusCancelled {
+			cancelled = true
+			return nil
+		}
+		if booking.SplitDeadline == nil || booking.SplitDeadline.After(now) ||
+			booking.Status != bookingDomain.StatusPending || booking.PaymentStatus != bookingDomain.PaymentStatusUnpaid {
+			return nil
+		}
+
+		var pending int64
+		if err := tx.Model(&database.Payment{}).
+			Where("booking_id = ? AND status = ?", booking.ID, domain.StatusPending).
+			Count(&pending).Error; err != nil {
+			return err
+		}
+		if pending > 0 {
+			return nil
+		}
+
+		cancelled = true
+		return tx.Model(&database.Booking{}).Where("id = ?", booking.ID).Updates(map[string]interface{}{
+			"status":     bookingDomain.StatusCancelled,
+			"updated_at": now,
+		}).Error
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return cancelled, nil
+}
+
+// CancelSplitShares cierra las partes de una reserva cancelada: las pagadas pasan a REFUNDED y
+// las pendientes a CANCELLED. Si la reserva no llegó a pagarse y se devolvió alguna parte, su
+// estado de pago pasa a REFUNDED.
+func (r *PaymentRepositoryImpl) CancelSplitShares(bookingID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		refunded := tx.Model(&database.BookingShare{}).
+			Where("booking_id = ? AND status = ?", bookingID, domain.ShareStatusPaid).
+			Updates(map[string]interface{}{"status": domain.ShareStatusRefunded, "updated_at": now})
+		if refunded.Error != nil {
+			return refunded.Error
+		}
+		if err := tx.Model(&database.BookingShare{}).
+			Where("booking_id = ? AND status = ?", bookingID, domain.ShareStatusPending).
+			Updates(map[string]interface{}{"status": domain.ShareStatusCancelled, "updated_at": now}).Error; err != nil {
+			return err
+		}
+
+		if refunded.RowsAffected == 0 {
+			return nil
+		}
+		return tx.Model(&database.Booking{}).
+			Where("id = ? AND payment_status = ?", bookingID, bookingDomain.PaymentStatusUnpaid).
+			Updates(map[string]interface{}{
+				"payment_status": bookingDomain.PaymentStatusRefunded,
+				"updated_at":     now,
+			}).Error
+	})
+}
+
+// bookingCharge construye los datos de cobro de una reserva bloqueada. Con pago dividido el
+// importe es la suma de las partes aún pendientes.
+func (r *PaymentRepositoryImpl) bookingCharge(tx *gorm.DB, booking *database.Booking) (*domain.BookingCharge, error) {
+	bookingCharge := &domain.BookingCharge{
+		BookingID:     booking.ID,
+		UserID:        booking.UserID,
+		AmountCents:   booking.PriceSnapshotCents,
+		StartTime:     booking.StartTime,
+		Status:        booking.Status,
+		PaymentStatus: booking.PaymentStatus,
+		SplitDeadline: booking.SplitDeadline,
+	}
+	if booking.User.StripeCustomerID != nil {
+		bookingCharge.CustomerID = *booking.User.StripeCustomerID
+	}
+
+	if booking.SplitDeadline != nil {
+		var outstanding int64
+		if err := tx.Model(&database.BookingShare{}).
+			Where("booking_id = ? AND status = ?", booking.ID, domain.ShareStatusPending).
+			Select("COALESCE(SUM(amount_cents), 0)").
+			Scan(&outstanding).Error; err != nil {
+			return nil, err
+		}
+		bookingCharge.AmountCents = int(outstanding)
+	}
+	return bookingCharge, nil
+}
+
+// markSharePaid registra el cobro de una parte que seguía pendiente
+func (r *PaymentRepositoryImpl) markSharePaid(tx *gorm.DB, shareID, paymentID uint, now time.Time) error {
+	return tx.Model(&database.BookingShare{}).
+		Where("id = ? AND status = ?", shareID, domain.ShareStatusPending).
+		Updates(map[string]interface{}{
+			"status":     domain.ShareStatusPaid,
+			"payment_id": paymentID,
+			"paid_at":    now,
+			"updated_at": now,
+		}).Error
+}
+
+// coverPendingShares marca como cubiertas por el pago del organizador las partes pendientes
+// (sin efecto en reservas sin pago dividido)
+func (r *PaymentRepositoryImpl) coverPendingShares(tx *gorm.DB, bookingID, paymentID uint, now time.Time) error {
+	return tx.Model(&database.BookingShare{}).
+		Where("booking_id = ? AND status = ?", bookingID, domain.ShareStatusPending).
+		Updates(map[string]interface{}{
+			"status":     domain.ShareStatusCovered,
+			"payment_id": paymentID,
+			"paid_at":    now,
+			"updated_at": now,
+		}).Error
+}
+
+// settleSplit marca la reserva como pagada (y confirmada si estaba pendiente) cuando ya no
+// quedan partes pendientes
+func (r *PaymentRepositoryImpl) settleSplit(tx *gorm.DB, bookingID uint, now time.Time) error {
+	var pending int64
+	if err := tx.Model(&database.BookingShare{}).
+		Where("booking_id = ? AND status = ?", bookingID, domain.ShareStatusPending).
+		Count(&pending).Error; err != nil {
+		return err
+	}
+	if pending > 0 {
+		return nil
+	}
+
+	if err := tx.Model(&database.Booking{}).
+		Where("id = ? AND payment_status = ? AND status != ?", bookingID, bookingDomain.PaymentStatusUnpaid, bookingDomain.StatusCancelled).
+		Updates(map[string]interface{}{
+			"payment_status": bookingDomain.PaymentStatusPaid,
+			"updated_at":     now,
+		}).Error; err != nil {
+		return err
+	}
+	return tx.Model(&database.Booking{}).
+		Where("id = ? AND status = ?", bookingID, bookingDomain.StatusPending).
+		Update("status", bookingDomain.StatusConfirmed).Error
+}
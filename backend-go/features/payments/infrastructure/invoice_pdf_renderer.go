@@ -0,0 +1,245 @@
+package infrastructure
+
+import (
+	"backend-go/features/payments/domain"
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// InvoicePDFRenderer genera la factura en PDF (una página A4) sin dependencias externas:
+// usa las fuentes estándar Helvetica con codificación WinAnsi (acentos, ñ y €).
+type InvoicePDFRenderer struct {
+	location *time.Location
+}
+
+// NewInvoicePDFRenderer crea el generador; location es la zona horaria de las fechas impresas
+func NewInvoicePDFRenderer(location *time.Location) domain.InvoiceRenderer {
+	if location == nil {
+		location = time.Local
+	}
+	return &InvoicePDFRenderer{location: location}
+}
+
+// Columnas de la tabla de líneas (borde derecho de las columnas numéricas)
+const (
+	pdfMarginLeft  = 50.0
+	pdfMarginRight = 545.0
+	pdfColQuantity = 290.0
+	pdfColPrice    = 355.0
+	pdfColBase     = 415.0
+	pdfColRate     = 455.0
+	pdfColVAT      = 500.0
+)
+
+// Render genera el PDF de la factura
+func (p *InvoicePDFRenderer) Render(invoice *domain.Invoice) ([]byte, error) {
+	page := &pdfPage{}
+
+	title := "FACTURA"
+	if invoice.Type == domain.InvoiceTypeRectifying {
+		title = "FACTURA RECTIFICATIVA"
+	}
+	page.text(pdfMarginLeft, 790, 18, true, title)
+	page.text(pdfMarginLeft, 768, 11, true, "Nº "+invoice.Number)
+	page.textRight(pdfMarginRight, 768, 10, false, "Fecha: "+invoice.IssuedAt.In(p.location).Format("02/01/2006"))
+
+	y := 750.0
+	if invoice.Type == domain.InvoiceTypeRectifying {
+		page.text(pdfMarginLeft, y, 9, false, "Rectifica la factura "+invoice.RectifiesNumber)
+		page.text(pdfMarginLeft, y-12, 9, false, "Motivo: "+truncate(invoice.Reason, 95))
+		y -= 24
+	}
+
+	// Emisor y cliente
+	y -= 20
+	page.text(pdfMarginLeft, y, 9, true, "EMISOR")
+	page.text(310, y, 9, true, "CLIENTE")
+	issuer := []string{invoice.Issuer.Name, labelled("NIF: ", invoice.Issuer.TaxID), invoice.Issuer.Address}
+	customer := []string{invoice.CustomerName, labelled("NIF/DNI: ", invoice.CustomerTaxID), invoice.CustomerEmail}
+	for i := range issuer {
+		y -= 13
+		page.text(pdfMarginLeft, y, 10, false, truncate(issuer[i], 45))
+		page.text(310, y, 10, false, truncate(customer[i], 45))
+	}
+
+	// Líneas
+	y -= 40
+	page.text(pdfMarginLeft, y, 9, true, "Concepto")
+	page.textRight(pdfColQuantity, y, 9, true, "Cant.")
+	page.textRight(pdfColPrice, y, 9, true, "Precio")
+	page.textRight(pdfColBase, y, 9, true, "Base")
+	page.textRight(pdfColRate, y, 9, true, "IVA")
+	page.textRight(pdfColVAT, y, 9, true, "Cuota")
+	page.textRight(pdfMarginRight, y, 9, true, "Total")
+	page.line(pdfMarginLeft, y-5, pdfMarginRight, y-5)
+
+	for _, line := range invoice.Lines {
+		y -= 18
+		page.text(pdfMarginLeft, y, 9, false, truncate(line.Description, 40))
+		page.textRight(pdfColQuantity, y, 9, false, fmt.Sprintf("%d", line.Quantity))
+		page.textRight(pdfColPrice, y, 9, false, formatEuros(line.UnitPriceCents))
+		page.textRight(pdfColBase, y, 9, false, formatEuros(line.BaseCents))
+		page.textRight(pdfColRate, y, 9, false, fmt.Sprintf("%d%%", line.VATRatePercent))
+		page.textRight(pdfColVAT, y, 9, false, formatEuros(line.VATCents))
+		page.textRight(pdfMarginRight, y, 9, false, formatEuros(line.TotalCents))
+	}
+	page.line(pdfMarginLeft, y-8, pdfMarginRight, y-8)
+
+	// Desglose de IVA y totales
+	y -= 30
+	page.text(pdfMarginLeft, y, 9, true, "Desglose de IVA")
+	for _, vat := range invoice.VATBreakdown() {
+		y -= 13
+		page.text(pdfMarginLeft, y, 9, false, fmt.Sprintf("Base imponible al %d%%: %s    Cuota: %s",
+			vat.VATRatePercent, formatEuros(vat.BaseCents), formatEuros(vat.VATCents)))
+	}
+
+	totalsY := y + 13*float64(len(invoice.VATBreakdown()))
+	page.text(380, totalsY, 10, false, "Base imponible")
+	page.textRight(pdfMarginRight, totalsY, 10, false, formatEuros(invoice.BaseCents))
+	page.text(380, totalsY-14, 10, false, "IVA")
+	page.textRight(pdfMarginRight, totalsY-14, 10, false, formatEuros(invoice.VATCents))
+	page.text(380, totalsY-32, 12, true, "TOTAL")
+	page.textRight(pdfMarginRight, totalsY-32, 12, true, formatEuros(invoice.TotalCents))
+
+	page.text(pdfMarginLeft, 60, 8, false, fmt.Sprintf("Precios con IVA incluido. Moneda: %s. Pago #%d.", invoice.Currency, invoice.PaymentID))
+
+	return page.document(invoice.Number), nil
+}
+
+// pdfPage acumula el contenido de una página
+type pdfPage struct {
+	content bytes.Buffer
+}
+
+func (p *pdfPage) text(x, y, size float64, bold bool, value string) {
+	if value == "" {
+		return
+	}
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(&p.content, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, y, pdfString(value))
+}
+
+// textRight escribe el texto alineado a la derecha en x
+func (p *pdfPage) textRight(x, y, size float64, bold bool, value string) {
+	p.text(x-textWidth(value, size), y, size, bold, value)
+}
+
+func (p *pdfPage) line(x1, y1, x2, y2 float64) {
+	fmt.Fprintf(&p.content, "0.5 w %.2f %.2f m %.2f %.2f l S\n", x1, y1, x2, y2)
+}
+
+// document compone el fichero PDF con su tabla de referencias cruzadas
+func (p *pdfPage) document(title string) []byte {
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 595 842] /Resources << /Font << /F1 4 0 R /F2 5 0 R >> >> /Contents 6 0 R >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", p.content.Len(), p.content.String()),
+		fmt.Sprintf("<< /Title (Factura %s) /Producer (PoliManage) >>", pdfString(title)),
+	}
+
+	var out bytes.Buffer
+	out.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = out.Len()
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, len(objects), xref)
+	return out.Bytes()
+}
+
+// pdfString convierte el texto a WinAnsi y escapa los caracteres especiales de las cadenas PDF
+func pdfString(value string) string {
+	var out strings.Builder
+	for _, r := range value {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			out.WriteByte('\\')
+			out.WriteByte(byte(r))
+		case r == '€':
+			out.WriteByte(0x80)
+		case r == '…':
+			out.WriteByte(0x85)
+		case r >= 0x20 && r < 0x7f, r >= 0xa0 && r <= 0xff:
+			out.WriteByte(byte(r))
+		default:
+			out.WriteByte('?')
+		}
+	}
+	return out.String()
+}
+
+// textWidth estima el ancho del texto con las métricas de Helvetica (en milésimas del tamaño):
+// exacto para importes y fechas, aproximado para el resto de letras
+func textWidth(value string, size float64) float64 {
+	units := 0
+	for _, r := range value {
+		switch {
+		case r == ' ' || r == ',' || r == '.' || r == ':' || r == '/':
+			units += 278
+		case r == '-':
+			units += 333
+		case r == '%':
+			units += 889
+		case r >= 'A' && r <= 'Z':
+			units += 667
+		case r >= 'a' && r <= 'z':
+			units += 500
+		default:
+			// Cifras y €
+			units += 556
+		}
+	}
+	return float64(units) * size / 1000
+}
+
+// formatEuros formatea céntimos al estilo español: 1.234,56 €
+func formatEuros(cents int) string {
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+
+	integer := fmt.Sprintf("%d", cents/100)
+	var grouped strings.Builder
+	for i, digit := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			grouped.WriteByte('.')
+		}
+		grouped.WriteRune(digit)
+	}
+	return fmt.Sprintf("%s%s,%02d €", sign, grouped.String(), cents%100)
+}
+
+// labelled devuelve la etiqueta con el valor o vacío si no hay valor
+func labelled(label, value string) string {
+	if value == "" {
+		return ""
+	}
+	return label + value
+}
+
+// truncate recorta el texto a max caracteres para que no se salga de su columna
+func truncate(value string, max int) string {
+	runes := []rune(value)
+	if len(runes) <= max {
+		return value
+	}
+	return string(runes[:max-1]) + "…"
+}
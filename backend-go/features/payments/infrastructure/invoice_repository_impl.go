@@ -0,0 +1,185 @@
+package infrastructure
+
+import (
+	"backend-go/features/payments/domain"
+	"backend-go/shared/database"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Facturación: las facturas se emiten dentro de la misma transacción que cobra o reembolsa el
+// pago, de modo que todo pago cobrado tiene factura y todo reembolso su rectificativa.
+
+// GetInvoiceByPayment obtiene la factura ordinaria de un pago con sus rectificativas
+func (r *PaymentRepositoryImpl) GetInvoiceByPayment(paymentID uint) (*domain.Invoice, error) {
+	var dbInvoice database.Invoice
+	if err := r.db.Preload("Lines").
+		Where("payment_id = ? AND type = ?", paymentID, domain.InvoiceTypeOrdinary).
+		First(&dbInvoice).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrInvoiceNotFound
+		}
+		return nil, err
+	}
+	return r.withRectifications(r.db, &dbInvoice)
+}
+
+// GetInvoiceByNumber obtiene una factura (ordinaria o rectificativa) por su número
+func (r *PaymentRepositoryImpl) GetInvoiceByNumber(number string) (*domain.Invoice, error) {
+	var dbInvoice database.Invoice
+	if err := r.db.Preload("Lines").Where("number = ?", number).First(&dbInvoice).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrInvoiceNotFound
+		}
+		return nil, err
+	}
+	if dbInvoice.Type != domain.InvoiceTypeOrdinary {
+		return r.mapper.InvoiceToDomain(&dbInvoice), nil
+	}
+	return r.withRectifications(r.db, &dbInvoice)
+}
+
+// IssueInvoice emite las facturas que le falten a un pago cobrado antes de que existiera la
+// facturación: la ordinaria y una rectificativa por cada reembolso. Es idempotente.
+func (r *PaymentRepositoryImpl) IssueInvoice(paymentID uint) (*domain.Invoice, error) {
+	var invoice *domain.Invoice
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var dbPayment database.Payment
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&dbPayment, paymentID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domain.ErrPaymentNotFound
+			}
+			return err
+		}
+
+		payment := r.mapper.ToDomain(&dbPayment)
+		if !payment.IsInvoiceable() {
+			return domain.ErrPaymentNotInvoiced
+		}
+
+		now := time.Now()
+		original, err := r.issueInvoice(tx, payment, now)
+		if err != nil {
+			return err
+		}
+
+		var dbRefunds []database.Refund
+		if err := tx.Where("payment_id = ?", paymentID).
+			Where("id NOT IN (?)", tx.Model(&database.Invoice{}).Select("refund_id").Where("refund_id IS NOT NULL")).
+			Order("created_at ASC").
+			Find(&dbRefunds).Error; err != nil {
+			return err
+		}
+		for i := range dbRefunds {
+			if _, err := r.issueRectifyingInvoice(tx, payment, r.mapper.RefundToDomain(&dbRefunds[i]), now); err != nil {
+				return err
+			}
+		}
+
+		invoice, err = r.withRectifications(tx, original)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return invoice, nil
+}
+
+// issueInvoice emite la factura ordinaria del pago si aún no la tiene
+func (r *PaymentRepositoryImpl) issueInvoice(tx *gorm.DB, payment *domain.Payment, now time.Time) (*database.Invoice, error) {
+	var existing database.Invoice
+	err := tx.Preload("Lines").
+		Where("payment_id = ? AND type = ?", payment.ID, domain.InvoiceTypeOrdinary).
+		First(&existing).Error
+	if err == nil {
+		return &existing, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	customer, err := r.invoiceCustomer(tx, payment.UserID)
+	if err != nil {
+		return nil, err
+	}
+	return r.saveInvoice(tx, domain.NewPaymentInvoice(payment, customer, r.invoicing, now))
+}
+
+// issueRectifyingInvoice emite la rectificativa de un reembolso (y antes la factura ordinaria
+// si el pago no la tenía), con los datos del cliente de la factura original
+func (r *PaymentRepositoryImpl) issueRectifyingInvoice(tx *gorm.DB, payment *domain.Payment, refund *domain.Refund, now time.Time) (*database.Invoice, error) {
+	dbOriginal, err := r.issueInvoice(tx, payment, now)
+	if err != nil {
+		return nil, err
+	}
+
+	original := r.mapper.InvoiceToDomain(dbOriginal)
+	customer := domain.InvoiceCustomer{
+		Name:  original.CustomerName,
+		Email: original.CustomerEmail,
+		TaxID: original.CustomerTaxID,
+	}
+	return r.saveInvoice(tx, domain.NewRectifyingInvoice(original, refund, customer, r.invoicing, now))
+}
+
+// saveInvoice numera la factura con el siguiente correlativo de su serie y ejercicio y la guarda
+func (r *PaymentRepositoryImpl) saveInvoice(tx *gorm.DB, invoice *domain.Invoice) (*database.Invoice, error) {
+	sequence, err := nextInvoiceSequence(tx, invoice.Series, invoice.FiscalYear)
+	if err != nil {
+		return nil, err
+	}
+	invoice.Assign(sequence)
+
+	dbInvoice := r.mapper.InvoiceToDatabase(invoice)
+	if err := tx.Create(dbInvoice).Error; err != nil {
+		return nil, err
+	}
+	invoice.ID = dbInvoice.ID
+	return dbInvoice, nil
+}
+
+// nextInvoiceSequence incrementa el contador de la serie y el ejercicio. El UPSERT bloquea la fila
+// hasta el commit: las facturas concurrentes se numeran en orden y un rollback no deja huecos.
+func nextInvoiceSequence(tx *gorm.DB, series string, fiscalYear int) (int, error) {
+	var sequence int
+	err := tx.Raw(`
+		INSERT INTO invoice_sequences (series, fiscal_year, last_number) VALUES (?, ?, 1)
+		ON CONFLICT (series, fiscal_year) DO UPDATE SET last_number = invoice_sequences.last_number + 1
+		RETURNING last_number`, series, fiscalYear).Row().Scan(&sequence)
+	return sequence, err
+}
+
+// invoiceCustomer obtiene los datos fiscales del usuario (también si la cuenta se dio de baja)
+func (r *PaymentRepositoryImpl) invoiceCustomer(tx *gorm.DB, userID uuid.UUID) (domain.InvoiceCustomer, error) {
+	var user database.User
+	if err := tx.Unscoped().First(&user, "id = ?", userID).Error; err != nil {
+		return domain.InvoiceCustomer{}, err
+	}
+
+	customer := domain.InvoiceCustomer{Name: user.FullName, Email: user.Email}
+	if user.DNI != nil {
+		customer.TaxID = *user.DNI
+	}
+	return customer, nil
+}
+
+// withRectifications carga las rectificativas emitidas sobre la factura
+func (r *PaymentRepositoryImpl) withRectifications(db *gorm.DB, dbInvoice *database.Invoice) (*domain.Invoice, error) {
+	var dbRectifications []database.Invoice
+	if err := db.Preload("Lines").Where("rectifies_id = ?", dbInvoice.ID).Order("id ASC").Find(&dbRectifications).Error; err != nil {
+		return nil, err
+	}
+
+	invoice := r.mapper.InvoiceToDomain(dbInvoice)
+	invoice.Rectifications = make([]domain.Invoice, len(dbRectifications))
+	for i := range dbRectifications {
+		invoice.Rectifications[i] = *r.mapper.InvoiceToDomain(&dbRectifications[i])
+	}
+	return invoice, nil
+}
@@ -4,15 +4,28 @@ import (
 	"backend-go/features/payments/domain"
 	"fmt"
 	"math/rand"
+	"sync"
 	"time"
 )
 
+// MockCustomerRequiresAction simula un cliente cuyo banco exige autenticación (3DS):
+// el cargo queda PENDING hasta que llegue un webhook mock (POST /api/payments/webhooks/mock)
+const MockCustomerRequiresAction = "cus_mock_requires_action"
+
 // MockPaymentProvider implementa PaymentGateway para desarrollo y testing
-type MockPaymentProvider struct{}
+type MockPaymentProvider struct {
+	mu      sync.Mutex
+	charges map[string]*domain.ChargeResult // Cargos por clave de idempotencia
+}
 
 // NewMockPaymentProvider crea una nueva instancia del proveedor mock
 func NewMockPaymentProvider() domain.PaymentGateway {
-	return &MockPaymentProvider{}
+	return &MockPaymentProvider{charges: map[string]*domain.ChargeResult{}}
+}
+
+// Name identifica el proveedor
+func (m *MockPaymentProvider) Name() string {
+	return domain.ProviderMock
 }
 
 // CreateCustomer simula la creación de un cliente
@@ -27,8 +40,18 @@ func (m *MockPaymentProvider) CreateCustomer(email, name string) (string, error)
 	return customerID, nil
 }
 
-// Charge simula un cargo
-func (m *MockPaymentProvider) Charge(amountCents int, customerID string, description string) (string, error) {
+// Charge simula un cargo. Como Stripe, una clave de idempotencia repetida devuelve el mismo cargo.
+func (m *MockPaymentProvider) Charge(amountCents int, customerID string, description string, idempotencyKey string) (*domain.ChargeResult, error) {
+	if idempotencyKey != "" {
+		m.mu.Lock()
+		previous, ok := m.charges[idempotencyKey]
+		m.mu.Unlock()
+		if ok {
+			fmt.Printf("🔁 [MOCK PAYMENT] Charge repetido (Idempotency-Key %s): %s\n\n", idempotencyKey, previous.PaymentIntentID)
+			return previous, nil
+		}
+	}
+
 	paymentIntentID := fmt.Sprintf("pi_mock_%d", time.Now().UnixNano())
 	amountEuros := float64(amountCents) / 100.0
 
@@ -40,14 +63,29 @@ func (m *MockPaymentProvider) Charge(amountCents int, customerID string, descrip
 	// Simular una pequeña latencia de red
 	time.Sleep(100 * time.Millisecond)
 
+	if customerID == MockCustomerRequiresAction {
+		fmt.Printf("   ⏳ PENDING - Payment Intent: %s (requiere autenticación)\n\n", paymentIntentID)
+		return m.remember(idempotencyKey, &domain.ChargeResult{PaymentIntentID: paymentIntentID, Status: domain.StatusPending}), nil
+	}
+
 	// 95% de éxito, 5% de fallo simulado
 	if rand.Intn(100) < 95 {
 		fmt.Printf("   ✅ SUCCESS - Payment Intent: %s\n\n", paymentIntentID)
-		return paymentIntentID, nil
+		return m.remember(idempotencyKey, &domain.ChargeResult{PaymentIntentID: paymentIntentID, Status: domain.StatusCompleted}), nil
 	}
 
 	fmt.Printf("   ❌ FAILED - Simulated payment failure\n\n")
-	return "", domain.ErrPaymentFailed
+	return nil, domain.ErrPaymentFailed
+}
+
+// remember guarda el cargo asociado a la clave de idempotencia
+func (m *MockPaymentProvider) remember(idempotencyKey string, result *domain.ChargeResult) *domain.ChargeResult {
+	if idempotencyKey != "" {
+		m.mu.Lock()
+		m.charges[idempotencyKey] = result
+		m.mu.Unlock()
+	}
+	return result
 }
 
 // Refund simula un reembolso
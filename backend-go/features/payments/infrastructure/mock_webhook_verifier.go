@@ -0,0 +1,78 @@
+package infrastructure
+
+import (
+	"backend-go/features/payments/domain"
+	"encoding/json"
+	"time"
+)
+
+// MockSignatureHeader es la cabecera con la firma de los webhooks del proveedor mock
+const MockSignatureHeader = "X-Mock-Signature"
+
+// MockWebhookVerifier verifica los webhooks simulados del MockPaymentProvider. Usa el mismo
+// esquema de firma que Stripe (SignWebhookPayload) con el secreto MOCK_WEBHOOK_SECRET.
+//
+// Formato del evento:
+//
+//	{"id": "evt_1", "type": "payment.succeeded", "payment_intent": "pi_mock_...", "amount_refunded": 0}
+//
+// Tipos: payment.succeeded, payment.failed y payment.refunded (amount_refunded = total reembolsado)
+type MockWebhookVerifier struct {
+	secret string
+}
+
+// NewMockWebhookVerifier crea el verificador de webhooks mock
+func NewMockWebhookVerifier(secret string) domain.WebhookVerifier {
+	return &MockWebhookVerifier{secret: secret}
+}
+
+type mockEvent struct {
+	ID             string `json:"id"`
+	Type           string `json:"type"`
+	PaymentIntent  string `json:"payment_intent"`
+	AmountRefunded int    `json:"amount_refunded"`
+	RefundID       string `json:"refund_id"`
+}
+
+// Provider identifica el proveedor
+func (v *MockWebhookVerifier) Provider() string {
+	return domain.ProviderMock
+}
+
+// SignatureHeader devuelve la cabecera de la firma
+func (v *MockWebhookVerifier) SignatureHeader() string {
+	return MockSignatureHeader
+}
+
+// Parse verifica la firma y traduce el evento
+func (v *MockWebhookVerifier) Parse(payload []byte, signature string, now time.Time) (*domain.PaymentEvent, error) {
+	if err := verifyWebhookSignature(v.secret, payload, signature, now); err != nil {
+		return nil, err
+	}
+
+	var raw mockEvent
+	if err := json.Unmarshal(payload, &raw); err != nil || raw.ID == "" || raw.Type == "" {
+		return nil, domain.ErrInvalidWebhookPayload
+	}
+
+	event := &domain.PaymentEvent{
+		Provider:        domain.ProviderMock,
+		EventID:         raw.ID,
+		Type:            raw.Type,
+		PaymentIntentID: raw.PaymentIntent,
+		Payload:         payload,
+	}
+
+	switch raw.Type {
+	case "payment.succeeded":
+		event.Action = domain.EventActionSucceeded
+	case "payment.failed":
+		event.Action = domain.EventActionFailed
+	case "payment.refunded":
+		event.Action = domain.EventActionRefunded
+		event.AmountRefundedCents = raw.AmountRefunded
+		event.ProviderRefundID = raw.RefundID
+	}
+
+	return event, nil
+}
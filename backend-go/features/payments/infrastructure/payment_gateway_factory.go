@@ -0,0 +1,71 @@
+package infrastructure
+
+import (
+	"backend-go/features/payments/domain"
+	"fmt"
+	"strings"
+	"time"
+	_ "time/tzdata" // La imagen alpine no incluye la base de datos de zonas horarias
+)
+
+// NewPaymentGateway selecciona el proveedor de pagos según la configuración (PAYMENT_PROVIDER):
+//   - "mock" (por defecto): MockPaymentProvider, sin llamadas externas
+//   - "stripe": StripePaymentProvider; requiere secretKey (STRIPE_SECRET_KEY) y admite una
+//     URL base alternativa (STRIPE_API_BASE), p. ej. el stub local http://localhost:12111
+func NewPaymentGateway(provider, secretKey, baseURL string) (domain.PaymentGateway, error) {
+	switch strings.ToLower(strings.TrimSpace(provider)) {
+	case "", "mock":
+		return NewMockPaymentProvider(), nil
+	case "stripe":
+		if secretKey == "" {
+			return nil, fmt.Errorf("PAYMENT_PROVIDER=stripe requiere STRIPE_SECRET_KEY")
+		}
+		return NewStripePaymentProvider(secretKey, baseURL), nil
+	}
+	return nil, fmt.Errorf("proveedor de pagos desconocido: %q (usar mock o stripe)", provider)
+}
+
+// NewWebhookVerifiers crea los verificadores de webhooks de los proveedores con secreto
+// configurado: STRIPE_WEBHOOK_SECRET (whsec_...) y MOCK_WEBHOOK_SECRET. Un proveedor sin
+// secreto no acepta webhooks.
+func NewWebhookVerifiers(stripeSecret, mockSecret string) []domain.WebhookVerifier {
+	verifiers := []domain.WebhookVerifier{}
+	if stripeSecret != "" {
+		verifiers = append(verifiers, NewStripeWebhookVerifier(stripeSecret))
+	}
+	if mockSecret != "" {
+		verifiers = append(verifiers, NewMockWebhookVerifier(mockSecret))
+	}
+	return verifiers
+}
+
+// NewInvoiceSettings construye la configuración de facturación (INVOICE_ISSUER_NAME,
+// INVOICE_ISSUER_TAX_ID, INVOICE_ISSUER_ADDRESS, INVOICE_VAT_RATE e INVOICE_TIMEZONE).
+// Por defecto IVA del 21% y ejercicio fiscal según la hora de Europe/Madrid.
+func NewInvoiceSettings(issuerName, issuerTaxID, issuerAddress, vatRate, timezone string) (domain.InvoiceSettings, error) {
+	rate, err := domain.ParseVATRate(vatRate)
+	if err != nil {
+		return domain.InvoiceSettings{}, err
+	}
+
+	if timezone == "" {
+		timezone = "Europe/Madrid"
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return domain.InvoiceSettings{}, fmt.Errorf("zona horaria de facturación inválida %q: %w", timezone, err)
+	}
+
+	if issuerName == "" {
+		issuerName = "PoliManage"
+	}
+	return domain.InvoiceSettings{
+		Issuer: domain.InvoiceIssuer{
+			Name:    issuerName,
+			TaxID:   issuerTaxID,
+			Address: issuerAddress,
+		},
+		VATRatePercent: rate,
+		Location:       location,
+	}, nil
+}
@@ -16,6 +16,7 @@ func (m *PaymentMapper) ToDomain(dbPayment *database.Payment) *domain.Payment {
 		ID:                    dbPayment.ID,
 		UserID:                dbPayment.UserID,
 		AmountCents:           dbPayment.AmountCents,
+		RefundedCents:         dbPayment.RefundedCents,
 		Currency:              dbPayment.Currency,
 		Status:                dbPayment.Status,
 		Provider:              dbPayment.Provider,
@@ -23,6 +24,11 @@ func (m *PaymentMapper) ToDomain(dbPayment *database.Payment) *domain.Payment {
 		BookingID:             dbPayment.BookingID,
 		ClassEnrollmentID:     dbPayment.ClassEnrollmentID,
 		ClubMembershipID:      dbPayment.ClubMembershipID,
+		PassID:                dbPayment.PassID,
+		BookingShareID:        dbPayment.BookingShareID,
+		DiscountCents:         dbPayment.DiscountCents,
+		PromoCode:             dbPayment.PromoCode,
+		PromoRedemptionID:     dbPayment.PromoRedemptionID,
 		CreatedAt:             dbPayment.CreatedAt,
 		UpdatedAt:             dbPayment.UpdatedAt,
 	}
@@ -33,6 +39,7 @@ func (m *PaymentMapper) ToDatabase(payment *domain.Payment) *database.Payment {
 		ID:                    payment.ID,
 		UserID:                payment.UserID,
 		AmountCents:           payment.AmountCents,
+		RefundedCents:         payment.RefundedCents,
 		Currency:              payment.Currency,
 		Status:                payment.Status,
 		Provider:              payment.Provider,
@@ -40,7 +47,202 @@ func (m *PaymentMapper) ToDatabase(payment *domain.Payment) *database.Payment {
 		BookingID:             payment.BookingID,
 		ClassEnrollmentID:     payment.ClassEnrollmentID,
 		ClubMembershipID:      payment.ClubMembershipID,
+		PassID:                payment.PassID,
+		BookingShareID:        payment.BookingShareID,
+		DiscountCents:         payment.DiscountCents,
+		PromoCode:             payment.PromoCode,
+		PromoRedemptionID:     payment.PromoRedemptionID,
 		CreatedAt:             payment.CreatedAt,
 		UpdatedAt:             payment.UpdatedAt,
 	}
 }
+
+func (m *PaymentMapper) RefundToDomain(dbRefund *database.Refund) *domain.Refund {
+	return &domain.Refund{
+		ID:               dbRefund.ID,
+		PaymentID:        dbRefund.PaymentID,
+		AmountCents:      dbRefund.AmountCents,
+		Reason:           dbRefund.Reason,
+		ProviderRefundID: dbRefund.ProviderRefundID,
+		Destination:      dbRefund.Destination,
+		CreatedAt:        dbRefund.CreatedAt,
+	}
+}
+
+func (m *PaymentMapper) RefundToDatabase(refund *domain.Refund) *database.Refund {
+	destination := refund.Destination
+	if destination == "" {
+		destination = domain.RefundDestinationOriginal
+	}
+	return &database.Refund{
+		ID:               refund.ID,
+		PaymentID:        refund.PaymentID,
+		AmountCents:      refund.AmountCents,
+		Reason:           refund.Reason,
+		ProviderRefundID: refund.ProviderRefundID,
+		Destination:      destination,
+	}
+}
+
+func (m *PaymentMapper) EventToDomain(dbEvent *database.PaymentEvent) *domain.PaymentEvent {
+	event := &domain.PaymentEvent{
+		ID:          dbEvent.ID,
+		Provider:    dbEvent.Provider,
+		EventID:     dbEvent.EventID,
+		Type:        dbEvent.Type,
+		PaymentID:   dbEvent.PaymentID,
+		Payload:     []byte(dbEvent.Payload),
+		Status:      dbEvent.Status,
+		Error:       dbEvent.Error,
+		ReceivedAt:  dbEvent.ReceivedAt,
+		ProcessedAt: dbEvent.ProcessedAt,
+	}
+	if dbEvent.PaymentIntentID != nil {
+		event.PaymentIntentID = *dbEvent.PaymentIntentID
+	}
+	return event
+}
+
+func (m *PaymentMapper) EventToDatabase(event *domain.PaymentEvent) *database.PaymentEvent {
+	dbEvent := &database.PaymentEvent{
+		ID:          event.ID,
+		Provider:    event.Provider,
+		EventID:     event.EventID,
+		Type:        event.Type,
+		PaymentID:   event.PaymentID,
+		Payload:     string(event.Payload),
+		Status:      event.Status,
+		Error:       event.Error,
+		ReceivedAt:  event.ReceivedAt,
+		ProcessedAt: event.ProcessedAt,
+	}
+	if event.PaymentIntentID != "" {
+		intentID := event.PaymentIntentID
+		dbEvent.PaymentIntentID = &intentID
+	}
+	return dbEvent
+}
+
+func (m *PaymentMapper) InvoiceToDomain(dbInvoice *database.Invoice) *domain.Invoice {
+	invoice := &domain.Invoice{
+		ID:              dbInvoice.ID,
+		PaymentID:       dbInvoice.PaymentID,
+		RefundID:        dbInvoice.RefundID,
+		UserID:          dbInvoice.UserID,
+		Type:            dbInvoice.Type,
+		Series:          dbInvoice.Series,
+		FiscalYear:      dbInvoice.FiscalYear,
+		Sequence:        dbInvoice.Sequence,
+		Number:          dbInvoice.Number,
+		RectifiesID:     dbInvoice.RectifiesID,
+		RectifiesNumber: dbInvoice.RectifiesNumber,
+		Reason:          dbInvoice.Reason,
+		Issuer: domain.InvoiceIssuer{
+			Name:    dbInvoice.IssuerName,
+			TaxID:   dbInvoice.IssuerTaxID,
+			Address: dbInvoice.IssuerAddress,
+		},
+		CustomerName:  dbInvoice.CustomerName,
+		CustomerEmail: dbInvoice.CustomerEmail,
+		CustomerTaxID: dbInvoice.CustomerTaxID,
+		Currency:      dbInvoice.Currency,
+		BaseCents:     dbInvoice.BaseCents,
+		VATCents:      dbInvoice.VATCents,
+		TotalCents:    dbInvoice.TotalCents,
+		IssuedAt:      dbInvoice.IssuedAt,
+		Lines:         make([]domain.InvoiceLine, len(dbInvoice.Lines)),
+	}
+	for i, line := range dbInvoice.Lines {
+		invoice.Lines[i] = domain.InvoiceLine{
+			ID:             line.ID,
+			Description:    line.Description,
+			Quantity:       line.Quantity,
+			UnitPriceCents: line.UnitPriceCents,
+			BaseCents:      line.BaseCents,
+			VATRatePercent: line.VATRatePercent,
+			VATCents:       line.VATCents,
+			TotalCents:     line.TotalCents,
+		}
+	}
+	return invoice
+}
+
+func (m *PaymentMapper) InvoiceToDatabase(invoice *domain.Invoice) *database.Invoice {
+	dbInvoice := &database.Invoice{
+		ID:              invoice.ID,
+		PaymentID:       invoice.PaymentID,
+		RefundID:        invoice.RefundID,
+		UserID:          invoice.UserID,
+		Type:            invoice.Type,
+		Series:          invoice.Series,
+		FiscalYear:      invoice.FiscalYear,
+		Sequence:        invoice.Sequence,
+		Number:          invoice.Number,
+		RectifiesID:     invoice.RectifiesID,
+		RectifiesNumber: invoice.RectifiesNumber,
+		Reason:          invoice.Reason,
+		IssuerName:      invoice.Issuer.Name,
+		IssuerTaxID:     invoice.Issuer.TaxID,
+		IssuerAddress:   invoice.Issuer.Address,
+		CustomerName:    invoice.CustomerName,
+		CustomerEmail:   invoice.CustomerEmail,
+		CustomerTaxID:   invoice.CustomerTaxID,
+		Currency:        invoice.Currency,
+		BaseCents:       invoice.BaseCents,
+		VATCents:        invoice.VATCents,
+		TotalCents:      invoice.TotalCents,
+		IssuedAt:        invoice.IssuedAt,
+		Lines:           make([]database.InvoiceLine, len(invoice.Lines)),
+	}
+	for i, line := range invoice.Lines {
+		dbInvoice.Lines[i] = database.InvoiceLine{
+			ID:             line.ID,
+			Description:    line.Description,
+			Quantity:       line.Quantity,
+			UnitPriceCents: line.UnitPriceCents,
+			BaseCents:      line.BaseCents,
+			VATRatePercent: line.VATRatePercent,
+			VATCents:       line.VATCents,
+			TotalCents:     line.TotalCents,
+		}
+	}
+	return dbInvoice
+}
+
+// ShareToDomain convierte una parte del pago dividido; si la relación está cargada incluye el
+// nombre del jugador y los datos de la reserva
+func (m *PaymentMapper) ShareToDomain(dbShare *database.BookingShare) *domain.BookingShare {
+	share := &domain.BookingShare{
+		ID:          dbShare.ID,
+		BookingID:   dbShare.BookingID,
+		UserID:      dbShare.UserID,
+		UserName:    dbShare.User.FullName,
+		AmountCents: dbShare.AmountCents,
+		Status:      dbShare.Status,
+		PaymentID:   dbShare.PaymentID,
+		PaidAt:      dbShare.PaidAt,
+		CreatedAt:   dbShare.CreatedAt,
+		UpdatedAt:   dbShare.UpdatedAt,
+	}
+	if dbShare.Booking.ID != 0 {
+		share.PistaName = dbShare.Booking.Pista.Name
+		share.BookingStartTime = dbShare.Booking.StartTime
+		share.SplitDeadline = dbShare.Booking.SplitDeadline
+	}
+	return share
+}
+
+// ShareToDatabase convierte una parte del pago dividido al modelo de BD
+func (m *PaymentMapper) ShareToDatabase(share *domain.BookingShare) *database.BookingShare {
+	return &database.BookingShare{
+		ID:          share.ID,
+		BookingID:   share.BookingID,
+		UserID:      share.UserID,
+		AmountCents: share.AmountCents,
+		Status:      share.Status,
+		PaymentID:   share.PaymentID,
+		PaidAt:      share.PaidAt,
+		CreatedAt:   share.CreatedAt,
+		UpdatedAt:   share.UpdatedAt,
+	}
+}
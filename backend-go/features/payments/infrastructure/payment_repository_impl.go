@@ -1,27 +1,60 @@
 package infrastructure
 
 import (
+	bookingDomain "backend-go/features/bookings/domain"
 	"backend-go/features/payments/domain"
 	"backend-go/shared/database"
+	"context"
+	"errors"
+	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type PaymentRepositoryImpl struct {
-	db     *gorm.DB
-	mapper *PaymentMapper
+	db        *gorm.DB
+	mapper    *PaymentMapper
+	invoicing domain.InvoiceSettings
 }
 
-func NewPaymentRepository(db *gorm.DB) domain.PaymentRepository {
+// NewPaymentRepository crea el repositorio; invoicing configura las facturas que se emiten
+// al cobrar y reembolsar
+func NewPaymentRepository(db *gorm.DB, invoicing domain.InvoiceSettings) domain.PaymentRepository {
 	return &PaymentRepositoryImpl{
-		db:     db,
-		mapper: NewPaymentMapper(),
+		db:        db,
+		mapper:    NewPaymentMapper(),
+		invoicing: invoicing,
 	}
 }
 
+// WithContext devuelve una copia del repositorio cuyas consultas usan ctx
+func (r *PaymentRepositoryImpl) WithContext(ctx context.Context) domain.PaymentRepository {
+	scoped := *r
+	scoped.db = r.db.WithContext(ctx)
+	return &scoped
+}
+
+// Create guarda el pago y, si ya está cobrado, emite su factura en la misma transacción
 func (r *PaymentRepositoryImpl) Create(payment *domain.Payment) error {
-	dbPayment := r.mapper.ToDatabase(payment)
-	return r.db.Create(dbPayment).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		dbPayment := r.mapper.ToDatabase(payment)
+		if err := tx.Create(dbPayment).Error; err != nil {
+			if isDuplicateEnrollmentPayment(err) {
+				return domain.ErrEnrollmentAlreadyPaid
+			}
+			return err
+		}
+		payment.ID = dbPayment.ID
+
+		if payment.Status != domain.StatusCompleted {
+			return nil
+		}
+		_, err := r.issueInvoice(tx, payment, time.Now())
+		return err
+	})
 }
 
 func (r *PaymentRepositoryImpl) GetByID(id uint) (*domain.Payment, error) {
@@ -35,6 +68,61 @@ func (r *PaymentRepositoryImpl) GetByID(id uint) (*domain.Payment, error) {
 	return r.mapper.ToDomain(&dbPayment), nil
 }
 
+// resourceChargeRow es la fila leída por GetEnrollmentCharge y GetMembershipCharge
+type resourceChargeRow struct {
+	UserID      uuid.UUID
+	AmountCents int
+	Paid        bool
+}
+
+// GetEnrollmentCharge lee el titular de la inscripción y el precio de su clase. Cuenta como
+// pagada si tiene un pago vigente (no fallido ni reembolsado) o un consumo de bono sin restituir.
+func (r *PaymentRepositoryImpl) GetEnrollmentCharge(enrollmentID uint) (*domain.ResourceCharge, error) {
+	var rows []resourceChargeRow
+	err := r.db.Model(&database.ClassEnrollment{}).
+		Select(`class_enrollments.user_id, classes.price_cents AS amount_cents,
+			EXISTS (SELECT 1 FROM payments WHERE payments.class_enrollment_id = class_enrollments.id
+				AND payments.status NOT IN (?, ?))
+			OR EXISTS (SELECT 1 FROM pass_redemptions WHERE pass_redemptions.enrollment_id = class_enrollments.id
+				AND pass_redemptions.restored_at IS NULL) AS paid`,
+			domain.StatusFailed, domain.StatusRefunded).
+		Joins("JOIN classes ON classes.id = class_enrollments.class_id").
+		Where("class_enrollments.id = ?", enrollmentID).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, domain.ErrEnrollmentNotFound
+	}
+	return &domain.ResourceCharge{UserID: rows[0].UserID, AmountCents: rows[0].AmountCents, Paid: rows[0].Paid}, nil
+}
+
+// GetMembershipCharge lee el titular de la membresía y la cuota mensual de su club. El periodo
+// actual cuenta como pagado si hay un cobro de renovación pendiente o si, antes de la fecha de
+// cobro, existe un pago vigente desde el inicio del periodo (sin fecha de cobro, en el último mes).
+func (r *PaymentRepositoryImpl) GetMembershipCharge(membershipID uint, now time.Time) (*domain.ResourceCharge, error) {
+	var rows []resourceChargeRow
+	err := r.db.Model(&database.ClubMembership{}).
+		Select(`club_memberships.user_id, clubs.monthly_fee_cents AS amount_cents,
+			club_memberships.pending_payment_id IS NOT NULL
+			OR ((club_memberships.next_billing_date IS NULL OR club_memberships.next_billing_date > ?)
+				AND EXISTS (SELECT 1 FROM payments WHERE payments.club_membership_id = club_memberships.id
+					AND payments.status NOT IN (?, ?)
+					AND payments.created_at >= date_trunc('day', COALESCE(club_memberships.next_billing_date, ?) - INTERVAL '1 month'))) AS paid`,
+			now, domain.StatusFailed, domain.StatusRefunded, now).
+		Joins("JOIN clubs ON clubs.id = club_memberships.club_id").
+		Where("club_memberships.id = ?", membershipID).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, domain.ErrMembershipNotFound
+	}
+	return &domain.ResourceCharge{UserID: rows[0].UserID, AmountCents: rows[0].AmountCents, Paid: rows[0].Paid}, nil
+}
+
 func (r *PaymentRepositoryImpl) GetByUser(userID uint) ([]domain.Payment, error) {
 	var dbPayments []database.Payment
 	if err := r.db.Where("user_id = ?", userID).Order("created_at DESC").Find(&dbPayments).Error; err != nil {
@@ -50,7 +138,7 @@ func (r *PaymentRepositoryImpl) GetByUser(userID uint) ([]domain.Payment, error)
 
 func (r *PaymentRepositoryImpl) GetByBooking(bookingID uint) (*domain.Payment, error) {
 	var dbPayment database.Payment
-	if err := r.db.Where("booking_id = ?", bookingID).First(&dbPayment).Error; err != nil {
+	if err := r.db.Where("booking_id = ?", bookingID).Order("created_at DESC").First(&dbPayment).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, domain.ErrPaymentNotFound
 		}
@@ -61,7 +149,7 @@ func (r *PaymentRepositoryImpl) GetByBooking(bookingID uint) (*domain.Payment, e
 
 func (r *PaymentRepositoryImpl) GetByClassEnrollment(enrollmentID uint) (*domain.Payment, error) {
 	var dbPayment database.Payment
-	if err := r.db.Where("class_enrollment_id = ?", enrollmentID).First(&dbPayment).Error; err != nil {
+	if err := r.db.Where("class_enrollment_id = ?", enrollmentID).Order("created_at DESC").First(&dbPayment).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, domain.ErrPaymentNotFound
 		}
@@ -83,7 +171,368 @@ func (r *PaymentRepositoryImpl) GetByClubMembership(membershipID uint) ([]domain
 	return payments, nil
 }
 
+// GetAllByBooking obtiene los pagos de una reserva: el del titular y, con pago dividido, los de
+// cada parte
+func (r *PaymentRepositoryImpl) GetAllByBooking(bookingID uint) ([]domain.Payment, error) {
+	var dbPayments []database.Payment
+	if err := r.db.Where("booking_id = ?", bookingID).Order("created_at ASC").Find(&dbPayments).Error; err != nil {
+		return nil, err
+	}
+
+	payments := make([]domain.Payment, len(dbPayments))
+	for i := range dbPayments {
+		payments[i] = *r.mapper.ToDomain(&dbPayments[i])
+	}
+	return payments, nil
+}
+
 func (r *PaymentRepositoryImpl) Update(payment *domain.Payment) error {
 	dbPayment := r.mapper.ToDatabase(payment)
 	return r.db.Save(dbPayment).Error
 }
+
+// CheckoutBooking cobra una reserva de forma atómica: la fila de la reserva queda bloqueada
+// hasta el commit, por lo que dos checkouts simultáneos de la misma reserva se serializan
+// y el segundo ve PaymentStatus = PAID. El índice único parcial sobre payments.booking_id
+// actúa como última barrera.
+func (r *PaymentRepositoryImpl) CheckoutBooking(bookingID uint, charge domain.BookingChargeFunc) (*domain.Payment, error) {
+	var payment *domain.Payment
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var booking database.Booking
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Preload("User").
+			First(&booking, bookingID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domain.ErrBookingNotFound
+			}
+			return err
+		}
+
+		bookingCharge, err := r.bookingCharge(tx, &booking)
+		if err != nil {
+			return err
+		}
+
+		var pending int64
+		if err := tx.Model(&database.Payment{}).
+			Where("booking_id = ? AND status = ?", booking.ID, domain.StatusPending).
+			Count(&pending).Error; err != nil {
+			return err
+		}
+		bookingCharge.HasPending = pending > 0
+
+		if payment, err = charge(bookingCharge); err != nil {
+			return err
+		}
+
+		dbPayment := r.mapper.ToDatabase(payment)
+		if err := tx.Create(dbPayment).Error; err != nil {
+			if isDuplicateBookingPayment(err) {
+				return domain.ErrBookingAlreadyPaid
+			}
+			return err
+		}
+		payment.ID = dbPayment.ID
+
+		// Un cobro pendiente (3DS, SEPA) deja la reserva sin pagar hasta que llegue el webhook
+		if payment.Status != domain.StatusCompleted {
+			return nil
+		}
+		if _, err := r.issueInvoice(tx, payment, time.Now()); err != nil {
+			return err
+		}
+		// Pago dividido: el titular cubre las partes que seguían pendientes
+		if err := r.coverPendingShares(tx, booking.ID, payment.ID, time.Now()); err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{
+			"payment_status": bookingDomain.PaymentStatusPaid,
+			"discount_cents": payment.DiscountCents,
+			"promo_code":     payment.PromoCode,
+			"updated_at":     time.Now(),
+		}
+		if booking.Status == bookingDomain.StatusPending {
+			updates["status"] = bookingDomain.StatusConfirmed
+		}
+		return tx.Model(&database.Booking{}).Where("id = ?", booking.ID).Updates(updates).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+// CreateRefund reembolsa un pago con la fila bloqueada, de modo que dos reembolsos simultáneos
+// no puedan superar entre ambos el importe cobrado. Emite la factura rectificativa del reembolso.
+func (r *PaymentRepositoryImpl) CreateRefund(paymentID uint, refund domain.RefundFunc) (*domain.Refund, error) {
+	var created *domain.Refund
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var dbPayment database.Payment
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&dbPayment, paymentID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domain.ErrPaymentNotFound
+			}
+			return err
+		}
+
+		payment := r.mapper.ToDomain(&dbPayment)
+		var err error
+		if created, err = refund(payment); err != nil {
+			return err
+		}
+
+		dbRefund := r.mapper.RefundToDatabase(created)
+		if err := tx.Create(dbRefund).Error; err != nil {
+			return err
+		}
+		created.ID = dbRefund.ID
+		created.CreatedAt = dbRefund.CreatedAt
+
+		refunded := payment.RefundedCents + created.AmountCents
+		status := domain.StatusPartiallyRefunded
+		if refunded >= payment.AmountCents {
+			status = domain.StatusRefunded
+		}
+		now := time.Now()
+		if err := tx.Model(&database.Payment{}).Where("id = ?", paymentID).Updates(map[string]interface{}{
+			"refunded_cents": refunded,
+			"status":         status,
+			"updated_at":     now,
+		}).Error; err != nil {
+			return err
+		}
+
+		_, err = r.issueRectifyingInvoice(tx, payment, created, now)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// GetRefundsByPayment obtiene los reembolsos de un pago
+func (r *PaymentRepositoryImpl) GetRefundsByPayment(paymentID uint) ([]domain.Refund, error) {
+	var dbRefunds []database.Refund
+	if err := r.db.Where("payment_id = ?", paymentID).Order("created_at ASC").Find(&dbRefunds).Error; err != nil {
+		return nil, err
+	}
+
+	refunds := make([]domain.Refund, len(dbRefunds))
+	for i := range dbRefunds {
+		refunds[i] = *r.mapper.RefundToDomain(&dbRefunds[i])
+	}
+	return refunds, nil
+}
+
+// SaveEvent guarda el evento recibido; el índice único (provider, event_id) descarta los reenvíos
+func (r *PaymentRepositoryImpl) SaveEvent(event *domain.PaymentEvent) (bool, error) {
+	dbEvent := r.mapper.EventToDatabase(event)
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(dbEvent)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 1 {
+		event.ID = dbEvent.ID
+		event.ReceivedAt = dbEvent.ReceivedAt
+		return true, nil
+	}
+
+	var existing database.PaymentEvent
+	if err := r.db.Where("provider = ? AND event_id = ?", event.Provider, event.EventID).First(&existing).Error; err != nil {
+		return false, err
+	}
+	event.ID = existing.ID
+	event.PaymentID = existing.PaymentID
+	event.Status = existing.Status
+	event.Error = existing.Error
+	event.ReceivedAt = existing.ReceivedAt
+	event.ProcessedAt = existing.ProcessedAt
+	return false, nil
+}
+
+// ApplyEvent aplica el evento con el evento y el pago bloqueados: dos entregas simultáneas del
+// mismo evento se serializan y la segunda lo encuentra ya procesado
+func (r *PaymentRepositoryImpl) ApplyEvent(event *domain.PaymentEvent) (*domain.PaymentEventResult, error) {
+	result := &domain.PaymentEventResult{Event: event}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var dbEvent database.PaymentEvent
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&dbEvent, event.ID).Error; err != nil {
+			return err
+		}
+		if stored := r.mapper.EventToDomain(&dbEvent); stored.IsFinal() {
+			event.Status = stored.Status
+			event.PaymentID = stored.PaymentID
+			event.ProcessedAt = stored.ProcessedAt
+			return nil
+		}
+
+		now := time.Now()
+		event.Status = domain.EventStatusIgnored
+		event.Error = nil
+		event.ProcessedAt = &now
+
+		if event.Action != "" && event.PaymentIntentID != "" {
+			var dbPayment database.Payment
+			err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("stripe_payment_intent_id = ? AND provider = ?", event.PaymentIntentID, event.Provider).
+				First(&dbPayment).Error
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+
+			if err == nil {
+				payment := r.mapper.ToDomain(&dbPayment)
+				changed, refundedDelta := payment.ApplyEvent(event)
+				if changed {
+					if err := r.applyPaymentTransition(tx, payment, event, refundedDelta, now); err != nil {
+						return err
+					}
+				}
+
+				result.Payment = payment
+				result.Changed = changed
+				event.PaymentID = &payment.ID
+				event.Status = domain.EventStatusProcessed
+			}
+		}
+
+		return tx.Model(&database.PaymentEvent{}).Where("id = ?", event.ID).Updates(map[string]interface{}{
+			"status":       event.Status,
+			"payment_id":   event.PaymentID,
+			"error":        nil,
+			"processed_at": now,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// applyPaymentTransition guarda el nuevo estado del pago, registra el reembolso hecho en el
+// proveedor, emite la factura (cobro confirmado) o la rectificativa (reembolso), libera el
+// código promocional de un cobro fallido y actualiza el estado de pago de la reserva enlazada
+func (r *PaymentRepositoryImpl) applyPaymentTransition(tx *gorm.DB, payment *domain.Payment, event *domain.PaymentEvent, refundedDelta int, now time.Time) error {
+	if err := tx.Model(&database.Payment{}).Where("id = ?", payment.ID).Updates(map[string]interface{}{
+		"status":         payment.Status,
+		"refunded_cents": payment.RefundedCents,
+		"updated_at":     now,
+	}).Error; err != nil {
+		return err
+	}
+
+	if payment.Status == domain.StatusCompleted {
+		if _, err := r.issueInvoice(tx, payment, now); err != nil {
+			return err
+		}
+	}
+
+	if refundedDelta > 0 {
+		refund := &domain.Refund{
+			PaymentID:        payment.ID,
+			AmountCents:      refundedDelta,
+			Reason:           domain.RefundReasonProvider,
+			ProviderRefundID: event.ProviderRefundID,
+		}
+		dbRefund := r.mapper.RefundToDatabase(refund)
+		if err := tx.Create(dbRefund).Error; err != nil {
+			return err
+		}
+		refund.ID = dbRefund.ID
+		if _, err := r.issueRectifyingInvoice(tx, payment, refund, now); err != nil {
+			return err
+		}
+	}
+
+	// Un cobro fallido no consume el código promocional canjeado
+	if payment.Status == domain.StatusFailed && payment.PromoRedemptionID != nil {
+		if err := tx.Model(&database.PromoRedemption{}).
+			Where("id = ? AND released_at IS NULL", *payment.PromoRedemptionID).
+			Update("released_at", now).Error; err != nil {
+			return err
+		}
+	}
+
+	if payment.BookingID == nil {
+		return nil
+	}
+
+	// El pago de una parte solo confirma la reserva cuando ya no quedan partes pendientes; sus
+	// reembolsos no cambian el estado de pago de la reserva (lo gestiona la cancelación)
+	if payment.BookingShareID != nil {
+		if payment.Status != domain.StatusCompleted {
+			return nil
+		}
+		if err := r.markSharePaid(tx, *payment.BookingShareID, payment.ID, now); err != nil {
+			return err
+		}
+		return r.settleSplit(tx, *payment.BookingID, now)
+	}
+
+	updates := map[string]interface{}{"updated_at": now}
+	switch payment.Status {
+	case domain.StatusCompleted:
+		if err := r.coverPendingShares(tx, *payment.BookingID, payment.ID, now); err != nil {
+			return err
+		}
+		updates["payment_status"] = bookingDomain.PaymentStatusPaid
+		updates["discount_cents"] = payment.DiscountCents
+		updates["promo_code"] = payment.PromoCode
+		if err := tx.Model(&database.Booking{}).
+			Where("id = ? AND status = ?", *payment.BookingID, bookingDomain.StatusPending).
+			Update("status", bookingDomain.StatusConfirmed).Error; err != nil {
+			return err
+		}
+	case domain.StatusRefunded:
+		updates["payment_status"] = bookingDomain.PaymentStatusRefunded
+	case domain.StatusPartiallyRefunded:
+		updates["payment_status"] = bookingDomain.PaymentStatusPartiallyRefunded
+	default:
+		// FAILED: la reserva sigue sin pagar y admite un nuevo checkout
+		return nil
+	}
+
+	return tx.Model(&database.Booking{}).Where("id = ?", *payment.BookingID).Updates(updates).Error
+}
+
+// MarkEventFailed registra el error al procesar un evento para que el reenvío lo reintente
+func (r *PaymentRepositoryImpl) MarkEventFailed(eventID uint, reason string) error {
+	return r.db.Model(&database.PaymentEvent{}).Where("id = ?", eventID).Updates(map[string]interface{}{
+		"status":       domain.EventStatusFailed,
+		"error":        reason,
+		"processed_at": time.Now(),
+	}).Error
+}
+
+// GetEventsByPayment obtiene los eventos de webhook recibidos para un pago
+func (r *PaymentRepositoryImpl) GetEventsByPayment(paymentID uint) ([]domain.PaymentEvent, error) {
+	var dbEvents []database.PaymentEvent
+	if err := r.db.Where("payment_id = ?", paymentID).Order("received_at ASC").Find(&dbEvents).Error; err != nil {
+		return nil, err
+	}
+
+	events := make([]domain.PaymentEvent, len(dbEvents))
+	for i := range dbEvents {
+		events[i] = *r.mapper.EventToDomain(&dbEvents[i])
+	}
+	return events, nil
+}
+
+// isDuplicateBookingPayment detecta la violación del índice único de pagos por reserva
+func isDuplicateBookingPayment(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "idx_payments_booking_active") || strings.Contains(msg, "23505")
+}
+
+// isDuplicateEnrollmentPayment detecta la violación del índice único de pagos por inscripción
+func isDuplicateEnrollmentPayment(err error) bool {
+	return strings.Contains(err.Error(), "idx_payments_enrollment_active")
+}
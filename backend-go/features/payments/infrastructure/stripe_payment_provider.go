@@ -0,0 +1,190 @@
+package infrastructure
+
+import (
+	"backend-go/features/payments/domain"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultStripeAPIBase es la URL de la API real de Stripe
+const DefaultStripeAPIBase = "https://api.stripe.com"
+
+// StripePaymentProvider implementa PaymentGateway contra la API REST de Stripe
+// (customers, payment_intents y refunds). La URL base es configurable para poder
+// apuntar al servidor stub local (cmd/stripe-stub) y probar el adaptador sin conexión.
+type StripePaymentProvider struct {
+	secretKey string
+	baseURL   string
+	client    *http.Client
+}
+
+// NewStripePaymentProvider crea el adaptador de Stripe.
+// baseURL vacío usa la API real (https://api.stripe.com).
+func NewStripePaymentProvider(secretKey, baseURL string) domain.PaymentGateway {
+	if baseURL == "" {
+		baseURL = DefaultStripeAPIBase
+	}
+	return &StripePaymentProvider{
+		secretKey: secretKey,
+		baseURL:   strings.TrimRight(baseURL, "/"),
+		client:    &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// stripeCustomer es la parte de la respuesta de /v1/customers que usa el adaptador
+type stripeCustomer struct {
+	ID              string `json:"id"`
+	InvoiceSettings struct {
+		DefaultPaymentMethod *string `json:"default_payment_method"`
+	} `json:"invoice_settings"`
+}
+
+// stripePaymentIntent es la parte de la respuesta de /v1/payment_intents que usa el adaptador
+type stripePaymentIntent struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+}
+
+// stripeRefund es la parte de la respuesta de /v1/refunds que usa el adaptador
+type stripeRefund struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+}
+
+// stripeErrorResponse es el formato de error de la API de Stripe
+type stripeErrorResponse struct {
+	Error struct {
+		Type        string `json:"type"`
+		Code        string `json:"code"`
+		DeclineCode string `json:"decline_code"`
+		Message     string `json:"message"`
+	} `json:"error"`
+}
+
+// Name identifica el proveedor
+func (s *StripePaymentProvider) Name() string {
+	return domain.ProviderStripe
+}
+
+// CreateCustomer crea un cliente en Stripe (POST /v1/customers)
+func (s *StripePaymentProvider) CreateCustomer(email, name string) (string, error) {
+	form := url.Values{}
+	form.Set("email", email)
+	form.Set("name", name)
+
+	var customer stripeCustomer
+	if err := s.do(http.MethodPost, "/v1/customers", form, "", &customer); err != nil {
+		return "", err
+	}
+	return customer.ID, nil
+}
+
+// Charge cobra al cliente con su método de pago por defecto mediante un PaymentIntent
+// confirmado fuera de sesión (POST /v1/payment_intents con confirm=true y off_session=true).
+// Si el banco exige autenticación (3DS) o el método es asíncrono (SEPA) el pago queda
+// pendiente y su resultado llega por webhook.
+func (s *StripePaymentProvider) Charge(amountCents int, customerID string, description string, idempotencyKey string) (*domain.ChargeResult, error) {
+	var customer stripeCustomer
+	if err := s.do(http.MethodGet, "/v1/customers/"+url.PathEscape(customerID), nil, "", &customer); err != nil {
+		return nil, err
+	}
+	if customer.InvoiceSettings.DefaultPaymentMethod == nil || *customer.InvoiceSettings.DefaultPaymentMethod == "" {
+		return nil, domain.ErrNoPaymentMethod
+	}
+
+	form := url.Values{}
+	form.Set("amount", strconv.Itoa(amountCents))
+	form.Set("currency", "eur")
+	form.Set("customer", customerID)
+	form.Set("payment_method", *customer.InvoiceSettings.DefaultPaymentMethod)
+	form.Set("description", description)
+	form.Set("confirm", "true")
+	form.Set("off_session", "true")
+
+	var intent stripePaymentIntent
+	if err := s.do(http.MethodPost, "/v1/payment_intents", form, idempotencyKey, &intent); err != nil {
+		return nil, err
+	}
+
+	switch intent.Status {
+	case "succeeded":
+		return &domain.ChargeResult{PaymentIntentID: intent.ID, Status: domain.StatusCompleted}, nil
+	case "processing", "requires_action":
+		return &domain.ChargeResult{PaymentIntentID: intent.ID, Status: domain.StatusPending}, nil
+	}
+	return nil, fmt.Errorf("%w: el pago quedó en estado %s (%s)", domain.ErrPaymentFailed, intent.Status, intent.ID)
+}
+
+// Refund reembolsa total o parcialmente un PaymentIntent (POST /v1/refunds)
+func (s *StripePaymentProvider) Refund(paymentIntentID string, amountCents int) (string, error) {
+	form := url.Values{}
+	form.Set("payment_intent", paymentIntentID)
+	form.Set("amount", strconv.Itoa(amountCents))
+
+	var refund stripeRefund
+	if err := s.do(http.MethodPost, "/v1/refunds", form, "", &refund); err != nil {
+		return "", err
+	}
+	if refund.Status == "failed" || refund.Status == "canceled" {
+		return "", fmt.Errorf("%w: el reembolso quedó en estado %s (%s)", domain.ErrPaymentFailed, refund.Status, refund.ID)
+	}
+	return refund.ID, nil
+}
+
+// do ejecuta una petición form-encoded contra la API y decodifica la respuesta en out.
+// idempotencyKey (opcional) se envía en la cabecera Idempotency-Key de Stripe.
+func (s *StripePaymentProvider) do(method, path string, form url.Values, idempotencyKey string, out interface{}) error {
+	var body *strings.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	} else {
+		body = strings.NewReader("")
+	}
+
+	req, err := http.NewRequest(method, s.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+s.secretKey)
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error de conexión con Stripe: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return stripeError(resp)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("respuesta inválida de Stripe: %w", err)
+	}
+	return nil
+}
+
+// stripeError traduce una respuesta de error de Stripe a errores de dominio
+func stripeError(resp *http.Response) error {
+	var apiErr stripeErrorResponse
+	if err := json.NewDecoder(resp.Body).Decode(&apiErr); err != nil || apiErr.Error.Message == "" {
+		return fmt.Errorf("error de Stripe (HTTP %d)", resp.StatusCode)
+	}
+
+	switch {
+	case apiErr.Error.Type == "card_error" || resp.StatusCode == http.StatusPaymentRequired:
+		return fmt.Errorf("%w: %s", domain.ErrPaymentFailed, apiErr.Error.Message)
+	case apiErr.Error.Code == "resource_missing" && strings.Contains(apiErr.Error.Message, "customer"):
+		return fmt.Errorf("%w: %s", domain.ErrCustomerNotFound, apiErr.Error.Message)
+	}
+	return fmt.Errorf("error de Stripe (HTTP %d, %s): %s", resp.StatusCode, apiErr.Error.Type, apiErr.Error.Message)
+}
@@ -0,0 +1,479 @@
+package infrastructure
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Clientes y métodos de pago predefinidos del stub (mismos nombres que las tarjetas de prueba de Stripe)
+const (
+	StubCustomerVisa            = "cus_stub_visa"              // Cobros correctos
+	StubCustomerDeclined        = "cus_stub_declined"          // Tarjeta rechazada (HTTP 402)
+	StubCustomerRequiresAction  = "cus_stub_requires_action"   // Requiere 3DS: el PaymentIntent queda en requires_action
+	StubCustomerNoPaymentMethod = "cus_stub_no_payment_method" // Sin método de pago por defecto
+
+	stubPaymentMethodVisa           = "pm_card_visa"
+	stubPaymentMethodDeclined       = "pm_card_chargeDeclined"
+	stubPaymentMethodRequiresAction = "pm_card_authenticationRequired"
+)
+
+// StripeStubServer imita los endpoints de Stripe que usa StripePaymentProvider
+// (customers, payment_intents y refunds) con estado en memoria y respuestas deterministas.
+// Permite probar el adaptador sin conexión: go run ./cmd/stripe-stub
+//
+// Con EnableWebhooks envía además los eventos firmados (payment_intent.succeeded,
+// payment_intent.payment_failed, payment_intent.canceled y charge.refunded) a la API.
+type StripeStubServer struct {
+	mu        sync.Mutex
+	sequence  int
+	customers map[string]*stubCustomer
+	intents   map[string]*stubPaymentIntent
+
+	webhookURL    string
+	webhookSecret string
+	client        *http.Client
+
+	idempotencyMu sync.Mutex
+	idempotent    map[string]*stubResponse // Respuestas por Idempotency-Key
+}
+
+// stubResponse es una respuesta guardada para repetirla ante la misma Idempotency-Key
+type stubResponse struct {
+	status int
+	body   []byte
+}
+
+// recordingWriter captura la respuesta de un handler para guardarla
+type recordingWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *recordingWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *recordingWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+type stubCustomer struct {
+	ID                   string
+	Email                string
+	Name                 string
+	DefaultPaymentMethod string
+	Created              int64
+}
+
+type stubPaymentIntent struct {
+	ID            string
+	Amount        int
+	AmountRefund  int
+	Currency      string
+	Customer      string
+	PaymentMethod string
+	Description   string
+	Status        string
+	Created       int64
+}
+
+// NewStripeStubServer crea el servidor stub con los clientes predefinidos
+func NewStripeStubServer() *StripeStubServer {
+	server := &StripeStubServer{
+		customers:  map[string]*stubCustomer{},
+		intents:    map[string]*stubPaymentIntent{},
+		client:     &http.Client{Timeout: 10 * time.Second},
+		idempotent: map[string]*stubResponse{},
+	}
+
+	seed := map[string]string{
+		StubCustomerVisa:            stubPaymentMethodVisa,
+		StubCustomerDeclined:        stubPaymentMethodDeclined,
+		StubCustomerRequiresAction:  stubPaymentMethodRequiresAction,
+		StubCustomerNoPaymentMethod: "",
+	}
+	for id, paymentMethod := range seed {
+		server.customers[id] = &stubCustomer{ID: id, DefaultPaymentMethod: paymentMethod, Created: time.Now().Unix()}
+	}
+
+	return server
+}
+
+// EnableWebhooks envía los eventos firmados con secret (mismo valor que STRIPE_WEBHOOK_SECRET
+// en la API) a url, p. ej. http://localhost:8080/api/payments/webhooks/stripe
+func (s *StripeStubServer) EnableWebhooks(url, secret string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.webhookURL = url
+	s.webhookSecret = secret
+}
+
+// Handler devuelve el http.Handler con las rutas de la API
+func (s *StripeStubServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/customers", s.createCustomer)
+	mux.HandleFunc("GET /v1/customers/{id}", s.getCustomer)
+	mux.HandleFunc("POST /v1/payment_intents", s.withIdempotency(s.createPaymentIntent))
+	mux.HandleFunc("GET /v1/payment_intents/{id}", s.getPaymentIntent)
+	mux.HandleFunc("POST /v1/payment_intents/{id}/confirm", s.confirmPaymentIntent)
+	mux.HandleFunc("POST /v1/payment_intents/{id}/cancel", s.cancelPaymentIntent)
+	mux.HandleFunc("POST /v1/refunds", s.withIdempotency(s.createRefund))
+	return s.authenticate(mux)
+}
+
+// authenticate exige una clave secreta con formato de Stripe (sk_...)
+func (s *StripeStubServer) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer sk_") {
+			writeStubError(w, http.StatusUnauthorized, "invalid_request_error", "", "Invalid API Key provided")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// withIdempotency repite la respuesta original si la petición trae una Idempotency-Key ya usada
+// (como Stripe, también los errores). Las peticiones con clave se procesan de una en una.
+func (s *StripeStubServer) withIdempotency(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("Idempotency-Key")
+		if key == "" {
+			next(w, r)
+			return
+		}
+
+		s.idempotencyMu.Lock()
+		defer s.idempotencyMu.Unlock()
+
+		key = r.URL.Path + " " + key
+		if saved, ok := s.idempotent[key]; ok {
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Idempotent-Replayed", "true")
+			w.WriteHeader(saved.status)
+			w.Write(saved.body)
+			return
+		}
+
+		recorder := &recordingWriter{ResponseWriter: w, status: http.StatusOK}
+		next(recorder, r)
+		s.idempotent[key] = &stubResponse{status: recorder.status, body: recorder.body.Bytes()}
+	}
+}
+
+func (s *StripeStubServer) createCustomer(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeStubError(w, http.StatusBadRequest, "invalid_request_error", "", "Invalid form body")
+		return
+	}
+
+	paymentMethod := r.PostForm.Get("invoice_settings[default_payment_method]")
+	if paymentMethod == "" {
+		paymentMethod = stubPaymentMethodVisa
+	}
+
+	s.mu.Lock()
+	customer := &stubCustomer{
+		ID:                   s.nextID("cus"),
+		Email:                r.PostForm.Get("email"),
+		Name:                 r.PostForm.Get("name"),
+		DefaultPaymentMethod: paymentMethod,
+		Created:              time.Now().Unix(),
+	}
+	s.customers[customer.ID] = customer
+	s.mu.Unlock()
+
+	writeStubJSON(w, http.StatusOK, customer.toJSON())
+}
+
+func (s *StripeStubServer) getCustomer(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	customer, ok := s.customers[r.PathValue("id")]
+	s.mu.Unlock()
+	if !ok {
+		writeStubError(w, http.StatusNotFound, "invalid_request_error", "resource_missing",
+			fmt.Sprintf("No such customer: '%s'", r.PathValue("id")))
+		return
+	}
+
+	writeStubJSON(w, http.StatusOK, customer.toJSON())
+}
+
+func (s *StripeStubServer) createPaymentIntent(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeStubError(w, http.StatusBadRequest, "invalid_request_error", "", "Invalid form body")
+		return
+	}
+
+	amount, err := strconv.Atoi(r.PostForm.Get("amount"))
+	if err != nil || amount <= 0 {
+		writeStubError(w, http.StatusBadRequest, "invalid_request_error", "parameter_invalid_integer", "Invalid positive integer: amount")
+		return
+	}
+	if r.PostForm.Get("currency") == "" {
+		writeStubError(w, http.StatusBadRequest, "invalid_request_error", "parameter_missing", "Missing required param: currency.")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	customerID := r.PostForm.Get("customer")
+	if _, ok := s.customers[customerID]; !ok {
+		writeStubError(w, http.StatusBadRequest, "invalid_request_error", "resource_missing",
+			fmt.Sprintf("No such customer: '%s'", customerID))
+		return
+	}
+
+	intent := &stubPaymentIntent{
+		ID:            s.nextID("pi"),
+		Amount:        amount,
+		Currency:      r.PostForm.Get("currency"),
+		Customer:      customerID,
+		PaymentMethod: r.PostForm.Get("payment_method"),
+		Description:   r.PostForm.Get("description"),
+		Status:        "requires_confirmation",
+		Created:       time.Now().Unix(),
+	}
+
+	if r.PostForm.Get("confirm") == "true" {
+		switch intent.PaymentMethod {
+		case "":
+			intent.Status = "requires_payment_method"
+		case stubPaymentMethodDeclined:
+			intent.Status = "requires_payment_method"
+			s.intents[intent.ID] = intent
+			s.emitEvent("payment_intent.payment_failed", intent.toJSON())
+			writeStubError(w, http.StatusPaymentRequired, "card_error", "card_declined", "Your card was declined.")
+			return
+		case stubPaymentMethodRequiresAction:
+			intent.Status = "requires_action"
+		default:
+			intent.Status = "succeeded"
+		}
+	}
+
+	s.intents[intent.ID] = intent
+	if intent.Status == "succeeded" {
+		s.emitEvent("payment_intent.succeeded", intent.toJSON())
+	}
+	writeStubJSON(w, http.StatusOK, intent.toJSON())
+}
+
+func (s *StripeStubServer) getPaymentIntent(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	intent, ok := s.intents[r.PathValue("id")]
+	s.mu.Unlock()
+	if !ok {
+		writeStubError(w, http.StatusNotFound, "invalid_request_error", "resource_missing",
+			fmt.Sprintf("No such payment_intent: '%s'", r.PathValue("id")))
+		return
+	}
+
+	writeStubJSON(w, http.StatusOK, intent.toJSON())
+}
+
+// confirmPaymentIntent simula que el cliente completa la autenticación (3DS) de un
+// PaymentIntent en requires_action: pasa a succeeded y se notifica por webhook
+func (s *StripeStubServer) confirmPaymentIntent(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	intent, ok := s.intents[r.PathValue("id")]
+	if !ok {
+		writeStubError(w, http.StatusNotFound, "invalid_request_error", "resource_missing",
+			fmt.Sprintf("No such payment_intent: '%s'", r.PathValue("id")))
+		return
+	}
+	if intent.Status != "requires_action" && intent.Status != "requires_confirmation" {
+		writeStubError(w, http.StatusBadRequest, "invalid_request_error", "payment_intent_unexpected_state",
+			fmt.Sprintf("You cannot confirm this PaymentIntent because it has a status of %s.", intent.Status))
+		return
+	}
+
+	intent.Status = "succeeded"
+	s.emitEvent("payment_intent.succeeded", intent.toJSON())
+	writeStubJSON(w, http.StatusOK, intent.toJSON())
+}
+
+// cancelPaymentIntent cancela un PaymentIntent no cobrado (p. ej. autenticación abandonada)
+func (s *StripeStubServer) cancelPaymentIntent(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	intent, ok := s.intents[r.PathValue("id")]
+	if !ok {
+		writeStubError(w, http.StatusNotFound, "invalid_request_error", "resource_missing",
+			fmt.Sprintf("No such payment_intent: '%s'", r.PathValue("id")))
+		return
+	}
+	if intent.Status == "succeeded" || intent.Status == "canceled" {
+		writeStubError(w, http.StatusBadRequest, "invalid_request_error", "payment_intent_unexpected_state",
+			fmt.Sprintf("You cannot cancel this PaymentIntent because it has a status of %s.", intent.Status))
+		return
+	}
+
+	intent.Status = "canceled"
+	s.emitEvent("payment_intent.canceled", intent.toJSON())
+	writeStubJSON(w, http.StatusOK, intent.toJSON())
+}
+
+func (s *StripeStubServer) createRefund(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeStubError(w, http.StatusBadRequest, "invalid_request_error", "", "Invalid form body")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	intentID := r.PostForm.Get("payment_intent")
+	intent, ok := s.intents[intentID]
+	if !ok {
+		writeStubError(w, http.StatusBadRequest, "invalid_request_error", "resource_missing",
+			fmt.Sprintf("No such payment_intent: '%s'", intentID))
+		return
+	}
+	if intent.Status != "succeeded" {
+		writeStubError(w, http.StatusBadRequest, "invalid_request_error", "charge_not_refundable",
+			"This PaymentIntent does not have a successful charge to refund.")
+		return
+	}
+
+	remaining := intent.Amount - intent.AmountRefund
+	amount := remaining
+	if raw := r.PostForm.Get("amount"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			writeStubError(w, http.StatusBadRequest, "invalid_request_error", "parameter_invalid_integer", "Invalid positive integer: amount")
+			return
+		}
+		amount = parsed
+	}
+	if amount > remaining {
+		writeStubError(w, http.StatusBadRequest, "invalid_request_error", "amount_too_large",
+			fmt.Sprintf("Refund amount (%d) is greater than unrefunded amount on charge (%d)", amount, remaining))
+		return
+	}
+
+	intent.AmountRefund += amount
+	refund := map[string]interface{}{
+		"id":             s.nextID("re"),
+		"object":         "refund",
+		"amount":         amount,
+		"currency":       intent.Currency,
+		"payment_intent": intent.ID,
+		"status":         "succeeded",
+		"created":        time.Now().Unix(),
+	}
+	s.emitEvent("charge.refunded", map[string]interface{}{
+		"id":              "ch_" + strings.TrimPrefix(intent.ID, "pi_"),
+		"object":          "charge",
+		"amount":          intent.Amount,
+		"amount_refunded": intent.AmountRefund,
+		"currency":        intent.Currency,
+		"payment_intent":  intent.ID,
+		"refunded":        intent.AmountRefund >= intent.Amount,
+		"refunds":         map[string]interface{}{"object": "list", "data": []interface{}{refund}},
+	})
+	writeStubJSON(w, http.StatusOK, refund)
+}
+
+// emitEvent construye el evento y lo envía firmado en segundo plano, como hace Stripe: la
+// respuesta de la API no espera a la entrega del webhook. Requiere s.mu.
+func (s *StripeStubServer) emitEvent(eventType string, object map[string]interface{}) {
+	if s.webhookURL == "" {
+		return
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"id":      s.nextID("evt"),
+		"object":  "event",
+		"type":    eventType,
+		"created": time.Now().Unix(),
+		"data":    map[string]interface{}{"object": object},
+	})
+	if err != nil {
+		return
+	}
+
+	url, secret := s.webhookURL, s.webhookSecret
+	go func() {
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(payload))
+		if err != nil {
+			return
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(StripeSignatureHeader, SignWebhookPayload(secret, payload, time.Now()))
+
+		resp, err := s.client.Do(req)
+		if err != nil {
+			log.Printf("⚠️  [STRIPE STUB] Webhook %s no entregado: %v", eventType, err)
+			return
+		}
+		resp.Body.Close()
+		log.Printf("📨 [STRIPE STUB] Webhook %s entregado (HTTP %d)", eventType, resp.StatusCode)
+	}()
+}
+
+// nextID genera identificadores secuenciales deterministas (ej. pi_stub_000001). Requiere s.mu.
+func (s *StripeStubServer) nextID(prefix string) string {
+	s.sequence++
+	return fmt.Sprintf("%s_stub_%06d", prefix, s.sequence)
+}
+
+func (c *stubCustomer) toJSON() map[string]interface{} {
+	var defaultPaymentMethod interface{}
+	if c.DefaultPaymentMethod != "" {
+		defaultPaymentMethod = c.DefaultPaymentMethod
+	}
+	return map[string]interface{}{
+		"id":               c.ID,
+		"object":           "customer",
+		"email":            c.Email,
+		"name":             c.Name,
+		"created":          c.Created,
+		"invoice_settings": map[string]interface{}{"default_payment_method": defaultPaymentMethod},
+	}
+}
+
+func (i *stubPaymentIntent) toJSON() map[string]interface{} {
+	return map[string]interface{}{
+		"id":              i.ID,
+		"object":          "payment_intent",
+		"amount":          i.Amount,
+		"amount_refunded": i.AmountRefund,
+		"currency":        i.Currency,
+		"customer":        i.Customer,
+		"payment_method":  i.PaymentMethod,
+		"description":     i.Description,
+		"status":          i.Status,
+		"created":         i.Created,
+	}
+}
+
+func writeStubJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeStubError(w http.ResponseWriter, status int, errorType, code, message string) {
+	body := map[string]interface{}{"type": errorType, "message": message}
+	if code != "" {
+		body["code"] = code
+	}
+	if errorType == "card_error" {
+		body["decline_code"] = "generic_decline"
+	}
+	writeStubJSON(w, status, map[string]interface{}{"error": body})
+}
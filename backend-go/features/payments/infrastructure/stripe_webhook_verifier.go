@@ -0,0 +1,156 @@
+package infrastructure
+
+import (
+	"backend-go/features/payments/domain"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StripeSignatureHeader es la cabecera con la firma de los webhooks de Stripe
+const StripeSignatureHeader = "Stripe-Signature"
+
+// StripeWebhookVerifier verifica y traduce los webhooks de Stripe (firmados con el secreto whsec_...)
+type StripeWebhookVerifier struct {
+	secret string
+}
+
+// NewStripeWebhookVerifier crea el verificador con el secreto del endpoint (STRIPE_WEBHOOK_SECRET)
+func NewStripeWebhookVerifier(secret string) domain.WebhookVerifier {
+	return &StripeWebhookVerifier{secret: secret}
+}
+
+// stripeEvent es la parte de un evento de Stripe que usa el verificador
+type stripeEvent struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Data struct {
+		Object json.RawMessage `json:"object"`
+	} `json:"data"`
+}
+
+// stripeCharge es la parte del objeto charge (evento charge.refunded) que usa el verificador
+type stripeCharge struct {
+	PaymentIntent  string `json:"payment_intent"`
+	AmountRefunded int    `json:"amount_refunded"`
+	Refunds        struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	} `json:"refunds"`
+}
+
+// Provider identifica el proveedor
+func (v *StripeWebhookVerifier) Provider() string {
+	return domain.ProviderStripe
+}
+
+// SignatureHeader devuelve la cabecera de la firma
+func (v *StripeWebhookVerifier) SignatureHeader() string {
+	return StripeSignatureHeader
+}
+
+// Parse verifica la firma y traduce los tipos de evento soportados:
+// payment_intent.succeeded, payment_intent.payment_failed, payment_intent.canceled y charge.refunded
+func (v *StripeWebhookVerifier) Parse(payload []byte, signature string, now time.Time) (*domain.PaymentEvent, error) {
+	if err := verifyWebhookSignature(v.secret, payload, signature, now); err != nil {
+		return nil, err
+	}
+
+	var raw stripeEvent
+	if err := json.Unmarshal(payload, &raw); err != nil || raw.ID == "" || raw.Type == "" {
+		return nil, domain.ErrInvalidWebhookPayload
+	}
+
+	event := &domain.PaymentEvent{
+		Provider: domain.ProviderStripe,
+		EventID:  raw.ID,
+		Type:     raw.Type,
+		Payload:  payload,
+	}
+
+	switch raw.Type {
+	case "payment_intent.succeeded", "payment_intent.payment_failed", "payment_intent.canceled":
+		var intent stripePaymentIntent
+		if err := json.Unmarshal(raw.Data.Object, &intent); err != nil || intent.ID == "" {
+			return nil, domain.ErrInvalidWebhookPayload
+		}
+		event.PaymentIntentID = intent.ID
+		event.Action = domain.EventActionFailed
+		if raw.Type == "payment_intent.succeeded" {
+			event.Action = domain.EventActionSucceeded
+		}
+	case "charge.refunded":
+		var charge stripeCharge
+		if err := json.Unmarshal(raw.Data.Object, &charge); err != nil || charge.PaymentIntent == "" {
+			return nil, domain.ErrInvalidWebhookPayload
+		}
+		event.PaymentIntentID = charge.PaymentIntent
+		event.Action = domain.EventActionRefunded
+		event.AmountRefundedCents = charge.AmountRefunded
+		if len(charge.Refunds.Data) > 0 {
+			event.ProviderRefundID = charge.Refunds.Data[0].ID
+		}
+	}
+
+	return event, nil
+}
+
+// SignWebhookPayload firma un payload con el esquema de Stripe: "t=<unix>,v1=<HMAC-SHA256 hex>",
+// donde el HMAC se calcula sobre "<unix>.<payload>" con el secreto del endpoint
+func SignWebhookPayload(secret string, payload []byte, timestamp time.Time) string {
+	unix := strconv.FormatInt(timestamp.Unix(), 10)
+	return fmt.Sprintf("t=%s,v1=%s", unix, computeWebhookSignature(secret, unix, payload))
+}
+
+// verifyWebhookSignature comprueba una cabecera "t=...,v1=..." (puede traer varias firmas v1
+// durante la rotación del secreto) y rechaza las firmas fuera de WebhookSignatureTolerance
+func verifyWebhookSignature(secret string, payload []byte, header string, now time.Time) error {
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	if timestamp == "" || len(signatures) == 0 {
+		return domain.ErrInvalidWebhookSignature
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return domain.ErrInvalidWebhookSignature
+	}
+	age := now.Sub(time.Unix(unix, 0))
+	if age > domain.WebhookSignatureTolerance || age < -domain.WebhookSignatureTolerance {
+		return fmt.Errorf("%w: la firma ha caducado", domain.ErrInvalidWebhookSignature)
+	}
+
+	expected := computeWebhookSignature(secret, timestamp, payload)
+	for _, signature := range signatures {
+		if hmac.Equal([]byte(signature), []byte(expected)) {
+			return nil
+		}
+	}
+	return domain.ErrInvalidWebhookSignature
+}
+
+func computeWebhookSignature(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,64 @@
+package infrastructure
+
+import (
+	"backend-go/features/payments/domain"
+	walletDomain "backend-go/features/wallet/domain"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// WalletPaymentProvider cobra del monedero del usuario en lugar de un proveedor externo.
+// Los cargos son inmediatos (COMPLETED) y su referencia (wal_...) se guarda como PaymentIntentID.
+type WalletPaymentProvider struct {
+	ledger domain.WalletLedger
+}
+
+// NewWalletPaymentProvider crea la fuente de financiación del monedero
+func NewWalletPaymentProvider(ledger domain.WalletLedger) domain.WalletGateway {
+	return &WalletPaymentProvider{ledger: ledger}
+}
+
+// Name identifica el proveedor
+func (p *WalletPaymentProvider) Name() string {
+	return domain.ProviderWallet
+}
+
+// CreateCustomer no aplica: el cliente del monedero es el propio usuario
+func (p *WalletPaymentProvider) CreateCustomer(email, name string) (string, error) {
+	return "", fmt.Errorf("el monedero no registra clientes: se cobra al usuario por su ID")
+}
+
+// Charge cobra del saldo del usuario customerID (UUID)
+func (p *WalletPaymentProvider) Charge(amountCents int, customerID string, description string, idempotencyKey string) (*domain.ChargeResult, error) {
+	userID, err := uuid.Parse(customerID)
+	if err != nil {
+		return nil, domain.ErrCustomerNotFound
+	}
+
+	reference, err := p.ledger.Debit(userID, amountCents, description, idempotencyKey)
+	if err != nil {
+		switch {
+		case errors.Is(err, walletDomain.ErrInsufficientBalance):
+			return nil, fmt.Errorf("%w: %v", domain.ErrInsufficientFunds, err)
+		case errors.Is(err, walletDomain.ErrUserNotFound):
+			return nil, domain.ErrCustomerNotFound
+		case errors.Is(err, walletDomain.ErrInvalidAmount):
+			return nil, domain.ErrInvalidAmount
+		}
+		return nil, err
+	}
+
+	return &domain.ChargeResult{PaymentIntentID: reference, Status: domain.StatusCompleted}, nil
+}
+
+// Refund devuelve al monedero (parte de) el cargo paymentIntentID
+func (p *WalletPaymentProvider) Refund(paymentIntentID string, amountCents int) (string, error) {
+	return p.ledger.RefundDebit(paymentIntentID, amountCents)
+}
+
+// CreditRefund abona en el monedero el reembolso de un pago con tarjeta
+func (p *WalletPaymentProvider) CreditRefund(userID uuid.UUID, amountCents int, paymentID uint) (string, error) {
+	return p.ledger.CreditRefund(userID, amountCents, paymentID)
+}
@@ -0,0 +1,175 @@
+package presentation
+
+import (
+	"backend-go/features/payments/domain"
+	"backend-go/shared/middleware"
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// SplitBooking maneja la división del pago de una reserva entre jugadores
+// @Summary Dividir el pago de una reserva entre jugadores
+// @Description Solo el titular de la reserva (o ADMIN/GESTOR) y antes de pagarla. El precio se reparte a partes iguales
+// @Description entre el titular y los invitados (usuarios registrados, por ID o email; hasta 8 jugadores en total).
+// @Description La reserva se confirma al pagarse todas las partes o cuando el titular cubre lo pendiente con POST /api/payments/booking.
+// @Description Si vence el plazo sin completar el pago, la reserva se cancela y se devuelven las partes pagadas.
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de la reserva"
+// @Param split body SplitBookingRequest true "Jugadores invitados y plazo"
+// @Success 201 {object} BookingSplitResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/payments/booking/{id}/split [post]
+func (h *PaymentHandler) SplitBooking(c *fiber.Ctx) error {
+	bookingID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid booking ID"})
+	}
+
+	var req SplitBookingRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request"})
+	}
+	userIDs := make([]uuid.UUID, 0, len(req.UserIDs))
+	for _, raw := range req.UserIDs {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid user ID"})
+		}
+		userIDs = append(userIDs, id)
+	}
+
+	userID, ok := c.Locals("userID").(uuid.UUID)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Usuario no autenticado"})
+	}
+	roleName, _ := c.Locals("roleName").(string)
+	isStaff := roleName == "ADMIN" || roleName == "GESTOR"
+
+	split, err := h.service.SplitBooking(uint(bookingID), userID, isStaff, userIDs, req.Emails, req.Deadline)
+	if err != nil {
+		return c.Status(splitErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(ToBookingSplitResponse(split))
+}
+
+// GetBookingSplit maneja la consulta del reparto del pago de una reserva
+// @Summary Ver el pago dividido de una reserva
+// @Description Estado de cada parte y lo que falta por pagar. Solo el titular, los jugadores invitados o ADMIN/GESTOR.
+// @Tags payments
+// @Produce json
+// @Param id path int true "ID de la reserva"
+// @Success 200 {object} BookingSplitResponse
+// @Failure 404 {object} map[string]string
+// @Router /api/payments/booking/{id}/split [get]
+func (h *PaymentHandler) GetBookingSplit(c *fiber.Ctx) error {
+	bookingID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid booking ID"})
+	}
+
+	userID, ok := c.Locals("userID").(uuid.UUID)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Usuario no autenticado"})
+	}
+	roleName, _ := c.Locals("roleName").(string)
+	isStaff := roleName == "ADMIN" || roleName == "GESTOR"
+
+	split, err := h.service.GetBookingSplit(uint(bookingID), userID, isStaff)
+	if err != nil {
+		return c.Status(splitErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(ToBookingSplitResponse(split))
+}
+
+// GetMyShares maneja el listado de las partes del usuario autenticado
+// @Summary Mis partes de reservas con pago dividido
+// @Tags payments
+// @Produce json
+// @Success 200 {array} BookingShareResponse
+// @Router /api/payments/shares/me [get]
+func (h *PaymentHandler) GetMyShares(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uuid.UUID)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Usuario no autenticado"})
+	}
+
+	shares, err := h.service.GetMyShares(userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	response := make([]BookingShareResponse, len(shares))
+	for i := range shares {
+		response[i] = ToBookingShareResponse(&shares[i])
+	}
+	return c.JSON(response)
+}
+
+// ProcessBookingSharePayment maneja el pago de la parte de un jugador
+// @Summary Pagar mi parte de una reserva con pago dividido
+// @Description Solo el jugador de la parte (o ADMIN/GESTOR). Con payment_method = "wallet" se cobra de su monedero.
+// @Description Al pagarse la última parte pendiente la reserva queda pagada y confirmada.
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Param Idempotency-Key header string false "Clave para reintentar sin cobrar dos veces"
+// @Param payment body CreateBookingSharePaymentRequest true "Parte a pagar"
+// @Success 201 {object} PaymentResponse
+// @Success 202 {object} PaymentResponse "Pago pendiente de confirmar por webhook"
+// @Failure 402 {object} map[string]string "Saldo insuficiente o cargo rechazado"
+// @Failure 409 {object} map[string]string
+// @Router /api/payments/booking-share [post]
+func (h *PaymentHandler) ProcessBookingSharePayment(c *fiber.Ctx) error {
+	var req CreateBookingSharePaymentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request"})
+	}
+	if req.ShareID == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid share ID"})
+	}
+
+	userID, ok := c.Locals("userID").(uuid.UUID)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Usuario no autenticado"})
+	}
+	roleName, _ := c.Locals("roleName").(string)
+	isStaff := roleName == "ADMIN" || roleName == "GESTOR"
+
+	payment, err := h.service.CheckoutBookingShare(req.ShareID, userID, isStaff, req.PaymentMethod, req.CustomerID, middleware.IdempotencyKey(c))
+	if err != nil {
+		return c.Status(splitErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.Status(createdStatus(payment)).JSON(ToPaymentResponse(payment))
+}
+
+// splitErrorStatus traduce los errores del pago dividido a códigos HTTP
+func splitErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrBookingNotFound), errors.Is(err, domain.ErrShareNotFound), errors.Is(err, domain.ErrBookingNotSplit):
+		return fiber.StatusNotFound
+	case errors.Is(err, domain.ErrSplitNotOrganizer), errors.Is(err, domain.ErrShareNotOwned):
+		return fiber.StatusForbidden
+	case errors.Is(err, domain.ErrBookingAlreadySplit),
+		errors.Is(err, domain.ErrBookingAlreadyPaid),
+		errors.Is(err, domain.ErrPaymentPending),
+		errors.Is(err, domain.ErrShareNotPayable):
+		return fiber.StatusConflict
+	case errors.Is(err, domain.ErrInvalidSplit),
+		errors.Is(err, domain.ErrSplitUserNotFound),
+		errors.Is(err, domain.ErrInvalidSplitDeadline),
+		errors.Is(err, domain.ErrSplitDeadlinePassed),
+		errors.Is(err, domain.ErrBookingNotPayable):
+		return fiber.StatusBadRequest
+	}
+	return chargeErrorStatus(err)
+}
@@ -0,0 +1,111 @@
+package presentation
+
+import (
+	"backend-go/features/payments/application"
+	"backend-go/features/payments/domain"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type InvoiceHandler struct {
+	service *application.InvoiceService
+}
+
+func NewInvoiceHandler(service *application.InvoiceService) *InvoiceHandler {
+	return &InvoiceHandler{service: service}
+}
+
+// GetPaymentInvoice obtiene la factura de un pago
+// @Summary Factura de un pago
+// @Description Factura ordinaria (serie F) del pago con sus rectificativas (serie R) por reembolsos.
+// @Description Con format=pdf descarga el PDF. Solo el titular del pago o ADMIN/GESTOR.
+// @Tags payments
+// @Produce json
+// @Produce application/pdf
+// @Param id path int true "Payment ID"
+// @Param format query string false "json (por defecto) o pdf"
+// @Success 200 {object} InvoiceResponse
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/payments/{id}/invoice [get]
+func (h *InvoiceHandler) GetPaymentInvoice(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid payment ID"})
+	}
+
+	userID, ok := c.Locals("userID").(uuid.UUID)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Usuario no autenticado"})
+	}
+	roleName, _ := c.Locals("roleName").(string)
+	isStaff := roleName == "ADMIN" || roleName == "GESTOR"
+
+	invoice, err := h.service.GetPaymentInvoice(uint(id), userID, isStaff)
+	if err != nil {
+		return invoiceError(c, err)
+	}
+	return h.respond(c, invoice)
+}
+
+// GetInvoiceByNumber obtiene una factura o rectificativa por su número
+// @Summary Factura por número
+// @Description Con format=pdf descarga el PDF. Solo el titular del pago o ADMIN/GESTOR.
+// @Tags payments
+// @Produce json
+// @Produce application/pdf
+// @Param number path string true "Número de factura (F2026-000001, R2026-000001)"
+// @Param format query string false "json (por defecto) o pdf"
+// @Success 200 {object} InvoiceResponse
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/payments/invoices/{number} [get]
+func (h *InvoiceHandler) GetInvoiceByNumber(c *fiber.Ctx) error {
+	invoice, err := h.service.GetInvoiceByNumber(c.Params("number"))
+	if err != nil {
+		return invoiceError(c, err)
+	}
+	return h.respond(c, invoice)
+}
+
+// respond comprueba el acceso y devuelve la factura en JSON o PDF
+func (h *InvoiceHandler) respond(c *fiber.Ctx, invoice *domain.Invoice) error {
+	userID, ok := c.Locals("userID").(uuid.UUID)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Usuario no autenticado"})
+	}
+	roleName, _ := c.Locals("roleName").(string)
+	if roleName != "ADMIN" && roleName != "GESTOR" && invoice.UserID != userID {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": domain.ErrInvoiceNotOwned.Error()})
+	}
+
+	if c.Query("format") != "pdf" {
+		return c.JSON(ToInvoiceResponse(invoice))
+	}
+
+	pdf, err := h.service.RenderPDF(invoice)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	c.Set(fiber.HeaderContentType, "application/pdf")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=\"factura-%s.pdf\"", invoice.Number))
+	return c.Send(pdf)
+}
+
+// invoiceError traduce los errores de facturación a respuestas HTTP
+func invoiceError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrPaymentNotFound), errors.Is(err, domain.ErrInvoiceNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrInvoiceNotOwned):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrPaymentNotInvoiced):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+}
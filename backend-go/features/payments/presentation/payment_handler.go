@@ -3,6 +3,7 @@ package presentation
 import (
 	"backend-go/features/payments/application"
 	"backend-go/features/payments/domain"
+	"backend-go/shared/middleware"
 	"errors"
 	"strconv"
 
@@ -23,8 +24,10 @@ func NewPaymentHandler(service *application.PaymentService) *PaymentHandler {
 // @Tags payments
 // @Accept json
 // @Produce json
+// @Param Idempotency-Key header string false "Clave para reintentar sin cobrar dos veces"
 // @Param payment body CreatePaymentRequest true "Datos del pago"
 // @Success 201 {object} PaymentResponse
+// @Success 202 {object} PaymentResponse "Pago pendiente de confirmar por webhook"
 // @Router /api/payments [post]
 func (h *PaymentHandler) ProcessPayment(c *fiber.Ctx) error {
 	var req CreatePaymentRequest
@@ -37,7 +40,7 @@ func (h *PaymentHandler) ProcessPayment(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid user ID"})
 	}
 
-	payment, err := h.service.ProcessPayment(userID, req.AmountCents, req.CustomerID, req.Description)
+	payment, err := h.service.ProcessPayment(userID, req.AmountCents, req.CustomerID, req.Description, middleware.IdempotencyKey(c))
 	if err != nil {
 		if errors.Is(err, domain.ErrInvalidAmount) {
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
@@ -45,34 +48,58 @@ func (h *PaymentHandler) ProcessPayment(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(ToPaymentResponse(payment))
+	return c.Status(createdStatus(payment)).JSON(ToPaymentResponse(payment))
 }
 
-// ProcessBookingPayment maneja el pago de una reserva
-// @Summary Procesar pago de reserva
+// ProcessBookingPayment maneja el pago (checkout) de una reserva
+// @Summary Pagar una reserva
+// @Description El importe se toma del precio congelado de la reserva. Confirma la reserva y rechaza pagos duplicados.
+// @Description Con payment_method = "wallet" se cobra del monedero del titular de la reserva.
+// @Description Con promo_code se cobra el precio menos el descuento del código, que queda registrado en la reserva y en el pago.
+// @Description Si el pago está dividido, el titular cubre las partes aún pendientes (sin código promocional y antes del plazo).
 // @Tags payments
 // @Accept json
 // @Produce json
-// @Param payment body CreateBookingPaymentRequest true "Datos del pago de reserva"
+// @Param Idempotency-Key header string false "Clave para reintentar sin cobrar dos veces"
+// @Param payment body CreateBookingPaymentRequest true "Reserva a pagar"
 // @Success 201 {object} PaymentResponse
+// @Success 202 {object} PaymentResponse "Pago pendiente de confirmar por webhook"
+// @Failure 400 {object} map[string]string "Código promocional no válido"
+// @Failure 402 {object} map[string]string "Saldo insuficiente o cargo rechazado"
+// @Failure 409 {object} map[string]string
 // @Router /api/payments/booking [post]
 func (h *PaymentHandler) ProcessBookingPayment(c *fiber.Ctx) error {
 	var req CreateBookingPaymentRequest
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request"})
 	}
+	if req.BookingID == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid booking ID"})
+	}
 
-	userID, err := uuid.Parse(req.UserID)
-	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid user ID"})
+	userID, ok := c.Locals("userID").(uuid.UUID)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Usuario no autenticado"})
 	}
+	roleName, _ := c.Locals("roleName").(string)
+	isStaff := roleName == "ADMIN" || roleName == "GESTOR"
 
-	payment, err := h.service.ProcessBookingPayment(userID, req.BookingID, req.AmountCents, req.CustomerID)
+	payment, err := h.service.CheckoutBooking(req.BookingID, userID, isStaff, req.PaymentMethod, req.CustomerID, req.PromoCode, middleware.IdempotencyKey(c))
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		switch {
+		case errors.Is(err, domain.ErrBookingNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, domain.ErrBookingNotOwned):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, domain.ErrBookingAlreadyPaid), errors.Is(err, domain.ErrPaymentPending):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, domain.ErrBookingNotPayable), errors.Is(err, domain.ErrSplitPromoCode), errors.Is(err, domain.ErrSplitDeadlinePassed):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(chargeErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(ToPaymentResponse(payment))
+	return c.Status(createdStatus(payment)).JSON(ToPaymentResponse(payment))
 }
 
 // ProcessClassPayment maneja el pago de una inscripción a clase
@@ -80,8 +107,15 @@ func (h *PaymentHandler) ProcessBookingPayment(c *fiber.Ctx) error {
 // @Tags payments
 // @Accept json
 // @Produce json
-// @Param payment body CreateClassPaymentRequest true "Datos del pago de clase"
+// @Param Idempotency-Key header string false "Clave para reintentar sin cobrar dos veces"
+// @Param payment body CreateClassPaymentRequest true "Datos del pago de clase (payment_method: card o wallet)"
 // @Success 201 {object} PaymentResponse
+// @Success 202 {object} PaymentResponse "Pago pendiente de confirmar por webhook"
+// @Failure 400 {object} map[string]string "Código promocional no válido"
+// @Failure 402 {object} map[string]string "Saldo insuficiente o cargo rechazado"
+// @Failure 403 {object} map[string]string "Inscripción de otro usuario"
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string "Ya pagada"
 // @Router /api/payments/class [post]
 func (h *PaymentHandler) ProcessClassPayment(c *fiber.Ctx) error {
 	var req CreateClassPaymentRequest
@@ -94,12 +128,17 @@ func (h *PaymentHandler) ProcessClassPayment(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid user ID"})
 	}
 
-	payment, err := h.service.ProcessClassPayment(userID, req.EnrollmentID, req.AmountCents, req.CustomerID)
+	// Solo el titular, o ADMIN/GESTOR en su nombre, puede pagar (y usar su monedero)
+	if !canPayFor(c, userID) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": domain.ErrEnrollmentNotOwned.Error()})
+	}
+
+	payment, err := h.service.ProcessClassPayment(userID, req.EnrollmentID, req.PaymentMethod, req.CustomerID, req.PromoCode, middleware.IdempotencyKey(c))
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return c.Status(chargeErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(ToPaymentResponse(payment))
+	return c.Status(createdStatus(payment)).JSON(ToPaymentResponse(payment))
 }
 
 // ProcessClubPayment maneja el pago de una membresía a club
@@ -107,8 +146,15 @@ func (h *PaymentHandler) ProcessClassPayment(c *fiber.Ctx) error {
 // @Tags payments
 // @Accept json
 // @Produce json
-// @Param payment body CreateClubPaymentRequest true "Datos del pago de membresía"
+// @Param Idempotency-Key header string false "Clave para reintentar sin cobrar dos veces"
+// @Param payment body CreateClubPaymentRequest true "Datos del pago de membresía (payment_method: card o wallet)"
 // @Success 201 {object} PaymentResponse
+// @Success 202 {object} PaymentResponse "Pago pendiente de confirmar por webhook"
+// @Failure 400 {object} map[string]string "Código promocional no válido"
+// @Failure 402 {object} map[string]string "Saldo insuficiente o cargo rechazado"
+// @Failure 403 {object} map[string]string "Membresía de otro usuario"
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string "Ya pagada"
 // @Router /api/payments/club [post]
 func (h *PaymentHandler) ProcessClubPayment(c *fiber.Ctx) error {
 	var req CreateClubPaymentRequest
@@ -121,12 +167,17 @@ func (h *PaymentHandler) ProcessClubPayment(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid user ID"})
 	}
 
-	payment, err := h.service.ProcessClubPayment(userID, req.MembershipID, req.AmountCents, req.CustomerID)
+	// Solo el titular, o ADMIN/GESTOR en su nombre, puede pagar (y usar su monedero)
+	if !canPayFor(c, userID) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": domain.ErrMembershipNotOwned.Error()})
+	}
+
+	payment, err := h.service.ProcessClubPayment(userID, req.MembershipID, req.PaymentMethod, req.CustomerID, req.PromoCode, middleware.IdempotencyKey(c))
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return c.Status(chargeErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(ToPaymentResponse(payment))
+	return c.Status(createdStatus(payment)).JSON(ToPaymentResponse(payment))
 }
 
 // GetUserPayments obtiene todos los pagos de un usuario
@@ -179,13 +230,14 @@ func (h *PaymentHandler) GetPaymentByID(c *fiber.Ctx) error {
 	return c.JSON(ToPaymentResponse(payment))
 }
 
-// RefundPayment procesa un reembolso
-// @Summary Reembolsar un pago
+// RefundPayment procesa un reembolso total o parcial
+// @Summary Reembolsar un pago (total o parcial)
+// @Description Con to_wallet el importe se abona en el monedero del titular en lugar de devolverse a la tarjeta.
 // @Tags payments
 // @Accept json
 // @Produce json
 // @Param refund body RefundPaymentRequest true "Datos del reembolso"
-// @Success 200 {object} MessageResponse
+// @Success 200 {object} RefundResponse
 // @Router /api/payments/refund [post]
 func (h *PaymentHandler) RefundPayment(c *fiber.Ctx) error {
 	var req RefundPaymentRequest
@@ -193,9 +245,95 @@ func (h *PaymentHandler) RefundPayment(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request"})
 	}
 
-	if err := h.service.RefundPayment(req.PaymentID); err != nil {
+	refund, err := h.service.RefundPayment(req.PaymentID, req.AmountCents, req.Reason, req.ToWallet)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrPaymentNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, domain.ErrInvalidAmount),
+			errors.Is(err, domain.ErrRefundExceedsPayment),
+			errors.Is(err, domain.ErrPaymentNotRefundable),
+			errors.Is(err, domain.ErrWalletUnavailable):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
 
-	return c.JSON(fiber.Map{"message": "Reembolso procesado exitosamente"})
+	return c.JSON(ToRefundResponse(refund))
+}
+
+// GetPaymentRefunds obtiene los reembolsos de un pago
+// @Summary Listar reembolsos de un pago
+// @Tags payments
+// @Produce json
+// @Param id path int true "Payment ID"
+// @Success 200 {array} RefundResponse
+// @Router /api/payments/{id}/refunds [get]
+func (h *PaymentHandler) GetPaymentRefunds(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid payment ID"})
+	}
+
+	refunds, err := h.service.GetPaymentRefunds(uint(id))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	response := make([]RefundResponse, len(refunds))
+	for i := range refunds {
+		response[i] = ToRefundResponse(&refunds[i])
+	}
+
+	return c.JSON(response)
+}
+
+// GetCancellationPolicy devuelve la política de cancelación vigente
+// @Summary Política de cancelación (tramos de reembolso por antelación)
+// @Tags payments
+// @Produce json
+// @Success 200 {object} CancellationPolicyResponse
+// @Router /api/payments/cancellation-policy [get]
+func (h *PaymentHandler) GetCancellationPolicy(c *fiber.Ctx) error {
+	return c.JSON(ToCancellationPolicyResponse(h.service.CancellationPolicy()))
+}
+
+// createdStatus devuelve 201 si el cobro se realizó o 202 si queda pendiente de confirmar por webhook
+func createdStatus(payment *domain.Payment) int {
+	if payment.Status == domain.StatusPending {
+		return fiber.StatusAccepted
+	}
+	return fiber.StatusCreated
+}
+
+// chargeErrorStatus devuelve el código HTTP de un error al cobrar
+func chargeErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrInvalidAmount),
+		errors.Is(err, domain.ErrInvalidPaymentMethod),
+		errors.Is(err, domain.ErrWalletUnavailable),
+		errors.Is(err, domain.ErrCustomerNotFound),
+		errors.Is(err, domain.ErrInvalidPromoCode),
+		errors.Is(err, domain.ErrPromoCodesUnavailable):
+		return fiber.StatusBadRequest
+	case errors.Is(err, domain.ErrEnrollmentNotOwned), errors.Is(err, domain.ErrMembershipNotOwned):
+		return fiber.StatusForbidden
+	case errors.Is(err, domain.ErrEnrollmentNotFound), errors.Is(err, domain.ErrMembershipNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, domain.ErrInsufficientFunds), errors.Is(err, domain.ErrPaymentFailed):
+		return fiber.StatusPaymentRequired
+	case errors.Is(err, domain.ErrChargeRefunded),
+		errors.Is(err, domain.ErrEnrollmentAlreadyPaid),
+		errors.Is(err, domain.ErrMembershipAlreadyPaid):
+		return fiber.StatusConflict
+	}
+	return fiber.StatusInternalServerError
+}
+
+// canPayFor indica si el usuario autenticado puede pagar en nombre de userID (y cobrar de su
+// monedero): solo el titular o ADMIN/GESTOR (cobro en recepción)
+func canPayFor(c *fiber.Ctx, userID uuid.UUID) bool {
+	authUserID, _ := c.Locals("userID").(uuid.UUID)
+	roleName, _ := c.Locals("roleName").(string)
+	return authUserID == userID || roleName == "ADMIN" || roleName == "GESTOR"
 }
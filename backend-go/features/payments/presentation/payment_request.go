@@ -1,5 +1,7 @@
 package presentation
 
+import "time"
+
 // CreatePaymentRequest representa la petición para crear un pago
 type CreatePaymentRequest struct {
 	UserID      string `json:"user_id" validate:"required"` // UUID como string
@@ -8,31 +10,51 @@ type CreatePaymentRequest struct {
 	Description string `json:"description" validate:"required"`
 }
 
-// CreateBookingPaymentRequest representa la petición para pago de reserva
+// CreateBookingPaymentRequest representa la petición para pago de reserva.
+// El importe y el titular se leen de la reserva (PriceSnapshotCents), no del cliente.
 type CreateBookingPaymentRequest struct {
-	UserID      string `json:"user_id" validate:"required"` // UUID como string
-	BookingID   uint   `json:"booking_id" validate:"required"`
-	AmountCents int    `json:"amount_cents" validate:"required,gt=0"`
-	CustomerID  string `json:"customer_id" validate:"required"`
+	BookingID     uint   `json:"booking_id" validate:"required"`
+	PaymentMethod string `json:"payment_method"` // Opcional: card (por defecto) o wallet
+	CustomerID    string `json:"customer_id"`    // Opcional: por defecto el StripeCustomerID del titular
+	PromoCode     string `json:"promo_code"`     // Opcional: código promocional
+}
+
+// SplitBookingRequest representa la petición para dividir el pago de una reserva entre jugadores
+type SplitBookingRequest struct {
+	UserIDs  []string   `json:"user_ids"` // Jugadores invitados por ID (UUID como string)
+	Emails   []string   `json:"emails"`   // Jugadores invitados por email (usuarios registrados)
+	Deadline *time.Time `json:"deadline"` // Opcional: por defecto 2 horas antes del inicio de la reserva
+}
+
+// CreateBookingSharePaymentRequest representa la petición para pagar la parte de una reserva
+type CreateBookingSharePaymentRequest struct {
+	ShareID       uint   `json:"share_id" validate:"required"`
+	PaymentMethod string `json:"payment_method"` // Opcional: card (por defecto) o wallet (saldo del jugador)
+	CustomerID    string `json:"customer_id"`    // Opcional: por defecto el StripeCustomerID del jugador
 }
 
 // CreateClassPaymentRequest representa la petición para pago de clase
 type CreateClassPaymentRequest struct {
-	UserID       string `json:"user_id" validate:"required"` // UUID como string
-	EnrollmentID uint   `json:"enrollment_id" validate:"required"`
-	AmountCents  int    `json:"amount_cents" validate:"required,gt=0"`
-	CustomerID   string `json:"customer_id" validate:"required"`
+	UserID        string `json:"user_id" validate:"required"`       // UUID como string
+	EnrollmentID  uint   `json:"enrollment_id" validate:"required"` // Se cobra el precio de la clase
+	PaymentMethod string `json:"payment_method"`                    // Opcional: card (por defecto) o wallet (saldo de user_id)
+	CustomerID    string `json:"customer_id"`                       // Obligatorio con card
+	PromoCode     string `json:"promo_code"`                        // Opcional: código promocional (se descuenta del precio)
 }
 
 // CreateClubPaymentRequest representa la petición para pago de membresía
 type CreateClubPaymentRequest struct {
-	UserID       string `json:"user_id" validate:"required"` // UUID como string
-	MembershipID uint   `json:"membership_id" validate:"required"`
-	AmountCents  int    `json:"amount_cents" validate:"required,gt=0"`
-	CustomerID   string `json:"customer_id" validate:"required"`
+	UserID        string `json:"user_id" validate:"required"`       // UUID como string
+	MembershipID  uint   `json:"membership_id" validate:"required"` // Se cobra la cuota mensual del club
+	PaymentMethod string `json:"payment_method"`                    // Opcional: card (por defecto) o wallet (saldo de user_id)
+	CustomerID    string `json:"customer_id"`                       // Obligatorio con card
+	PromoCode     string `json:"promo_code"`                        // Opcional: código promocional (se descuenta del precio)
 }
 
 // RefundPaymentRequest representa la petición para reembolsar un pago
 type RefundPaymentRequest struct {
-	PaymentID uint `json:"payment_id" validate:"required"`
+	PaymentID   uint   `json:"payment_id" validate:"required"`
+	AmountCents int    `json:"amount_cents" validate:"gte=0"` // Opcional: 0 = reembolsar todo lo pendiente
+	Reason      string `json:"reason"`                        // Opcional: MANUAL por defecto
+	ToWallet    bool   `json:"to_wallet"`                     // Opcional: abonar en el monedero del titular
 }
@@ -11,6 +11,7 @@ type PaymentResponse struct {
 	UserID                string  `json:"user_id"` // UUID como string
 	AmountCents           int     `json:"amount_cents"`
 	AmountEuros           float64 `json:"amount_euros"`
+	RefundedCents         int     `json:"refunded_cents"`
 	Currency              string  `json:"currency"`
 	Status                string  `json:"status"`
 	Provider              string  `json:"provider"`
@@ -18,6 +19,10 @@ type PaymentResponse struct {
 	BookingID             *uint   `json:"booking_id,omitempty"`
 	ClassEnrollmentID     *uint   `json:"class_enrollment_id,omitempty"`
 	ClubMembershipID      *uint   `json:"club_membership_id,omitempty"`
+	PassID                *uint   `json:"pass_id,omitempty"`
+	BookingShareID        *uint   `json:"booking_share_id,omitempty"` // Pago dividido: parte del jugador
+	DiscountCents         int     `json:"discount_cents"`
+	PromoCode             *string `json:"promo_code,omitempty"`
 	PaymentType           string  `json:"payment_type"`
 	CreatedAt             string  `json:"created_at"`
 	UpdatedAt             string  `json:"updated_at"`
@@ -30,6 +35,7 @@ func ToPaymentResponse(payment *domain.Payment) PaymentResponse {
 		UserID:                payment.UserID.String(),
 		AmountCents:           payment.AmountCents,
 		AmountEuros:           float64(payment.AmountCents) / 100.0,
+		RefundedCents:         payment.RefundedCents,
 		Currency:              payment.Currency,
 		Status:                payment.Status,
 		Provider:              payment.Provider,
@@ -37,6 +43,10 @@ func ToPaymentResponse(payment *domain.Payment) PaymentResponse {
 		BookingID:             payment.BookingID,
 		ClassEnrollmentID:     payment.ClassEnrollmentID,
 		ClubMembershipID:      payment.ClubMembershipID,
+		PassID:                payment.PassID,
+		BookingShareID:        payment.BookingShareID,
+		DiscountCents:         payment.DiscountCents,
+		PromoCode:             payment.PromoCode,
 		CreatedAt:             payment.CreatedAt.Format(time.RFC3339),
 		UpdatedAt:             payment.UpdatedAt.Format(time.RFC3339),
 	}
@@ -48,6 +58,8 @@ func ToPaymentResponse(payment *domain.Payment) PaymentResponse {
 		response.PaymentType = "class"
 	} else if payment.ClubMembershipID != nil {
 		response.PaymentType = "club"
+	} else if payment.PassID != nil {
+		response.PaymentType = "pass"
 	} else {
 		response.PaymentType = "generic"
 	}
@@ -55,7 +67,275 @@ func ToPaymentResponse(payment *domain.Payment) PaymentResponse {
 	return response
 }
 
+// RefundResponse representa la respuesta de un reembolso
+type RefundResponse struct {
+	ID               uint    `json:"id"`
+	PaymentID        uint    `json:"payment_id"`
+	AmountCents      int     `json:"amount_cents"`
+	AmountEuros      float64 `json:"amount_euros"`
+	Reason           string  `json:"reason"`
+	ProviderRefundID string  `json:"provider_refund_id"`
+	Destination      string  `json:"destination"`
+	CreatedAt        string  `json:"created_at"`
+}
+
+// ToRefundResponse convierte un domain.Refund a RefundResponse
+func ToRefundResponse(refund *domain.Refund) RefundResponse {
+	return RefundResponse{
+		ID:               refund.ID,
+		PaymentID:        refund.PaymentID,
+		AmountCents:      refund.AmountCents,
+		AmountEuros:      float64(refund.AmountCents) / 100.0,
+		Reason:           refund.Reason,
+		ProviderRefundID: refund.ProviderRefundID,
+		Destination:      refund.Destination,
+		CreatedAt:        refund.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// PaymentEventResponse representa un evento de webhook recibido del proveedor
+type PaymentEventResponse struct {
+	ID              uint    `json:"id"`
+	Provider        string  `json:"provider"`
+	EventID         string  `json:"event_id"`
+	Type            string  `json:"type"`
+	PaymentIntentID string  `json:"payment_intent_id,omitempty"`
+	PaymentID       *uint   `json:"payment_id,omitempty"`
+	Status          string  `json:"status"`
+	Error           *string `json:"error,omitempty"`
+	ReceivedAt      string  `json:"received_at"`
+	ProcessedAt     *string `json:"processed_at,omitempty"`
+}
+
+// ToPaymentEventResponse convierte un domain.PaymentEvent a PaymentEventResponse
+func ToPaymentEventResponse(event *domain.PaymentEvent) PaymentEventResponse {
+	response := PaymentEventResponse{
+		ID:              event.ID,
+		Provider:        event.Provider,
+		EventID:         event.EventID,
+		Type:            event.Type,
+		PaymentIntentID: event.PaymentIntentID,
+		PaymentID:       event.PaymentID,
+		Status:          event.Status,
+		Error:           event.Error,
+		ReceivedAt:      event.ReceivedAt.Format(time.RFC3339),
+	}
+	if event.ProcessedAt != nil {
+		processedAt := event.ProcessedAt.Format(time.RFC3339)
+		response.ProcessedAt = &processedAt
+	}
+	return response
+}
+
+// WebhookAckResponse es la respuesta a un webhook aceptado
+type WebhookAckResponse struct {
+	Received  bool   `json:"received"`
+	EventID   string `json:"event_id"`
+	Status    string `json:"status"`    // PROCESSED o IGNORED
+	Duplicate bool   `json:"duplicate"` // Reenvío de un evento ya procesado
+}
+
+// RefundTierResponse representa un tramo de la política de cancelación
+type RefundTierResponse struct {
+	MinHoursBefore int `json:"min_hours_before"`
+	Percent        int `json:"percent"`
+}
+
+// CancellationPolicyResponse representa la política de cancelación vigente
+type CancellationPolicyResponse struct {
+	Tiers []RefundTierResponse `json:"tiers"`
+}
+
+// ToCancellationPolicyResponse convierte la política de dominio a DTO
+func ToCancellationPolicyResponse(policy domain.CancellationPolicy) CancellationPolicyResponse {
+	response := CancellationPolicyResponse{Tiers: make([]RefundTierResponse, len(policy.Tiers))}
+	for i, tier := range policy.Tiers {
+		response.Tiers[i] = RefundTierResponse{MinHoursBefore: tier.MinHoursBefore, Percent: tier.Percent}
+	}
+	return response
+}
+
+// InvoiceLineResponse representa una línea de factura
+type InvoiceLineResponse struct {
+	Description    string `json:"description"`
+	Quantity       int    `json:"quantity"`
+	UnitPriceCents int    `json:"unit_price_cents"` // Con IVA
+	BaseCents      int    `json:"base_cents"`
+	VATRatePercent int    `json:"vat_rate_percent"`
+	VATCents       int    `json:"vat_cents"`
+	TotalCents     int    `json:"total_cents"`
+}
+
+// VATBreakdownResponse representa el desglose de la factura por tipo de IVA
+type VATBreakdownResponse struct {
+	VATRatePercent int `json:"vat_rate_percent"`
+	BaseCents      int `json:"base_cents"`
+	VATCents       int `json:"vat_cents"`
+}
+
+// InvoicePartyResponse representa los datos fiscales del emisor o del cliente
+type InvoicePartyResponse struct {
+	Name    string `json:"name"`
+	TaxID   string `json:"tax_id,omitempty"`
+	Address string `json:"address,omitempty"`
+	Email   string `json:"email,omitempty"`
+}
+
+// InvoiceResponse representa una factura (ORDINARY) o factura rectificativa (RECTIFYING)
+type InvoiceResponse struct {
+	ID                 uint                   `json:"id"`
+	Number             string                 `json:"number"`
+	Type               string                 `json:"type"`
+	Series             string                 `json:"series"`
+	FiscalYear         int                    `json:"fiscal_year"`
+	PaymentID          uint                   `json:"payment_id"`
+	RefundID           *uint                  `json:"refund_id,omitempty"`
+	RectifiesNumber    string                 `json:"rectifies_number,omitempty"`
+	Reason             string                 `json:"reason,omitempty"`
+	IssuedAt           string                 `json:"issued_at"`
+	Issuer             InvoicePartyResponse   `json:"issuer"`
+	Customer           InvoicePartyResponse   `json:"customer"`
+	Currency           string                 `json:"currency"`
+	Lines              []InvoiceLineResponse  `json:"lines"`
+	VATBreakdown       []VATBreakdownResponse `json:"vat_breakdown"`
+	BaseCents          int                    `json:"base_cents"`
+	VATCents           int                    `json:"vat_cents"`
+	TotalCents         int                    `json:"total_cents"`
+	TotalEuros         float64                `json:"total_euros"`
+	RectifyingInvoices []InvoiceResponse      `json:"rectifying_invoices,omitempty"`
+}
+
+// ToInvoiceResponse convierte un domain.Invoice a InvoiceResponse
+func ToInvoiceResponse(invoice *domain.Invoice) InvoiceResponse {
+	response := InvoiceResponse{
+		ID:              invoice.ID,
+		Number:          invoice.Number,
+		Type:            invoice.Type,
+		Series:          invoice.Series,
+		FiscalYear:      invoice.FiscalYear,
+		PaymentID:       invoice.PaymentID,
+		RefundID:        invoice.RefundID,
+		RectifiesNumber: invoice.RectifiesNumber,
+		Reason:          invoice.Reason,
+		IssuedAt:        invoice.IssuedAt.Format(time.RFC3339),
+		Issuer: InvoicePartyResponse{
+			Name:    invoice.Issuer.Name,
+			TaxID:   invoice.Issuer.TaxID,
+			Address: invoice.Issuer.Address,
+		},
+		Customer: InvoicePartyResponse{
+			Name:  invoice.CustomerName,
+			TaxID: invoice.CustomerTaxID,
+			Email: invoice.CustomerEmail,
+		},
+		Currency:   invoice.Currency,
+		Lines:      make([]InvoiceLineResponse, len(invoice.Lines)),
+		BaseCents:  invoice.BaseCents,
+		VATCents:   invoice.VATCents,
+		TotalCents: invoice.TotalCents,
+		TotalEuros: float64(invoice.TotalCents) / 100.0,
+	}
+
+	for i, line := range invoice.Lines {
+		response.Lines[i] = InvoiceLineResponse{
+			Description:    line.Description,
+			Quantity:       line.Quantity,
+			UnitPriceCents: line.UnitPriceCents,
+			BaseCents:      line.BaseCents,
+			VATRatePercent: line.VATRatePercent,
+			VATCents:       line.VATCents,
+			TotalCents:     line.TotalCents,
+		}
+	}
+	for _, vat := range invoice.VATBreakdown() {
+		response.VATBreakdown = append(response.VATBreakdown, VATBreakdownResponse{
+			VATRatePercent: vat.VATRatePercent,
+			BaseCents:      vat.BaseCents,
+			VATCents:       vat.VATCents,
+		})
+	}
+	for i := range invoice.Rectifications {
+		response.RectifyingInvoices = append(response.RectifyingInvoices, ToInvoiceResponse(&invoice.Rectifications[i]))
+	}
+
+	return response
+}
+
 // MessageResponse representa una respuesta simple con mensaje
 type MessageResponse struct {
 	Message string `json:"message"`
 }
+
+// BookingShareResponse representa la parte de un jugador en una reserva con pago dividido
+type BookingShareResponse struct {
+	ID            uint    `json:"id"`
+	BookingID     uint    `json:"booking_id"`
+	UserID        string  `json:"user_id"` // UUID como string
+	UserName      string  `json:"user_name,omitempty"`
+	AmountCents   int     `json:"amount_cents"`
+	AmountEuros   float64 `json:"amount_euros"`
+	Status        string  `json:"status"` // PENDING, PAID, COVERED, REFUNDED, CANCELLED
+	PaymentID     *uint   `json:"payment_id,omitempty"`
+	PaidAt        *string `json:"paid_at,omitempty"`
+	PistaName     string  `json:"pista_name,omitempty"`
+	StartTime     *string `json:"start_time,omitempty"`
+	SplitDeadline *string `json:"split_deadline,omitempty"`
+}
+
+// ToBookingShareResponse convierte un domain.BookingShare a BookingShareResponse
+func ToBookingShareResponse(share *domain.BookingShare) BookingShareResponse {
+	response := BookingShareResponse{
+		ID:          share.ID,
+		BookingID:   share.BookingID,
+		UserID:      share.UserID.String(),
+		UserName:    share.UserName,
+		AmountCents: share.AmountCents,
+		AmountEuros: float64(share.AmountCents) / 100.0,
+		Status:      share.Status,
+		PaymentID:   share.PaymentID,
+		PistaName:   share.PistaName,
+	}
+	if share.PaidAt != nil {
+		paidAt := share.PaidAt.Format(time.RFC3339)
+		response.PaidAt = &paidAt
+	}
+	if !share.BookingStartTime.IsZero() {
+		startTime := share.BookingStartTime.Format(time.RFC3339)
+		response.StartTime = &startTime
+	}
+	if share.SplitDeadline != nil {
+		deadline := share.SplitDeadline.Format(time.RFC3339)
+		response.SplitDeadline = &deadline
+	}
+	return response
+}
+
+// BookingSplitResponse representa el reparto del pago de una reserva
+type BookingSplitResponse struct {
+	BookingID        uint                   `json:"booking_id"`
+	OrganizerID      string                 `json:"organizer_id"` // UUID como string
+	TotalCents       int                    `json:"total_cents"`
+	OutstandingCents int                    `json:"outstanding_cents"` // Partes aún pendientes
+	Deadline         string                 `json:"deadline"`
+	BookingStatus    string                 `json:"booking_status"`
+	PaymentStatus    string                 `json:"payment_status"`
+	Shares           []BookingShareResponse `json:"shares"`
+}
+
+// ToBookingSplitResponse convierte un domain.BookingSplit a BookingSplitResponse
+func ToBookingSplitResponse(split *domain.BookingSplit) BookingSplitResponse {
+	response := BookingSplitResponse{
+		BookingID:        split.BookingID,
+		OrganizerID:      split.OrganizerID.String(),
+		TotalCents:       split.TotalCents,
+		OutstandingCents: split.OutstandingCents(),
+		Deadline:         split.Deadline.Format(time.RFC3339),
+		BookingStatus:    split.BookingStatus,
+		PaymentStatus:    split.PaymentStatus,
+		Shares:           make([]BookingShareResponse, len(split.Shares)),
+	}
+	for i := range split.Shares {
+		response.Shares[i] = ToBookingShareResponse(&split.Shares[i])
+	}
+	return response
+}
@@ -1,6 +1,7 @@
 package presentation
 
 import (
+	"backend-go/shared/idempotency"
 	"backend-go/shared/middleware"
 	"backend-go/shared/security"
 
@@ -9,25 +10,47 @@ import (
 
 // ======================================================================================
 // PAYMENT ROUTES
-// Admin: GET /:id (ver pago específico), POST /refund (reembolso)
+// Público: GET /cancellation-policy, POST /webhooks/:provider (autenticado por firma del proveedor)
+// Admin: GET /:id (ver pago específico), GET /:id/refunds, GET /:id/events, POST /refund (reembolso total o parcial)
 // Autenticado: POST / (procesar pago), GET /user/:user_id (mis pagos)
+// Titular del pago o ADMIN/GESTOR: GET /:id/invoice, GET /invoices/:number (JSON o ?format=pdf)
+// Pago dividido: POST/GET /booking/:id/split, GET /shares/me, POST /booking-share (pagar mi parte)
+// Los POST que cobran admiten la cabecera Idempotency-Key (reintentos sin doble cargo)
 // ======================================================================================
 
 // RegisterRoutes registra todas las rutas de pagos
-func RegisterRoutes(app *fiber.App, handler *PaymentHandler, jwtService security.JWTService) {
-	// Rutas protegidas - Solo ADMIN y GESTOR (reembolsos y ver pagos específicos)
-	admin := app.Group("/api/payments")
-	admin.Use(middleware.JWTMiddleware(jwtService))
-	admin.Use(middleware.RequireRoleByName("ADMIN", "GESTOR"))
-	admin.Post("/refund", handler.RefundPayment) // Reembolso - Solo ADMIN
-	admin.Get("/:id", handler.GetPaymentByID)    // Ver pago por ID - Solo ADMIN
+func RegisterRoutes(app *fiber.App, handler *PaymentHandler, webhookHandler *WebhookHandler, invoiceHandler *InvoiceHandler, jwtService security.JWTService, idempotencyStore *idempotency.Store) {
+	// Rutas públicas
+	app.Get("/api/payments/cancellation-policy", handler.GetCancellationPolicy)
+	app.Post("/api/payments/webhooks/:provider", webhookHandler.HandleWebhook) // Webhooks firmados del proveedor
+
+	// El resto requiere autenticación. La restricción de rol se aplica por ruta (no con Use en un
+	// grupo) para que no alcance a las rutas de clientes que comparten el prefijo /api/payments.
+	payments := app.Group("/api/payments")
+	payments.Use(middleware.JWTMiddleware(jwtService))
+	staff := middleware.RequireRoleByName("ADMIN", "GESTOR")
+	idempotent := middleware.Idempotency(idempotencyStore)
 
 	// Rutas protegidas - Autenticado (procesar pagos y ver mis pagos)
-	protected := app.Group("/api/payments")
-	protected.Use(middleware.JWTMiddleware(jwtService))
-	protected.Post("/", handler.ProcessPayment)               // Procesar pago genérico - Autenticado
-	protected.Post("/booking", handler.ProcessBookingPayment) // Pago de reserva - Autenticado
-	protected.Post("/class", handler.ProcessClassPayment)     // Pago de clase - Autenticado
-	protected.Post("/club", handler.ProcessClubPayment)       // Pago de club - Autenticado
-	protected.Get("/user/:user_id", handler.GetUserPayments)  // Mis pagos - Validar usuario en handler
+	payments.Post("/", idempotent, handler.ProcessPayment)               // Procesar pago genérico - Autenticado
+	payments.Post("/booking", idempotent, handler.ProcessBookingPayment) // Pago de reserva - Autenticado
+	payments.Post("/class", idempotent, handler.ProcessClassPayment)     // Pago de clase - Autenticado
+	payments.Post("/club", idempotent, handler.ProcessClubPayment)       // Pago de club - Autenticado
+	payments.Get("/user/:user_id", handler.GetUserPayments)              // Mis pagos - Validar usuario en handler
+
+	// Rutas protegidas - Facturas (titular del pago o ADMIN/GESTOR, se comprueba en el handler)
+	payments.Get("/invoices/:number", invoiceHandler.GetInvoiceByNumber)
+	payments.Get("/:id/invoice", invoiceHandler.GetPaymentInvoice)
+
+	// Rutas protegidas - Pago dividido (titular, jugadores invitados o ADMIN/GESTOR, se comprueba en el handler)
+	payments.Post("/booking/:id/split", handler.SplitBooking)
+	payments.Get("/booking/:id/split", handler.GetBookingSplit)
+	payments.Get("/shares/me", handler.GetMyShares)
+	payments.Post("/booking-share", idempotent, handler.ProcessBookingSharePayment)
+
+	// Rutas protegidas - Solo ADMIN y GESTOR (reembolsos y ver pagos específicos)
+	payments.Post("/refund", staff, handler.RefundPayment)              // Reembolso - Solo ADMIN
+	payments.Get("/:id", staff, handler.GetPaymentByID)                 // Ver pago por ID - Solo ADMIN
+	payments.Get("/:id/refunds", staff, handler.GetPaymentRefunds)      // Reembolsos de un pago - Solo ADMIN
+	payments.Get("/:id/events", staff, webhookHandler.GetPaymentEvents) // Eventos de webhook de un pago - Solo ADMIN
 }
@@ -0,0 +1,80 @@
+package presentation
+
+import (
+	"backend-go/features/payments/application"
+	"backend-go/features/payments/domain"
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type WebhookHandler struct {
+	service *application.WebhookService
+}
+
+func NewWebhookHandler(service *application.WebhookService) *WebhookHandler {
+	return &WebhookHandler{service: service}
+}
+
+// HandleWebhook recibe los eventos del proveedor de pagos
+// @Summary Webhook del proveedor de pagos
+// @Description Verifica la firma (Stripe-Signature / X-Mock-Signature), guarda el evento una sola vez y
+// @Description aplica la transición del pago (PENDING -> COMPLETED/FAILED, reembolsos) y de la reserva,
+// @Description inscripción o membresía enlazada. Los reenvíos de un evento ya procesado devuelven 200.
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Param provider path string true "Proveedor (stripe, mock)"
+// @Success 200 {object} WebhookAckResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/payments/webhooks/{provider} [post]
+func (h *WebhookHandler) HandleWebhook(c *fiber.Ctx) error {
+	event, duplicate, err := h.service.HandleWebhook(c.Params("provider"), c.Body(), func(name string) string {
+		return c.Get(name)
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrUnknownWebhookProvider):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, domain.ErrInvalidWebhookSignature), errors.Is(err, domain.ErrInvalidWebhookPayload):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		// 5xx: el proveedor reenvía el evento más tarde
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(WebhookAckResponse{
+		Received:  true,
+		EventID:   event.EventID,
+		Status:    event.Status,
+		Duplicate: duplicate,
+	})
+}
+
+// GetPaymentEvents obtiene los eventos de webhook de un pago
+// @Summary Listar eventos de webhook de un pago
+// @Tags payments
+// @Produce json
+// @Param id path int true "Payment ID"
+// @Success 200 {array} PaymentEventResponse
+// @Router /api/payments/{id}/events [get]
+func (h *WebhookHandler) GetPaymentEvents(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid payment ID"})
+	}
+
+	events, err := h.service.GetPaymentEvents(uint(id))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	response := make([]PaymentEventResponse, len(events))
+	for i := range events {
+		response[i] = ToPaymentEventResponse(&events[i])
+	}
+
+	return c.JSON(response)
+}
@@ -0,0 +1,37 @@
+package application
+
+import (
+	paymentDomain "backend-go/features/payments/domain"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// PaymentPromoRedeemer implementa paymentDomain.PromoCodeRedeemer
+type PaymentPromoRedeemer struct {
+	service *PromoCodeService
+}
+
+func NewPaymentPromoRedeemer(service *PromoCodeService) paymentDomain.PromoCodeRedeemer {
+	return &PaymentPromoRedeemer{service: service}
+}
+
+func (p *PaymentPromoRedeemer) Redeem(code string, userID uuid.UUID, resourceType string, amountCents int) (*paymentDomain.PromoDiscount, error) {
+	redemption, err := p.service.Redeem(code, userID, resourceType, amountCents)
+	if err != nil {
+		if IsPromoCodeRejection(err) {
+			return nil, fmt.Errorf("%w: %v", paymentDomain.ErrInvalidPromoCode, err)
+		}
+		return nil, err
+	}
+
+	return &paymentDomain.PromoDiscount{
+		RedemptionID:  redemption.ID,
+		Code:          redemption.Code,
+		DiscountCents: redemption.DiscountCents,
+	}, nil
+}
+
+func (p *PaymentPromoRedeemer) Release(redemptionID uint) error {
+	return p.service.Release(redemptionID)
+}
@@ -0,0 +1,31 @@
+package application
+
+import (
+	bookingApp "backend-go/features/bookings/application"
+	"backend-go/features/pricing/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BookingPriceCalculator implementa bookingApp.PriceCalculator
+type BookingPriceCalculator struct {
+	service *PricingService
+}
+
+func NewBookingPriceCalculator(service *PricingService) bookingApp.PriceCalculator {
+	return &BookingPriceCalculator{service: service}
+}
+
+func (p *BookingPriceCalculator) CalculatePrice(pistaID int, userID uuid.UUID, startTime, endTime time.Time) (int, error) {
+	quote, err := p.service.Quote(domain.QuoteRequest{
+		PistaID:   pistaID,
+		UserID:    &userID,
+		StartTime: startTime,
+		EndTime:   endTime,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return quote.TotalCents, nil
+}
@@ -0,0 +1,213 @@
+package application
+
+import (
+	"backend-go/features/pricing/domain"
+	"math"
+)
+
+// PricingService calcula el precio de las reservas aplicando las reglas configuradas
+type PricingService struct {
+	repo   domain.PricingRepository
+	promos *PromoCodeService
+}
+
+func NewPricingService(repo domain.PricingRepository, promos *PromoCodeService) *PricingService {
+	return &PricingService{repo: repo, promos: promos}
+}
+
+// GetAllRules obtiene todas las reglas de precio
+func (s *PricingService) GetAllRules() ([]domain.PricingRule, error) {
+	return s.repo.FindAll()
+}
+
+// GetRuleByID obtiene una regla por ID
+func (s *PricingService) GetRuleByID(id int) (*domain.PricingRule, error) {
+	return s.repo.FindByID(id)
+}
+
+// CreateRule crea una regla de precio
+func (s *PricingService) CreateRule(rule *domain.PricingRule) error {
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+	return s.repo.Create(rule)
+}
+
+// UpdateRule actualiza una regla de precio existente
+func (s *PricingService) UpdateRule(rule *domain.PricingRule) error {
+	existing, err := s.repo.FindByID(rule.ID)
+	if err != nil {
+		return err
+	}
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+
+	rule.CreatedAt = existing.CreatedAt
+	return s.repo.Update(rule)
+}
+
+// DeleteRule elimina una regla de precio
+func (s *PricingService) DeleteRule(id int) error {
+	return s.repo.Delete(id)
+}
+
+// Quote calcula el presupuesto de una reserva.
+//
+// 1. Base: precio por hora de la pista x duración.
+// 2. Recargos/ajustes sobre la base: franjas horarias (prorrateadas por minutos) y fin de semana.
+// 3. Descuentos sobre el subtotal: el mejor descuento de socio y la mejor promoción vigente.
+// 4. Código promocional (opcional) sobre el total resultante; solo se previsualiza, el uso se
+// registra al pagar.
+func (s *PricingService) Quote(req domain.QuoteRequest) (*domain.Quote, error) {
+	if !req.EndTime.After(req.StartTime) {
+		return nil, domain.ErrInvalidQuoteRange
+	}
+
+	pista, err := s.repo.FindPista(req.PistaID)
+	if err != nil {
+		return nil, err
+	}
+	if !pista.IsActive {
+		return nil, domain.ErrPistaInactive
+	}
+
+	rules, err := s.repo.FindActive()
+	if err != nil {
+		return nil, err
+	}
+
+	isMember := false
+	if req.UserID != nil {
+		if isMember, err = s.repo.IsMember(*req.UserID); err != nil {
+			return nil, err
+		}
+	}
+
+	minutes := int(req.EndTime.Sub(req.StartTime).Minutes())
+	quote := &domain.Quote{
+		PistaID:         pista.ID,
+		StartTime:       req.StartTime,
+		EndTime:         req.EndTime,
+		DurationMinutes: minutes,
+		HourlyRateCents: pista.BasePriceCents,
+		BaseCents:       prorate(pista.BasePriceCents, minutes),
+		Lines:           []domain.QuoteLine{},
+		IsMember:        isMember,
+	}
+
+	// Etapa 1: ajustes sobre la base
+	subtotal := quote.BaseCents
+	for i := range rules {
+		rule := &rules[i]
+		if rule.IsDiscountStage() || !rule.AppliesToPista(pista) {
+			continue
+		}
+
+		var amount int
+		switch rule.Type {
+		case domain.RuleTypeTimeBand:
+			overlap := rule.BandOverlapMinutes(req.StartTime, req.EndTime)
+			if overlap == 0 {
+				continue
+			}
+			amount = percentOf(prorate(pista.BasePriceCents, overlap), rule.PercentAdjustment)
+		case domain.RuleTypeWeekend:
+			if !rule.AppliesOn(req.StartTime) {
+				continue
+			}
+			amount = percentOf(quote.BaseCents, rule.PercentAdjustment)
+		}
+
+		quote.Lines = append(quote.Lines, newLine(rule, amount))
+		subtotal += amount
+	}
+
+	// Etapa 2: descuentos sobre el subtotal (no se acumulan varios del mismo tipo)
+	bestMember := bestDiscount(rules, pista, req, domain.RuleTypeMemberDiscount, isMember)
+	bestPromo := bestDiscount(rules, pista, req, domain.RuleTypePromo, true)
+
+	total := subtotal
+	for _, rule := range []*domain.PricingRule{bestMember, bestPromo} {
+		if rule == nil {
+			continue
+		}
+		share := subtotal
+		if rule.StartMinute != nil {
+			share = subtotal * rule.BandOverlapMinutes(req.StartTime, req.EndTime) / minutes
+		}
+		amount := percentOf(share, rule.PercentAdjustment)
+		quote.Lines = append(quote.Lines, newLine(rule, amount))
+		total += amount
+	}
+
+	if total < 0 {
+		total = 0
+	}
+
+	// Etapa 3: código promocional
+	if req.PromoCode != "" {
+		promo, discount, err := s.promos.Preview(req.PromoCode, req.UserID, domain.PromoResourcePista, total)
+		if err != nil {
+			return nil, err
+		}
+		quote.Lines = append(quote.Lines, domain.QuoteLine{
+			Name:        promo.Code,
+			Type:        domain.QuoteLineTypePromoCode,
+			Percent:     -promo.PercentOff,
+			AmountCents: -discount,
+		})
+		quote.PromoCode = promo.Code
+		quote.DiscountCents = discount
+		total -= discount
+	}
+	quote.TotalCents = total
+
+	return quote, nil
+}
+
+// bestDiscount elige, entre las reglas del tipo indicado que aplican, la de mayor descuento
+func bestDiscount(rules []domain.PricingRule, pista *domain.PistaPricing, req domain.QuoteRequest, ruleType string, eligible bool) *domain.PricingRule {
+	if !eligible {
+		return nil
+	}
+
+	var best *domain.PricingRule
+	for i := range rules {
+		rule := &rules[i]
+		if rule.Type != ruleType || !rule.AppliesToPista(pista) {
+			continue
+		}
+		if rule.StartMinute != nil {
+			if rule.BandOverlapMinutes(req.StartTime, req.EndTime) == 0 {
+				continue
+			}
+		} else if !rule.AppliesOn(req.StartTime) {
+			continue
+		}
+		if best == nil || rule.PercentAdjustment < best.PercentAdjustment {
+			best = rule
+		}
+	}
+	return best
+}
+
+func newLine(rule *domain.PricingRule, amount int) domain.QuoteLine {
+	return domain.QuoteLine{
+		RuleID:      rule.ID,
+		Name:        rule.Name,
+		Type:        rule.Type,
+		Percent:     rule.PercentAdjustment,
+		AmountCents: amount,
+	}
+}
+
+// prorate calcula el importe de una tarifa por hora para los minutos indicados
+func prorate(hourlyCents, minutes int) int {
+	return int(math.Round(float64(hourlyCents) * float64(minutes) / 60))
+}
+
+// percentOf calcula el porcentaje de un importe redondeando al céntimo
+func percentOf(amountCents, percent int) int {
+	return int(math.Round(float64(amountCents) * float64(percent) / 100))
+}
@@ -0,0 +1,121 @@
+package application
+
+import (
+	"backend-go/features/pricing/domain"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PromoCodeService gestiona los códigos promocionales y su canje
+type PromoCodeService struct {
+	repo domain.PromoCodeRepository
+}
+
+func NewPromoCodeService(repo domain.PromoCodeRepository) *PromoCodeService {
+	return &PromoCodeService{repo: repo}
+}
+
+// GetAll obtiene todos los códigos promocionales
+func (s *PromoCodeService) GetAll() ([]domain.PromoCode, error) {
+	return s.repo.FindAll()
+}
+
+// GetByID obtiene un código promocional por ID
+func (s *PromoCodeService) GetByID(id int) (*domain.PromoCode, error) {
+	return s.repo.FindByID(id)
+}
+
+// Create crea un código promocional
+func (s *PromoCodeService) Create(promo *domain.PromoCode) error {
+	promo.Code = domain.NormalizePromoCode(promo.Code)
+	if err := promo.Validate(); err != nil {
+		return err
+	}
+	return s.repo.Create(promo)
+}
+
+// Update actualiza un código promocional existente. Los usos ya registrados se conservan.
+func (s *PromoCodeService) Update(promo *domain.PromoCode) error {
+	existing, err := s.repo.FindByID(promo.ID)
+	if err != nil {
+		return err
+	}
+	promo.Code = domain.NormalizePromoCode(promo.Code)
+	if err := promo.Validate(); err != nil {
+		return err
+	}
+
+	promo.CreatedAt = existing.CreatedAt
+	promo.Redemptions = existing.Redemptions
+	return s.repo.Update(promo)
+}
+
+// Preview calcula, sin registrar el uso, el descuento del código sobre amountCents.
+// Sin usuario no se comprueba el límite por usuario (se comprobará al pagar).
+func (s *PromoCodeService) Preview(code string, userID *uuid.UUID, resourceType string, amountCents int) (*domain.PromoCode, int, error) {
+	promo, err := s.repo.FindByCode(domain.NormalizePromoCode(code))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	usage, err := s.repo.Usage(promo.ID, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := promo.Check(resourceType, usage, time.Now()); err != nil {
+		return nil, 0, err
+	}
+
+	discount, err := promo.DiscountFor(amountCents)
+	if err != nil {
+		return nil, 0, err
+	}
+	return promo, discount, nil
+}
+
+// Redeem canjea el código para un cobro de amountCents y registra el uso
+func (s *PromoCodeService) Redeem(code string, userID uuid.UUID, resourceType string, amountCents int) (*domain.PromoRedemption, error) {
+	return s.repo.Redeem(domain.NormalizePromoCode(code), userID, func(promo *domain.PromoCode, usage domain.PromoUsage) (*domain.PromoRedemption, error) {
+		if err := promo.Check(resourceType, usage, time.Now()); err != nil {
+			return nil, err
+		}
+		discount, err := promo.DiscountFor(amountCents)
+		if err != nil {
+			return nil, err
+		}
+
+		return &domain.PromoRedemption{
+			PromoCodeID:   promo.ID,
+			Code:          promo.Code,
+			UserID:        userID,
+			ResourceType:  resourceType,
+			AmountCents:   amountCents,
+			DiscountCents: discount,
+		}, nil
+	})
+}
+
+// Release libera el uso de un código cuyo cobro no llegó a realizarse
+func (s *PromoCodeService) Release(redemptionID uint) error {
+	return s.repo.Release(redemptionID)
+}
+
+// IsPromoCodeRejection indica si el error es un motivo de rechazo del código (no encontrado,
+// no vigente, no aplicable, límites agotados...) y no un fallo interno
+func IsPromoCodeRejection(err error) bool {
+	for _, rejection := range []error{
+		domain.ErrPromoCodeNotFound,
+		domain.ErrPromoCodeInactive,
+		domain.ErrPromoCodeNotApplicable,
+		domain.ErrPromoCodeExhausted,
+		domain.ErrPromoCodeUserLimit,
+		domain.ErrPromoCodeCoversTotal,
+	} {
+		if errors.Is(err, rejection) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,17 @@
+package domain
+
+import "github.com/google/uuid"
+
+// PricingRepository define las operaciones de persistencia de reglas de precio
+type PricingRepository interface {
+	FindAll() ([]PricingRule, error)
+	FindActive() ([]PricingRule, error)
+	FindByID(id int) (*PricingRule, error)
+	Create(rule *PricingRule) error
+	Update(rule *PricingRule) error
+	Delete(id int) error
+
+	// Datos de apoyo para el cálculo
+	FindPista(pistaID int) (*PistaPricing, error)
+	IsMember(userID uuid.UUID) (bool, error) // User.IsMember o membresía de club activa
+}
@@ -0,0 +1,205 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Errores de dominio
+var (
+	ErrRuleNotFound       = errors.New("regla de precio no encontrada")
+	ErrInvalidRuleType    = errors.New("tipo de regla inválido (usar TIME_BAND, WEEKEND, MEMBER_DISCOUNT o PROMO)")
+	ErrInvalidPercent     = errors.New("el ajuste debe estar entre -100% y +500%")
+	ErrInvalidBand        = errors.New("la franja horaria requiere inicio y fin (HH:MM) con fin posterior al inicio")
+	ErrInvalidWeekday     = errors.New("día de la semana inválido (0 = domingo ... 6 = sábado)")
+	ErrInvalidPromoWindow = errors.New("la promoción requiere fecha de inicio y fin con fin posterior al inicio")
+	ErrPistaNotFound      = errors.New("pista no encontrada")
+	ErrPistaInactive      = errors.New("la pista no está activa")
+	ErrInvalidQuoteRange  = errors.New("la hora de fin debe ser posterior a la hora de inicio")
+)
+
+// Tipos de regla
+const (
+	RuleTypeTimeBand       = "TIME_BAND"       // Franja horaria punta/valle (se prorratea por minutos)
+	RuleTypeWeekend        = "WEEKEND"         // Recargo de sábado y domingo
+	RuleTypeMemberDiscount = "MEMBER_DISCOUNT" // Descuento para socios
+	RuleTypePromo          = "PROMO"           // Promoción con ventana de fechas
+)
+
+// PricingRule representa una regla de precio en el dominio
+type PricingRule struct {
+	ID                int
+	Name              string
+	Type              string
+	PistaID           *int    // nil = todas las pistas
+	PistaType         *string // nil = todos los tipos
+	Weekdays          []int   // Vacío = todos los días
+	StartMinute       *int    // Franja horaria (minutos desde medianoche, UTC)
+	EndMinute         *int
+	ValidFrom         *time.Time
+	ValidUntil        *time.Time
+	PercentAdjustment int // +20 = recargo del 20%, -10 = descuento del 10%
+	IsActive          bool
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+// PistaPricing contiene los datos de la pista necesarios para calcular el precio
+type PistaPricing struct {
+	ID             int
+	Type           string
+	BasePriceCents int // Precio por hora
+	IsActive       bool
+}
+
+// QuoteRequest son los datos de entrada de un presupuesto
+type QuoteRequest struct {
+	PistaID   int
+	UserID    *uuid.UUID // Opcional: necesario para aplicar descuentos de socio
+	StartTime time.Time
+	EndTime   time.Time
+	PromoCode string // Opcional: código promocional a aplicar sobre el total
+}
+
+// QuoteLine es una línea del desglose del presupuesto
+type QuoteLine struct {
+	RuleID      int
+	Name        string
+	Type        string
+	Percent     int
+	AmountCents int // Positivo = recargo, negativo = descuento
+}
+
+// Quote es el presupuesto calculado de una reserva
+type Quote struct {
+	PistaID         int
+	StartTime       time.Time
+	EndTime         time.Time
+	DurationMinutes int
+	HourlyRateCents int
+	BaseCents       int // Precio por hora x duración
+	Lines           []QuoteLine
+	TotalCents      int
+	IsMember        bool
+	PromoCode       string // Código promocional aplicado (vacío si no hay)
+	DiscountCents   int    // Descuento del código promocional (incluido en TotalCents)
+}
+
+// Validate verifica la coherencia de la regla según su tipo
+func (r *PricingRule) Validate() error {
+	switch r.Type {
+	case RuleTypeTimeBand:
+		if r.StartMinute == nil || r.EndMinute == nil {
+			return ErrInvalidBand
+		}
+	case RuleTypePromo:
+		if r.ValidFrom == nil || r.ValidUntil == nil {
+			return ErrInvalidPromoWindow
+		}
+	case RuleTypeWeekend, RuleTypeMemberDiscount:
+	default:
+		return ErrInvalidRuleType
+	}
+
+	if r.PercentAdjustment < -100 || r.PercentAdjustment > 500 {
+		return ErrInvalidPercent
+	}
+	if (r.StartMinute == nil) != (r.EndMinute == nil) {
+		return ErrInvalidBand
+	}
+	if r.StartMinute != nil && (*r.StartMinute < 0 || *r.EndMinute > 24*60 || *r.EndMinute <= *r.StartMinute) {
+		return ErrInvalidBand
+	}
+	if r.ValidFrom != nil && r.ValidUntil != nil && !r.ValidUntil.After(*r.ValidFrom) {
+		return ErrInvalidPromoWindow
+	}
+	for _, weekday := range r.Weekdays {
+		if weekday < 0 || weekday > 6 {
+			return ErrInvalidWeekday
+		}
+	}
+	return nil
+}
+
+// AppliesToPista indica si la regla aplica a la pista
+func (r *PricingRule) AppliesToPista(pista *PistaPricing) bool {
+	if r.PistaID != nil && *r.PistaID != pista.ID {
+		return false
+	}
+	if r.PistaType != nil && *r.PistaType != pista.Type {
+		return false
+	}
+	return true
+}
+
+// AppliesOn indica si la regla está vigente en el instante dado (ventana de fechas y día de la semana)
+func (r *PricingRule) AppliesOn(instant time.Time) bool {
+	if r.ValidFrom != nil && instant.Before(*r.ValidFrom) {
+		return false
+	}
+	if r.ValidUntil != nil && !instant.Before(*r.ValidUntil) {
+		return false
+	}
+	if r.Type == RuleTypeWeekend {
+		weekday := instant.Weekday()
+		if weekday != time.Saturday && weekday != time.Sunday {
+			return false
+		}
+	}
+	if len(r.Weekdays) > 0 {
+		matches := false
+		for _, weekday := range r.Weekdays {
+			if int(instant.Weekday()) == weekday {
+				matches = true
+				break
+			}
+		}
+		if !matches {
+			return false
+		}
+	}
+	return true
+}
+
+// BandOverlapMinutes devuelve los minutos de la reserva que caen dentro de la franja horaria
+// de la regla (todos si la regla no tiene franja). Considera reservas que cruzan la medianoche.
+func (r *PricingRule) BandOverlapMinutes(start, end time.Time) int {
+	if r.StartMinute == nil {
+		return int(end.Sub(start).Minutes())
+	}
+
+	total := 0
+	day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+	for ; day.Before(end); day = day.AddDate(0, 0, 1) {
+		bandStart := day.Add(time.Duration(*r.StartMinute) * time.Minute)
+		bandEnd := day.Add(time.Duration(*r.EndMinute) * time.Minute)
+
+		overlapStart := maxTime(start, bandStart)
+		overlapEnd := minTime(end, bandEnd)
+		if overlapEnd.After(overlapStart) && r.AppliesOn(overlapStart) {
+			total += int(overlapEnd.Sub(overlapStart).Minutes())
+		}
+	}
+	return total
+}
+
+// IsDiscountStage indica si la regla se aplica sobre el subtotal (tras recargos)
+func (r *PricingRule) IsDiscountStage() bool {
+	return r.Type == RuleTypeMemberDiscount || r.Type == RuleTypePromo
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,187 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Errores de los códigos promocionales
+var (
+	ErrPromoCodeNotFound       = errors.New("código promocional no encontrado")
+	ErrPromoCodeExists         = errors.New("ya existe un código promocional con ese código")
+	ErrPromoCodeInactive       = errors.New("el código promocional no está vigente")
+	ErrPromoCodeNotApplicable  = errors.New("el código promocional no es aplicable a este concepto")
+	ErrPromoCodeExhausted      = errors.New("el código promocional ha alcanzado su límite de usos")
+	ErrPromoCodeUserLimit      = errors.New("ya has usado este código promocional el máximo de veces permitido")
+	ErrPromoCodeCoversTotal    = errors.New("el descuento no puede cubrir el importe completo")
+	ErrInvalidPromoCode        = errors.New("el código debe tener entre 3 y 50 caracteres (letras, números, guion o guion bajo)")
+	ErrInvalidPromoDiscount    = errors.New("el descuento debe ser PERCENT (1-99%) o FIXED (importe mayor que 0)")
+	ErrInvalidPromoResource    = errors.New("tipo de concepto inválido (usar PISTA, CLASS o CLUB)")
+	ErrInvalidPromoLimit       = errors.New("los límites de usos deben ser mayores que 0")
+	ErrInvalidPromoCodeWindow  = errors.New("la fecha de fin debe ser posterior a la de inicio")
+	ErrPromoRedemptionNotFound = errors.New("uso de código promocional no encontrado")
+)
+
+// Tipos de descuento
+const (
+	DiscountTypePercent = "PERCENT" // Porcentaje sobre el importe
+	DiscountTypeFixed   = "FIXED"   // Importe fijo en céntimos
+)
+
+// Conceptos a los que se puede aplicar un código
+const (
+	PromoResourcePista = "PISTA" // Reservas de pista
+	PromoResourceClass = "CLASS" // Inscripciones a clases
+	PromoResourceClub  = "CLUB"  // Cuotas de club
+)
+
+// QuoteLineTypePromoCode identifica en el presupuesto la línea del código promocional
+const QuoteLineTypePromoCode = "PROMO_CODE"
+
+// PromoCode representa un código promocional en el dominio
+type PromoCode struct {
+	ID                    int
+	Code                  string
+	Description           *string
+	DiscountType          string
+	PercentOff            int
+	AmountOffCents        int
+	ResourceTypes         []string // PISTA, CLASS y/o CLUB
+	ValidFrom             *time.Time
+	ValidUntil            *time.Time
+	MaxRedemptions        *int
+	MaxRedemptionsPerUser *int
+	IsActive              bool
+	Redemptions           int64 // Usos vigentes (solo lectura)
+	CreatedAt             time.Time
+	UpdatedAt             time.Time
+}
+
+// PromoUsage son los usos vigentes de un código, en total y del usuario que lo canjea
+type PromoUsage struct {
+	Total   int64
+	ForUser int64
+}
+
+// PromoRedemption es el uso de un código en un cobro
+type PromoRedemption struct {
+	ID            uint
+	PromoCodeID   int
+	Code          string
+	UserID        uuid.UUID
+	ResourceType  string
+	AmountCents   int // Importe antes del descuento
+	DiscountCents int
+	ReleasedAt    *time.Time
+	CreatedAt     time.Time
+}
+
+// PromoRedeemFunc valida el código bloqueado con sus usos vigentes y devuelve el uso a registrar
+type PromoRedeemFunc func(promo *PromoCode, usage PromoUsage) (*PromoRedemption, error)
+
+// NormalizePromoCode normaliza el código tal como se guarda y se busca (mayúsculas, sin espacios)
+func NormalizePromoCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Validate verifica la coherencia del código
+func (p *PromoCode) Validate() error {
+	if len(p.Code) < 3 || len(p.Code) > 50 {
+		return ErrInvalidPromoCode
+	}
+	for _, r := range p.Code {
+		if !(r >= 'A' && r <= 'Z') && !(r >= '0' && r <= '9') && r != '-' && r != '_' {
+			return ErrInvalidPromoCode
+		}
+	}
+
+	switch p.DiscountType {
+	case DiscountTypePercent:
+		if p.PercentOff < 1 || p.PercentOff > 99 || p.AmountOffCents != 0 {
+			return ErrInvalidPromoDiscount
+		}
+	case DiscountTypeFixed:
+		if p.AmountOffCents <= 0 || p.PercentOff != 0 {
+			return ErrInvalidPromoDiscount
+		}
+	default:
+		return ErrInvalidPromoDiscount
+	}
+
+	if len(p.ResourceTypes) == 0 {
+		return ErrInvalidPromoResource
+	}
+	for _, resourceType := range p.ResourceTypes {
+		switch resourceType {
+		case PromoResourcePista, PromoResourceClass, PromoResourceClub:
+		default:
+			return ErrInvalidPromoResource
+		}
+	}
+
+	if (p.MaxRedemptions != nil && *p.MaxRedemptions <= 0) || (p.MaxRedemptionsPerUser != nil && *p.MaxRedemptionsPerUser <= 0) {
+		return ErrInvalidPromoLimit
+	}
+	if p.ValidFrom != nil && p.ValidUntil != nil && !p.ValidUntil.After(*p.ValidFrom) {
+		return ErrInvalidPromoCodeWindow
+	}
+	return nil
+}
+
+// AppliesTo indica si el código se puede usar con el tipo de concepto
+func (p *PromoCode) AppliesTo(resourceType string) bool {
+	for _, candidate := range p.ResourceTypes {
+		if candidate == resourceType {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidAt indica si el código está activo y dentro de su ventana de validez
+func (p *PromoCode) IsValidAt(instant time.Time) bool {
+	if !p.IsActive {
+		return false
+	}
+	if p.ValidFrom != nil && instant.Before(*p.ValidFrom) {
+		return false
+	}
+	if p.ValidUntil != nil && !instant.Before(*p.ValidUntil) {
+		return false
+	}
+	return true
+}
+
+// Check verifica que el código se puede canjear ahora para el concepto con los usos actuales
+// (usage.ForUser solo cuenta si hay usuario)
+func (p *PromoCode) Check(resourceType string, usage PromoUsage, now time.Time) error {
+	if !p.IsValidAt(now) {
+		return ErrPromoCodeInactive
+	}
+	if !p.AppliesTo(resourceType) {
+		return ErrPromoCodeNotApplicable
+	}
+	if p.MaxRedemptions != nil && usage.Total >= int64(*p.MaxRedemptions) {
+		return ErrPromoCodeExhausted
+	}
+	if p.MaxRedemptionsPerUser != nil && usage.ForUser >= int64(*p.MaxRedemptionsPerUser) {
+		return ErrPromoCodeUserLimit
+	}
+	return nil
+}
+
+// DiscountFor calcula el descuento sobre un importe (redondeo al céntimo). El cobro resultante
+// tiene que ser mayor que 0, por lo que un descuento que cubre el importe completo es un error.
+func (p *PromoCode) DiscountFor(amountCents int) (int, error) {
+	discount := p.AmountOffCents
+	if p.DiscountType == DiscountTypePercent {
+		discount = (amountCents*p.PercentOff + 50) / 100
+	}
+	if discount >= amountCents {
+		return 0, ErrPromoCodeCoversTotal
+	}
+	return discount, nil
+}
@@ -0,0 +1,21 @@
+package domain
+
+import "github.com/google/uuid"
+
+// PromoCodeRepository define las operaciones de persistencia de códigos promocionales
+type PromoCodeRepository interface {
+	FindAll() ([]PromoCode, error)
+	FindByID(id int) (*PromoCode, error)
+	FindByCode(code string) (*PromoCode, error)
+	Create(promo *PromoCode) error
+	Update(promo *PromoCode) error
+
+	// Usage cuenta los usos vigentes del código (no liberados), en total y de userID (si no es nil)
+	Usage(promoCodeID int, userID *uuid.UUID) (PromoUsage, error)
+	// Redeem bloquea el código (SELECT ... FOR UPDATE), cuenta sus usos vigentes, ejecuta redeem
+	// y registra el uso en la misma transacción, de modo que dos canjes simultáneos no puedan
+	// superar los límites
+	Redeem(code string, userID uuid.UUID, redeem PromoRedeemFunc) (*PromoRedemption, error)
+	// Release libera un uso (el cobro no llegó a realizarse); es idempotente
+	Release(redemptionID uint) error
+}
@@ -0,0 +1,74 @@
+package infrastructure
+
+import (
+	"backend-go/features/pricing/domain"
+	"backend-go/shared/database"
+	"strconv"
+	"strings"
+)
+
+// ToEntity convierte database.PricingRule a domain.PricingRule
+func ToEntity(m *database.PricingRule) *domain.PricingRule {
+	rule := &domain.PricingRule{
+		ID:                int(m.ID),
+		Name:              m.Name,
+		Type:              m.Type,
+		PistaType:         m.PistaType,
+		Weekdays:          parseWeekdays(m.Weekdays),
+		StartMinute:       m.StartMinute,
+		EndMinute:         m.EndMinute,
+		ValidFrom:         m.ValidFrom,
+		ValidUntil:        m.ValidUntil,
+		PercentAdjustment: m.PercentAdjustment,
+		IsActive:          m.IsActive,
+		CreatedAt:         m.CreatedAt,
+		UpdatedAt:         m.UpdatedAt,
+	}
+	if m.PistaID != nil {
+		pistaID := int(*m.PistaID)
+		rule.PistaID = &pistaID
+	}
+	return rule
+}
+
+// FromEntity convierte domain.PricingRule a database.PricingRule
+func FromEntity(rule *domain.PricingRule) *database.PricingRule {
+	model := &database.PricingRule{
+		ID:                uint(rule.ID),
+		Name:              rule.Name,
+		Type:              rule.Type,
+		PistaType:         rule.PistaType,
+		Weekdays:          formatWeekdays(rule.Weekdays),
+		StartMinute:       rule.StartMinute,
+		EndMinute:         rule.EndMinute,
+		ValidFrom:         rule.ValidFrom,
+		ValidUntil:        rule.ValidUntil,
+		PercentAdjustment: rule.PercentAdjustment,
+		IsActive:          rule.IsActive,
+	}
+	if rule.PistaID != nil {
+		pistaID := uint(*rule.PistaID)
+		model.PistaID = &pistaID
+	}
+	return model
+}
+
+// parseWeekdays convierte "1,2,3" a []int (se ignoran valores no numéricos)
+func parseWeekdays(value string) []int {
+	weekdays := []int{}
+	for _, raw := range strings.Split(value, ",") {
+		if weekday, err := strconv.Atoi(strings.TrimSpace(raw)); err == nil {
+			weekdays = append(weekdays, weekday)
+		}
+	}
+	return weekdays
+}
+
+// formatWeekdays convierte []int a "1,2,3"
+func formatWeekdays(weekdays []int) string {
+	parts := make([]string, len(weekdays))
+	for i, weekday := range weekdays {
+		parts[i] = strconv.Itoa(weekday)
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,139 @@
+package infrastructure
+
+import (
+	"backend-go/features/pricing/domain"
+	"backend-go/shared/database"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PricingRepositoryImpl implementa domain.PricingRepository usando GORM
+type PricingRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewPricingRepository crea una nueva instancia del repositorio
+func NewPricingRepository(db *gorm.DB) domain.PricingRepository {
+	return &PricingRepositoryImpl{db: db}
+}
+
+// FindAll obtiene todas las reglas de precio
+func (r *PricingRepositoryImpl) FindAll() ([]domain.PricingRule, error) {
+	var models []database.PricingRule
+	if err := r.db.Order("type ASC, id ASC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return toEntities(models), nil
+}
+
+// FindActive obtiene las reglas de precio activas
+func (r *PricingRepositoryImpl) FindActive() ([]domain.PricingRule, error) {
+	var models []database.PricingRule
+	if err := r.db.Where("is_active = ?", true).Order("id ASC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return toEntities(models), nil
+}
+
+// FindByID obtiene una regla por ID
+func (r *PricingRepositoryImpl) FindByID(id int) (*domain.PricingRule, error) {
+	var model database.PricingRule
+	if err := r.db.First(&model, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrRuleNotFound
+		}
+		return nil, err
+	}
+	return ToEntity(&model), nil
+}
+
+// Create crea una regla de precio
+func (r *PricingRepositoryImpl) Create(rule *domain.PricingRule) error {
+	model := FromEntity(rule)
+	if err := r.db.Create(model).Error; err != nil {
+		return err
+	}
+
+	rule.ID = int(model.ID)
+	rule.CreatedAt = model.CreatedAt
+	rule.UpdatedAt = model.UpdatedAt
+	return nil
+}
+
+// Update actualiza una regla de precio
+func (r *PricingRepositoryImpl) Update(rule *domain.PricingRule) error {
+	model := FromEntity(rule)
+	if err := r.db.Omit("created_at").Save(model).Error; err != nil {
+		return err
+	}
+
+	rule.UpdatedAt = model.UpdatedAt
+	return nil
+}
+
+// Delete elimina una regla de precio
+func (r *PricingRepositoryImpl) Delete(id int) error {
+	result := r.db.Delete(&database.PricingRule{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrRuleNotFound
+	}
+	return nil
+}
+
+// FindPista obtiene los datos de precio de una pista
+func (r *PricingRepositoryImpl) FindPista(pistaID int) (*domain.PistaPricing, error) {
+	var pista database.Pista
+	if err := r.db.First(&pista, pistaID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrPistaNotFound
+		}
+		return nil, err
+	}
+
+	return &domain.PistaPricing{
+		ID:             int(pista.ID),
+		Type:           pista.Type,
+		BasePriceCents: pista.BasePriceCents,
+		IsActive:       pista.IsActive,
+	}, nil
+}
+
+// IsMember indica si el usuario es socio del polideportivo o tiene una membresía de club activa
+func (r *PricingRepositoryImpl) IsMember(userID uuid.UUID) (bool, error) {
+	var user database.User
+	if err := r.db.Select("id", "is_member").First(&user, "id = ?", userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	if user.IsMember {
+		return true, nil
+	}
+
+	var count int64
+	if err := r.db.Model(&database.ClubMembership{}).
+		Where("user_id = ?", userID).
+		Where("status = ?", "ACTIVE").
+		Where("is_active = ?", true).
+		Where("end_date IS NULL OR end_date > ?", time.Now()).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func toEntities(models []database.PricingRule) []domain.PricingRule {
+	rules := make([]domain.PricingRule, len(models))
+	for i := range models {
+		rules[i] = *ToEntity(&models[i])
+	}
+	return rules
+}
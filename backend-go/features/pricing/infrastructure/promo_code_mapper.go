@@ -0,0 +1,69 @@
+package infrastructure
+
+import (
+	"backend-go/features/pricing/domain"
+	"backend-go/shared/database"
+	"strings"
+)
+
+// PromoCodeToEntity convierte database.PromoCode a domain.PromoCode
+func PromoCodeToEntity(m *database.PromoCode) *domain.PromoCode {
+	return &domain.PromoCode{
+		ID:                    int(m.ID),
+		Code:                  m.Code,
+		Description:           m.Description,
+		DiscountType:          m.DiscountType,
+		PercentOff:            m.PercentOff,
+		AmountOffCents:        m.AmountOffCents,
+		ResourceTypes:         parseResourceTypes(m.ResourceTypes),
+		ValidFrom:             m.ValidFrom,
+		ValidUntil:            m.ValidUntil,
+		MaxRedemptions:        m.MaxRedemptions,
+		MaxRedemptionsPerUser: m.MaxRedemptionsPerUser,
+		IsActive:              m.IsActive,
+		CreatedAt:             m.CreatedAt,
+		UpdatedAt:             m.UpdatedAt,
+	}
+}
+
+// PromoCodeFromEntity convierte domain.PromoCode a database.PromoCode
+func PromoCodeFromEntity(promo *domain.PromoCode) *database.PromoCode {
+	return &database.PromoCode{
+		ID:                    uint(promo.ID),
+		Code:                  promo.Code,
+		Description:           promo.Description,
+		DiscountType:          promo.DiscountType,
+		PercentOff:            promo.PercentOff,
+		AmountOffCents:        promo.AmountOffCents,
+		ResourceTypes:         strings.Join(promo.ResourceTypes, ","),
+		ValidFrom:             promo.ValidFrom,
+		ValidUntil:            promo.ValidUntil,
+		MaxRedemptions:        promo.MaxRedemptions,
+		MaxRedemptionsPerUser: promo.MaxRedemptionsPerUser,
+		IsActive:              promo.IsActive,
+	}
+}
+
+// PromoRedemptionFromEntity convierte domain.PromoRedemption a database.PromoRedemption
+func PromoRedemptionFromEntity(redemption *domain.PromoRedemption) *database.PromoRedemption {
+	return &database.PromoRedemption{
+		ID:            redemption.ID,
+		PromoCodeID:   uint(redemption.PromoCodeID),
+		UserID:        redemption.UserID,
+		ResourceType:  redemption.ResourceType,
+		AmountCents:   redemption.AmountCents,
+		DiscountCents: redemption.DiscountCents,
+		ReleasedAt:    redemption.ReleasedAt,
+	}
+}
+
+// parseResourceTypes convierte "PISTA,CLASS" a []string
+func parseResourceTypes(value string) []string {
+	resourceTypes := []string{}
+	for _, raw := range strings.Split(value, ",") {
+		if resourceType := strings.TrimSpace(raw); resourceType != "" {
+			resourceTypes = append(resourceTypes, resourceType)
+		}
+	}
+	return resourceTypes
+}
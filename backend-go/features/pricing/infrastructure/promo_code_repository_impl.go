@@ -0,0 +1,210 @@
+package infrastructure
+
+import (
+	"backend-go/features/pricing/domain"
+	"backend-go/shared/database"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PromoCodeRepositoryImpl implementa domain.PromoCodeRepository usando GORM
+type PromoCodeRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewPromoCodeRepository crea una nueva instancia del repositorio
+func NewPromoCodeRepository(db *gorm.DB) domain.PromoCodeRepository {
+	return &PromoCodeRepositoryImpl{db: db}
+}
+
+// FindAll obtiene todos los códigos promocionales con sus usos vigentes
+func (r *PromoCodeRepositoryImpl) FindAll() ([]domain.PromoCode, error) {
+	var models []database.PromoCode
+	if err := r.db.Order("created_at DESC, id DESC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	var counts []struct {
+		PromoCodeID uint
+		Total       int64
+	}
+	if err := r.db.Model(&database.PromoRedemption{}).
+		Select("promo_code_id, COUNT(*) AS total").
+		Where("released_at IS NULL").
+		Group("promo_code_id").
+		Scan(&counts).Error; err != nil {
+		return nil, err
+	}
+	usage := make(map[uint]int64, len(counts))
+	for _, count := range counts {
+		usage[count.PromoCodeID] = count.Total
+	}
+
+	promos := make([]domain.PromoCode, len(models))
+	for i := range models {
+		promos[i] = *PromoCodeToEntity(&models[i])
+		promos[i].Redemptions = usage[models[i].ID]
+	}
+	return promos, nil
+}
+
+// FindByID obtiene un código por ID
+func (r *PromoCodeRepositoryImpl) FindByID(id int) (*domain.PromoCode, error) {
+	var model database.PromoCode
+	if err := r.db.First(&model, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrPromoCodeNotFound
+		}
+		return nil, err
+	}
+	return r.withUsage(&model)
+}
+
+// FindByCode obtiene un código por su texto (normalizado)
+func (r *PromoCodeRepositoryImpl) FindByCode(code string) (*domain.PromoCode, error) {
+	var model database.PromoCode
+	if err := r.db.Where("code = ?", code).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrPromoCodeNotFound
+		}
+		return nil, err
+	}
+	return r.withUsage(&model)
+}
+
+// Create crea un código promocional
+func (r *PromoCodeRepositoryImpl) Create(promo *domain.PromoCode) error {
+	model := PromoCodeFromEntity(promo)
+	if err := r.db.Create(model).Error; err != nil {
+		if isDuplicatePromoCode(err) {
+			return domain.ErrPromoCodeExists
+		}
+		return err
+	}
+
+	promo.ID = int(model.ID)
+	promo.CreatedAt = model.CreatedAt
+	promo.UpdatedAt = model.UpdatedAt
+	return nil
+}
+
+// Update actualiza un código promocional
+func (r *PromoCodeRepositoryImpl) Update(promo *domain.PromoCode) error {
+	model := PromoCodeFromEntity(promo)
+	if err := r.db.Omit("created_at").Save(model).Error; err != nil {
+		if isDuplicatePromoCode(err) {
+			return domain.ErrPromoCodeExists
+		}
+		return err
+	}
+
+	promo.UpdatedAt = model.UpdatedAt
+	return nil
+}
+
+// Usage cuenta los usos vigentes del código, en total y de userID
+func (r *PromoCodeRepositoryImpl) Usage(promoCodeID int, userID *uuid.UUID) (domain.PromoUsage, error) {
+	return countUsage(r.db, promoCodeID, userID)
+}
+
+// Redeem canjea el código con la fila bloqueada y registra el uso
+func (r *PromoCodeRepositoryImpl) Redeem(code string, userID uuid.UUID, redeem domain.PromoRedeemFunc) (*domain.PromoRedemption, error) {
+	var redemption *domain.PromoRedemption
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var model database.PromoCode
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("code = ?", code).
+			First(&model).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domain.ErrPromoCodeNotFound
+			}
+			return err
+		}
+
+		usage, err := countUsage(tx, int(model.ID), &userID)
+		if err != nil {
+			return err
+		}
+
+		promo := PromoCodeToEntity(&model)
+		promo.Redemptions = usage.Total
+		if redemption, err = redeem(promo, usage); err != nil {
+			return err
+		}
+
+		dbRedemption := PromoRedemptionFromEntity(redemption)
+		if err := tx.Create(dbRedemption).Error; err != nil {
+			return err
+		}
+		redemption.ID = dbRedemption.ID
+		redemption.CreatedAt = dbRedemption.CreatedAt
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return redemption, nil
+}
+
+// Release libera un uso; si ya estaba liberado no hace nada
+func (r *PromoCodeRepositoryImpl) Release(redemptionID uint) error {
+	result := r.db.Model(&database.PromoRedemption{}).
+		Where("id = ? AND released_at IS NULL", redemptionID).
+		Update("released_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		var count int64
+		if err := r.db.Model(&database.PromoRedemption{}).Where("id = ?", redemptionID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return domain.ErrPromoRedemptionNotFound
+		}
+	}
+	return nil
+}
+
+// withUsage convierte el modelo añadiendo sus usos vigentes
+func (r *PromoCodeRepositoryImpl) withUsage(model *database.PromoCode) (*domain.PromoCode, error) {
+	usage, err := countUsage(r.db, int(model.ID), nil)
+	if err != nil {
+		return nil, err
+	}
+	promo := PromoCodeToEntity(model)
+	promo.Redemptions = usage.Total
+	return promo, nil
+}
+
+// countUsage cuenta los usos no liberados de un código, en total y de userID (si no es nil)
+func countUsage(db *gorm.DB, promoCodeID int, userID *uuid.UUID) (domain.PromoUsage, error) {
+	var usage domain.PromoUsage
+	if err := db.Model(&database.PromoRedemption{}).
+		Where("promo_code_id = ? AND released_at IS NULL", promoCodeID).
+		Count(&usage.Total).Error; err != nil {
+		return usage, err
+	}
+	if userID == nil {
+		return usage, nil
+	}
+	if err := db.Model(&database.PromoRedemption{}).
+		Where("promo_code_id = ? AND user_id = ? AND released_at IS NULL", promoCodeID, *userID).
+		Count(&usage.ForUser).Error; err != nil {
+		return usage, err
+	}
+	return usage, nil
+}
+
+// isDuplicatePromoCode detecta la violación del índice único del código
+func isDuplicatePromoCode(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "idx_promo_codes_code") || strings.Contains(msg, "23505")
+}
@@ -0,0 +1,206 @@
+package presentation
+
+import (
+	"backend-go/features/pricing/application"
+	"backend-go/features/pricing/domain"
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type PricingHandler struct {
+	service *application.PricingService
+}
+
+func NewPricingHandler(service *application.PricingService) *PricingHandler {
+	return &PricingHandler{service: service}
+}
+
+// Quote maneja POST /api/pricing/quote
+// @Summary Presupuesto de una reserva con desglose de reglas aplicadas
+// @Description Si el usuario está autenticado se aplican sus descuentos de socio. ADMIN/GESTOR pueden indicar userId.
+// @Description Con promoCode se añade la línea PROMO_CODE con el descuento del código (el uso se registra al pagar).
+// @Tags pricing
+// @Accept json
+// @Produce json
+// @Param quote body QuoteRequest true "Pista y horario"
+// @Success 200 {object} QuoteResponse
+// @Router /api/pricing/quote [post]
+func (h *PricingHandler) Quote(c *fiber.Ctx) error {
+	var req QuoteRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Datos inválidos"})
+	}
+
+	userID, err := resolveQuoteUser(c, req.UserID)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	quote, err := h.service.Quote(domain.QuoteRequest{
+		PistaID:   req.PistaID,
+		UserID:    userID,
+		StartTime: req.StartTime.UTC(),
+		EndTime:   req.EndTime.UTC(),
+		PromoCode: req.PromoCode,
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrPistaNotFound) {
+			return c.Status(404).JSON(fiber.Map{"error": err.Error()})
+		}
+		if errors.Is(err, domain.ErrPistaInactive) || errors.Is(err, domain.ErrInvalidQuoteRange) || application.IsPromoCodeRejection(err) {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(ToQuoteResponse(quote))
+}
+
+// GetAllRules maneja GET /api/pricing/rules
+// @Summary Listar reglas de precio
+// @Tags pricing
+// @Produce json
+// @Success 200 {array} PricingRuleResponse
+// @Router /api/pricing/rules [get]
+func (h *PricingHandler) GetAllRules(c *fiber.Ctx) error {
+	rules, err := h.service.GetAllRules()
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	responses := make([]PricingRuleResponse, len(rules))
+	for i := range rules {
+		responses[i] = ToRuleResponse(&rules[i])
+	}
+
+	return c.JSON(responses)
+}
+
+// GetRuleByID maneja GET /api/pricing/rules/:id
+// @Summary Obtener regla de precio
+// @Tags pricing
+// @Produce json
+// @Param id path int true "ID de la regla"
+// @Success 200 {object} PricingRuleResponse
+// @Router /api/pricing/rules/{id} [get]
+func (h *PricingHandler) GetRuleByID(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "ID inválido"})
+	}
+
+	rule, err := h.service.GetRuleByID(id)
+	if err != nil {
+		if errors.Is(err, domain.ErrRuleNotFound) {
+			return c.Status(404).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(ToRuleResponse(rule))
+}
+
+// CreateRule maneja POST /api/pricing/rules
+// @Summary Crear regla de precio (franja horaria, fin de semana, socio o promoción)
+// @Tags pricing
+// @Accept json
+// @Produce json
+// @Param rule body PricingRuleRequest true "Regla de precio"
+// @Success 201 {object} PricingRuleResponse
+// @Router /api/pricing/rules [post]
+func (h *PricingHandler) CreateRule(c *fiber.Ctx) error {
+	var req PricingRuleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Datos inválidos"})
+	}
+
+	rule, err := RuleRequestToDomain(&req)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	if err := h.service.CreateRule(rule); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.Status(201).JSON(ToRuleResponse(rule))
+}
+
+// UpdateRule maneja PUT /api/pricing/rules/:id
+// @Summary Actualizar regla de precio
+// @Tags pricing
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de la regla"
+// @Param rule body PricingRuleRequest true "Regla de precio"
+// @Success 200 {object} PricingRuleResponse
+// @Router /api/pricing/rules/{id} [put]
+func (h *PricingHandler) UpdateRule(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "ID inválido"})
+	}
+
+	var req PricingRuleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Datos inválidos"})
+	}
+
+	rule, err := RuleRequestToDomain(&req)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	rule.ID = id
+
+	if err := h.service.UpdateRule(rule); err != nil {
+		if errors.Is(err, domain.ErrRuleNotFound) {
+			return c.Status(404).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(ToRuleResponse(rule))
+}
+
+// DeleteRule maneja DELETE /api/pricing/rules/:id
+// @Summary Eliminar regla de precio
+// @Tags pricing
+// @Param id path int true "ID de la regla"
+// @Success 204
+// @Router /api/pricing/rules/{id} [delete]
+func (h *PricingHandler) DeleteRule(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "ID inválido"})
+	}
+
+	if err := h.service.DeleteRule(id); err != nil {
+		if errors.Is(err, domain.ErrRuleNotFound) {
+			return c.Status(404).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.SendStatus(204)
+}
+
+// resolveQuoteUser decide para qué usuario se calcula el presupuesto:
+// ADMIN/GESTOR pueden indicar userId; el resto usa el usuario autenticado (si lo hay).
+func resolveQuoteUser(c *fiber.Ctx, requested string) (*uuid.UUID, error) {
+	roleName, _ := c.Locals("roleName").(string)
+	if requested != "" && (roleName == "ADMIN" || roleName == "GESTOR") {
+		userID, err := uuid.Parse(requested)
+		if err != nil {
+			return nil, errors.New("ID de usuario inválido")
+		}
+		return &userID, nil
+	}
+
+	if userID, ok := c.Locals("userID").(uuid.UUID); ok {
+		return &userID, nil
+	}
+	return nil, nil
+}
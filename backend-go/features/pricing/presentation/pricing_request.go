@@ -0,0 +1,75 @@
+package presentation
+
+import (
+	"backend-go/features/pricing/domain"
+	"time"
+)
+
+// PricingRuleRequest DTO para crear o actualizar una regla de precio
+type PricingRuleRequest struct {
+	Name              string     `json:"name" validate:"required,max=100"`
+	Type              string     `json:"type" validate:"required,oneof=TIME_BAND WEEKEND MEMBER_DISCOUNT PROMO"`
+	PistaID           *int       `json:"pistaId"`   // nil = todas las pistas
+	PistaType         *string    `json:"pistaType"` // nil = todos los tipos
+	Weekdays          []int      `json:"weekdays"`  // 0 = domingo ... 6 = sábado; vacío = todos
+	StartsAt          *string    `json:"startsAt"`  // HH:MM (franja horaria)
+	EndsAt            *string    `json:"endsAt"`    // HH:MM (24:00 permitido)
+	ValidFrom         *time.Time `json:"validFrom"`
+	ValidUntil        *time.Time `json:"validUntil"`
+	PercentAdjustment int        `json:"percentAdjustment" validate:"min=-100,max=500"` // +20 recargo, -10 descuento
+	IsActive          *bool      `json:"isActive"`                                      // Por defecto true
+}
+
+// QuoteRequest DTO para pedir un presupuesto de reserva
+type QuoteRequest struct {
+	PistaID   int       `json:"pistaId" validate:"required,min=1"`
+	StartTime time.Time `json:"startTime" validate:"required"`
+	EndTime   time.Time `json:"endTime" validate:"required"`
+	UserID    string    `json:"userId"`    // Solo ADMIN/GESTOR: presupuesto para otro usuario
+	PromoCode string    `json:"promoCode"` // Opcional: código promocional
+}
+
+// RuleRequestToDomain convierte PricingRuleRequest a domain.PricingRule
+func RuleRequestToDomain(req *PricingRuleRequest) (*domain.PricingRule, error) {
+	rule := &domain.PricingRule{
+		Name:              req.Name,
+		Type:              req.Type,
+		PistaID:           req.PistaID,
+		PistaType:         req.PistaType,
+		Weekdays:          req.Weekdays,
+		ValidFrom:         req.ValidFrom,
+		ValidUntil:        req.ValidUntil,
+		PercentAdjustment: req.PercentAdjustment,
+		IsActive:          req.IsActive == nil || *req.IsActive,
+	}
+	if rule.Weekdays == nil {
+		rule.Weekdays = []int{}
+	}
+
+	var err error
+	if rule.StartMinute, err = parseOptionalClock(req.StartsAt); err != nil {
+		return nil, err
+	}
+	if rule.EndMinute, err = parseOptionalClock(req.EndsAt); err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+// parseOptionalClock convierte "HH:MM" opcional a minutos desde medianoche
+func parseOptionalClock(value *string) (*int, error) {
+	if value == nil || *value == "" {
+		return nil, nil
+	}
+	parsed, err := time.Parse("15:04", *value)
+	if err != nil {
+		if *value == "24:00" {
+			minutes := 24 * 60
+			return &minutes, nil
+		}
+		return nil, domain.ErrInvalidBand
+	}
+	minutes := parsed.Hour()*60 + parsed.Minute()
+	return &minutes, nil
+}
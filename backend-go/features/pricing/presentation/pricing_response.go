@@ -0,0 +1,110 @@
+package presentation
+
+import (
+	"backend-go/features/pricing/domain"
+	"fmt"
+	"time"
+)
+
+// PricingRuleResponse DTO de una regla de precio
+type PricingRuleResponse struct {
+	ID                int        `json:"id"`
+	Name              string     `json:"name"`
+	Type              string     `json:"type"`
+	PistaID           *int       `json:"pistaId"`
+	PistaType         *string    `json:"pistaType"`
+	Weekdays          []int      `json:"weekdays"`
+	StartsAt          *string    `json:"startsAt"`
+	EndsAt            *string    `json:"endsAt"`
+	ValidFrom         *time.Time `json:"validFrom"`
+	ValidUntil        *time.Time `json:"validUntil"`
+	PercentAdjustment int        `json:"percentAdjustment"`
+	IsActive          bool       `json:"isActive"`
+	CreatedAt         time.Time  `json:"createdAt"`
+	UpdatedAt         time.Time  `json:"updatedAt"`
+}
+
+// QuoteLineResponse DTO de una línea del desglose
+type QuoteLineResponse struct {
+	RuleID      int     `json:"ruleId"`
+	Name        string  `json:"name"`
+	Type        string  `json:"type"`
+	Percent     int     `json:"percent"`
+	AmountCents int     `json:"amountCents"`
+	AmountEuros float64 `json:"amountEuros"`
+}
+
+// QuoteResponse DTO del presupuesto de una reserva
+type QuoteResponse struct {
+	PistaID         int                 `json:"pistaId"`
+	StartTime       time.Time           `json:"startTime"`
+	EndTime         time.Time           `json:"endTime"`
+	DurationMinutes int                 `json:"durationMinutes"`
+	HourlyRateCents int                 `json:"hourlyRateCents"`
+	BaseCents       int                 `json:"baseCents"`
+	Lines           []QuoteLineResponse `json:"lines"`
+	TotalCents      int                 `json:"totalCents"`
+	TotalEuros      float64             `json:"totalEuros"` // Convertido a euros para UI
+	IsMember        bool                `json:"isMember"`
+	PromoCode       *string             `json:"promoCode"`
+	DiscountCents   int                 `json:"discountCents"` // Descuento del código promocional
+}
+
+// ToRuleResponse convierte domain.PricingRule a DTO
+func ToRuleResponse(rule *domain.PricingRule) PricingRuleResponse {
+	return PricingRuleResponse{
+		ID:                rule.ID,
+		Name:              rule.Name,
+		Type:              rule.Type,
+		PistaID:           rule.PistaID,
+		PistaType:         rule.PistaType,
+		Weekdays:          rule.Weekdays,
+		StartsAt:          formatOptionalClock(rule.StartMinute),
+		EndsAt:            formatOptionalClock(rule.EndMinute),
+		ValidFrom:         rule.ValidFrom,
+		ValidUntil:        rule.ValidUntil,
+		PercentAdjustment: rule.PercentAdjustment,
+		IsActive:          rule.IsActive,
+		CreatedAt:         rule.CreatedAt,
+		UpdatedAt:         rule.UpdatedAt,
+	}
+}
+
+// ToQuoteResponse convierte domain.Quote a DTO
+func ToQuoteResponse(quote *domain.Quote) QuoteResponse {
+	response := QuoteResponse{
+		PistaID:         quote.PistaID,
+		StartTime:       quote.StartTime,
+		EndTime:         quote.EndTime,
+		DurationMinutes: quote.DurationMinutes,
+		HourlyRateCents: quote.HourlyRateCents,
+		BaseCents:       quote.BaseCents,
+		Lines:           make([]QuoteLineResponse, len(quote.Lines)),
+		TotalCents:      quote.TotalCents,
+		TotalEuros:      float64(quote.TotalCents) / 100.0,
+		IsMember:        quote.IsMember,
+		DiscountCents:   quote.DiscountCents,
+	}
+	if quote.PromoCode != "" {
+		response.PromoCode = &quote.PromoCode
+	}
+	for i, line := range quote.Lines {
+		response.Lines[i] = QuoteLineResponse{
+			RuleID:      line.RuleID,
+			Name:        line.Name,
+			Type:        line.Type,
+			Percent:     line.Percent,
+			AmountCents: line.AmountCents,
+			AmountEuros: float64(line.AmountCents) / 100.0,
+		}
+	}
+	return response
+}
+
+func formatOptionalClock(minutes *int) *string {
+	if minutes == nil {
+		return nil
+	}
+	formatted := fmt.Sprintf("%02d:%02d", *minutes/60, *minutes%60)
+	return &formatted
+}
@@ -0,0 +1,37 @@
+package presentation
+
+import (
+	"backend-go/shared/middleware"
+	"backend-go/shared/security"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ======================================================================================
+// PRICING ROUTES
+// Público (JWT opcional para descuentos de socio y límites por usuario): POST /quote,
+// POST /promo-codes/validate
+// Admin: CRUD /rules, /promo-codes
+// ======================================================================================
+
+// RegisterRoutes registra las rutas de precios
+func RegisterRoutes(app *fiber.App, handler *PricingHandler, promoHandler *PromoCodeHandler, jwtService security.JWTService) {
+	// Rutas públicas
+	public := app.Group("/api/pricing")
+	public.Post("/quote", middleware.OptionalJWTMiddleware(jwtService), handler.Quote)
+	public.Post("/promo-codes/validate", middleware.OptionalJWTMiddleware(jwtService), promoHandler.Validate)
+
+	// Rutas protegidas - Solo ADMIN y GESTOR
+	admin := app.Group("/api/pricing")
+	admin.Use(middleware.JWTMiddleware(jwtService))
+	admin.Use(middleware.RequireRoleByName("ADMIN", "GESTOR"))
+	admin.Get("/rules", handler.GetAllRules)
+	admin.Get("/rules/:id", handler.GetRuleByID)
+	admin.Post("/rules", handler.CreateRule)
+	admin.Put("/rules/:id", handler.UpdateRule)
+	admin.Delete("/rules/:id", handler.DeleteRule)
+	admin.Get("/promo-codes", promoHandler.GetAll)
+	admin.Get("/promo-codes/:id", promoHandler.GetByID)
+	admin.Post("/promo-codes", promoHandler.Create)
+	admin.Put("/promo-codes/:id", promoHandler.Update)
+}
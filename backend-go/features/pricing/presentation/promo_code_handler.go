@@ -0,0 +1,161 @@
+package presentation
+
+import (
+	"backend-go/features/pricing/application"
+	"backend-go/features/pricing/domain"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type PromoCodeHandler struct {
+	service *application.PromoCodeService
+}
+
+func NewPromoCodeHandler(service *application.PromoCodeService) *PromoCodeHandler {
+	return &PromoCodeHandler{service: service}
+}
+
+// Validate maneja POST /api/pricing/promo-codes/validate
+// @Summary Comprobar un código promocional y el descuento que aplicaría
+// @Description No registra el uso: el código se canjea al pagar (promo_code en /api/payments/booking, /class o /club).
+// @Description Si el usuario está autenticado se comprueba también su límite de usos. ADMIN/GESTOR pueden indicar userId.
+// @Tags pricing
+// @Accept json
+// @Produce json
+// @Param promo body ValidatePromoCodeRequest true "Código, concepto e importe"
+// @Success 200 {object} PromoCodePreviewResponse
+// @Failure 400 {object} map[string]string "Código no válido para este pago"
+// @Router /api/pricing/promo-codes/validate [post]
+func (h *PromoCodeHandler) Validate(c *fiber.Ctx) error {
+	var req ValidatePromoCodeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Datos inválidos"})
+	}
+	if req.AmountCents <= 0 {
+		return c.Status(400).JSON(fiber.Map{"error": "El importe debe ser mayor que 0"})
+	}
+
+	userID, err := resolveQuoteUser(c, req.UserID)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	promo, discount, err := h.service.Preview(req.Code, userID, strings.ToUpper(req.ResourceType), req.AmountCents)
+	if err != nil {
+		if application.IsPromoCodeRejection(err) {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(ToPromoCodePreviewResponse(promo, req.AmountCents, discount))
+}
+
+// GetAll maneja GET /api/pricing/promo-codes
+// @Summary Listar códigos promocionales con sus usos
+// @Tags pricing
+// @Produce json
+// @Success 200 {array} PromoCodeResponse
+// @Router /api/pricing/promo-codes [get]
+func (h *PromoCodeHandler) GetAll(c *fiber.Ctx) error {
+	promos, err := h.service.GetAll()
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	responses := make([]PromoCodeResponse, len(promos))
+	for i := range promos {
+		responses[i] = ToPromoCodeResponse(&promos[i])
+	}
+
+	return c.JSON(responses)
+}
+
+// GetByID maneja GET /api/pricing/promo-codes/:id
+// @Summary Obtener código promocional
+// @Tags pricing
+// @Produce json
+// @Param id path int true "ID del código"
+// @Success 200 {object} PromoCodeResponse
+// @Router /api/pricing/promo-codes/{id} [get]
+func (h *PromoCodeHandler) GetByID(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "ID inválido"})
+	}
+
+	promo, err := h.service.GetByID(id)
+	if err != nil {
+		if errors.Is(err, domain.ErrPromoCodeNotFound) {
+			return c.Status(404).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(ToPromoCodeResponse(promo))
+}
+
+// Create maneja POST /api/pricing/promo-codes
+// @Summary Crear código promocional (porcentaje o importe fijo, ventana de validez y límites de uso)
+// @Tags pricing
+// @Accept json
+// @Produce json
+// @Param promo body PromoCodeRequest true "Código promocional"
+// @Success 201 {object} PromoCodeResponse
+// @Failure 409 {object} map[string]string "El código ya existe"
+// @Router /api/pricing/promo-codes [post]
+func (h *PromoCodeHandler) Create(c *fiber.Ctx) error {
+	var req PromoCodeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Datos inválidos"})
+	}
+
+	promo := PromoCodeRequestToDomain(&req)
+	if err := h.service.Create(promo); err != nil {
+		if errors.Is(err, domain.ErrPromoCodeExists) {
+			return c.Status(409).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.Status(201).JSON(ToPromoCodeResponse(promo))
+}
+
+// Update maneja PUT /api/pricing/promo-codes/:id
+// @Summary Actualizar código promocional (isActive = false lo desactiva)
+// @Tags pricing
+// @Accept json
+// @Produce json
+// @Param id path int true "ID del código"
+// @Param promo body PromoCodeRequest true "Código promocional"
+// @Success 200 {object} PromoCodeResponse
+// @Router /api/pricing/promo-codes/{id} [put]
+func (h *PromoCodeHandler) Update(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "ID inválido"})
+	}
+
+	var req PromoCodeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Datos inválidos"})
+	}
+
+	promo := PromoCodeRequestToDomain(&req)
+	promo.ID = id
+
+	if err := h.service.Update(promo); err != nil {
+		if errors.Is(err, domain.ErrPromoCodeNotFound) {
+			return c.Status(404).JSON(fiber.Map{"error": err.Error()})
+		}
+		if errors.Is(err, domain.ErrPromoCodeExists) {
+			return c.Status(409).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(ToPromoCodeResponse(promo))
+}
@@ -0,0 +1,51 @@
+package presentation
+
+import (
+	"backend-go/features/pricing/domain"
+	"strings"
+	"time"
+)
+
+// PromoCodeRequest DTO para crear o actualizar un código promocional
+type PromoCodeRequest struct {
+	Code                  string     `json:"code" validate:"required,min=3,max=50"` // Se guarda en mayúsculas
+	Description           *string    `json:"description"`
+	DiscountType          string     `json:"discountType" validate:"required,oneof=PERCENT FIXED"`
+	PercentOff            int        `json:"percentOff"`                              // PERCENT: 1-99
+	AmountOffCents        int        `json:"amountOffCents"`                          // FIXED: importe en céntimos
+	ResourceTypes         []string   `json:"resourceTypes" validate:"required,min=1"` // PISTA, CLASS, CLUB
+	ValidFrom             *time.Time `json:"validFrom"`
+	ValidUntil            *time.Time `json:"validUntil"`
+	MaxRedemptions        *int       `json:"maxRedemptions"`        // nil = sin límite
+	MaxRedemptionsPerUser *int       `json:"maxRedemptionsPerUser"` // nil = sin límite
+	IsActive              *bool      `json:"isActive"`              // Por defecto true
+}
+
+// ValidatePromoCodeRequest DTO para comprobar un código antes de pagar
+type ValidatePromoCodeRequest struct {
+	Code         string `json:"code" validate:"required"`
+	ResourceType string `json:"resourceType" validate:"required,oneof=PISTA CLASS CLUB"`
+	AmountCents  int    `json:"amountCents" validate:"required,gt=0"` // Importe antes del descuento
+	UserID       string `json:"userId"`                               // Solo ADMIN/GESTOR: comprobar para otro usuario
+}
+
+// PromoCodeRequestToDomain convierte PromoCodeRequest a domain.PromoCode
+func PromoCodeRequestToDomain(req *PromoCodeRequest) *domain.PromoCode {
+	promo := &domain.PromoCode{
+		Code:                  req.Code,
+		Description:           req.Description,
+		DiscountType:          strings.ToUpper(req.DiscountType),
+		PercentOff:            req.PercentOff,
+		AmountOffCents:        req.AmountOffCents,
+		ResourceTypes:         make([]string, 0, len(req.ResourceTypes)),
+		ValidFrom:             req.ValidFrom,
+		ValidUntil:            req.ValidUntil,
+		MaxRedemptions:        req.MaxRedemptions,
+		MaxRedemptionsPerUser: req.MaxRedemptionsPerUser,
+		IsActive:              req.IsActive == nil || *req.IsActive,
+	}
+	for _, resourceType := range req.ResourceTypes {
+		promo.ResourceTypes = append(promo.ResourceTypes, strings.ToUpper(strings.TrimSpace(resourceType)))
+	}
+	return promo
+}
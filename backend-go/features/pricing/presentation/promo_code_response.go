@@ -0,0 +1,73 @@
+package presentation
+
+import (
+	"backend-go/features/pricing/domain"
+	"time"
+)
+
+// PromoCodeResponse DTO de un código promocional
+type PromoCodeResponse struct {
+	ID                    int        `json:"id"`
+	Code                  string     `json:"code"`
+	Description           *string    `json:"description"`
+	DiscountType          string     `json:"discountType"`
+	PercentOff            int        `json:"percentOff"`
+	AmountOffCents        int        `json:"amountOffCents"`
+	ResourceTypes         []string   `json:"resourceTypes"`
+	ValidFrom             *time.Time `json:"validFrom"`
+	ValidUntil            *time.Time `json:"validUntil"`
+	MaxRedemptions        *int       `json:"maxRedemptions"`
+	MaxRedemptionsPerUser *int       `json:"maxRedemptionsPerUser"`
+	Redemptions           int64      `json:"redemptions"` // Usos vigentes
+	IsActive              bool       `json:"isActive"`
+	CreatedAt             time.Time  `json:"createdAt"`
+	UpdatedAt             time.Time  `json:"updatedAt"`
+}
+
+// PromoCodePreviewResponse DTO del descuento que aplicaría un código
+type PromoCodePreviewResponse struct {
+	Code          string  `json:"code"`
+	Description   *string `json:"description"`
+	DiscountType  string  `json:"discountType"`
+	PercentOff    int     `json:"percentOff"`
+	AmountCents   int     `json:"amountCents"`
+	DiscountCents int     `json:"discountCents"`
+	TotalCents    int     `json:"totalCents"`
+	TotalEuros    float64 `json:"totalEuros"` // Convertido a euros para UI
+}
+
+// ToPromoCodeResponse convierte domain.PromoCode a DTO
+func ToPromoCodeResponse(promo *domain.PromoCode) PromoCodeResponse {
+	return PromoCodeResponse{
+		ID:                    promo.ID,
+		Code:                  promo.Code,
+		Description:           promo.Description,
+		DiscountType:          promo.DiscountType,
+		PercentOff:            promo.PercentOff,
+		AmountOffCents:        promo.AmountOffCents,
+		ResourceTypes:         promo.ResourceTypes,
+		ValidFrom:             promo.ValidFrom,
+		ValidUntil:            promo.ValidUntil,
+		MaxRedemptions:        promo.MaxRedemptions,
+		MaxRedemptionsPerUser: promo.MaxRedemptionsPerUser,
+		Redemptions:           promo.Redemptions,
+		IsActive:              promo.IsActive,
+		CreatedAt:             promo.CreatedAt,
+		UpdatedAt:             promo.UpdatedAt,
+	}
+}
+
+// ToPromoCodePreviewResponse construye el DTO del descuento sobre amountCents
+func ToPromoCodePreviewResponse(promo *domain.PromoCode, amountCents, discountCents int) PromoCodePreviewResponse {
+	total := amountCents - discountCents
+	return PromoCodePreviewResponse{
+		Code:          promo.Code,
+		Description:   promo.Description,
+		DiscountType:  promo.DiscountType,
+		PercentOff:    promo.PercentOff,
+		AmountCents:   amountCents,
+		DiscountCents: discountCents,
+		TotalCents:    total,
+		TotalEuros:    float64(total) / 100.0,
+	}
+}
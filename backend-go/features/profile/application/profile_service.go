@@ -37,6 +37,9 @@ func (s *ProfileService) UpdateMyProfile(userID uuid.UUID, data *domain.UpdatePr
 	if userID == uuid.Nil {
 		return nil, domain.ErrInvalidUserID
 	}
+	if data.SkillLevel != nil && (*data.SkillLevel < domain.MinSkillLevel || *data.SkillLevel > domain.MaxSkillLevel) {
+		return nil, domain.ErrInvalidSkillLevel
+	}
 
 	// Actualizar perfil
 	if err := s.profileRepo.UpdateProfile(userID, data); err != nil {
@@ -84,3 +87,12 @@ func (s *ProfileService) ChangePassword(userID uuid.UUID, data *domain.ChangePas
 	// Invalidar todas las sesiones activas (fuerza re-login en todos los dispositivos)
 	return s.profileRepo.BumpSessionAndRevokeSessions(userID)
 }
+
+// GetMyPlayingPartners obtiene con quién juega el usuario autenticado
+func (s *ProfileService) GetMyPlayingPartners(userID uuid.UUID) ([]domain.PlayingPartner, error) {
+	if userID == uuid.Nil {
+		return nil, domain.ErrInvalidUserID
+	}
+
+	return s.profileRepo.GetPlayingPartners(userID)
+}
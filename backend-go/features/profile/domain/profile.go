@@ -13,31 +13,50 @@ import (
 
 // Profile representa el perfil completo del usuario autenticado
 type Profile struct {
-	ID        uuid.UUID
-	RoleID    uint
-	Slug      string
-	Email     string
-	FullName  string
-	Phone     *string
-	DNI       *string
-	AvatarURL *string
-	RoleName  string
-	IsActive  bool
-	IsPremium bool // Basado en IsMember
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID         uuid.UUID
+	RoleID     uint
+	Slug       string
+	Email      string
+	FullName   string
+	Phone      *string
+	DNI        *string
+	AvatarURL  *string
+	SkillLevel *float64 // Nivel de juego (1.0 - 7.0), nil si no lo ha indicado
+	RoleName   string
+	IsActive   bool
+	IsPremium  bool // Basado en IsMember
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
 }
 
 // UpdateProfileData contiene los datos actualizables del perfil
 type UpdateProfileData struct {
-	FullName  *string
-	Phone     *string
-	DNI       *string
-	AvatarURL *string
+	FullName   *string
+	Phone      *string
+	DNI        *string
+	AvatarURL  *string
+	SkillLevel *float64
 }
 
+// Rango de niveles de juego admitidos (escala habitual de pádel)
+const (
+	MinSkillLevel = 1.0
+	MaxSkillLevel = 7.0
+)
+
 // ChangePasswordData contiene los datos para cambiar contraseña
 type ChangePasswordData struct {
 	CurrentPassword string
 	NewPassword     string
 }
+
+// PlayingPartner es un jugador con el que el usuario ha compartido reservas (como titular o
+// como participante que aceptó la invitación)
+type PlayingPartner struct {
+	UserID           uuid.UUID
+	Slug             string
+	FullName         string
+	AvatarURL        *string
+	BookingsTogether int
+	LastPlayedAt     time.Time
+}
@@ -17,4 +17,5 @@ var (
 	ErrAvatarUploadFailed   = errors.New("error al subir el avatar")
 	ErrInvalidFileType      = errors.New("tipo de archivo no permitido")
 	ErrFileTooLarge         = errors.New("el archivo es demasiado grande")
+	ErrInvalidSkillLevel    = errors.New("el nivel de juego debe estar entre 1.0 y 7.0")
 )
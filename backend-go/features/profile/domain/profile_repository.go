@@ -23,4 +23,8 @@ type ProfileRepository interface {
 	// BumpSessionAndRevokeSessions incrementa el SessionVersion del usuario e invalida
 	// todas sus sesiones activas. Se llama tras un cambio de contraseña.
 	BumpSessionAndRevokeSessions(userID uuid.UUID) error
+
+	// GetPlayingPartners obtiene los jugadores con los que el usuario ha compartido reservas no
+	// canceladas, ordenados por número de reservas juntos
+	GetPlayingPartners(userID uuid.UUID) ([]PlayingPartner, error)
 }
@@ -3,6 +3,8 @@ package infrastructure
 import (
 	"backend-go/features/profile/domain"
 	"backend-go/shared/database"
+	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -33,19 +35,20 @@ func (r *ProfileRepositoryImpl) GetProfileByUserID(userID uuid.UUID) (*domain.Pr
 	}
 
 	return &domain.Profile{
-		ID:        user.ID,
-		RoleID:    user.RoleID,
-		Slug:      user.Slug,
-		Email:     user.Email,
-		FullName:  user.FullName,
-		Phone:     user.Phone,
-		DNI:       user.DNI,
-		AvatarURL: user.AvatarURL,
-		RoleName:  user.Role.Name,
-		IsActive:  user.IsActive,
-		IsPremium: user.IsMember,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
+		ID:         user.ID,
+		RoleID:     user.RoleID,
+		Slug:       user.Slug,
+		Email:      user.Email,
+		FullName:   user.FullName,
+		Phone:      user.Phone,
+		DNI:        user.DNI,
+		AvatarURL:  user.AvatarURL,
+		SkillLevel: user.SkillLevel,
+		RoleName:   user.Role.Name,
+		IsActive:   user.IsActive,
+		IsPremium:  user.IsMember,
+		CreatedAt:  user.CreatedAt,
+		UpdatedAt:  user.UpdatedAt,
 	}, nil
 }
 
@@ -62,6 +65,9 @@ func (r *ProfileRepositoryImpl) UpdateProfile(userID uuid.UUID, data *domain.Upd
 	if data.DNI != nil {
 		updates["dni"] = *data.DNI
 	}
+	if data.SkillLevel != nil {
+		updates["skill_level"] = *data.SkillLevel
+	}
 	if data.AvatarURL != nil {
 		updates["avatar_url"] = *data.AvatarURL
 	}
@@ -131,3 +137,57 @@ func (r *ProfileRepositoryImpl) BumpSessionAndRevokeSessions(userID uuid.UUID) e
 			"reason":  "password_change",
 		}).Error
 }
+
+// GetPlayingPartners obtiene los compañeros de juego: titulares y participantes aceptados de
+// las reservas no canceladas en las que ha jugado el usuario
+func (r *ProfileRepositoryImpl) GetPlayingPartners(userID uuid.UUID) ([]domain.PlayingPartner, error) {
+	var rows []struct {
+		UserID           uuid.UUID
+		Slug             string
+		FullName         string
+		AvatarURL        *string
+		BookingsTogether int
+		LastPlayedAt     time.Time
+	}
+
+	err := r.db.Raw(`
+		WITH my_bookings AS (
+			SELECT b.id, b.start_time
+			FROM bookings b
+			WHERE b.deleted_at IS NULL AND b.status != 'CANCELLED'
+			  AND (b.user_id = @user OR EXISTS (
+			      SELECT 1 FROM booking_participants p
+			      WHERE p.booking_id = b.id AND p.user_id = @user AND p.status = 'ACCEPTED'))
+		), players AS (
+			SELECT mb.id, mb.start_time, b.user_id
+			FROM my_bookings mb JOIN bookings b ON b.id = mb.id
+			UNION
+			SELECT mb.id, mb.start_time, p.user_id
+			FROM my_bookings mb JOIN booking_participants p ON p.booking_id = mb.id AND p.status = 'ACCEPTED'
+		)
+		SELECT u.id AS user_id, u.slug, u.full_name, u.avatar_url,
+		       COUNT(DISTINCT players.id) AS bookings_together,
+		       MAX(players.start_time) AS last_played_at
+		FROM players JOIN users u ON u.id = players.user_id
+		WHERE players.user_id != @user AND u.deleted_at IS NULL
+		GROUP BY u.id, u.slug, u.full_name, u.avatar_url
+		ORDER BY bookings_together DESC, last_played_at DESC`,
+		sql.Named("user", userID),
+	).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	partners := make([]domain.PlayingPartner, len(rows))
+	for i, row := range rows {
+		partners[i] = domain.PlayingPartner{
+			UserID:           row.UserID,
+			Slug:             row.Slug,
+			FullName:         row.FullName,
+			AvatarURL:        row.AvatarURL,
+			BookingsTogether: row.BookingsTogether,
+			LastPlayedAt:     row.LastPlayedAt,
+		}
+	}
+	return partners, nil
+}
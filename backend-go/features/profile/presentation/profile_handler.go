@@ -49,6 +49,35 @@ func (h *ProfileHandler) GetMyProfile(c *fiber.Ctx) error {
 	return c.JSON(ToProfileResponse(profile))
 }
 
+// GetMyPlayingPartners maneja GET /profile/me/partners
+// @Summary Con quién juego: jugadores con los que he compartido reservas
+// @Description Titulares y participantes que aceptaron la invitación de reservas no canceladas, ordenados por reservas juntos.
+// @Tags profile
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} PlayingPartnerResponse
+// @Router /api/profile/me/partners [get]
+func (h *ProfileHandler) GetMyPlayingPartners(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uuid.UUID)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Usuario no autenticado",
+		})
+	}
+
+	partners, err := h.profileService.GetMyPlayingPartners(userID)
+	if err != nil {
+		return handleProfileError(c, err)
+	}
+
+	responses := make([]PlayingPartnerResponse, len(partners))
+	for i := range partners {
+		responses[i] = ToPlayingPartnerResponse(&partners[i])
+	}
+
+	return c.JSON(responses)
+}
+
 // UpdateMyProfile maneja PUT /profile/me
 // @Summary Actualizar mi perfil
 // @Tags profile
@@ -77,8 +106,9 @@ func (h *ProfileHandler) UpdateMyProfile(c *fiber.Ctx) error {
 
 	// Convertir a domain
 	updateData := &domain.UpdateProfileData{
-		Phone: req.Phone,
-		DNI:   req.DNI,
+		Phone:      req.Phone,
+		DNI:        req.DNI,
+		SkillLevel: req.SkillLevel,
 	}
 
 	// Solo actualizar fullName si se proporciona
@@ -252,6 +282,10 @@ func handleProfileError(c *fiber.Ctx, err error) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": "La contraseña debe tener al menos 8 caracteres",
 		})
+	case errors.Is(err, domain.ErrInvalidSkillLevel):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "El nivel de juego debe estar entre 1.0 y 7.0",
+		})
 	case errors.Is(err, domain.ErrUpdateFailed):
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Error al actualizar el perfil",
@@ -6,9 +6,10 @@ package presentation
 
 // UpdateProfileRequest representa la solicitud para actualizar el perfil
 type UpdateProfileRequest struct {
-	FullName string  `json:"fullName" validate:"omitempty,min=3,max=100"`
-	Phone    *string `json:"phone" validate:"omitempty,min=10,max=20"`
-	DNI      *string `json:"dni" validate:"omitempty,min=8,max=20"`
+	FullName   string   `json:"fullName" validate:"omitempty,min=3,max=100"`
+	Phone      *string  `json:"phone" validate:"omitempty,min=10,max=20"`
+	DNI        *string  `json:"dni" validate:"omitempty,min=8,max=20"`
+	SkillLevel *float64 `json:"skillLevel" validate:"omitempty,min=1,max=7"`
 }
 
 // ChangePasswordRequest representa la solicitud para cambiar contraseña
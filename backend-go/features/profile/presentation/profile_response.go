@@ -13,36 +13,60 @@ import (
 
 // ProfileResponse representa el DTO del perfil del usuario autenticado
 type ProfileResponse struct {
-	ID        uuid.UUID `json:"id"`
-	RoleID    uint      `json:"roleId"`
-	Slug      string    `json:"slug"`
-	Email     string    `json:"email"`
-	FullName  string    `json:"fullName"`
-	Phone     *string   `json:"phone"`
-	DNI       *string   `json:"dni"`
-	AvatarURL *string   `json:"avatarUrl"`
-	RoleName  string    `json:"roleName"`
-	IsActive  bool      `json:"isActive"`
-	IsPremium bool      `json:"isPremium"`
-	CreatedAt time.Time `json:"createdAt"`
-	UpdatedAt time.Time `json:"updatedAt"`
+	ID         uuid.UUID `json:"id"`
+	RoleID     uint      `json:"roleId"`
+	Slug       string    `json:"slug"`
+	Email      string    `json:"email"`
+	FullName   string    `json:"fullName"`
+	Phone      *string   `json:"phone"`
+	DNI        *string   `json:"dni"`
+	AvatarURL  *string   `json:"avatarUrl"`
+	SkillLevel *float64  `json:"skillLevel"`
+	RoleName   string    `json:"roleName"`
+	IsActive   bool      `json:"isActive"`
+	IsPremium  bool      `json:"isPremium"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
 }
 
 // ToProfileResponse convierte de dominio a response DTO
 func ToProfileResponse(profile *domain.Profile) ProfileResponse {
 	return ProfileResponse{
-		ID:        profile.ID,
-		RoleID:    profile.RoleID,
-		Slug:      profile.Slug,
-		Email:     profile.Email,
-		FullName:  profile.FullName,
-		Phone:     profile.Phone,
-		DNI:       profile.DNI,
-		AvatarURL: profile.AvatarURL,
-		RoleName:  profile.RoleName,
-		IsActive:  profile.IsActive,
-		IsPremium: profile.IsPremium,
-		CreatedAt: profile.CreatedAt,
-		UpdatedAt: profile.UpdatedAt,
+		ID:         profile.ID,
+		RoleID:     profile.RoleID,
+		Slug:       profile.Slug,
+		Email:      profile.Email,
+		FullName:   profile.FullName,
+		Phone:      profile.Phone,
+		DNI:        profile.DNI,
+		AvatarURL:  profile.AvatarURL,
+		SkillLevel: profile.SkillLevel,
+		RoleName:   profile.RoleName,
+		IsActive:   profile.IsActive,
+		IsPremium:  profile.IsPremium,
+		CreatedAt:  profile.CreatedAt,
+		UpdatedAt:  profile.UpdatedAt,
+	}
+}
+
+// PlayingPartnerResponse representa a un compañero de juego del usuario autenticado
+type PlayingPartnerResponse struct {
+	UserID           uuid.UUID `json:"userId"`
+	Slug             string    `json:"slug"`
+	FullName         string    `json:"fullName"`
+	AvatarURL        *string   `json:"avatarUrl"`
+	BookingsTogether int       `json:"bookingsTogether"`
+	LastPlayedAt     time.Time `json:"lastPlayedAt"`
+}
+
+// ToPlayingPartnerResponse convierte de dominio a response DTO
+func ToPlayingPartnerResponse(partner *domain.PlayingPartner) PlayingPartnerResponse {
+	return PlayingPartnerResponse{
+		UserID:           partner.UserID,
+		Slug:             partner.Slug,
+		FullName:         partner.FullName,
+		AvatarURL:        partner.AvatarURL,
+		BookingsTogether: partner.BookingsTogether,
+		LastPlayedAt:     partner.LastPlayedAt,
 	}
 }
@@ -19,6 +19,7 @@ func RegisterProfileRoutes(app *fiber.App, handler *ProfileHandler, jwtService s
 	// Rutas del perfil del usuario autenticado
 	profile.Get("/me", handler.GetMyProfile)
 	profile.Put("/me", handler.UpdateMyProfile)
+	profile.Get("/me/partners", handler.GetMyPlayingPartners)
 	profile.Post("/change-password", handler.ChangePassword)
 	profile.Post("/avatar", handler.UploadAvatar)
 }
@@ -0,0 +1,96 @@
+package presentation
+
+import (
+	"time"
+
+	"backend-go/internal/scheduler"
+)
+
+// TaskResponse es el estado de una tarea programada
+type TaskResponse struct {
+	ID             string       `json:"id"`
+	Name           string       `json:"name"`
+	Schedule       string       `json:"schedule"`
+	TimeoutSeconds int          `json:"timeoutSeconds"`
+	Running        bool         `json:"running"`
+	NextRun        *time.Time   `json:"nextRun,omitempty"`
+	LastRun        *RunResponse `json:"lastRun,omitempty"`
+}
+
+// RunResponse es una ejecución de una tarea
+type RunResponse struct {
+	ID           uint       `json:"id"`
+	TaskID       string     `json:"taskId"`
+	TaskName     string     `json:"taskName"`
+	Trigger      string     `json:"trigger"`
+	Status       string     `json:"status"`
+	Instance     string     `json:"instance"`
+	StartedAt    time.Time  `json:"startedAt"`
+	FinishedAt   *time.Time `json:"finishedAt,omitempty"`
+	DurationMs   int64      `json:"durationMs"`
+	AffectedRows int        `json:"affectedRows"`
+	Error        *string    `json:"error,omitempty"`
+}
+
+// LeadershipResponse es el liderazgo del scheduler entre réplicas
+type LeadershipResponse struct {
+	Instance string         `json:"instance"`
+	IsLeader bool           `json:"isLeader"`
+	Lease    *LeaseResponse `json:"lease,omitempty"`
+}
+
+// LeaseResponse es el lease de liderazgo vigente
+type LeaseResponse struct {
+	Holder     string    `json:"holder"`
+	AcquiredAt time.Time `json:"acquiredAt"`
+	RenewedAt  time.Time `json:"renewedAt"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+}
+
+// ToLeadershipResponse convierte el estado del liderazgo a su respuesta
+func ToLeadershipResponse(leadership scheduler.Leadership) LeadershipResponse {
+	response := LeadershipResponse{Instance: leadership.Instance, IsLeader: leadership.IsLeader}
+	if leadership.Lease != nil {
+		response.Lease = &LeaseResponse{
+			Holder:     leadership.Lease.Holder,
+			AcquiredAt: leadership.Lease.AcquiredAt,
+			RenewedAt:  leadership.Lease.RenewedAt,
+			ExpiresAt:  leadership.Lease.ExpiresAt,
+		}
+	}
+	return response
+}
+
+// ToTaskResponse convierte el estado de una tarea a su respuesta
+func ToTaskResponse(info scheduler.TaskInfo) TaskResponse {
+	response := TaskResponse{
+		ID:             info.ID,
+		Name:           info.Name,
+		Schedule:       info.Schedule,
+		TimeoutSeconds: int(info.Timeout / time.Second),
+		Running:        info.Running,
+		NextRun:        info.NextRun,
+	}
+	if info.LastRun != nil {
+		lastRun := ToRunResponse(*info.LastRun)
+		response.LastRun = &lastRun
+	}
+	return response
+}
+
+// ToRunResponse convierte una ejecución a su respuesta
+func ToRunResponse(run scheduler.Run) RunResponse {
+	return RunResponse{
+		ID:           run.ID,
+		TaskID:       run.TaskID,
+		TaskName:     run.TaskName,
+		Trigger:      run.Trigger,
+		Status:       run.Status,
+		Instance:     run.Instance,
+		StartedAt:    run.StartedAt,
+		FinishedAt:   run.FinishedAt,
+		DurationMs:   run.Duration().Milliseconds(),
+		AffectedRows: run.AffectedRows,
+		Error:        run.Error,
+	}
+}
@@ -0,0 +1,111 @@
+package presentation
+
+import (
+	"errors"
+	"strconv"
+
+	"backend-go/internal/scheduler"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type SchedulerHandler struct {
+	scheduler *scheduler.Scheduler
+}
+
+func NewSchedulerHandler(taskScheduler *scheduler.Scheduler) *SchedulerHandler {
+	return &SchedulerHandler{scheduler: taskScheduler}
+}
+
+// GetTasks maneja GET /api/admin/scheduler/tasks
+// @Summary Listar las tareas programadas
+// @Description Planificación, estado actual, próxima ejecución y última ejecución en esta instancia
+// @Tags scheduler
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} TaskResponse
+// @Router /api/admin/scheduler/tasks [get]
+func (h *SchedulerHandler) GetTasks(c *fiber.Ctx) error {
+	tasks := h.scheduler.Tasks()
+	response := make([]TaskResponse, len(tasks))
+	for i, task := range tasks {
+		response[i] = ToTaskResponse(task)
+	}
+	return c.JSON(response)
+}
+
+// GetRuns maneja GET /api/admin/scheduler/runs
+// @Summary Historial de ejecuciones
+// @Tags scheduler
+// @Security BearerAuth
+// @Produce json
+// @Param task query string false "ID de la tarea (por defecto todas)"
+// @Param limit query int false "Número de ejecuciones (por defecto 50, máximo 500)"
+// @Success 200 {array} RunResponse
+// @Router /api/admin/scheduler/runs [get]
+func (h *SchedulerHandler) GetRuns(c *fiber.Ctx) error {
+	limit := 0
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 {
+			return c.Status(400).JSON(fiber.Map{"error": "Límite inválido"})
+		}
+		limit = parsed
+	}
+
+	runs, err := h.scheduler.History(c.Query("task"), limit)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	response := make([]RunResponse, len(runs))
+	for i, run := range runs {
+		response[i] = ToRunResponse(run)
+	}
+	return c.JSON(response)
+}
+
+// RunTask maneja POST /api/admin/scheduler/tasks/:id/run
+// @Summary Ejecutar una tarea manualmente
+// @Description Lanza la tarea en segundo plano; el resultado queda en el historial
+// @Tags scheduler
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "ID de la tarea"
+// @Success 202 {object} RunResponse
+// @Router /api/admin/scheduler/tasks/{id}/run [post]
+func (h *SchedulerHandler) RunTask(c *fiber.Ctx) error {
+	run, err := h.scheduler.Trigger(c.Params("id"))
+	if err != nil {
+		return h.handleError(c, err)
+	}
+	return c.Status(202).JSON(ToRunResponse(*run))
+}
+
+// GetLeader maneja GET /api/admin/scheduler/leader
+// @Summary Liderazgo del scheduler entre réplicas
+// @Description Qué instancia lanza las ejecuciones programadas y si es la que atiende la petición
+// @Tags scheduler
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} LeadershipResponse
+// @Router /api/admin/scheduler/leader [get]
+func (h *SchedulerHandler) GetLeader(c *fiber.Ctx) error {
+	leadership, err := h.scheduler.Leadership()
+	if err != nil {
+		return h.handleError(c, err)
+	}
+	return c.JSON(ToLeadershipResponse(*leadership))
+}
+
+func (h *SchedulerHandler) handleError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, scheduler.ErrTaskNotFound):
+		return c.Status(404).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, scheduler.ErrTaskRunning), errors.Is(err, scheduler.ErrTaskLocked), errors.Is(err, scheduler.ErrSchedulerBusy):
+		return c.Status(409).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, scheduler.ErrSchedulerStopped):
+		return c.Status(503).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.Status(500).JSON(fiber.Map{"error": "Error al consultar el scheduler"})
+}
@@ -0,0 +1,23 @@
+package presentation
+
+import (
+	"backend-go/shared/middleware"
+	"backend-go/shared/security"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ======================================================================================
+// SCHEDULER ROUTES - Tareas automáticas, solo ADMIN
+// ======================================================================================
+
+func RegisterRoutes(app *fiber.App, handler *SchedulerHandler, jwtService security.JWTService) {
+	admin := app.Group("/api/admin/scheduler")
+	admin.Use(middleware.JWTMiddleware(jwtService))
+	admin.Use(middleware.RequireRoleByName("ADMIN"))
+
+	admin.Get("/tasks", handler.GetTasks)         // Estado de las tareas
+	admin.Get("/leader", handler.GetLeader)       // Réplica líder
+	admin.Get("/runs", handler.GetRuns)           // Historial (?task=&limit=)
+	admin.Post("/tasks/:id/run", handler.RunTask) // Ejecución manual (202)
+}
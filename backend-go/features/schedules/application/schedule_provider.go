@@ -0,0 +1,46 @@
+package application
+
+import (
+	"backend-go/shared/availability"
+	"time"
+)
+
+// AvailabilityScheduleProvider implementa availability.ScheduleProvider
+type AvailabilityScheduleProvider struct {
+	service *ScheduleService
+}
+
+func NewAvailabilityScheduleProvider(service *ScheduleService) availability.ScheduleProvider {
+	return &AvailabilityScheduleProvider{service: service}
+}
+
+func (p *AvailabilityScheduleProvider) GetDaySchedules(pistaID int, from, to time.Time) ([]availability.DaySchedule, error) {
+	schedules, err := p.service.GetDaySchedules(pistaID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]availability.DaySchedule, len(schedules))
+	for i, schedule := range schedules {
+		result[i] = availability.DaySchedule{
+			Date:   schedule.Date,
+			Closed: schedule.Closed,
+			Reason: schedule.Reason,
+			Open:   schedule.Open,
+			Close:  schedule.Close,
+		}
+		for _, blackout := range schedule.Blackouts {
+			result[i].Blackouts = append(result[i].Blackouts, availability.TimeWindow{
+				StartTime: blackout.StartTime,
+				EndTime:   blackout.EndTime,
+				Reason:    blackout.Reason,
+			})
+		}
+	}
+
+	return result, nil
+}
+
+func (p *AvailabilityScheduleProvider) CheckSlot(pistaID int, startTime, endTime time.Time) error {
+	return p.service.CheckSlot(pistaID, startTime, endTime)
+}
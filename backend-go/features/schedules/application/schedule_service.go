@@ -0,0 +1,189 @@
+package application
+
+import (
+	"backend-go/features/schedules/domain"
+	"time"
+)
+
+// ScheduleService resuelve el horario efectivo de las pistas (horario semanal,
+// festivos y bloqueos) y gestiona su configuración
+type ScheduleService struct {
+	repo domain.ScheduleRepository
+}
+
+func NewScheduleService(repo domain.ScheduleRepository) *ScheduleService {
+	return &ScheduleService{repo: repo}
+}
+
+// GetDaySchedule obtiene el horario efectivo de una pista en un día
+func (s *ScheduleService) GetDaySchedule(pistaID int, day time.Time) (*domain.DaySchedule, error) {
+	schedules, err := s.GetDaySchedules(pistaID, day, day)
+	if err != nil {
+		return nil, err
+	}
+	return &schedules[0], nil
+}
+
+// GetDaySchedules obtiene el horario efectivo de una pista para cada día entre from y to (inclusive).
+// Para cada día se aplica la regla semanal más específica (pista > global, temporada > permanente);
+// si no hay ninguna se usa el horario por defecto.
+func (s *ScheduleService) GetDaySchedules(pistaID int, from, to time.Time) ([]domain.DaySchedule, error) {
+	from = domain.TruncateDay(from)
+	to = domain.TruncateDay(to)
+
+	rules, err := s.repo.FindOpeningHours(&pistaID)
+	if err != nil {
+		return nil, err
+	}
+
+	closures, err := s.repo.FindClosures(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	blackouts, err := s.repo.FindBlackoutsInRange(pistaID, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+
+	schedules := []domain.DaySchedule{}
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		schedule := domain.DaySchedule{PistaID: pistaID, Date: day}
+
+		if closure := findClosure(closures, pistaID, day); closure != nil {
+			schedule.Closed = true
+			schedule.Reason = closure.Reason
+			schedules = append(schedules, schedule)
+			continue
+		}
+
+		openMinute, closeMinute := domain.DefaultOpeningHour*60, domain.DefaultClosingHour*60
+		if rule := selectRule(rules, pistaID, day); rule != nil {
+			openMinute, closeMinute = rule.OpenMinute, rule.CloseMinute
+		}
+		schedule.Open = day.Add(time.Duration(openMinute) * time.Minute)
+		schedule.Close = day.Add(time.Duration(closeMinute) * time.Minute)
+
+		for _, blackout := range blackouts {
+			if blackout.Overlaps(schedule.Open, schedule.Close) {
+				schedule.Blackouts = append(schedule.Blackouts, blackout)
+			}
+		}
+
+		schedules = append(schedules, schedule)
+	}
+
+	return schedules, nil
+}
+
+// CheckSlot verifica que una franja esté dentro del horario efectivo de la pista
+func (s *ScheduleService) CheckSlot(pistaID int, startTime, endTime time.Time) error {
+	schedule, err := s.GetDaySchedule(pistaID, startTime)
+	if err != nil {
+		return err
+	}
+	return schedule.CheckSlot(startTime, endTime)
+}
+
+// ======================================================================================
+// HORARIOS SEMANALES
+// ======================================================================================
+
+// GetOpeningHours obtiene las reglas de horario (filtradas por pista si se indica)
+func (s *ScheduleService) GetOpeningHours(pistaID *int) ([]domain.OpeningHours, error) {
+	return s.repo.FindOpeningHours(pistaID)
+}
+
+// CreateOpeningHours crea una regla de horario semanal
+func (s *ScheduleService) CreateOpeningHours(hours *domain.OpeningHours) error {
+	if err := hours.Validate(); err != nil {
+		return err
+	}
+	return s.repo.CreateOpeningHours(hours)
+}
+
+// UpdateOpeningHours actualiza una regla de horario semanal
+func (s *ScheduleService) UpdateOpeningHours(hours *domain.OpeningHours) error {
+	existing, err := s.repo.FindOpeningHoursByID(hours.ID)
+	if err != nil {
+		return err
+	}
+	if err := hours.Validate(); err != nil {
+		return err
+	}
+
+	hours.CreatedAt = existing.CreatedAt
+	return s.repo.UpdateOpeningHours(hours)
+}
+
+// DeleteOpeningHours elimina una regla de horario semanal
+func (s *ScheduleService) DeleteOpeningHours(id int) error {
+	return s.repo.DeleteOpeningHours(id)
+}
+
+// ======================================================================================
+// CIERRES Y BLOQUEOS
+// ======================================================================================
+
+// GetClosures obtiene los cierres entre dos fechas
+func (s *ScheduleService) GetClosures(from, to time.Time) ([]domain.Closure, error) {
+	return s.repo.FindClosures(domain.TruncateDay(from), domain.TruncateDay(to))
+}
+
+// CreateClosure registra un día de cierre (festivo)
+func (s *ScheduleService) CreateClosure(closure *domain.Closure) error {
+	closure.Date = domain.TruncateDay(closure.Date)
+	return s.repo.CreateClosure(closure)
+}
+
+// DeleteClosure elimina un día de cierre
+func (s *ScheduleService) DeleteClosure(id int) error {
+	return s.repo.DeleteClosure(id)
+}
+
+// GetBlackouts obtiene los bloqueos que se solapan con el rango dado
+func (s *ScheduleService) GetBlackouts(from, to time.Time) ([]domain.Blackout, error) {
+	return s.repo.FindBlackouts(from, to)
+}
+
+// CreateBlackout registra un bloqueo puntual (ej. mantenimiento)
+func (s *ScheduleService) CreateBlackout(blackout *domain.Blackout) error {
+	if err := blackout.Validate(); err != nil {
+		return err
+	}
+	return s.repo.CreateBlackout(blackout)
+}
+
+// DeleteBlackout elimina un bloqueo puntual
+func (s *ScheduleService) DeleteBlackout(id int) error {
+	return s.repo.DeleteBlackout(id)
+}
+
+// findClosure devuelve el cierre que afecta a la pista ese día (nil si está abierta)
+func findClosure(closures []domain.Closure, pistaID int, day time.Time) *domain.Closure {
+	for i := range closures {
+		closure := &closures[i]
+		if !domain.TruncateDay(closure.Date).Equal(day) {
+			continue
+		}
+		if closure.PistaID == nil || *closure.PistaID == pistaID {
+			return closure
+		}
+	}
+	return nil
+}
+
+// selectRule elige la regla semanal más específica que aplica a la pista ese día
+func selectRule(rules []domain.OpeningHours, pistaID int, day time.Time) *domain.OpeningHours {
+	var selected *domain.OpeningHours
+	for i := range rules {
+		rule := &rules[i]
+		if !rule.AppliesTo(pistaID, day) {
+			continue
+		}
+		if selected == nil || rule.Specificity() >= selected.Specificity() {
+			selected = rule
+		}
+	}
+	return selected
+}
@@ -0,0 +1,177 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Errores de dominio
+var (
+	ErrOpeningHoursNotFound = errors.New("horario de apertura no encontrado")
+	ErrClosureNotFound      = errors.New("cierre no encontrado")
+	ErrBlackoutNotFound     = errors.New("bloqueo no encontrado")
+	ErrInvalidWeekday       = errors.New("día de la semana inválido (0 = domingo ... 6 = sábado)")
+	ErrInvalidClock         = errors.New("hora inválida (usar HH:MM)")
+	ErrInvalidHoursRange    = errors.New("la hora de cierre debe ser posterior a la de apertura")
+	ErrInvalidValidity      = errors.New("la fecha de fin de vigencia debe ser posterior a la de inicio")
+	ErrInvalidBlackout      = errors.New("el fin del bloqueo debe ser posterior al inicio")
+	ErrOutsideOpeningHours  = errors.New("la franja está fuera del horario de apertura")
+	ErrPistaClosed          = errors.New("la pista está cerrada ese día")
+	ErrPistaBlackout        = errors.New("la pista está bloqueada en ese horario")
+)
+
+// Horario por defecto cuando no hay ninguna regla configurada (horario histórico del MVP)
+const (
+	DefaultOpeningHour = 9  // 09:00
+	DefaultClosingHour = 23 // 23:00
+)
+
+// OpeningHours representa una regla de horario semanal en el dominio.
+// PistaID nil = regla global para todas las pistas.
+type OpeningHours struct {
+	ID          int
+	PistaID     *int
+	Weekday     int // 0 = domingo ... 6 = sábado
+	OpenMinute  int // Minutos desde medianoche
+	CloseMinute int
+	ValidFrom   *time.Time // Inicio de vigencia (temporada), inclusive
+	ValidUntil  *time.Time // Fin de vigencia, inclusive
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// Closure representa un día festivo o de cierre (PistaID nil = todo el polideportivo)
+type Closure struct {
+	ID        int
+	PistaID   *int
+	Date      time.Time
+	Reason    string
+	CreatedAt time.Time
+}
+
+// Blackout representa un bloqueo puntual de una franja (PistaID nil = todas las pistas)
+type Blackout struct {
+	ID        int
+	PistaID   *int
+	StartTime time.Time
+	EndTime   time.Time
+	Reason    string
+	CreatedAt time.Time
+}
+
+// DaySchedule es el horario efectivo de una pista en un día concreto
+type DaySchedule struct {
+	PistaID   int
+	Date      time.Time
+	Closed    bool
+	Reason    string // Motivo del cierre (si Closed)
+	Open      time.Time
+	Close     time.Time
+	Blackouts []Blackout
+}
+
+// Validate verifica la coherencia de una regla de horario
+func (h *OpeningHours) Validate() error {
+	if h.Weekday < 0 || h.Weekday > 6 {
+		return ErrInvalidWeekday
+	}
+	if h.OpenMinute < 0 || h.CloseMinute > 24*60 {
+		return ErrInvalidClock
+	}
+	if h.CloseMinute <= h.OpenMinute {
+		return ErrInvalidHoursRange
+	}
+	if h.ValidFrom != nil && h.ValidUntil != nil && h.ValidUntil.Before(*h.ValidFrom) {
+		return ErrInvalidValidity
+	}
+	return nil
+}
+
+// AppliesTo indica si la regla aplica a la pista y al día indicados
+func (h *OpeningHours) AppliesTo(pistaID int, day time.Time) bool {
+	if h.PistaID != nil && *h.PistaID != pistaID {
+		return false
+	}
+	if int(day.Weekday()) != h.Weekday {
+		return false
+	}
+	if h.ValidFrom != nil && day.Before(TruncateDay(*h.ValidFrom)) {
+		return false
+	}
+	if h.ValidUntil != nil && day.After(TruncateDay(*h.ValidUntil)) {
+		return false
+	}
+	return true
+}
+
+// Specificity puntúa la regla para elegir la más concreta cuando varias aplican:
+// una regla de pista prevalece sobre una global y una de temporada sobre una permanente
+func (h *OpeningHours) Specificity() int {
+	score := 0
+	if h.PistaID != nil {
+		score += 2
+	}
+	if h.ValidFrom != nil || h.ValidUntil != nil {
+		score++
+	}
+	return score
+}
+
+// Validate verifica la coherencia de un bloqueo
+func (b *Blackout) Validate() error {
+	if !b.EndTime.After(b.StartTime) {
+		return ErrInvalidBlackout
+	}
+	return nil
+}
+
+// Overlaps indica si el bloqueo se solapa con el rango dado
+func (b *Blackout) Overlaps(start, end time.Time) bool {
+	return b.StartTime.Before(end) && b.EndTime.After(start)
+}
+
+// CheckSlot verifica que una franja encaje en el horario efectivo del día
+func (d *DaySchedule) CheckSlot(start, end time.Time) error {
+	if d.Closed {
+		if d.Reason != "" {
+			return fmt.Errorf("%w (%s)", ErrPistaClosed, d.Reason)
+		}
+		return ErrPistaClosed
+	}
+	if start.Before(d.Open) || end.After(d.Close) {
+		return fmt.Errorf("%w (%s - %s)", ErrOutsideOpeningHours, d.Open.Format("15:04"), d.Close.Format("15:04"))
+	}
+	for _, blackout := range d.Blackouts {
+		if blackout.Overlaps(start, end) {
+			if blackout.Reason != "" {
+				return fmt.Errorf("%w: %s", ErrPistaBlackout, blackout.Reason)
+			}
+			return ErrPistaBlackout
+		}
+	}
+	return nil
+}
+
+// ParseClock convierte "HH:MM" a minutos desde medianoche ("24:00" se admite como cierre)
+func ParseClock(value string) (int, error) {
+	var hours, minutes int
+	if _, err := fmt.Sscanf(value, "%d:%d", &hours, &minutes); err != nil {
+		return 0, ErrInvalidClock
+	}
+	if hours < 0 || minutes < 0 || minutes > 59 || hours > 24 || (hours == 24 && minutes > 0) {
+		return 0, ErrInvalidClock
+	}
+	return hours*60 + minutes, nil
+}
+
+// FormatClock convierte minutos desde medianoche a "HH:MM"
+func FormatClock(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+// TruncateDay devuelve el inicio del día (UTC) de la fecha dada
+func TruncateDay(date time.Time) time.Time {
+	date = date.UTC()
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+// ScheduleRepository define las operaciones de persistencia de horarios, cierres y bloqueos
+type ScheduleRepository interface {
+	// Horarios semanales
+	FindOpeningHours(pistaID *int) ([]OpeningHours, error)
+	FindOpeningHoursByID(id int) (*OpeningHours, error)
+	CreateOpeningHours(hours *OpeningHours) error
+	UpdateOpeningHours(hours *OpeningHours) error
+	DeleteOpeningHours(id int) error
+
+	// Cierres (festivos)
+	FindClosures(from, to time.Time) ([]Closure, error)
+	CreateClosure(closure *Closure) error
+	DeleteClosure(id int) error
+
+	// Bloqueos puntuales
+	FindBlackouts(from, to time.Time) ([]Blackout, error)
+	FindBlackoutsInRange(pistaID int, from, to time.Time) ([]Blackout, error)
+	CreateBlackout(blackout *Blackout) error
+	DeleteBlackout(id int) error
+}
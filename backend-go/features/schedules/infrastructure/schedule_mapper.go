@@ -0,0 +1,94 @@
+package infrastructure
+
+import (
+	"backend-go/features/schedules/domain"
+	"backend-go/shared/database"
+)
+
+// OpeningHoursToEntity convierte database.OpeningHours a domain.OpeningHours
+func OpeningHoursToEntity(m *database.OpeningHours) *domain.OpeningHours {
+	return &domain.OpeningHours{
+		ID:          int(m.ID),
+		PistaID:     toIntPtr(m.PistaID),
+		Weekday:     m.Weekday,
+		OpenMinute:  m.OpenMinute,
+		CloseMinute: m.CloseMinute,
+		ValidFrom:   m.ValidFrom,
+		ValidUntil:  m.ValidUntil,
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.UpdatedAt,
+	}
+}
+
+// OpeningHoursFromEntity convierte domain.OpeningHours a database.OpeningHours
+func OpeningHoursFromEntity(h *domain.OpeningHours) *database.OpeningHours {
+	return &database.OpeningHours{
+		ID:          uint(h.ID),
+		PistaID:     toUintPtr(h.PistaID),
+		Weekday:     h.Weekday,
+		OpenMinute:  h.OpenMinute,
+		CloseMinute: h.CloseMinute,
+		ValidFrom:   h.ValidFrom,
+		ValidUntil:  h.ValidUntil,
+	}
+}
+
+// ClosureToEntity convierte database.ScheduleClosure a domain.Closure
+func ClosureToEntity(m *database.ScheduleClosure) *domain.Closure {
+	return &domain.Closure{
+		ID:        int(m.ID),
+		PistaID:   toIntPtr(m.PistaID),
+		Date:      m.Date,
+		Reason:    m.Reason,
+		CreatedAt: m.CreatedAt,
+	}
+}
+
+// ClosureFromEntity convierte domain.Closure a database.ScheduleClosure
+func ClosureFromEntity(c *domain.Closure) *database.ScheduleClosure {
+	return &database.ScheduleClosure{
+		ID:      uint(c.ID),
+		PistaID: toUintPtr(c.PistaID),
+		Date:    c.Date,
+		Reason:  c.Reason,
+	}
+}
+
+// BlackoutToEntity convierte database.ScheduleBlackout a domain.Blackout
+func BlackoutToEntity(m *database.ScheduleBlackout) *domain.Blackout {
+	return &domain.Blackout{
+		ID:        int(m.ID),
+		PistaID:   toIntPtr(m.PistaID),
+		StartTime: m.StartTime,
+		EndTime:   m.EndTime,
+		Reason:    m.Reason,
+		CreatedAt: m.CreatedAt,
+	}
+}
+
+// BlackoutFromEntity convierte domain.Blackout a database.ScheduleBlackout
+func BlackoutFromEntity(b *domain.Blackout) *database.ScheduleBlackout {
+	return &database.ScheduleBlackout{
+		ID:        uint(b.ID),
+		PistaID:   toUintPtr(b.PistaID),
+		StartTime: b.StartTime,
+		EndTime:   b.EndTime,
+		Reason:    b.Reason,
+	}
+}
+
+func toIntPtr(value *uint) *int {
+	if value == nil {
+		return nil
+	}
+	converted := int(*value)
+	return &converted
+}
+
+func toUintPtr(value *int) *uint {
+	if value == nil {
+		return nil
+	}
+	converted := uint(*value)
+	return &converted
+}
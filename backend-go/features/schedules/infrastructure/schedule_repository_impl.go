@@ -0,0 +1,186 @@
+package infrastructure
+
+import (
+	"backend-go/features/schedules/domain"
+	"backend-go/shared/database"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ScheduleRepositoryImpl implementa domain.ScheduleRepository usando GORM
+type ScheduleRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewScheduleRepository crea una nueva instancia del repositorio
+func NewScheduleRepository(db *gorm.DB) domain.ScheduleRepository {
+	return &ScheduleRepositoryImpl{db: db}
+}
+
+// FindOpeningHours obtiene las reglas de horario (todas o solo las de una pista y las globales)
+func (r *ScheduleRepositoryImpl) FindOpeningHours(pistaID *int) ([]domain.OpeningHours, error) {
+	var models []database.OpeningHours
+	query := r.db.Order("pista_id NULLS FIRST, weekday ASC, valid_from NULLS FIRST")
+	if pistaID != nil {
+		query = query.Where("pista_id = ? OR pista_id IS NULL", *pistaID)
+	}
+	if err := query.Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	hours := make([]domain.OpeningHours, len(models))
+	for i := range models {
+		hours[i] = *OpeningHoursToEntity(&models[i])
+	}
+	return hours, nil
+}
+
+// FindOpeningHoursByID obtiene una regla de horario por ID
+func (r *ScheduleRepositoryImpl) FindOpeningHoursByID(id int) (*domain.OpeningHours, error) {
+	var model database.OpeningHours
+	if err := r.db.First(&model, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrOpeningHoursNotFound
+		}
+		return nil, err
+	}
+	return OpeningHoursToEntity(&model), nil
+}
+
+// CreateOpeningHours crea una regla de horario
+func (r *ScheduleRepositoryImpl) CreateOpeningHours(hours *domain.OpeningHours) error {
+	model := OpeningHoursFromEntity(hours)
+	if err := r.db.Create(model).Error; err != nil {
+		return err
+	}
+
+	hours.ID = int(model.ID)
+	hours.CreatedAt = model.CreatedAt
+	hours.UpdatedAt = model.UpdatedAt
+	return nil
+}
+
+// UpdateOpeningHours actualiza una regla de horario
+func (r *ScheduleRepositoryImpl) UpdateOpeningHours(hours *domain.OpeningHours) error {
+	model := OpeningHoursFromEntity(hours)
+	if err := r.db.Omit("created_at").Save(model).Error; err != nil {
+		return err
+	}
+
+	hours.UpdatedAt = model.UpdatedAt
+	return nil
+}
+
+// DeleteOpeningHours elimina una regla de horario
+func (r *ScheduleRepositoryImpl) DeleteOpeningHours(id int) error {
+	result := r.db.Delete(&database.OpeningHours{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrOpeningHoursNotFound
+	}
+	return nil
+}
+
+// FindClosures obtiene los cierres entre dos fechas (inclusive)
+func (r *ScheduleRepositoryImpl) FindClosures(from, to time.Time) ([]domain.Closure, error) {
+	var models []database.ScheduleClosure
+	if err := r.db.
+		Where("date BETWEEN ? AND ?", from.Format("2006-01-02"), to.Format("2006-01-02")).
+		Order("date ASC").
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	closures := make([]domain.Closure, len(models))
+	for i := range models {
+		closures[i] = *ClosureToEntity(&models[i])
+	}
+	return closures, nil
+}
+
+// CreateClosure crea un cierre
+func (r *ScheduleRepositoryImpl) CreateClosure(closure *domain.Closure) error {
+	model := ClosureFromEntity(closure)
+	if err := r.db.Create(model).Error; err != nil {
+		return err
+	}
+
+	closure.ID = int(model.ID)
+	closure.CreatedAt = model.CreatedAt
+	return nil
+}
+
+// DeleteClosure elimina un cierre
+func (r *ScheduleRepositoryImpl) DeleteClosure(id int) error {
+	result := r.db.Delete(&database.ScheduleClosure{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrClosureNotFound
+	}
+	return nil
+}
+
+// FindBlackouts obtiene los bloqueos que se solapan con el rango dado
+func (r *ScheduleRepositoryImpl) FindBlackouts(from, to time.Time) ([]domain.Blackout, error) {
+	var models []database.ScheduleBlackout
+	if err := r.db.
+		Where("start_time < ? AND end_time > ?", to, from).
+		Order("start_time ASC").
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	blackouts := make([]domain.Blackout, len(models))
+	for i := range models {
+		blackouts[i] = *BlackoutToEntity(&models[i])
+	}
+	return blackouts, nil
+}
+
+// FindBlackoutsInRange obtiene los bloqueos (globales y de la pista) que se solapan con el rango
+func (r *ScheduleRepositoryImpl) FindBlackoutsInRange(pistaID int, from, to time.Time) ([]domain.Blackout, error) {
+	var models []database.ScheduleBlackout
+	if err := r.db.
+		Where("pista_id = ? OR pista_id IS NULL", pistaID).
+		Where("start_time < ? AND end_time > ?", to, from).
+		Order("start_time ASC").
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	blackouts := make([]domain.Blackout, len(models))
+	for i := range models {
+		blackouts[i] = *BlackoutToEntity(&models[i])
+	}
+	return blackouts, nil
+}
+
+// CreateBlackout crea un bloqueo
+func (r *ScheduleRepositoryImpl) CreateBlackout(blackout *domain.Blackout) error {
+	model := BlackoutFromEntity(blackout)
+	if err := r.db.Create(model).Error; err != nil {
+		return err
+	}
+
+	blackout.ID = int(model.ID)
+	blackout.CreatedAt = model.CreatedAt
+	return nil
+}
+
+// DeleteBlackout elimina un bloqueo
+func (r *ScheduleRepositoryImpl) DeleteBlackout(id int) error {
+	result := r.db.Delete(&database.ScheduleBlackout{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrBlackoutNotFound
+	}
+	return nil
+}
@@ -0,0 +1,367 @@
+package presentation
+
+import (
+	"backend-go/features/schedules/application"
+	"backend-go/features/schedules/domain"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// maxScheduleRangeDays limita el rango consultable del horario de una pista
+const maxScheduleRangeDays = 31
+
+type ScheduleHandler struct {
+	service *application.ScheduleService
+}
+
+func NewScheduleHandler(service *application.ScheduleService) *ScheduleHandler {
+	return &ScheduleHandler{service: service}
+}
+
+// GetPistaSchedule maneja GET /api/schedules/pista/:pistaId
+// @Summary Horario efectivo de una pista (apertura, cierres y bloqueos) por día
+// @Tags schedules
+// @Produce json
+// @Param pistaId path int true "ID de la pista"
+// @Param from query string false "Fecha inicial (YYYY-MM-DD, por defecto hoy)"
+// @Param to query string false "Fecha final inclusive (YYYY-MM-DD, por defecto from)"
+// @Success 200 {array} DayScheduleResponse
+// @Router /api/schedules/pista/{pistaId} [get]
+func (h *ScheduleHandler) GetPistaSchedule(c *fiber.Ctx) error {
+	pistaID, err := strconv.Atoi(c.Params("pistaId"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "ID de pista inválido"})
+	}
+
+	from, to, err := parseRange(c, time.Now().UTC())
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	if to.Sub(from) >= maxScheduleRangeDays*24*time.Hour {
+		return c.Status(400).JSON(fiber.Map{"error": "el rango de fechas no puede superar los 31 días"})
+	}
+
+	schedules, err := h.service.GetDaySchedules(pistaID, from, to)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	responses := make([]DayScheduleResponse, len(schedules))
+	for i := range schedules {
+		responses[i] = ToDayScheduleResponse(&schedules[i])
+	}
+
+	return c.JSON(responses)
+}
+
+// GetOpeningHours maneja GET /api/schedules/opening-hours
+// @Summary Listar reglas de horario semanal
+// @Tags schedules
+// @Produce json
+// @Param pistaId query int false "Filtrar por pista (incluye las reglas globales)"
+// @Success 200 {array} OpeningHoursResponse
+// @Router /api/schedules/opening-hours [get]
+func (h *ScheduleHandler) GetOpeningHours(c *fiber.Ctx) error {
+	var pistaID *int
+	if raw := c.Query("pistaId"); raw != "" {
+		id, err := strconv.Atoi(raw)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": "ID de pista inválido"})
+		}
+		pistaID = &id
+	}
+
+	hours, err := h.service.GetOpeningHours(pistaID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	responses := make([]OpeningHoursResponse, len(hours))
+	for i := range hours {
+		responses[i] = ToOpeningHoursResponse(&hours[i])
+	}
+
+	return c.JSON(responses)
+}
+
+// CreateOpeningHours maneja POST /api/schedules/opening-hours
+// @Summary Crear regla de horario semanal (global o por pista, opcionalmente de temporada)
+// @Tags schedules
+// @Accept json
+// @Produce json
+// @Param hours body OpeningHoursRequest true "Regla de horario"
+// @Success 201 {object} OpeningHoursResponse
+// @Router /api/schedules/opening-hours [post]
+func (h *ScheduleHandler) CreateOpeningHours(c *fiber.Ctx) error {
+	var req OpeningHoursRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Datos inválidos"})
+	}
+
+	hours, err := OpeningHoursRequestToDomain(&req)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	if err := h.service.CreateOpeningHours(hours); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.Status(201).JSON(ToOpeningHoursResponse(hours))
+}
+
+// UpdateOpeningHours maneja PUT /api/schedules/opening-hours/:id
+// @Summary Actualizar regla de horario semanal
+// @Tags schedules
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de la regla"
+// @Param hours body OpeningHoursRequest true "Regla de horario"
+// @Success 200 {object} OpeningHoursResponse
+// @Router /api/schedules/opening-hours/{id} [put]
+func (h *ScheduleHandler) UpdateOpeningHours(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "ID inválido"})
+	}
+
+	var req OpeningHoursRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Datos inválidos"})
+	}
+
+	hours, err := OpeningHoursRequestToDomain(&req)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	hours.ID = id
+
+	if err := h.service.UpdateOpeningHours(hours); err != nil {
+		if errors.Is(err, domain.ErrOpeningHoursNotFound) {
+			return c.Status(404).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(ToOpeningHoursResponse(hours))
+}
+
+// DeleteOpeningHours maneja DELETE /api/schedules/opening-hours/:id
+// @Summary Eliminar regla de horario semanal
+// @Tags schedules
+// @Param id path int true "ID de la regla"
+// @Success 204
+// @Router /api/schedules/opening-hours/{id} [delete]
+func (h *ScheduleHandler) DeleteOpeningHours(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "ID inválido"})
+	}
+
+	if err := h.service.DeleteOpeningHours(id); err != nil {
+		if errors.Is(err, domain.ErrOpeningHoursNotFound) {
+			return c.Status(404).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.SendStatus(204)
+}
+
+// GetClosures maneja GET /api/schedules/closures
+// @Summary Listar días de cierre en un rango
+// @Tags schedules
+// @Produce json
+// @Param from query string false "Fecha inicial (YYYY-MM-DD, por defecto hoy)"
+// @Param to query string false "Fecha final inclusive (YYYY-MM-DD, por defecto un año después)"
+// @Success 200 {array} ClosureResponse
+// @Router /api/schedules/closures [get]
+func (h *ScheduleHandler) GetClosures(c *fiber.Ctx) error {
+	from, to, err := parseRangeWithDefaultYear(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	closures, err := h.service.GetClosures(from, to)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	responses := make([]ClosureResponse, len(closures))
+	for i := range closures {
+		responses[i] = ToClosureResponse(&closures[i])
+	}
+
+	return c.JSON(responses)
+}
+
+// CreateClosure maneja POST /api/schedules/closures
+// @Summary Registrar un día de cierre (festivo), global o de una pista
+// @Tags schedules
+// @Accept json
+// @Produce json
+// @Param closure body CreateClosureRequest true "Día de cierre"
+// @Success 201 {object} ClosureResponse
+// @Router /api/schedules/closures [post]
+func (h *ScheduleHandler) CreateClosure(c *fiber.Ctx) error {
+	var req CreateClosureRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Datos inválidos"})
+	}
+
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Formato de fecha inválido (usar YYYY-MM-DD)"})
+	}
+
+	closure := &domain.Closure{PistaID: req.PistaID, Date: date, Reason: req.Reason}
+	if err := h.service.CreateClosure(closure); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.Status(201).JSON(ToClosureResponse(closure))
+}
+
+// DeleteClosure maneja DELETE /api/schedules/closures/:id
+// @Summary Eliminar un día de cierre
+// @Tags schedules
+// @Param id path int true "ID del cierre"
+// @Success 204
+// @Router /api/schedules/closures/{id} [delete]
+func (h *ScheduleHandler) DeleteClosure(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "ID inválido"})
+	}
+
+	if err := h.service.DeleteClosure(id); err != nil {
+		if errors.Is(err, domain.ErrClosureNotFound) {
+			return c.Status(404).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.SendStatus(204)
+}
+
+// GetBlackouts maneja GET /api/schedules/blackouts
+// @Summary Listar bloqueos puntuales en un rango
+// @Tags schedules
+// @Produce json
+// @Param from query string false "Fecha inicial (YYYY-MM-DD, por defecto hoy)"
+// @Param to query string false "Fecha final inclusive (YYYY-MM-DD, por defecto un año después)"
+// @Success 200 {array} BlackoutResponse
+// @Router /api/schedules/blackouts [get]
+func (h *ScheduleHandler) GetBlackouts(c *fiber.Ctx) error {
+	from, to, err := parseRangeWithDefaultYear(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	blackouts, err := h.service.GetBlackouts(from, to.AddDate(0, 0, 1))
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	responses := make([]BlackoutResponse, len(blackouts))
+	for i := range blackouts {
+		responses[i] = ToBlackoutResponse(&blackouts[i])
+	}
+
+	return c.JSON(responses)
+}
+
+// CreateBlackout maneja POST /api/schedules/blackouts
+// @Summary Registrar un bloqueo puntual (mantenimiento), global o de una pista
+// @Tags schedules
+// @Accept json
+// @Produce json
+// @Param blackout body CreateBlackoutRequest true "Bloqueo"
+// @Success 201 {object} BlackoutResponse
+// @Router /api/schedules/blackouts [post]
+func (h *ScheduleHandler) CreateBlackout(c *fiber.Ctx) error {
+	var req CreateBlackoutRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Datos inválidos"})
+	}
+
+	blackout := &domain.Blackout{
+		PistaID:   req.PistaID,
+		StartTime: req.StartTime.UTC(),
+		EndTime:   req.EndTime.UTC(),
+		Reason:    req.Reason,
+	}
+	if err := h.service.CreateBlackout(blackout); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.Status(201).JSON(ToBlackoutResponse(blackout))
+}
+
+// DeleteBlackout maneja DELETE /api/schedules/blackouts/:id
+// @Summary Eliminar un bloqueo puntual
+// @Tags schedules
+// @Param id path int true "ID del bloqueo"
+// @Success 204
+// @Router /api/schedules/blackouts/{id} [delete]
+func (h *ScheduleHandler) DeleteBlackout(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "ID inválido"})
+	}
+
+	if err := h.service.DeleteBlackout(id); err != nil {
+		if errors.Is(err, domain.ErrBlackoutNotFound) {
+			return c.Status(404).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.SendStatus(204)
+}
+
+// parseRange lee from/to de la query; to por defecto es igual a from
+func parseRange(c *fiber.Ctx, defaultFrom time.Time) (time.Time, time.Time, error) {
+	var query RangeQuery
+	if err := c.QueryParser(&query); err != nil {
+		return time.Time{}, time.Time{}, errors.New("parámetros inválidos")
+	}
+
+	from := domain.TruncateDay(defaultFrom)
+	if query.From != "" {
+		parsed, err := time.Parse("2006-01-02", query.From)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("formato de fecha inválido en 'from' (usar YYYY-MM-DD)")
+		}
+		from = parsed
+	}
+
+	to := from
+	if query.To != "" {
+		parsed, err := time.Parse("2006-01-02", query.To)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("formato de fecha inválido en 'to' (usar YYYY-MM-DD)")
+		}
+		to = parsed
+	}
+
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, errors.New("la fecha final debe ser igual o posterior a la inicial")
+	}
+	return from, to, nil
+}
+
+// parseRangeWithDefaultYear lee from/to de la query; sin 'to' se consulta un año desde from
+func parseRangeWithDefaultYear(c *fiber.Ctx) (time.Time, time.Time, error) {
+	from, to, err := parseRange(c, time.Now().UTC())
+	if err != nil {
+		return from, to, err
+	}
+	if c.Query("to") == "" {
+		to = from.AddDate(1, 0, 0)
+	}
+	return from, to, nil
+}
@@ -0,0 +1,77 @@
+package presentation
+
+import (
+	"backend-go/features/schedules/domain"
+	"time"
+)
+
+// OpeningHoursRequest DTO para crear o actualizar una regla de horario semanal
+type OpeningHoursRequest struct {
+	PistaID    *int    `json:"pistaId"`                        // nil = todas las pistas
+	Weekday    int     `json:"weekday" validate:"min=0,max=6"` // 0 = domingo ... 6 = sábado
+	OpensAt    string  `json:"opensAt" validate:"required"`    // HH:MM
+	ClosesAt   string  `json:"closesAt" validate:"required"`   // HH:MM (24:00 permitido)
+	ValidFrom  *string `json:"validFrom"`                      // YYYY-MM-DD (temporada, opcional)
+	ValidUntil *string `json:"validUntil"`                     // YYYY-MM-DD (inclusive, opcional)
+}
+
+// CreateClosureRequest DTO para registrar un día de cierre
+type CreateClosureRequest struct {
+	PistaID *int   `json:"pistaId"`                  // nil = todo el polideportivo
+	Date    string `json:"date" validate:"required"` // YYYY-MM-DD
+	Reason  string `json:"reason"`
+}
+
+// CreateBlackoutRequest DTO para registrar un bloqueo puntual
+type CreateBlackoutRequest struct {
+	PistaID   *int      `json:"pistaId"` // nil = todas las pistas
+	StartTime time.Time `json:"startTime" validate:"required"`
+	EndTime   time.Time `json:"endTime" validate:"required"`
+	Reason    string    `json:"reason"`
+}
+
+// RangeQuery parámetros de rango de fechas (query string)
+type RangeQuery struct {
+	From string `query:"from"` // YYYY-MM-DD
+	To   string `query:"to"`   // YYYY-MM-DD (inclusive)
+}
+
+// OpeningHoursRequestToDomain convierte OpeningHoursRequest a domain.OpeningHours
+func OpeningHoursRequestToDomain(req *OpeningHoursRequest) (*domain.OpeningHours, error) {
+	openMinute, err := domain.ParseClock(req.OpensAt)
+	if err != nil {
+		return nil, err
+	}
+	closeMinute, err := domain.ParseClock(req.ClosesAt)
+	if err != nil {
+		return nil, err
+	}
+
+	hours := &domain.OpeningHours{
+		PistaID:     req.PistaID,
+		Weekday:     req.Weekday,
+		OpenMinute:  openMinute,
+		CloseMinute: closeMinute,
+	}
+
+	if hours.ValidFrom, err = parseOptionalDate(req.ValidFrom); err != nil {
+		return nil, err
+	}
+	if hours.ValidUntil, err = parseOptionalDate(req.ValidUntil); err != nil {
+		return nil, err
+	}
+
+	return hours, nil
+}
+
+// parseOptionalDate convierte una fecha YYYY-MM-DD opcional
+func parseOptionalDate(value *string) (*time.Time, error) {
+	if value == nil || *value == "" {
+		return nil, nil
+	}
+	date, err := time.Parse("2006-01-02", *value)
+	if err != nil {
+		return nil, err
+	}
+	return &date, nil
+}
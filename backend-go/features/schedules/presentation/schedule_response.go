@@ -0,0 +1,110 @@
+package presentation
+
+import (
+	"backend-go/features/schedules/domain"
+	"time"
+)
+
+// OpeningHoursResponse DTO de una regla de horario semanal
+type OpeningHoursResponse struct {
+	ID         int       `json:"id"`
+	PistaID    *int      `json:"pistaId"`
+	Weekday    int       `json:"weekday"`
+	OpensAt    string    `json:"opensAt"`
+	ClosesAt   string    `json:"closesAt"`
+	ValidFrom  *string   `json:"validFrom"`
+	ValidUntil *string   `json:"validUntil"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// ClosureResponse DTO de un día de cierre
+type ClosureResponse struct {
+	ID      int    `json:"id"`
+	PistaID *int   `json:"pistaId"`
+	Date    string `json:"date"`
+	Reason  string `json:"reason"`
+}
+
+// BlackoutResponse DTO de un bloqueo puntual
+type BlackoutResponse struct {
+	ID        int       `json:"id"`
+	PistaID   *int      `json:"pistaId"`
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime"`
+	Reason    string    `json:"reason"`
+}
+
+// DayScheduleResponse DTO del horario efectivo de una pista en un día
+type DayScheduleResponse struct {
+	PistaID   int                `json:"pistaId"`
+	Date      string             `json:"date"`
+	Closed    bool               `json:"closed"`
+	Reason    string             `json:"reason,omitempty"`
+	OpensAt   *time.Time         `json:"opensAt,omitempty"`
+	ClosesAt  *time.Time         `json:"closesAt,omitempty"`
+	Blackouts []BlackoutResponse `json:"blackouts"`
+}
+
+// ToOpeningHoursResponse convierte domain.OpeningHours a DTO
+func ToOpeningHoursResponse(hours *domain.OpeningHours) OpeningHoursResponse {
+	return OpeningHoursResponse{
+		ID:         hours.ID,
+		PistaID:    hours.PistaID,
+		Weekday:    hours.Weekday,
+		OpensAt:    domain.FormatClock(hours.OpenMinute),
+		ClosesAt:   domain.FormatClock(hours.CloseMinute),
+		ValidFrom:  formatOptionalDate(hours.ValidFrom),
+		ValidUntil: formatOptionalDate(hours.ValidUntil),
+		CreatedAt:  hours.CreatedAt,
+		UpdatedAt:  hours.UpdatedAt,
+	}
+}
+
+// ToClosureResponse convierte domain.Closure a DTO
+func ToClosureResponse(closure *domain.Closure) ClosureResponse {
+	return ClosureResponse{
+		ID:      closure.ID,
+		PistaID: closure.PistaID,
+		Date:    closure.Date.Format("2006-01-02"),
+		Reason:  closure.Reason,
+	}
+}
+
+// ToBlackoutResponse convierte domain.Blackout a DTO
+func ToBlackoutResponse(blackout *domain.Blackout) BlackoutResponse {
+	return BlackoutResponse{
+		ID:        blackout.ID,
+		PistaID:   blackout.PistaID,
+		StartTime: blackout.StartTime,
+		EndTime:   blackout.EndTime,
+		Reason:    blackout.Reason,
+	}
+}
+
+// ToDayScheduleResponse convierte domain.DaySchedule a DTO
+func ToDayScheduleResponse(schedule *domain.DaySchedule) DayScheduleResponse {
+	response := DayScheduleResponse{
+		PistaID:   schedule.PistaID,
+		Date:      schedule.Date.Format("2006-01-02"),
+		Closed:    schedule.Closed,
+		Reason:    schedule.Reason,
+		Blackouts: make([]BlackoutResponse, len(schedule.Blackouts)),
+	}
+	if !schedule.Closed {
+		response.OpensAt = &schedule.Open
+		response.ClosesAt = &schedule.Close
+	}
+	for i := range schedule.Blackouts {
+		response.Blackouts[i] = ToBlackoutResponse(&schedule.Blackouts[i])
+	}
+	return response
+}
+
+func formatOptionalDate(date *time.Time) *string {
+	if date == nil {
+		return nil
+	}
+	formatted := date.Format("2006-01-02")
+	return &formatted
+}
@@ -0,0 +1,39 @@
+package presentation
+
+import (
+	"backend-go/shared/middleware"
+	"backend-go/shared/security"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ======================================================================================
+// SCHEDULE ROUTES
+// Público: GET /pista/:pistaId (horario efectivo por día)
+// Admin: CRUD /opening-hours, /closures y /blackouts
+// ======================================================================================
+
+// RegisterRoutes registra las rutas de horarios
+func RegisterRoutes(app *fiber.App, handler *ScheduleHandler, jwtService security.JWTService) {
+	// Rutas públicas
+	public := app.Group("/api/schedules")
+	public.Get("/pista/:pistaId", handler.GetPistaSchedule)
+
+	// Rutas protegidas - Solo ADMIN y GESTOR
+	admin := app.Group("/api/schedules")
+	admin.Use(middleware.JWTMiddleware(jwtService))
+	admin.Use(middleware.RequireRoleByName("ADMIN", "GESTOR"))
+
+	admin.Get("/opening-hours", handler.GetOpeningHours)
+	admin.Post("/opening-hours", handler.CreateOpeningHours)
+	admin.Put("/opening-hours/:id", handler.UpdateOpeningHours)
+	admin.Delete("/opening-hours/:id", handler.DeleteOpeningHours)
+
+	admin.Get("/closures", handler.GetClosures)
+	admin.Post("/closures", handler.CreateClosure)
+	admin.Delete("/closures/:id", handler.DeleteClosure)
+
+	admin.Get("/blackouts", handler.GetBlackouts)
+	admin.Post("/blackouts", handler.CreateBlackout)
+	admin.Delete("/blackouts/:id", handler.DeleteBlackout)
+}
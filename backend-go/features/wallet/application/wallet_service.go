@@ -0,0 +1,149 @@
+package application
+
+import (
+	"backend-go/features/wallet/domain"
+	"backend-go/shared/pagination"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// WalletService maneja el monedero (saldo prepago) de los usuarios. Además de las recargas,
+// implementa el libro que usa el módulo de pagos para cobrar reservas, clases y cuotas con saldo.
+type WalletService struct {
+	repo domain.WalletRepository
+}
+
+// NewWalletService crea una nueva instancia del servicio
+func NewWalletService(repo domain.WalletRepository) *WalletService {
+	return &WalletService{repo: repo}
+}
+
+// TopUp recarga el monedero del usuario (recepción: efectivo o datáfono). createdBy es el
+// ADMIN/GESTOR que registra la recarga.
+func (s *WalletService) TopUp(userID uuid.UUID, amountCents int, description string, createdBy uuid.UUID) (*domain.WalletEntry, error) {
+	if amountCents <= 0 {
+		return nil, domain.ErrInvalidAmount
+	}
+	if description == "" {
+		description = "Recarga de saldo"
+	}
+
+	return s.repo.Append(userID, func(ledger domain.Ledger) (*domain.WalletEntry, error) {
+		return &domain.WalletEntry{
+			Type:        domain.EntryTypeTopUp,
+			AmountCents: amountCents,
+			Reference:   domain.NewReference(),
+			Description: description,
+			CreatedBy:   &createdBy,
+		}, nil
+	})
+}
+
+// Debit cobra del saldo del usuario y devuelve la referencia del cargo. Con idempotencyKey,
+// repetir la llamada devuelve el mismo cargo en lugar de cobrar otra vez.
+func (s *WalletService) Debit(userID uuid.UUID, amountCents int, description, idempotencyKey string) (string, error) {
+	if amountCents <= 0 {
+		return "", domain.ErrInvalidAmount
+	}
+
+	entry, err := s.repo.Append(userID, func(ledger domain.Ledger) (*domain.WalletEntry, error) {
+		debit := &domain.WalletEntry{
+			Type:        domain.EntryTypeDebit,
+			AmountCents: -amountCents,
+			Reference:   domain.NewReference(),
+			Description: description,
+		}
+		if idempotencyKey != "" {
+			existing, err := ledger.FindByIdempotencyKey(idempotencyKey)
+			if err == nil {
+				return existing, nil
+			}
+			if !errors.Is(err, domain.ErrEntryNotFound) {
+				return nil, err
+			}
+			debit.IdempotencyKey = &idempotencyKey
+		}
+
+		if ledger.BalanceCents() < amountCents {
+			return nil, fmt.Errorf("%w (saldo %d, importe %d)", domain.ErrInsufficientBalance, ledger.BalanceCents(), amountCents)
+		}
+		return debit, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return entry.Reference, nil
+}
+
+// RefundDebit devuelve al monedero (total o parcialmente) un cargo anterior y devuelve la
+// referencia de la devolución. Lo devuelto nunca supera lo cargado.
+func (s *WalletService) RefundDebit(debitReference string, amountCents int) (string, error) {
+	if amountCents <= 0 {
+		return "", domain.ErrInvalidAmount
+	}
+
+	debit, err := s.repo.FindByReference(debitReference)
+	if err != nil {
+		return "", err
+	}
+	if debit.Type != domain.EntryTypeDebit {
+		return "", domain.ErrEntryNotFound
+	}
+
+	entry, err := s.repo.Append(debit.UserID, func(ledger domain.Ledger) (*domain.WalletEntry, error) {
+		refunded, err := ledger.RefundedCents(debit.ID)
+		if err != nil {
+			return nil, err
+		}
+		if refunded+amountCents > -debit.AmountCents {
+			return nil, domain.ErrRefundExceedsDebit
+		}
+
+		debitID := debit.ID
+		return &domain.WalletEntry{
+			Type:           domain.EntryTypeRefund,
+			AmountCents:    amountCents,
+			Reference:      domain.NewReference(),
+			RelatedEntryID: &debitID,
+			Description:    "Devolución: " + debit.Description,
+		}, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return entry.Reference, nil
+}
+
+// CreditRefund abona en el monedero el reembolso de un pago cobrado con otro medio (tarjeta)
+func (s *WalletService) CreditRefund(userID uuid.UUID, amountCents int, paymentID uint) (string, error) {
+	if amountCents <= 0 {
+		return "", domain.ErrInvalidAmount
+	}
+
+	entry, err := s.repo.Append(userID, func(ledger domain.Ledger) (*domain.WalletEntry, error) {
+		return &domain.WalletEntry{
+			Type:        domain.EntryTypeRefund,
+			AmountCents: amountCents,
+			Reference:   domain.NewReference(),
+			PaymentID:   &paymentID,
+			Description: fmt.Sprintf("Reembolso al monedero del pago #%d", paymentID),
+		}, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return entry.Reference, nil
+}
+
+// GetBalance obtiene el saldo del usuario
+func (s *WalletService) GetBalance(userID uuid.UUID) (int, error) {
+	return s.repo.GetBalance(userID)
+}
+
+// GetEntries obtiene los movimientos del usuario paginados (params.Status filtra por tipo)
+func (s *WalletService) GetEntries(userID uuid.UUID, params pagination.PaginationParams) ([]domain.WalletEntry, int64, error) {
+	params.Validate()
+	return s.repo.GetEntries(userID, params)
+}
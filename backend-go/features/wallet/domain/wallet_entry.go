@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"backend-go/shared/pagination"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Errores de dominio
+var (
+	ErrInvalidAmount       = errors.New("importe inválido")
+	ErrUserNotFound        = errors.New("usuario no encontrado")
+	ErrInsufficientBalance = errors.New("saldo insuficiente en el monedero")
+	ErrEntryNotFound       = errors.New("movimiento del monedero no encontrado")
+	ErrRefundExceedsDebit  = errors.New("la devolución supera el importe pagado con el monedero")
+)
+
+// Tipos de movimiento
+const (
+	EntryTypeTopUp  = "TOP_UP" // Recarga de saldo (p. ej. en recepción)
+	EntryTypeDebit  = "DEBIT"  // Pago con saldo
+	EntryTypeRefund = "REFUND" // Devolución al monedero (de un pago con saldo o con tarjeta)
+)
+
+// WalletEntry es un movimiento del libro del monedero de un usuario. El libro es de solo
+// inserción: el saldo es la suma de AmountCents y nunca se modifica un movimiento.
+type WalletEntry struct {
+	ID                uint
+	UserID            uuid.UUID
+	Type              string
+	AmountCents       int // Positivo abona, negativo carga
+	BalanceAfterCents int // Saldo tras el movimiento
+	Reference         string
+	RelatedEntryID    *uint // REFUND: cargo (DEBIT) que se devuelve
+	PaymentID         *uint // REFUND: pago con tarjeta reembolsado al monedero
+	IdempotencyKey    *string
+	Description       string
+	CreatedBy         *uuid.UUID // Quién hizo la recarga (ADMIN/GESTOR)
+	CreatedAt         time.Time
+}
+
+// NewReference genera la referencia pública del movimiento (se guarda como PaymentIntentID
+// de los pagos cobrados con el monedero)
+func NewReference() string {
+	return "wal_" + uuid.NewString()
+}
+
+// Ledger es el libro del monedero de un usuario con el monedero bloqueado: las consultas
+// reflejan todos los movimientos confirmados y ningún otro movimiento puede entrar a la vez
+type Ledger interface {
+	BalanceCents() int
+	// RefundedCents suma lo ya devuelto de un cargo
+	RefundedCents(debitID uint) (int, error)
+	FindByIdempotencyKey(key string) (*WalletEntry, error)
+}
+
+// EntryFunc decide el movimiento a añadir a partir del libro bloqueado. Si devuelve un
+// movimiento ya guardado (ID != 0, p. ej. un reintento con la misma clave) no se añade nada.
+type EntryFunc func(ledger Ledger) (*WalletEntry, error)
+
+// WalletRepository define el contrato de persistencia del monedero
+type WalletRepository interface {
+	// Append bloquea el monedero del usuario, ejecuta build y añade el movimiento con su saldo
+	// resultante en la misma transacción. Dos cargos simultáneos se serializan.
+	Append(userID uuid.UUID, build EntryFunc) (*WalletEntry, error)
+	GetBalance(userID uuid.UUID) (int, error)
+	GetEntries(userID uuid.UUID, params pagination.PaginationParams) ([]WalletEntry, int64, error)
+	FindByReference(reference string) (*WalletEntry, error)
+}
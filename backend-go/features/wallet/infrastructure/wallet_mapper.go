@@ -0,0 +1,47 @@
+package infrastructure
+
+import (
+	"backend-go/features/wallet/domain"
+	"backend-go/shared/database"
+)
+
+func toEntity(model *database.WalletEntry) *domain.WalletEntry {
+	return &domain.WalletEntry{
+		ID:                model.ID,
+		UserID:            model.UserID,
+		Type:              model.Type,
+		AmountCents:       model.AmountCents,
+		BalanceAfterCents: model.BalanceAfterCents,
+		Reference:         model.Reference,
+		RelatedEntryID:    model.RelatedEntryID,
+		PaymentID:         model.PaymentID,
+		IdempotencyKey:    model.IdempotencyKey,
+		Description:       model.Description,
+		CreatedBy:         model.CreatedBy,
+		CreatedAt:         model.CreatedAt,
+	}
+}
+
+func toModel(entry *domain.WalletEntry) *database.WalletEntry {
+	return &database.WalletEntry{
+		ID:                entry.ID,
+		UserID:            entry.UserID,
+		Type:              entry.Type,
+		AmountCents:       entry.AmountCents,
+		BalanceAfterCents: entry.BalanceAfterCents,
+		Reference:         entry.Reference,
+		RelatedEntryID:    entry.RelatedEntryID,
+		PaymentID:         entry.PaymentID,
+		IdempotencyKey:    entry.IdempotencyKey,
+		Description:       entry.Description,
+		CreatedBy:         entry.CreatedBy,
+	}
+}
+
+func toEntities(models []database.WalletEntry) []domain.WalletEntry {
+	entries := make([]domain.WalletEntry, len(models))
+	for i := range models {
+		entries[i] = *toEntity(&models[i])
+	}
+	return entries
+}
@@ -0,0 +1,147 @@
+package infrastructure
+
+import (
+	"backend-go/features/wallet/domain"
+	"backend-go/shared/database"
+	"backend-go/shared/pagination"
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// WalletRepositoryImpl implementa domain.WalletRepository usando GORM
+type WalletRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewWalletRepository crea una nueva instancia del repositorio
+func NewWalletRepository(db *gorm.DB) domain.WalletRepository {
+	return &WalletRepositoryImpl{db: db}
+}
+
+// Append bloquea la fila del usuario (FOR NO KEY UPDATE: no bloquea las tablas que la
+// referencian), calcula el saldo desde el libro y añade el movimiento
+func (r *WalletRepositoryImpl) Append(userID uuid.UUID, build domain.EntryFunc) (*domain.WalletEntry, error) {
+	var entry *domain.WalletEntry
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var user database.User
+		if err := tx.Clauses(clause.Locking{Strength: "NO KEY UPDATE"}).
+			Select("id").
+			First(&user, "id = ?", userID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domain.ErrUserNotFound
+			}
+			return err
+		}
+
+		balance, err := sumBalance(tx, userID)
+		if err != nil {
+			return err
+		}
+
+		if entry, err = build(&lockedLedger{tx: tx, userID: userID, balance: balance}); err != nil {
+			return err
+		}
+		if entry.ID != 0 {
+			return nil
+		}
+
+		entry.UserID = userID
+		entry.BalanceAfterCents = balance + entry.AmountCents
+		if entry.BalanceAfterCents < 0 {
+			return domain.ErrInsufficientBalance
+		}
+
+		model := toModel(entry)
+		if err := tx.Create(model).Error; err != nil {
+			return err
+		}
+		entry.ID = model.ID
+		entry.CreatedAt = model.CreatedAt
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// GetBalance calcula el saldo del usuario como la suma de sus movimientos
+func (r *WalletRepositoryImpl) GetBalance(userID uuid.UUID) (int, error) {
+	return sumBalance(r.db, userID)
+}
+
+// GetEntries obtiene los movimientos del usuario, del más reciente al más antiguo
+func (r *WalletRepositoryImpl) GetEntries(userID uuid.UUID, params pagination.PaginationParams) ([]domain.WalletEntry, int64, error) {
+	query := r.db.Model(&database.WalletEntry{}).Where("user_id = ?", userID)
+	if params.Status != "" {
+		query = query.Where("type = ?", params.Status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var models []database.WalletEntry
+	if err := query.Order("id DESC").Offset(params.GetOffset()).Limit(params.Limit).Find(&models).Error; err != nil {
+		return nil, 0, err
+	}
+	return toEntities(models), total, nil
+}
+
+// FindByReference obtiene un movimiento por su referencia (wal_...)
+func (r *WalletRepositoryImpl) FindByReference(reference string) (*domain.WalletEntry, error) {
+	var model database.WalletEntry
+	if err := r.db.Where("reference = ?", reference).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrEntryNotFound
+		}
+		return nil, err
+	}
+	return toEntity(&model), nil
+}
+
+func sumBalance(db *gorm.DB, userID uuid.UUID) (int, error) {
+	var balance int
+	err := db.Model(&database.WalletEntry{}).
+		Where("user_id = ?", userID).
+		Select("COALESCE(SUM(amount_cents), 0)").
+		Scan(&balance).Error
+	return balance, err
+}
+
+// lockedLedger consulta el libro dentro de la transacción de Append
+type lockedLedger struct {
+	tx      *gorm.DB
+	userID  uuid.UUID
+	balance int
+}
+
+func (l *lockedLedger) BalanceCents() int {
+	return l.balance
+}
+
+func (l *lockedLedger) RefundedCents(debitID uint) (int, error) {
+	var refunded int
+	err := l.tx.Model(&database.WalletEntry{}).
+		Where("related_entry_id = ? AND type = ?", debitID, domain.EntryTypeRefund).
+		Select("COALESCE(SUM(amount_cents), 0)").
+		Scan(&refunded).Error
+	return refunded, err
+}
+
+func (l *lockedLedger) FindByIdempotencyKey(key string) (*domain.WalletEntry, error) {
+	var model database.WalletEntry
+	if err := l.tx.Where("user_id = ? AND idempotency_key = ?", l.userID, key).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrEntryNotFound
+		}
+		return nil, err
+	}
+	return toEntity(&model), nil
+}
@@ -0,0 +1,163 @@
+package presentation
+
+import (
+	"backend-go/features/wallet/application"
+	"backend-go/features/wallet/domain"
+	"backend-go/shared/pagination"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type WalletHandler struct {
+	service *application.WalletService
+}
+
+func NewWalletHandler(service *application.WalletService) *WalletHandler {
+	return &WalletHandler{service: service}
+}
+
+// GetMyWallet maneja GET /api/wallet
+// @Summary Saldo de mi monedero
+// @Tags wallet
+// @Produce json
+// @Success 200 {object} WalletResponse
+// @Router /api/wallet [get]
+func (h *WalletHandler) GetMyWallet(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uuid.UUID)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Usuario no autenticado"})
+	}
+	return h.balance(c, userID)
+}
+
+// GetMyEntries maneja GET /api/wallet/entries
+// @Summary Movimientos de mi monedero
+// @Tags wallet
+// @Produce json
+// @Param page query int false "Número de página"
+// @Param limit query int false "Elementos por página"
+// @Param status query string false "Filtrar por tipo (TOP_UP, DEBIT, REFUND)"
+// @Success 200 {object} pagination.PaginatedResponse
+// @Router /api/wallet/entries [get]
+func (h *WalletHandler) GetMyEntries(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uuid.UUID)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Usuario no autenticado"})
+	}
+	return h.entries(c, userID)
+}
+
+// GetUserWallet maneja GET /api/wallet/users/:user_id
+// @Summary Saldo del monedero de un usuario (ADMIN/GESTOR)
+// @Tags wallet
+// @Produce json
+// @Param user_id path string true "User ID (UUID)"
+// @Success 200 {object} WalletResponse
+// @Router /api/wallet/users/{user_id} [get]
+func (h *WalletHandler) GetUserWallet(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Params("user_id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid user ID"})
+	}
+	return h.balance(c, userID)
+}
+
+// GetUserEntries maneja GET /api/wallet/users/:user_id/entries
+// @Summary Movimientos del monedero de un usuario (ADMIN/GESTOR)
+// @Tags wallet
+// @Produce json
+// @Param user_id path string true "User ID (UUID)"
+// @Param page query int false "Número de página"
+// @Param limit query int false "Elementos por página"
+// @Param status query string false "Filtrar por tipo (TOP_UP, DEBIT, REFUND)"
+// @Success 200 {object} pagination.PaginatedResponse
+// @Router /api/wallet/users/{user_id}/entries [get]
+func (h *WalletHandler) GetUserEntries(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Params("user_id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid user ID"})
+	}
+	return h.entries(c, userID)
+}
+
+// TopUp maneja POST /api/wallet/users/:user_id/top-up
+// @Summary Recargar el monedero de un usuario (recepción, ADMIN/GESTOR)
+// @Tags wallet
+// @Accept json
+// @Produce json
+// @Param user_id path string true "User ID (UUID)"
+// @Param Idempotency-Key header string false "Clave para reintentar sin recargar dos veces"
+// @Param topUp body TopUpRequest true "Importe de la recarga"
+// @Success 201 {object} WalletEntryResponse
+// @Router /api/wallet/users/{user_id}/top-up [post]
+func (h *WalletHandler) TopUp(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Params("user_id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid user ID"})
+	}
+	staffID, ok := c.Locals("userID").(uuid.UUID)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Usuario no autenticado"})
+	}
+
+	var req TopUpRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request"})
+	}
+
+	entry, err := h.service.TopUp(userID, req.AmountCents, req.Description, staffID)
+	if err != nil {
+		return walletError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(ToWalletEntryResponse(entry))
+}
+
+func (h *WalletHandler) balance(c *fiber.Ctx, userID uuid.UUID) error {
+	balance, err := h.service.GetBalance(userID)
+	if err != nil {
+		return walletError(c, err)
+	}
+	return c.JSON(ToWalletResponse(userID, balance))
+}
+
+func (h *WalletHandler) entries(c *fiber.Ctx, userID uuid.UUID) error {
+	params := pagination.NewPaginationParams()
+	if err := c.QueryParser(&params); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Parámetros inválidos"})
+	}
+	params.Validate()
+
+	entries, total, err := h.service.GetEntries(userID, params)
+	if err != nil {
+		return walletError(c, err)
+	}
+
+	response := make([]WalletEntryResponse, len(entries))
+	for i := range entries {
+		response[i] = ToWalletEntryResponse(&entries[i])
+	}
+
+	return c.JSON(pagination.PaginatedResponse{
+		Data: response,
+		Meta: &pagination.PaginationMeta{
+			TotalItems:   total,
+			TotalPages:   int((total + int64(params.Limit) - 1) / int64(params.Limit)),
+			CurrentPage:  params.Page,
+			ItemsPerPage: params.Limit,
+		},
+	})
+}
+
+// walletError traduce los errores del monedero a respuestas HTTP
+func walletError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrUserNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrInvalidAmount):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+}
@@ -0,0 +1,7 @@
+package presentation
+
+// TopUpRequest representa una recarga de saldo en recepción
+type TopUpRequest struct {
+	AmountCents int    `json:"amount_cents" validate:"required,gt=0"`
+	Description string `json:"description"` // Opcional: p. ej. "Recarga en efectivo"
+}
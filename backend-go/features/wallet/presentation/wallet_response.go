@@ -0,0 +1,60 @@
+package presentation
+
+import (
+	"backend-go/features/wallet/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WalletResponse representa el saldo del monedero de un usuario
+type WalletResponse struct {
+	UserID       string  `json:"user_id"`
+	BalanceCents int     `json:"balance_cents"`
+	BalanceEuros float64 `json:"balance_euros"`
+}
+
+// ToWalletResponse construye la respuesta del saldo
+func ToWalletResponse(userID uuid.UUID, balanceCents int) WalletResponse {
+	return WalletResponse{
+		UserID:       userID.String(),
+		BalanceCents: balanceCents,
+		BalanceEuros: float64(balanceCents) / 100.0,
+	}
+}
+
+// WalletEntryResponse representa un movimiento del monedero
+type WalletEntryResponse struct {
+	ID                uint    `json:"id"`
+	Type              string  `json:"type"` // TOP_UP, DEBIT, REFUND
+	AmountCents       int     `json:"amount_cents"`
+	AmountEuros       float64 `json:"amount_euros"`
+	BalanceAfterCents int     `json:"balance_after_cents"`
+	Reference         string  `json:"reference"`
+	RelatedEntryID    *uint   `json:"related_entry_id,omitempty"`
+	PaymentID         *uint   `json:"payment_id,omitempty"`
+	Description       string  `json:"description"`
+	CreatedBy         *string `json:"created_by,omitempty"`
+	CreatedAt         string  `json:"created_at"`
+}
+
+// ToWalletEntryResponse convierte un domain.WalletEntry a WalletEntryResponse
+func ToWalletEntryResponse(entry *domain.WalletEntry) WalletEntryResponse {
+	response := WalletEntryResponse{
+		ID:                entry.ID,
+		Type:              entry.Type,
+		AmountCents:       entry.AmountCents,
+		AmountEuros:       float64(entry.AmountCents) / 100.0,
+		BalanceAfterCents: entry.BalanceAfterCents,
+		Reference:         entry.Reference,
+		RelatedEntryID:    entry.RelatedEntryID,
+		PaymentID:         entry.PaymentID,
+		Description:       entry.Description,
+		CreatedAt:         entry.CreatedAt.Format(time.RFC3339),
+	}
+	if entry.CreatedBy != nil {
+		createdBy := entry.CreatedBy.String()
+		response.CreatedBy = &createdBy
+	}
+	return response
+}
@@ -0,0 +1,32 @@
+package presentation
+
+import (
+	"backend-go/shared/idempotency"
+	"backend-go/shared/middleware"
+	"backend-go/shared/security"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ======================================================================================
+// WALLET ROUTES
+// Autenticado: GET / (mi saldo), GET /entries (mis movimientos)
+// Admin/Gestor: GET /users/:user_id, GET /users/:user_id/entries, POST /users/:user_id/top-up
+// El pago con saldo se hace desde /api/payments con payment_method = "wallet"
+// ======================================================================================
+
+// RegisterRoutes registra las rutas del monedero
+func RegisterRoutes(app *fiber.App, handler *WalletHandler, jwtService security.JWTService, idempotencyStore *idempotency.Store) {
+	wallet := app.Group("/api/wallet")
+	wallet.Use(middleware.JWTMiddleware(jwtService))
+
+	// Rutas protegidas - Autenticado (mi monedero)
+	wallet.Get("/", handler.GetMyWallet)
+	wallet.Get("/entries", handler.GetMyEntries)
+
+	// Rutas protegidas - Solo ADMIN y GESTOR (recepción)
+	staff := middleware.RequireRoleByName("ADMIN", "GESTOR")
+	wallet.Get("/users/:user_id", staff, handler.GetUserWallet)
+	wallet.Get("/users/:user_id/entries", staff, handler.GetUserEntries)
+	wallet.Post("/users/:user_id/top-up", staff, middleware.Idempotency(idempotencyStore), handler.TopUp)
+}
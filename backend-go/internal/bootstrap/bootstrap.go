@@ -0,0 +1,260 @@
+package bootstrap
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	authApp "backend-go/features/auth/application"
+	authInfra "backend-go/features/auth/infrastructure"
+	bookingApp "backend-go/features/bookings/application"
+	bookingInfra "backend-go/features/bookings/infrastructure"
+	classApp "backend-go/features/classes/application"
+	classInfra "backend-go/features/classes/infrastructure"
+	clubApp "backend-go/features/clubs/application"
+	clubInfra "backend-go/features/clubs/infrastructure"
+	passApp "backend-go/features/passes/application"
+	passInfra "backend-go/features/passes/infrastructure"
+	paymentApp "backend-go/features/payments/application"
+	paymentDomain "backend-go/features/payments/domain"
+	paymentInfra "backend-go/features/payments/infrastructure"
+	pistaApp "backend-go/features/pista/application"
+	pistaInfra "backend-go/features/pista/infrastructure"
+	pricingApp "backend-go/features/pricing/application"
+	pricingInfra "backend-go/features/pricing/infrastructure"
+	profileApp "backend-go/features/profile/application"
+	profileInfra "backend-go/features/profile/infrastructure"
+	roleApp "backend-go/features/roles/application"
+	roleInfra "backend-go/features/roles/infrastructure"
+	scheduleApp "backend-go/features/schedules/application"
+	scheduleInfra "backend-go/features/schedules/infrastructure"
+	userApp "backend-go/features/users/application"
+	userDomain "backend-go/features/users/domain"
+	userInfra "backend-go/features/users/infrastructure"
+	walletApp "backend-go/features/wallet/application"
+	walletInfra "backend-go/features/wallet/infrastructure"
+	"backend-go/shared/availability"
+	"backend-go/shared/idempotency"
+	"backend-go/shared/security"
+
+	"gorm.io/gorm"
+)
+
+// ======================================================================================
+// BOOTSTRAP
+// Construye los servicios de todas las features a partir de la conexión y las variables de
+// entorno. Lo comparten la API (cmd/api, que añade handlers y rutas) y el CLI de operaciones
+// (cmd/polimanage), para que ambos ejecuten exactamente la misma lógica de negocio.
+// ======================================================================================
+
+// defaultFacilityTimezone es la hora local de la instalación si no se indica FACILITY_TIMEZONE
+const defaultFacilityTimezone = "Europe/Madrid"
+
+// Container agrupa los servicios de aplicación ya cableados entre sí
+type Container struct {
+	DB *gorm.DB
+
+	// Zona horaria de la instalación (FACILITY_TIMEZONE)
+	FacilityLocation *time.Location
+
+	// Seguridad compartida (Argon2, JWT)
+	CryptoService security.CryptoService
+	JWTService    security.JWTService
+
+	// Identidad
+	UserRepo       userDomain.UserRepository
+	AuthService    *authApp.AuthService
+	UserService    *userApp.UserService
+	ProfileService *profileApp.ProfileService
+	RoleService    *roleApp.RoleService
+
+	// Recursos y reservas
+	PistaService              *pistaApp.PistaService
+	ScheduleService           *scheduleApp.ScheduleService
+	AvailabilityService       *availability.AvailabilityService
+	PricingService            *pricingApp.PricingService
+	PromoCodeService          *pricingApp.PromoCodeService
+	BookingService            *bookingApp.BookingService
+	BookingSeriesService      *bookingApp.BookingSeriesService
+	BookingParticipantService *bookingApp.BookingParticipantService
+
+	// Academia
+	ClassService         *classApp.ClassService
+	EnrollmentService    *classApp.EnrollmentService
+	ClassTemplateService *classApp.ClassTemplateService
+
+	// Clubs
+	ClubService           *clubApp.ClubService
+	ClubMembershipService *clubApp.ClubMembershipService
+	RenewalService        *clubApp.RenewalService
+	ClubUserProvider      clubApp.UserProvider
+
+	// Pagos, monedero y bonos
+	PaymentGatewayName string
+	PaymentService     *paymentApp.PaymentService
+	WebhookService     *paymentApp.WebhookService
+	InvoiceService     *paymentApp.InvoiceService
+	IdempotencyStore   *idempotency.Store
+	WalletService      *walletApp.WalletService
+	PassService        *passApp.PassService
+}
+
+// NewContainer construye todos los servicios. Devuelve error si falta o es inválida alguna
+// variable de entorno obligatoria (JWT_SECRET, FACILITY_TIMEZONE, CANCELLATION_POLICY, PAYMENT_PROVIDER, INVOICE_*).
+func NewContainer(db *gorm.DB) (*Container, error) {
+	c := &Container{DB: db}
+	var err error
+
+	// ============================================================
+	// SHARED SECURITY SERVICES (Argon2, JWT)
+	// ============================================================
+	c.CryptoService = security.NewArgon2CryptoService()
+
+	// Leer JWT secret desde variable de entorno
+	jwtSecret := os.Getenv("JWT_SECRET")
+	if jwtSecret == "" {
+		return nil, fmt.Errorf("JWT_SECRET no está configurado en las variables de entorno")
+	}
+	c.JWTService = security.NewJWTService(jwtSecret)
+
+	// FACILITY_TIMEZONE: hora local de la instalación (por defecto Europe/Madrid). Las series de
+	// reservas y las plantillas de clases se definen en hora local y conservan esa hora en verano
+	// e invierno.
+	facilityTimezone := os.Getenv("FACILITY_TIMEZONE")
+	if facilityTimezone == "" {
+		facilityTimezone = defaultFacilityTimezone
+	}
+	c.FacilityLocation, err = time.LoadLocation(facilityTimezone)
+	if err != nil {
+		return nil, fmt.Errorf("FACILITY_TIMEZONE inválido %q: %w", facilityTimezone, err)
+	}
+
+	// ============================================================
+	// IDENTIDAD (Auth, Users, Profile, Roles)
+	// ============================================================
+	// Infraestructura - Servicios específicos de Auth (DiceBear) y repository compartido
+	avatarService := authInfra.NewDiceBearService()
+	c.UserRepo = userInfra.NewUserRepository(db)
+
+	// V2: Repository para RefreshSessions
+	sessionRepo := authInfra.NewRefreshSessionRepository(db)
+	c.AuthService = authApp.NewAuthService(c.UserRepo, sessionRepo, c.CryptoService, c.JWTService, avatarService)
+	c.UserService = userApp.NewUserService(c.UserRepo, c.CryptoService)
+	c.ProfileService = profileApp.NewProfileService(profileInfra.NewProfileRepository(db), c.CryptoService)
+	c.RoleService = roleApp.NewRoleService(roleInfra.NewRoleRepository(db))
+
+	// ============================================================
+	// PISTAS, HORARIOS, DISPONIBILIDAD Y PRECIOS
+	// ============================================================
+	c.PistaService = pistaApp.NewPistaService(pistaInfra.NewPistaRepository(db))
+
+	// Servicio de disponibilidad compartido (pistas no pueden tener booking Y clase al mismo tiempo)
+	bookingRepo := bookingInfra.NewBookingRepository(db)
+	classRepo := classInfra.NewClassRepository(db)
+
+	// Módulo Schedules (horario de apertura, festivos y bloqueos) - consultado por la disponibilidad
+	c.ScheduleService = scheduleApp.NewScheduleService(scheduleInfra.NewScheduleRepository(db))
+	c.AvailabilityService = availability.NewAvailabilityService(db).
+		WithScheduleProvider(scheduleApp.NewAvailabilityScheduleProvider(c.ScheduleService))
+
+	// Módulo Pricing (reglas de precio, códigos promocionales y presupuestos) - calcula el precio de las reservas
+	c.PromoCodeService = pricingApp.NewPromoCodeService(pricingInfra.NewPromoCodeRepository(db))
+	c.PricingService = pricingApp.NewPricingService(pricingInfra.NewPricingRepository(db), c.PromoCodeService)
+
+	// ============================================================
+	// PAGOS Y MONEDERO
+	// ============================================================
+	// Módulo Payments (Mock o Stripe según PAYMENT_PROVIDER) - cobros y reembolsos de reservas, clases y clubs
+	// CANCELLATION_POLICY: "horas:porcentaje" por tramo (por defecto "24:100,6:50,0:0")
+	cancellationPolicy, err := paymentDomain.ParseCancellationPolicy(os.Getenv("CANCELLATION_POLICY"))
+	if err != nil {
+		return nil, fmt.Errorf("CANCELLATION_POLICY: %w", err)
+	}
+	// PAYMENT_PROVIDER: "mock" (por defecto) o "stripe" (STRIPE_SECRET_KEY, STRIPE_API_BASE opcional)
+	paymentGateway, err := paymentInfra.NewPaymentGateway(
+		os.Getenv("PAYMENT_PROVIDER"),
+		os.Getenv("STRIPE_SECRET_KEY"),
+		os.Getenv("STRIPE_API_BASE"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("PAYMENT_PROVIDER: %w", err)
+	}
+	c.PaymentGatewayName = paymentGateway.Name()
+	// Facturas: INVOICE_ISSUER_NAME, INVOICE_ISSUER_TAX_ID, INVOICE_ISSUER_ADDRESS,
+	// INVOICE_VAT_RATE (por defecto 21) e INVOICE_TIMEZONE (por defecto Europe/Madrid)
+	invoiceSettings, err := paymentInfra.NewInvoiceSettings(
+		os.Getenv("INVOICE_ISSUER_NAME"),
+		os.Getenv("INVOICE_ISSUER_TAX_ID"),
+		os.Getenv("INVOICE_ISSUER_ADDRESS"),
+		os.Getenv("INVOICE_VAT_RATE"),
+		os.Getenv("INVOICE_TIMEZONE"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("INVOICE_*: %w", err)
+	}
+	paymentRepo := paymentInfra.NewPaymentRepository(db, invoiceSettings)
+	c.PaymentService = paymentApp.NewPaymentService(paymentRepo, paymentGateway, cancellationPolicy)
+	c.PaymentService.SetPromoCodeRedeemer(pricingApp.NewPaymentPromoRedeemer(c.PromoCodeService))
+	// Webhooks (POST /api/payments/webhooks/:provider): solo proveedores con secreto configurado
+	// (STRIPE_WEBHOOK_SECRET, MOCK_WEBHOOK_SECRET)
+	c.WebhookService = paymentApp.NewWebhookService(paymentRepo, paymentInfra.NewWebhookVerifiers(
+		os.Getenv("STRIPE_WEBHOOK_SECRET"),
+		os.Getenv("MOCK_WEBHOOK_SECRET"),
+	)...)
+	c.InvoiceService = paymentApp.NewInvoiceService(paymentRepo, paymentInfra.NewInvoicePDFRenderer(invoiceSettings.Location))
+	// Idempotency-Key en los endpoints que cobran (pagos, recargas y renovación de membresías)
+	c.IdempotencyStore = idempotency.NewStore(db)
+
+	// Módulo Wallet (monedero prepago) - libro de movimientos del que se cobra con payment_method = "wallet"
+	c.WalletService = walletApp.NewWalletService(walletInfra.NewWalletRepository(db))
+	c.PaymentService.SetWalletGateway(paymentInfra.NewWalletPaymentProvider(c.WalletService))
+
+	// ============================================================
+	// RESERVAS
+	// ============================================================
+	c.BookingService = bookingApp.NewBookingService(
+		bookingRepo,
+		c.AvailabilityService,
+		pricingApp.NewBookingPriceCalculator(c.PricingService),
+		paymentApp.NewBookingRefundProcessor(c.PaymentService),
+	)
+	c.BookingSeriesService = bookingApp.NewBookingSeriesService(bookingInfra.NewBookingSeriesRepository(db), bookingRepo, c.BookingService, c.FacilityLocation)
+	c.BookingParticipantService = bookingApp.NewBookingParticipantService(bookingInfra.NewBookingParticipantRepository(db), bookingRepo)
+
+	// ============================================================
+	// CLASES E INSCRIPCIONES
+	// ============================================================
+	c.ClassService = classApp.NewClassService(classRepo, c.AvailabilityService)
+	enrollmentRepo := classInfra.NewEnrollmentRepository(db)
+	classProvider := classApp.NewClassProvider(c.ClassService)
+	classUserProvider := userApp.NewClassUserProvider(c.UserRepo)
+	c.EnrollmentService = classApp.NewEnrollmentService(enrollmentRepo, classProvider, classUserProvider, paymentApp.NewEnrollmentRefundProcessor(c.PaymentService))
+	c.ClassService.SetWaitlistPromoter(c.EnrollmentService)    // Ampliar aforo promociona la lista de espera
+	c.WebhookService.SetEnrollmentHandler(c.EnrollmentService) // Un pago fallido (webhook) libera la plaza
+
+	// Plantillas de clases recurrentes - generan las sesiones con antelación (ver scheduler)
+	c.ClassTemplateService = classApp.NewClassTemplateService(classInfra.NewClassTemplateRepository(db), c.ClassService, c.FacilityLocation)
+
+	// ============================================================
+	// CLUBS Y MEMBRESÍAS
+	// ============================================================
+	clubRepo := clubInfra.NewClubRepository(db)
+	clubMembershipRepo := clubInfra.NewClubMembershipRepository(db)
+	c.ClubService = clubApp.NewClubService(clubRepo, clubMembershipRepo)
+	c.ClubMembershipService = clubApp.NewClubMembershipService(clubMembershipRepo, c.PaymentService)
+	c.ClubUserProvider = userApp.NewClubUserProvider(c.UserRepo)
+
+	// Servicio de renovación de membresías (integra Clubs + Payments)
+	c.RenewalService = clubApp.NewRenewalService(clubMembershipRepo, clubRepo, c.PaymentService)
+	c.WebhookService.SetMembershipHandler(c.RenewalService) // Cobros de renovación pendientes confirmados por webhook
+
+	// ============================================================
+	// BONOS (clases y horas de pista) - se consumen al reservar o inscribirse
+	// ============================================================
+	c.PassService = passApp.NewPassService(passInfra.NewPassRepository(db), c.PaymentService)
+	c.BookingService.SetPassRedeemer(passApp.NewBookingPassRedeemer(c.PassService))
+	c.EnrollmentService.SetPassRedeemer(passApp.NewEnrollmentPassRedeemer(c.PassService))
+	c.WebhookService.SetPassHandler(passApp.NewPassPaymentHandler(c.PassService)) // Cobros de bonos pendientes confirmados por webhook
+
+	return c, nil
+}
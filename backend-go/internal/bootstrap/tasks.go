@@ -0,0 +1,200 @@
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend-go/internal/scheduler"
+)
+
+// defaultSchedulerTimezone es la zona horaria de las expresiones cron si no se indica SCHEDULER_TIMEZONE
+const defaultSchedulerTimezone = "Europe/Madrid"
+
+// schedulerLeaseName es el lease de liderazgo compartido por todas las réplicas de la API
+const schedulerLeaseName = "scheduler"
+
+// NewScheduler crea el scheduler con las tareas automáticas y su historial en base de datos.
+// Con varias réplicas, solo la que tiene el lease de liderazgo lanza las ejecuciones programadas
+// y un advisory lock por tarea impide que la misma tarea corra en dos réplicas a la vez.
+//
+// Configuración por entorno:
+//   - SCHEDULER_MAX_CONCURRENT: tareas simultáneas (por defecto 2)
+//   - SCHEDULER_TIMEZONE: zona horaria de las expresiones cron (por defecto Europe/Madrid)
+//   - SCHEDULER_LEASE_TTL: duración del lease de liderazgo (por defecto 30s); si la líder cae,
+//     otra réplica toma el relevo como mucho tras este tiempo
+//   - SCHEDULER_<ID>: planificación de una tarea, como duración ("15m") o expresión cron
+//     ("0 3 * * *"); el ID va en mayúsculas con guiones bajos (SCHEDULER_BOOKING_STATUSES)
+func NewScheduler(c *Container) (*scheduler.Scheduler, error) {
+	instance := scheduler.InstanceID()
+	options := scheduler.Options{
+		Store:    scheduler.NewRunStore(c.DB),
+		Instance: instance,
+		Locker:   scheduler.NewAdvisoryTaskLocker(c.DB),
+	}
+
+	if raw := os.Getenv("SCHEDULER_MAX_CONCURRENT"); raw != "" {
+		maxConcurrent, err := strconv.Atoi(raw)
+		if err != nil || maxConcurrent <= 0 {
+			return nil, fmt.Errorf("SCHEDULER_MAX_CONCURRENT inválido: %q", raw)
+		}
+		options.MaxConcurrent = maxConcurrent
+	}
+
+	timezone := os.Getenv("SCHEDULER_TIMEZONE")
+	if timezone == "" {
+		timezone = defaultSchedulerTimezone
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("SCHEDULER_TIMEZONE inválido: %w", err)
+	}
+	options.Location = location
+
+	leaseTTL := scheduler.DefaultLeaseTTL
+	if raw := os.Getenv("SCHEDULER_LEASE_TTL"); raw != "" {
+		leaseTTL, err = time.ParseDuration(raw)
+		if err != nil || leaseTTL < 3*time.Second {
+			return nil, fmt.Errorf("SCHEDULER_LEASE_TTL inválido (mínimo 3s): %q", raw)
+		}
+	}
+	options.Elector = scheduler.NewLeaseElector(c.DB, schedulerLeaseName, instance, leaseTTL)
+
+	taskScheduler := scheduler.NewScheduler(options)
+	for _, task := range ScheduledTasks(c) {
+		if err := applyScheduleOverride(&task); err != nil {
+			return nil, err
+		}
+		if err := taskScheduler.AddTask(task); err != nil {
+			return nil, err
+		}
+	}
+
+	return taskScheduler, nil
+}
+
+// applyScheduleOverride aplica SCHEDULER_<ID> a la tarea si está definida
+func applyScheduleOverride(task *scheduler.ScheduledTask) error {
+	name := "SCHEDULER_" + strings.ToUpper(strings.ReplaceAll(task.ID, "-", "_"))
+	raw := strings.TrimSpace(os.Getenv(name))
+	if raw == "" {
+		return nil
+	}
+
+	if interval, err := time.ParseDuration(raw); err == nil {
+		if interval <= 0 {
+			return fmt.Errorf("%s debe ser positivo: %q", name, raw)
+		}
+		task.Interval = interval
+		task.Cron = ""
+		return nil
+	}
+	if _, err := scheduler.ParseCron(raw); err != nil {
+		return fmt.Errorf("%s no es una duración ni una expresión cron válida: %w", name, err)
+	}
+	task.Cron = raw
+	return nil
+}
+
+// ScheduledTasks devuelve las tareas automáticas en segundo plano. La API las registra en el
+// scheduler y el CLI puede lanzar cualquiera de ellas una sola vez.
+//
+// Cada tarea pasa ctx a su servicio: al vencer el plazo (TIMEOUT) o si la parada del
+// scheduler agota su espera se cancelan las consultas en curso.
+func ScheduledTasks(c *Container) []scheduler.ScheduledTask {
+	return []scheduler.ScheduledTask{
+		// Tarea 1: Actualizar estados de reservas
+		{
+			ID:       "booking-statuses",
+			Name:     "Actualizar estados de reservas",
+			Interval: 10 * time.Minute,
+			Timeout:  2 * time.Minute,
+			Execute: func(ctx context.Context) (int, error) {
+				return c.BookingService.AutoUpdateBookingStatuses(ctx)
+			},
+		},
+
+		// Tarea 2: Actualizar estados de clases
+		{
+			ID:       "class-statuses",
+			Name:     "Actualizar estados de clases",
+			Interval: 10 * time.Minute,
+			Timeout:  2 * time.Minute,
+			Execute: func(ctx context.Context) (int, error) {
+				return c.ClassService.AutoUpdateClassStatuses(ctx)
+			},
+		},
+
+		// Tarea 3: Caducar plazas ofertadas de lista de espera no confirmadas y promocionar al siguiente
+		{
+			ID:       "waitlist-offers",
+			Name:     "Caducar ofertas de lista de espera",
+			Interval: 10 * time.Minute,
+			Timeout:  2 * time.Minute,
+			Execute: func(ctx context.Context) (int, error) {
+				return c.EnrollmentService.ExpireWaitlistOffers(ctx)
+			},
+		},
+
+		// Tarea 4: Generar sesiones de las plantillas de clases recurrentes
+		{
+			ID:       "class-templates",
+			Name:     "Generar sesiones de plantillas de clase",
+			Interval: 10 * time.Minute,
+			Timeout:  5 * time.Minute,
+			Execute: func(ctx context.Context) (int, error) {
+				created, conflicts, err := c.ClassTemplateService.GenerateAll(ctx)
+				if conflicts > 0 {
+					log.Printf("[Scheduler] %d sesiones de plantillas con conflicto (ver GET /api/class-templates/:id)", conflicts)
+				}
+				return created, err
+			},
+		},
+
+		// Tarea 5: Renovación automática de membresías (cobro con reintentos y suspensión tras el periodo de gracia)
+		{
+			ID:       "membership-renewals",
+			Name:     "Renovar membresías de clubs",
+			Interval: 10 * time.Minute,
+			Timeout:  5 * time.Minute,
+			Execute: func(ctx context.Context) (int, error) {
+				result, err := c.RenewalService.AutoRenewMemberships(ctx)
+				if err != nil {
+					return 0, err
+				}
+				affected := result.Renewed + result.Failed + result.Suspended + result.Pending
+				if affected > 0 {
+					log.Printf("[Scheduler] Membresías: %d renovadas, %d con cobro fallido, %d suspendidas, %d pendientes de confirmar",
+						result.Renewed, result.Failed, result.Suspended, result.Pending)
+				}
+				return affected, nil
+			},
+		},
+
+		// Tarea 6: Purgar las claves de idempotencia caducadas (24h)
+		{
+			ID:       "idempotency-keys",
+			Name:     "Purgar claves de idempotencia caducadas",
+			Interval: 1 * time.Hour,
+			Execute: func(ctx context.Context) (int, error) {
+				deleted, err := c.IdempotencyStore.DeleteExpired(ctx, time.Now())
+				return int(deleted), err
+			},
+		},
+
+		// Tarea 7: Cancelar las reservas con pago dividido cuyo plazo venció sin completar el pago
+		{
+			ID:       "booking-splits",
+			Name:     "Caducar pagos divididos",
+			Interval: 10 * time.Minute,
+			Timeout:  5 * time.Minute,
+			Execute: func(ctx context.Context) (int, error) {
+				return c.PaymentService.ExpireBookingSplits(ctx)
+			},
+		},
+	}
+}
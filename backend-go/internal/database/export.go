@@ -0,0 +1,184 @@
+package database
+
+import (
+	"backend-go/shared/database"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// ======================================================================================
+// EXPORTACIÓN / IMPORTACIÓN DE DATOS
+// Volcado JSON de todas las tablas de la aplicación. La serialización la hace PostgreSQL
+// (json_agg / json_populate_recordset), así que los tipos (uuid, numeric, jsonb, bytea,
+// timestamptz) se conservan exactamente. Solo se importa sobre una base de datos vacía con
+// el esquema en la misma versión que la exportación.
+// ======================================================================================
+
+// exportFormat identifica los ficheros generados por ExportData
+const exportFormat = "polimanage-export"
+
+// ErrImportTargetNotEmpty se devuelve al importar sobre tablas con datos
+var ErrImportTargetNotEmpty = errors.New("la base de datos de destino no está vacía")
+
+// DataExport es el contenido de un fichero de exportación
+type DataExport struct {
+	Format        string        `json:"format"`
+	SchemaVersion int64         `json:"schema_version"`
+	ExportedAt    time.Time     `json:"exported_at"`
+	Tables        []TableExport `json:"tables"`
+}
+
+// TableExport son las filas de una tabla en el orden de sus dependencias
+type TableExport struct {
+	Name  string          `json:"name"`
+	Count int             `json:"count"`
+	Rows  json.RawMessage `json:"rows"`
+}
+
+// DataModels devuelve todas las tablas de la aplicación en orden de dependencias (FKs).
+// Las migraciones que añadan tablas deben añadirlas también aquí, salvo las de estado efímero
+// de una instancia en marcha (scheduler_leases), que no tiene sentido exportar.
+func DataModels() []interface{} {
+	return append(initialSchemaModels(),
+		&database.SchedulerRun{}, // 0004_scheduler_runs
+	)
+}
+
+// ExportData vuelca todas las tablas en w como JSON desde una única instantánea consistente
+func ExportData(w io.Writer) (*DataExport, error) {
+	export := &DataExport{Format: exportFormat, ExportedAt: time.Now().UTC()}
+
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		version, err := currentSchemaVersion(tx)
+		if err != nil {
+			return err
+		}
+		export.SchemaVersion = version
+
+		for _, model := range DataModels() {
+			name := tableName(model)
+			var rows string
+			var count int
+			if err := tx.Raw(fmt.Sprintf(
+				"SELECT COALESCE(json_agg(t), '[]'::json)::text, COUNT(*) FROM %s t", tx.Statement.Quote(name),
+			)).Row().Scan(&rows, &count); err != nil {
+				return fmt.Errorf("error exportando %s: %w", name, err)
+			}
+			export.Tables = append(export.Tables, TableExport{Name: name, Count: count, Rows: json.RawMessage(rows)})
+		}
+		return nil
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.NewEncoder(w).Encode(export); err != nil {
+		return nil, fmt.Errorf("error escribiendo la exportación: %w", err)
+	}
+	return export, nil
+}
+
+// ImportData carga un fichero de ExportData en una base de datos vacía, en una sola transacción
+func ImportData(r io.Reader) (*DataExport, error) {
+	var export DataExport
+	if err := json.NewDecoder(r).Decode(&export); err != nil {
+		return nil, fmt.Errorf("fichero de exportación inválido: %w", err)
+	}
+	if export.Format != exportFormat {
+		return nil, fmt.Errorf("formato de exportación desconocido: %q", export.Format)
+	}
+
+	models := map[string]interface{}{}
+	for _, model := range DataModels() {
+		models[tableName(model)] = model
+	}
+
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		version, err := currentSchemaVersion(tx)
+		if err != nil {
+			return err
+		}
+		if version != export.SchemaVersion {
+			return fmt.Errorf("el esquema está en la versión %d y la exportación en la %d (usar `migrate to %d`)",
+				version, export.SchemaVersion, export.SchemaVersion)
+		}
+
+		for _, table := range export.Tables {
+			if _, ok := models[table.Name]; !ok {
+				return fmt.Errorf("tabla desconocida en la exportación: %s", table.Name)
+			}
+			var count int64
+			if err := tx.Table(table.Name).Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				return fmt.Errorf("%w: %s tiene %d filas", ErrImportTargetNotEmpty, table.Name, count)
+			}
+		}
+
+		for _, table := range export.Tables {
+			if table.Count == 0 {
+				continue
+			}
+			quoted := tx.Statement.Quote(table.Name)
+			if err := tx.Exec(fmt.Sprintf(
+				"INSERT INTO %s SELECT * FROM json_populate_recordset(NULL::%s, ?::json)", quoted, quoted,
+			), string(table.Rows)).Error; err != nil {
+				return fmt.Errorf("error importando %s: %w", table.Name, err)
+			}
+			if err := resetSequence(tx, models[table.Name], table.Name); err != nil {
+				return err
+			}
+			log.Printf("📥 %s: %d filas", table.Name, table.Count)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &export, nil
+}
+
+// resetSequence ajusta la secuencia del ID tras insertar IDs explícitos
+func resetSequence(tx *gorm.DB, model interface{}, name string) error {
+	if !tx.Migrator().HasColumn(model, "id") {
+		return nil
+	}
+
+	var sequence sql.NullString
+	if err := tx.Raw("SELECT pg_get_serial_sequence(?, 'id')", name).Scan(&sequence).Error; err != nil {
+		return err
+	}
+	if !sequence.Valid {
+		return nil // PK uuid u otra sin secuencia
+	}
+
+	return tx.Exec(fmt.Sprintf(
+		"SELECT setval(?, MAX(id)) FROM %s HAVING MAX(id) IS NOT NULL", tx.Statement.Quote(name),
+	), sequence.String).Error
+}
+
+// currentSchemaVersion devuelve la versión de migración más alta aplicada
+func currentSchemaVersion(tx *gorm.DB) (int64, error) {
+	if !tx.Migrator().HasTable(&schemaMigration{}) {
+		return 0, nil
+	}
+	var version sql.NullInt64
+	if err := tx.Model(&schemaMigration{}).Select("MAX(version)").Scan(&version).Error; err != nil {
+		return 0, err
+	}
+	return version.Int64, nil
+}
+
+func tableName(model interface{}) string {
+	return model.(schema.Tabler).TableName()
+}
@@ -0,0 +1,294 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ======================================================================================
+// MIGRACIONES VERSIONADAS
+// Cada migración tiene una versión única y creciente, se aplica en su propia transacción y
+// queda registrada en schema_migrations. Un advisory lock de PostgreSQL impide que dos
+// instancias migren a la vez: la segunda espera y, al entrar, ya no encuentra pendientes.
+// ======================================================================================
+
+// migrationsTable es la tabla donde se registran las versiones aplicadas
+const migrationsTable = "schema_migrations"
+
+// migrationsLockKey identifica el advisory lock de las migraciones (pg_advisory_lock)
+const migrationsLockKey int64 = 7_261_001
+
+// ErrIrreversibleMigration se devuelve al revertir una migración sin Down
+var ErrIrreversibleMigration = errors.New("la migración no se puede revertir")
+
+// Migration es un cambio de esquema versionado
+type Migration struct {
+	Version int64
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error // nil = irreversible
+}
+
+// MigrationStatus describe el estado de una migración
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	AppliedAt *time.Time // nil = pendiente
+	Unknown   bool       // Aplicada en la base de datos pero no registrada en el código
+}
+
+// schemaMigration es el registro de una versión aplicada
+type schemaMigration struct {
+	Version   int64     `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"type:varchar(255);not null"`
+	AppliedAt time.Time `gorm:"type:timestamptz;not null;default:NOW()"`
+}
+
+func (schemaMigration) TableName() string { return migrationsTable }
+
+// Migrator aplica y revierte migraciones sobre una conexión
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+// NewMigrator crea un migrador con las migraciones registradas del proyecto
+func NewMigrator(db *gorm.DB) (*Migrator, error) {
+	migrations, err := registeredMigrations()
+	if err != nil {
+		return nil, err
+	}
+	return newMigrator(db, migrations)
+}
+
+func newMigrator(db *gorm.DB, migrations []Migration) (*Migrator, error) {
+	sorted := make([]Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+
+	for i, migration := range sorted {
+		if migration.Version <= 0 || migration.Up == nil {
+			return nil, fmt.Errorf("migración %d (%s) inválida", migration.Version, migration.Name)
+		}
+		if i > 0 && sorted[i-1].Version == migration.Version {
+			return nil, fmt.Errorf("versión de migración duplicada: %d", migration.Version)
+		}
+	}
+
+	return &Migrator{db: db, migrations: sorted}, nil
+}
+
+// LatestVersion devuelve la versión más alta registrada en el código (0 si no hay)
+func (m *Migrator) LatestVersion() int64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Up aplica todas las migraciones pendientes y devuelve cuántas se aplicaron
+func (m *Migrator) Up() (int, error) {
+	return m.To(m.LatestVersion())
+}
+
+// Down revierte las últimas `steps` migraciones aplicadas
+func (m *Migrator) Down(steps int) (int, error) {
+	if steps <= 0 {
+		return 0, fmt.Errorf("el número de migraciones a revertir debe ser positivo")
+	}
+
+	reverted := 0
+	err := m.withLock(func(conn *gorm.DB) error {
+		applied, err := appliedVersions(conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && reverted < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+			if err := m.revert(conn, migration); err != nil {
+				return err
+			}
+			reverted++
+		}
+		return nil
+	})
+
+	return reverted, err
+}
+
+// To deja el esquema exactamente en la versión indicada: aplica las pendientes hasta ella y
+// revierte las posteriores. Devuelve cuántas migraciones se aplicaron o revirtieron.
+func (m *Migrator) To(version int64) (int, error) {
+	if version < 0 {
+		return 0, fmt.Errorf("versión inválida: %d", version)
+	}
+	if version != 0 && m.find(version) == nil {
+		return 0, fmt.Errorf("no existe la migración %d", version)
+	}
+
+	changed := 0
+	err := m.withLock(func(conn *gorm.DB) error {
+		applied, err := appliedVersions(conn)
+		if err != nil {
+			return err
+		}
+
+		// Revertir primero las posteriores a la versión objetivo, de la más nueva a la más antigua
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			migration := m.migrations[i]
+			if migration.Version <= version {
+				break
+			}
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+			if err := m.revert(conn, migration); err != nil {
+				return err
+			}
+			changed++
+		}
+
+		for _, migration := range m.migrations {
+			if migration.Version > version {
+				break
+			}
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+			if err := m.apply(conn, migration); err != nil {
+				return err
+			}
+			changed++
+		}
+		return nil
+	})
+
+	return changed, err
+}
+
+// Status devuelve el estado de cada migración (registradas en el código y desconocidas)
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	var statuses []MigrationStatus
+	err := m.withLock(func(conn *gorm.DB) error {
+		applied, err := appliedVersions(conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+			if record, ok := applied[migration.Version]; ok {
+				appliedAt := record.AppliedAt
+				status.AppliedAt = &appliedAt
+				delete(applied, migration.Version)
+			}
+			statuses = append(statuses, status)
+		}
+
+		for _, record := range applied {
+			appliedAt := record.AppliedAt
+			statuses = append(statuses, MigrationStatus{
+				Version:   record.Version,
+				Name:      record.Name,
+				AppliedAt: &appliedAt,
+				Unknown:   true,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses, nil
+}
+
+// apply ejecuta una migración y registra su versión en la misma transacción
+func (m *Migrator) apply(conn *gorm.DB, migration Migration) error {
+	log.Printf("🔧 Aplicando migración %04d_%s...", migration.Version, migration.Name)
+
+	if err := conn.Transaction(func(tx *gorm.DB) error {
+		if err := migration.Up(tx); err != nil {
+			return err
+		}
+		return tx.Create(&schemaMigration{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now()}).Error
+	}); err != nil {
+		return fmt.Errorf("error aplicando la migración %d (%s): %w", migration.Version, migration.Name, err)
+	}
+
+	return nil
+}
+
+// revert deshace una migración y borra su registro en la misma transacción
+func (m *Migrator) revert(conn *gorm.DB, migration Migration) error {
+	if migration.Down == nil {
+		return fmt.Errorf("migración %d (%s): %w", migration.Version, migration.Name, ErrIrreversibleMigration)
+	}
+
+	log.Printf("↩️  Revirtiendo migración %04d_%s...", migration.Version, migration.Name)
+
+	if err := conn.Transaction(func(tx *gorm.DB) error {
+		if err := migration.Down(tx); err != nil {
+			return err
+		}
+		return tx.Delete(&schemaMigration{}, migration.Version).Error
+	}); err != nil {
+		return fmt.Errorf("error revirtiendo la migración %d (%s): %w", migration.Version, migration.Name, err)
+	}
+
+	return nil
+}
+
+// withLock ejecuta fn sobre una única conexión que mantiene el advisory lock de migraciones.
+// El lock es de sesión, por eso no puede tomarse sobre el pool.
+func (m *Migrator) withLock(fn func(conn *gorm.DB) error) error {
+	return m.db.Connection(func(conn *gorm.DB) error {
+		if err := conn.Exec("SELECT pg_advisory_lock(?)", migrationsLockKey).Error; err != nil {
+			return fmt.Errorf("error obteniendo el lock de migraciones: %w", err)
+		}
+		defer func() {
+			if err := conn.Exec("SELECT pg_advisory_unlock(?)", migrationsLockKey).Error; err != nil {
+				log.Printf("⚠️  Error liberando el lock de migraciones: %v", err)
+			}
+		}()
+
+		if err := conn.AutoMigrate(&schemaMigration{}); err != nil {
+			return fmt.Errorf("error creando la tabla %s: %w", migrationsTable, err)
+		}
+
+		return fn(conn)
+	})
+}
+
+func (m *Migrator) find(version int64) *Migration {
+	for i := range m.migrations {
+		if m.migrations[i].Version == version {
+			return &m.migrations[i]
+		}
+	}
+	return nil
+}
+
+// appliedVersions obtiene las versiones aplicadas indexadas por versión
+func appliedVersions(conn *gorm.DB) (map[int64]schemaMigration, error) {
+	var records []schemaMigration
+	if err := conn.Order("version ASC").Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int64]schemaMigration, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return applied, nil
+}
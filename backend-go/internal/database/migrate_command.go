@@ -0,0 +1,100 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+)
+
+// MigrateUsage describe los subcomandos de RunMigrateCommand
+const MigrateUsage = `Comandos de migración:
+  up              Aplica todas las migraciones pendientes
+  down [n]        Revierte las últimas n migraciones aplicadas (por defecto 1)
+  to <versión>    Aplica o revierte migraciones hasta dejar el esquema en esa versión (0 = vacío)
+  status          Muestra las migraciones aplicadas y pendientes
+`
+
+// ErrMigrateUsage indica que los argumentos no corresponden a ningún subcomando válido
+var ErrMigrateUsage = errors.New("uso incorrecto del comando de migraciones")
+
+// RunMigrateCommand ejecuta un subcomando de migración (up, down, to, status) sobre DB.
+// Lo comparten cmd/migrate y cmd/polimanage.
+func RunMigrateCommand(args []string) error {
+	if len(args) == 0 {
+		return ErrMigrateUsage
+	}
+
+	migrator, err := NewMigrator(DB)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up()
+		if err != nil {
+			return err
+		}
+		log.Printf("✅ %d migraciones aplicadas", applied)
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps <= 0 {
+				return fmt.Errorf("número de migraciones inválido: %s", args[1])
+			}
+		}
+		reverted, err := migrator.Down(steps)
+		if err != nil {
+			return err
+		}
+		log.Printf("✅ %d migraciones revertidas", reverted)
+
+	case "to":
+		if len(args) < 2 {
+			return fmt.Errorf("indica la versión destino: to <versión>")
+		}
+		version, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("versión inválida: %s", args[1])
+		}
+		changed, err := migrator.To(version)
+		if err != nil {
+			return err
+		}
+		log.Printf("✅ Esquema en la versión %d (%d migraciones aplicadas o revertidas)", version, changed)
+
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+		printMigrationStatus(statuses)
+
+	default:
+		return fmt.Errorf("%w: %s", ErrMigrateUsage, args[0])
+	}
+
+	return nil
+}
+
+// printMigrationStatus muestra una tabla con el estado de cada migración
+func printMigrationStatus(statuses []MigrationStatus) {
+	fmt.Printf("%-8s %-40s %s\n", "VERSIÓN", "NOMBRE", "APLICADA")
+	pending := 0
+	for _, status := range statuses {
+		applied := "pendiente"
+		if status.AppliedAt != nil {
+			applied = status.AppliedAt.Local().Format("2006-01-02 15:04:05")
+		} else {
+			pending++
+		}
+		if status.Unknown {
+			applied += " (no registrada en el código)"
+		}
+		fmt.Printf("%-8d %-40s %s\n", status.Version, status.Name, applied)
+	}
+	fmt.Printf("\n%d migraciones, %d pendientes\n", len(statuses), pending)
+}
@@ -0,0 +1,234 @@
+package database
+
+import (
+	"backend-go/shared/database"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ======================================================================================
+// REGISTRO DE MIGRACIONES
+// Las migraciones SQL viven en migrations/NNNN_nombre.up.sql (y .down.sql opcional); las que
+// necesitan Go (AutoMigrate, backfills) se registran en goMigrations. Las versiones son únicas
+// entre ambas fuentes. Una migración aplicada no se edita: cualquier cambio va en una nueva.
+// ======================================================================================
+
+//go:embed migrations/*.sql
+var sqlMigrationFiles embed.FS
+
+// goMigrations son las migraciones escritas en Go
+var goMigrations = []Migration{
+	{
+		Version: 1,
+		Name:    "initial_schema",
+		Up:      migrateInitialSchema,
+		Down:    dropInitialSchema,
+	},
+	{
+		Version: 4,
+		Name:    "scheduler_runs",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&database.SchedulerRun{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&database.SchedulerRun{})
+		},
+	},
+	{
+		Version: 5,
+		Name:    "scheduler_leases",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&database.SchedulerLease{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&database.SchedulerLease{})
+		},
+	},
+}
+
+// registeredMigrations devuelve las migraciones Go y SQL del proyecto
+func registeredMigrations() ([]Migration, error) {
+	sqlMigrations, err := loadSQLMigrations(sqlMigrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]Migration{}, goMigrations...), sqlMigrations...), nil
+}
+
+// loadSQLMigrations lee los pares NNNN_nombre.up.sql / NNNN_nombre.down.sql de un directorio
+func loadSQLMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo migraciones SQL: %w", err)
+	}
+
+	byVersion := map[int64]*Migration{}
+	var versions []int64
+	for _, entry := range entries {
+		fileName := entry.Name()
+		var direction string
+		switch {
+		case strings.HasSuffix(fileName, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(fileName, ".down.sql"):
+			direction = "down"
+		default:
+			continue
+		}
+
+		base := strings.TrimSuffix(fileName, "."+direction+".sql")
+		rawVersion, name, ok := strings.Cut(base, "_")
+		if !ok {
+			return nil, fmt.Errorf("nombre de migración inválido: %s (usar NNNN_nombre.up.sql)", fileName)
+		}
+		version, err := strconv.ParseInt(rawVersion, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("versión de migración inválida en %s: %w", fileName, err)
+		}
+
+		content, err := fs.ReadFile(fsys, path.Join(dir, fileName))
+		if err != nil {
+			return nil, fmt.Errorf("error leyendo %s: %w", fileName, err)
+		}
+
+		migration, exists := byVersion[version]
+		if !exists {
+			migration = &Migration{Version: version, Name: name}
+			byVersion[version] = migration
+			versions = append(versions, version)
+		}
+		if direction == "up" {
+			migration.Up = execSQL(string(content))
+		} else {
+			migration.Down = execSQL(string(content))
+		}
+	}
+
+	migrations := make([]Migration, 0, len(versions))
+	for _, version := range versions {
+		migration := byVersion[version]
+		if migration.Up == nil {
+			return nil, fmt.Errorf("la migración %d no tiene fichero .up.sql", version)
+		}
+		migrations = append(migrations, *migration)
+	}
+
+	return migrations, nil
+}
+
+// execSQL ejecuta el contenido de un fichero de migración
+func execSQL(statements string) func(tx *gorm.DB) error {
+	return func(tx *gorm.DB) error {
+		return tx.Exec(statements).Error
+	}
+}
+
+// initialSchemaModels son las tablas del esquema inicial en orden de dependencias (FKs).
+// Las tablas nuevas se añaden en una migración propia, no aquí.
+func initialSchemaModels() []interface{} {
+	return []interface{}{
+		// Módulo 1: Identidad
+		&database.Role{},
+		&database.User{},
+		&database.RefreshSession{}, // V2: Sesiones de refresh token
+
+		// Módulo 2: Recursos y Reservas
+		&database.Pista{},
+		&database.BookingSeries{},
+		&database.Booking{},
+		&database.BookingParticipant{},
+		&database.OpeningHours{},
+		&database.ScheduleClosure{},
+		&database.ScheduleBlackout{},
+		&database.PricingRule{},
+		&database.PromoCode{},
+		&database.PromoRedemption{},
+
+		// Módulo 3: Academia
+		&database.Class{},
+		&database.ClassEnrollment{},
+		&database.ClassTemplate{},
+		&database.ClassTemplateConflict{},
+
+		// Módulo 4: Clubs
+		&database.Club{},
+		&database.ClubMembership{},
+
+		// Módulo 5: Pagos
+		&database.BookingShare{},
+		&database.Payment{},
+		&database.Refund{},
+		&database.PaymentEvent{},
+		&database.IdempotencyKey{},
+		&database.Invoice{},
+		&database.InvoiceLine{},
+		&database.InvoiceSequence{},
+
+		// Módulo 6: Monedero
+		&database.WalletEntry{},
+
+		// Módulo 7: Bonos
+		&database.PassProduct{},
+		&database.Pass{},
+		&database.PassRedemption{},
+	}
+}
+
+// migrateInitialSchema crea el esquema inicial con AutoMigrate. Sobre una base de datos creada
+// por versiones anteriores (AutoMigrate en cada arranque) solo añade lo que falte, así que la
+// adopta sin perder datos.
+func migrateInitialSchema(tx *gorm.DB) error {
+	if err := tx.AutoMigrate(initialSchemaModels()...); err != nil {
+		return fmt.Errorf("error en AutoMigrate: %w", err)
+	}
+
+	// Generar slugs para clases existentes que no lo tienen
+	if err := generateMissingSlugs(tx); err != nil {
+		return fmt.Errorf("error generando slugs: %w", err)
+	}
+
+	return nil
+}
+
+// dropInitialSchema elimina las tablas del esquema inicial en orden inverso
+func dropInitialSchema(tx *gorm.DB) error {
+	models := initialSchemaModels()
+	for i := len(models) - 1; i >= 0; i-- {
+		if err := tx.Migrator().DropTable(models[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// generateMissingSlugs genera slugs para las clases existentes que no tienen
+func generateMissingSlugs(tx *gorm.DB) error {
+	var classes []database.Class
+	if err := tx.Where("slug IS NULL OR slug = ''").Find(&classes).Error; err != nil {
+		return err
+	}
+
+	for _, class := range classes {
+		slug := generateClassSlug(class.Title, class.StartTime)
+		if err := tx.Model(&class).Update("slug", slug).Error; err != nil {
+			return fmt.Errorf("error actualizando slug para clase %d: %w", class.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// generateClassSlug genera un slug único para una clase
+func generateClassSlug(title string, startTime time.Time) string {
+	// Formato: "title-YYYYMMDD-HHMM"
+	slug := title
+	slug = fmt.Sprintf("%s-%s", slug, startTime.Format("20060102-1504"))
+	return slug
+}
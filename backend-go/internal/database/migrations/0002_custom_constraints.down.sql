@@ -0,0 +1,2 @@
+ALTER TABLE bookings DROP CONSTRAINT IF EXISTS excl_booking_overlap;
+ALTER TABLE payments DROP CONSTRAINT IF EXISTS check_payment_origin;
@@ -0,0 +1,32 @@
+-- Constraints que GORM no crea automáticamente.
+-- Protegidos con IF NOT EXISTS para adoptar bases de datos creadas antes de las migraciones.
+
+-- Exclusive Arc en payments: exactamente un origen (Booking, ClassEnrollment, ClubMembership o Pass)
+DO $$ BEGIN
+	IF NOT EXISTS (
+		SELECT 1 FROM pg_constraint WHERE conname = 'check_payment_origin'
+	) THEN
+		ALTER TABLE payments ADD CONSTRAINT check_payment_origin CHECK (
+			(booking_id IS NOT NULL)::integer +
+			(class_enrollment_id IS NOT NULL)::integer +
+			(club_membership_id IS NOT NULL)::integer +
+			(pass_id IS NOT NULL)::integer
+			= 1
+		);
+	END IF;
+END $$;
+
+-- Exclusion constraint sobre rangos: impide a nivel de BD dos reservas activas solapadas en la
+-- misma pista aunque empiecen a horas distintas
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+DO $$ BEGIN
+	IF NOT EXISTS (
+		SELECT 1 FROM pg_constraint WHERE conname = 'excl_booking_overlap'
+	) THEN
+		ALTER TABLE bookings ADD CONSTRAINT excl_booking_overlap EXCLUDE USING gist (
+			pista_id WITH =,
+			tstzrange(start_time, end_time, '[)') WITH &&
+		) WHERE (status != 'CANCELLED' AND deleted_at IS NULL);
+	END IF;
+END $$;
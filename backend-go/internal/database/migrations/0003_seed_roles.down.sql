@@ -0,0 +1 @@
+DELETE FROM roles WHERE id IN (1, 2, 3, 4, 5) AND NOT EXISTS (SELECT 1 FROM users WHERE users.role_id = roles.id);
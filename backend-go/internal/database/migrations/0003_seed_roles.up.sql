@@ -0,0 +1,11 @@
+-- Roles del sistema: datos de referencia necesarios para registrar usuarios (CLIENTE = 5)
+INSERT INTO roles (id, name, description) VALUES
+	(1, 'ADMIN', 'Administrador con acceso completo al sistema'),
+	(2, 'GESTOR', 'Personal del polideportivo con permisos de gestión'),
+	(3, 'CLUB', 'Dueño/Gestor de club deportivo'),
+	(4, 'MONITOR', 'Monitor de clases y entrenamientos'),
+	(5, 'CLIENTE', 'Usuario externo del polideportivo')
+ON CONFLICT (id) DO NOTHING;
+
+-- Los IDs explícitos no avanzan la secuencia: se ajusta para futuros roles
+SELECT setval(pg_get_serial_sequence('roles', 'id'), (SELECT MAX(id) FROM roles));
@@ -0,0 +1,5 @@
+-- Un único pago vigente (no fallido ni reembolsado) por inscripción a clase.
+-- Protegido con IF NOT EXISTS para adoptar bases de datos creadas antes de las migraciones.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_payments_enrollment_active
+	ON payments (class_enrollment_id)
+	WHERE status != 'FAILED' AND status != 'REFUNDED';
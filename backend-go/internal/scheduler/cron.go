@@ -0,0 +1,151 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CronSchedule es una expresión cron estándar de 5 campos: minuto hora día-del-mes mes
+// día-de-la-semana. Admite *, listas (1,15), rangos (1-5), pasos (*/10, 8-20/2) y los atajos
+// @hourly, @daily, @weekly y @monthly. Día de la semana: 0-6 (0 y 7 = domingo).
+type CronSchedule struct {
+	expr                          string
+	minute, hour, dom, month, dow uint64 // Bit i activo = valor i permitido
+	domAny, dowAny                bool
+}
+
+var cronMacros = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+}
+
+// ParseCron interpreta una expresión cron
+func ParseCron(expr string) (*CronSchedule, error) {
+	normalized := strings.TrimSpace(expr)
+	if macro, ok := cronMacros[normalized]; ok {
+		normalized = macro
+	}
+
+	fields := strings.Fields(normalized)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("expresión cron inválida %q: se esperan 5 campos", expr)
+	}
+
+	schedule := &CronSchedule{expr: expr, domAny: fields[2] == "*", dowAny: fields[4] == "*"}
+	var err error
+	if schedule.minute, err = parseCronField(fields[0], 0, 59); err != nil {
+		return nil, fmt.Errorf("cron %q, minuto: %w", expr, err)
+	}
+	if schedule.hour, err = parseCronField(fields[1], 0, 23); err != nil {
+		return nil, fmt.Errorf("cron %q, hora: %w", expr, err)
+	}
+	if schedule.dom, err = parseCronField(fields[2], 1, 31); err != nil {
+		return nil, fmt.Errorf("cron %q, día del mes: %w", expr, err)
+	}
+	if schedule.month, err = parseCronField(fields[3], 1, 12); err != nil {
+		return nil, fmt.Errorf("cron %q, mes: %w", expr, err)
+	}
+	if schedule.dow, err = parseCronField(fields[4], 0, 7); err != nil {
+		return nil, fmt.Errorf("cron %q, día de la semana: %w", expr, err)
+	}
+	// El 7 también es domingo
+	if schedule.dow&(1<<7) != 0 {
+		schedule.dow |= 1
+	}
+
+	return schedule, nil
+}
+
+// String devuelve la expresión original
+func (c *CronSchedule) String() string {
+	return c.expr
+}
+
+// Next devuelve el primer minuto estrictamente posterior a after que cumple la expresión, en la
+// zona horaria de after. Devuelve el instante cero si no hay ninguno en los próximos 5 años.
+func (c *CronSchedule) Next(after time.Time) time.Time {
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := after.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if c.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !c.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if c.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if c.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+
+	return time.Time{}
+}
+
+// dayMatches aplica la regla clásica de cron: si se restringen día del mes y día de la semana
+// basta con que se cumpla uno de los dos
+func (c *CronSchedule) dayMatches(t time.Time) bool {
+	domMatch := c.dom&(1<<uint(t.Day())) != 0
+	dowMatch := c.dow&(1<<uint(t.Weekday())) != 0
+	switch {
+	case c.domAny && c.dowAny:
+		return true
+	case c.domAny:
+		return dowMatch
+	case c.dowAny:
+		return domMatch
+	}
+	return domMatch || dowMatch
+}
+
+// parseCronField convierte un campo (lista de rangos con paso opcional) en un conjunto de bits
+func parseCronField(field string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepPart); err != nil || step <= 0 {
+				return 0, fmt.Errorf("paso inválido %q", part)
+			}
+		}
+
+		start, end := min, max
+		if rangePart != "*" {
+			low, high, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if start, err = strconv.Atoi(low); err != nil {
+				return 0, fmt.Errorf("valor inválido %q", part)
+			}
+			end = start
+			if isRange {
+				if end, err = strconv.Atoi(high); err != nil {
+					return 0, fmt.Errorf("valor inválido %q", part)
+				}
+			} else if hasStep {
+				end = max // "5/15" = desde 5 hasta el máximo cada 15
+			}
+		}
+		if start < min || end > max || start > end {
+			return 0, fmt.Errorf("valor fuera de rango %q (%d-%d)", part, min, max)
+		}
+
+		for value := start; value <= end; value += step {
+			bits |= 1 << uint(value)
+		}
+	}
+	return bits, nil
+}
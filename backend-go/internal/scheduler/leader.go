@@ -0,0 +1,154 @@
+package scheduler
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"backend-go/shared/database"
+
+	"gorm.io/gorm"
+)
+
+// DefaultLeaseTTL es la duración del lease de liderazgo si no se indica otra
+const DefaultLeaseTTL = 30 * time.Second
+
+// Elector decide qué instancia lanza las ejecuciones programadas cuando hay varias réplicas
+type Elector interface {
+	Campaign() bool               // Adquiere o renueva el liderazgo; devuelve si esta instancia es líder
+	IsLeader() bool               // Si esta instancia es líder ahora (sin consultar la base de datos)
+	Resign()                      // Libera el liderazgo para que otra instancia lo tome sin esperar
+	RenewInterval() time.Duration // Cada cuánto debe llamarse a Campaign
+	Current() (*Lease, error)     // Lease vigente (nil si no hay líder)
+}
+
+// Lease es el liderazgo vigente
+type Lease struct {
+	Holder     string
+	AcquiredAt time.Time
+	RenewedAt  time.Time
+	ExpiresAt  time.Time
+}
+
+// InstanceID identifica esta instancia ante las demás (hostname:pid). Dos procesos en la misma
+// máquina tienen IDs distintos; un contenedor reiniciado recupera su propio lease.
+func InstanceID() string {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "desconocida"
+	}
+	return fmt.Sprintf("%s:%d", hostname, os.Getpid())
+}
+
+// LeaseElector implementa Elector con una fila en scheduler_leases. El líder renueva el lease
+// cada TTL/3; si deja de hacerlo (caída, bloqueo, red), otra instancia lo adquiere al caducar.
+// Las horas las pone PostgreSQL, así que no influye el desfase de reloj entre réplicas.
+type LeaseElector struct {
+	db     *gorm.DB
+	name   string
+	holder string
+	ttl    time.Duration
+
+	mu          sync.Mutex
+	leaderUntil time.Time // Hora local hasta la que el lease es nuestro con seguridad
+}
+
+// NewLeaseElector crea un elector para el lease name (ttl 0 = DefaultLeaseTTL)
+func NewLeaseElector(db *gorm.DB, name, holder string, ttl time.Duration) *LeaseElector {
+	if ttl <= 0 {
+		ttl = DefaultLeaseTTL
+	}
+	return &LeaseElector{db: db, name: name, holder: holder, ttl: ttl}
+}
+
+// Campaign intenta adquirir el lease (si está libre o caducado) o renovarlo (si ya es nuestro)
+func (e *LeaseElector) Campaign() bool {
+	// El plazo local se cuenta desde antes de la consulta: nunca creemos tenerlo más de lo real
+	requestedAt := time.Now()
+
+	var holders []string
+	err := e.db.Raw(`
+		INSERT INTO scheduler_leases (name, holder, acquired_at, renewed_at, expires_at)
+		VALUES (?, ?, now(), now(), now() + make_interval(secs => ?))
+		ON CONFLICT (name) DO UPDATE SET
+			holder      = EXCLUDED.holder,
+			acquired_at = CASE WHEN scheduler_leases.holder = EXCLUDED.holder
+			                   THEN scheduler_leases.acquired_at ELSE now() END,
+			renewed_at  = now(),
+			expires_at  = EXCLUDED.expires_at
+		WHERE scheduler_leases.holder = EXCLUDED.holder OR scheduler_leases.expires_at < now()
+		RETURNING holder`,
+		e.name, e.holder, e.ttl.Seconds(),
+	).Scan(&holders).Error
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	wasLeader := time.Now().Before(e.leaderUntil)
+
+	if err != nil {
+		// Sin base de datos no se puede renovar: el liderazgo local caduca solo con el TTL
+		log.Printf("[Scheduler] ⚠️  Error renovando el lease de liderazgo: %v", err)
+		return wasLeader
+	}
+
+	if len(holders) == 0 {
+		e.leaderUntil = time.Time{}
+		if wasLeader {
+			log.Printf("[Scheduler] ⚠️  Liderazgo perdido: otra instancia tiene el lease")
+		}
+		return false
+	}
+
+	e.leaderUntil = requestedAt.Add(e.ttl)
+	if !wasLeader {
+		log.Printf("[Scheduler] 👑 Esta instancia (%s) es ahora líder del scheduler", e.holder)
+	}
+	return true
+}
+
+// IsLeader indica si el lease es nuestro y no ha caducado
+func (e *LeaseElector) IsLeader() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return time.Now().Before(e.leaderUntil)
+}
+
+// Resign libera el lease si es nuestro
+func (e *LeaseElector) Resign() {
+	e.mu.Lock()
+	e.leaderUntil = time.Time{}
+	e.mu.Unlock()
+
+	result := e.db.Where("name = ? AND holder = ?", e.name, e.holder).Delete(&database.SchedulerLease{})
+	if result.Error != nil {
+		log.Printf("[Scheduler] ⚠️  Error liberando el lease de liderazgo: %v", result.Error)
+		return
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("[Scheduler] Liderazgo liberado")
+	}
+}
+
+// RenewInterval devuelve TTL/3: da margen a dos renovaciones fallidas antes de perder el lease
+func (e *LeaseElector) RenewInterval() time.Duration {
+	return e.ttl / 3
+}
+
+// Current devuelve el lease vigente
+func (e *LeaseElector) Current() (*Lease, error) {
+	var leases []database.SchedulerLease
+	if err := e.db.Where("name = ? AND expires_at > now()", e.name).Limit(1).Find(&leases).Error; err != nil {
+		return nil, err
+	}
+	if len(leases) == 0 {
+		return nil, nil
+	}
+	return &Lease{
+		Holder:     leases[0].Holder,
+		AcquiredAt: leases[0].AcquiredAt,
+		RenewedAt:  leases[0].RenewedAt,
+		ExpiresAt:  leases[0].ExpiresAt,
+	}, nil
+}
@@ -0,0 +1,143 @@
+package scheduler
+
+import (
+	"time"
+
+	"backend-go/shared/database"
+
+	"gorm.io/gorm"
+)
+
+// Estados de una ejecución
+const (
+	RunStatusRunning = "RUNNING"
+	RunStatusSuccess = "SUCCESS"
+	RunStatusFailed  = "FAILED"
+	RunStatusTimeout = "TIMEOUT"
+)
+
+// Origen de una ejecución
+const (
+	TriggerSchedule = "SCHEDULE"
+	TriggerManual   = "MANUAL"
+)
+
+// DefaultHistoryLimit es el número de ejecuciones devueltas si no se indica límite
+const DefaultHistoryLimit = 50
+
+// MaxHistoryLimit es el máximo de ejecuciones devueltas por consulta
+const MaxHistoryLimit = 500
+
+// Run es una ejecución de una tarea
+type Run struct {
+	ID           uint
+	TaskID       string
+	TaskName     string
+	Trigger      string
+	Status       string
+	Instance     string
+	StartedAt    time.Time
+	FinishedAt   *time.Time
+	AffectedRows int
+	Error        *string
+}
+
+// Duration devuelve lo que tardó la ejecución (o lo que lleva si sigue en curso)
+func (r *Run) Duration() time.Duration {
+	if r.FinishedAt == nil {
+		return time.Since(r.StartedAt)
+	}
+	return r.FinishedAt.Sub(r.StartedAt)
+}
+
+// RunStore persiste el historial de ejecuciones
+type RunStore interface {
+	Start(run *Run) error // Inserta la ejecución en curso y rellena run.ID
+	Finish(run *Run) error
+	List(taskID string, limit int) ([]Run, error) // Más recientes primero; taskID vacío = todas
+}
+
+// gormRunStore guarda el historial en la tabla scheduler_runs
+type gormRunStore struct {
+	db *gorm.DB
+}
+
+// NewRunStore crea un RunStore sobre PostgreSQL
+func NewRunStore(db *gorm.DB) RunStore {
+	return &gormRunStore{db: db}
+}
+
+func (s *gormRunStore) Start(run *Run) error {
+	model := toModel(run)
+	if err := s.db.Create(&model).Error; err != nil {
+		return err
+	}
+	run.ID = model.ID
+	return nil
+}
+
+func (s *gormRunStore) Finish(run *Run) error {
+	if run.ID == 0 {
+		// El inicio no se pudo registrar: se guarda la ejecución completa
+		return s.Start(run)
+	}
+	return s.db.Model(&database.SchedulerRun{}).
+		Where("id = ?", run.ID).
+		Updates(map[string]interface{}{
+			"status":        run.Status,
+			"finished_at":   run.FinishedAt,
+			"affected_rows": run.AffectedRows,
+			"error":         run.Error,
+		}).Error
+}
+
+func (s *gormRunStore) List(taskID string, limit int) ([]Run, error) {
+	if limit <= 0 {
+		limit = DefaultHistoryLimit
+	}
+	if limit > MaxHistoryLimit {
+		limit = MaxHistoryLimit
+	}
+
+	query := s.db.Order("started_at DESC, id DESC").Limit(limit)
+	if taskID != "" {
+		query = query.Where("task_id = ?", taskID)
+	}
+
+	var models []database.SchedulerRun
+	if err := query.Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	runs := make([]Run, len(models))
+	for i, model := range models {
+		runs[i] = Run{
+			ID:           model.ID,
+			TaskID:       model.TaskID,
+			TaskName:     model.TaskName,
+			Trigger:      model.Trigger,
+			Status:       model.Status,
+			Instance:     model.Instance,
+			StartedAt:    model.StartedAt,
+			FinishedAt:   model.FinishedAt,
+			AffectedRows: model.AffectedRows,
+			Error:        model.Error,
+		}
+	}
+	return runs, nil
+}
+
+func toModel(run *Run) database.SchedulerRun {
+	return database.SchedulerRun{
+		ID:           run.ID,
+		TaskID:       run.TaskID,
+		TaskName:     run.TaskName,
+		Trigger:      run.Trigger,
+		Status:       run.Status,
+		Instance:     run.Instance,
+		StartedAt:    run.StartedAt,
+		FinishedAt:   run.FinishedAt,
+		AffectedRows: run.AffectedRows,
+		Error:        run.Error,
+	}
+}
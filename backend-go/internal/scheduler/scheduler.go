@@ -1,77 +1,620 @@
 package scheduler
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log"
+	"sync"
 	"time"
 )
 
-// Scheduler representa un servicio de tareas programadas
-type Scheduler struct {
-	ticker   *time.Ticker
-	stopChan chan bool
-	tasks    []ScheduledTask
-}
+// DefaultTaskTimeout se aplica a las tareas sin Timeout
+const DefaultTaskTimeout = 5 * time.Minute
+
+// DefaultMaxConcurrent es el número de tareas simultáneas por defecto
+const DefaultMaxConcurrent = 2
+
+// Errores del scheduler
+var (
+	ErrTaskNotFound     = errors.New("tarea no encontrada")
+	ErrTaskRunning      = errors.New("la tarea ya se está ejecutando")
+	ErrTaskLocked       = errors.New("la tarea se está ejecutando en otra instancia")
+	ErrSchedulerBusy    = errors.New("se ha alcanzado el límite de tareas simultáneas")
+	ErrSchedulerStopped = errors.New("el scheduler se está deteniendo")
+)
 
 // ScheduledTask representa una tarea que se ejecuta periódicamente
 type ScheduledTask struct {
+	ID       string        // Identificador estable (historial, endpoints y CLI)
+	Name     string        // Descripción legible
+	Interval time.Duration // Cada cuánto se ejecuta (si no hay Cron); la primera vez al arrancar
+	Cron     string        // Expresión cron de 5 campos; tiene prioridad sobre Interval
+	Timeout  time.Duration // Tiempo máximo por ejecución (0 = DefaultTaskTimeout)
+	// Execute realiza el trabajo y devuelve el número de filas afectadas. Debe respetar ctx:
+	// se cancela al vencer Timeout o al agotarse la espera de la parada. Si no lo respeta, la
+	// ejecución queda como TIMEOUT pero la tarea sigue ocupada hasta que Execute vuelva.
+	Execute func(ctx context.Context) (int, error)
+}
+
+// Options configura el scheduler
+type Options struct {
+	MaxConcurrent int            // Tareas simultáneas (0 = DefaultMaxConcurrent)
+	Location      *time.Location // Zona horaria de las expresiones cron (nil = time.Local)
+	Store         RunStore       // Historial de ejecuciones (nil = sin persistir)
+	Instance      string         // Identificador de esta instancia (vacío = InstanceID())
+	Elector       Elector        // Liderazgo entre réplicas (nil = esta instancia siempre es líder)
+	Locker        TaskLocker     // Exclusión de cada tarea entre réplicas (nil = solo en memoria)
+}
+
+// Leadership es el estado del liderazgo visto desde esta instancia
+type Leadership struct {
+	Instance string
+	IsLeader bool
+	Lease    *Lease // nil si no hay elector o ningún líder vigente
+}
+
+// TaskInfo es el estado actual de una tarea
+type TaskInfo struct {
+	ID       string
 	Name     string
-	Interval time.Duration
-	Execute  func() error
+	Schedule string // "cada 10m0s" o la expresión cron
+	Timeout  time.Duration
+	Running  bool
+	NextRun  *time.Time
+	LastRun  *Run
+}
+
+// taskState es una tarea registrada con su planificación y su estado en memoria
+type taskState struct {
+	task    ScheduledTask
+	cron    *CronSchedule
+	mu      sync.Mutex
+	running bool
+	unlock  func() // Libera el lock distribuido de la ejecución en curso
+	nextRun time.Time
+	lastRun *Run
+}
+
+// Scheduler ejecuta cada tarea según su propia planificación (intervalo o cron), con un límite
+// de tareas simultáneas, timeout por ejecución e historial persistido.
+//
+// Con varias réplicas, solo la líder (Elector) lanza las ejecuciones programadas y el historial
+// evita repetir un tick que ya ejecutó otra instancia (p. ej. la líder anterior). Además, el
+// Locker impide que la misma tarea corra a la vez en dos instancias, incluidas las manuales.
+type Scheduler struct {
+	tasks    []*taskState
+	byID     map[string]*taskState
+	slots    chan struct{} // Semáforo de concurrencia
+	location *time.Location
+	store    RunStore
+	instance string
+	elector  Elector
+	locker   TaskLocker
+
+	ctx      context.Context // Se cancela si la parada agota su tiempo
+	cancel   context.CancelFunc
+	stopChan chan struct{}
+	stopOnce sync.Once
+	running  sync.WaitGroup // Bucles de tareas y ejecuciones en curso
+
+	campaigning  bool // Start lanzó la campaña de liderazgo (solo con elector)
+	electionStop chan struct{}
+	electionDone chan struct{}
+	resignOnce   sync.Once
 }
 
 // NewScheduler crea una nueva instancia del scheduler
-func NewScheduler(interval time.Duration) *Scheduler {
+func NewScheduler(options Options) *Scheduler {
+	maxConcurrent := options.MaxConcurrent
+	if maxConcurrent <= 0 {
+		maxConcurrent = DefaultMaxConcurrent
+	}
+	location := options.Location
+	if location == nil {
+		location = time.Local
+	}
+	instance := options.Instance
+	if instance == "" {
+		instance = InstanceID()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
 	return &Scheduler{
-		ticker:   time.NewTicker(interval),
-		stopChan: make(chan bool),
-		tasks:    []ScheduledTask{},
+		byID:     map[string]*taskState{},
+		slots:    make(chan struct{}, maxConcurrent),
+		location: location,
+		store:    options.Store,
+		instance: instance,
+		elector:  options.Elector,
+		locker:   options.Locker,
+		ctx:      ctx,
+		cancel:   cancel,
+		stopChan: make(chan struct{}),
+
+		electionStop: make(chan struct{}),
+		electionDone: make(chan struct{}),
 	}
 }
 
-// AddTask añade una tarea al scheduler
-func (s *Scheduler) AddTask(task ScheduledTask) {
-	s.tasks = append(s.tasks, task)
-	log.Printf("[Scheduler] Tarea añadida: %s (intervalo: %v)", task.Name, task.Interval)
+// AddTask añade una tarea al scheduler. Devuelve error si el ID está repetido o la
+// planificación no es válida.
+func (s *Scheduler) AddTask(task ScheduledTask) error {
+	if task.ID == "" || task.Execute == nil {
+		return fmt.Errorf("tarea %q inválida: ID y Execute son obligatorios", task.Name)
+	}
+	if _, exists := s.byID[task.ID]; exists {
+		return fmt.Errorf("tarea duplicada: %s", task.ID)
+	}
+
+	state := &taskState{task: task}
+	if task.Cron != "" {
+		cron, err := ParseCron(task.Cron)
+		if err != nil {
+			return err
+		}
+		state.cron = cron
+	} else if task.Interval <= 0 {
+		return fmt.Errorf("tarea %s sin planificación: indica Interval o Cron", task.ID)
+	}
+
+	s.tasks = append(s.tasks, state)
+	s.byID[task.ID] = state
+	log.Printf("[Scheduler] Tarea añadida: %s (%s)", task.Name, state.schedule())
+	return nil
 }
 
-// Start inicia el scheduler en una goroutine
+// Start inicia un bucle por tarea en segundo plano
 func (s *Scheduler) Start() {
 	log.Println("[Scheduler] Iniciando scheduler de tareas automáticas...")
 
-	// Ejecutar todas las tareas inmediatamente al inicio
+	if s.elector != nil {
+		// Primera campaña antes de lanzar los bucles para que la líder no espere una renovación
+		s.elector.Campaign()
+		s.campaigning = true
+		go s.campaign()
+	}
+
+	for _, state := range s.tasks {
+		s.running.Add(1)
+		go s.loop(state)
+	}
+}
+
+// Stop detiene el scheduler y espera a que terminen las tareas en curso
+func (s *Scheduler) Stop() {
+	_ = s.Shutdown(context.Background())
+}
+
+// Shutdown deja de lanzar tareas y espera a que terminen las que están en curso o a que venza
+// ctx; en ese caso cancela su contexto. Es seguro llamarlo varias veces.
+func (s *Scheduler) Shutdown(ctx context.Context) error {
+	s.stopOnce.Do(func() {
+		close(s.stopChan)
+	})
+
+	done := make(chan struct{})
 	go func() {
-		log.Println("[Scheduler] Ejecutando tareas iniciales...")
-		s.runAllTasks()
+		s.running.Wait()
+		close(done)
 	}()
 
-	// Luego ejecutar en intervalos
-	go func() {
-		for {
-			select {
-			case <-s.ticker.C:
-				s.runAllTasks()
-			case <-s.stopChan:
-				log.Println("[Scheduler] Deteniendo scheduler...")
-				return
-			}
+	// El liderazgo se mantiene mientras terminan las tareas en curso y se libera al final,
+	// para que otra réplica lo tome sin esperar a que caduque el lease
+	defer s.resign()
+
+	select {
+	case <-done:
+		log.Println("[Scheduler] Scheduler detenido")
+		return nil
+	case <-ctx.Done():
+		log.Println("[Scheduler] Tiempo agotado esperando a las tareas en curso: cancelándolas")
+		s.cancel()
+		return ctx.Err()
+	}
+}
+
+// Leadership devuelve si esta instancia es líder y el lease vigente
+func (s *Scheduler) Leadership() (*Leadership, error) {
+	leadership := &Leadership{Instance: s.instance, IsLeader: s.isLeader()}
+	if s.elector == nil {
+		return leadership, nil
+	}
+	lease, err := s.elector.Current()
+	if err != nil {
+		return nil, err
+	}
+	leadership.Lease = lease
+	return leadership, nil
+}
+
+// Tasks devuelve el estado de todas las tareas
+func (s *Scheduler) Tasks() []TaskInfo {
+	infos := make([]TaskInfo, len(s.tasks))
+	for i, state := range s.tasks {
+		infos[i] = state.info()
+	}
+	return infos
+}
+
+// Task devuelve el estado de una tarea
+func (s *Scheduler) Task(id string) (*TaskInfo, error) {
+	state, ok := s.byID[id]
+	if !ok {
+		return nil, ErrTaskNotFound
+	}
+	info := state.info()
+	return &info, nil
+}
+
+// History devuelve las últimas ejecuciones (de una tarea o de todas si taskID está vacío)
+func (s *Scheduler) History(taskID string, limit int) ([]Run, error) {
+	if taskID != "" {
+		if _, ok := s.byID[taskID]; !ok {
+			return nil, ErrTaskNotFound
 		}
+	}
+	if s.store == nil {
+		return []Run{}, nil
+	}
+	return s.store.List(taskID, limit)
+}
+
+// Trigger lanza una ejecución manual en segundo plano y devuelve su registro inicial.
+// Falla si la tarea ya está en marcha o no hay hueco libre.
+func (s *Scheduler) Trigger(id string) (*Run, error) {
+	state, ok := s.byID[id]
+	if !ok {
+		return nil, ErrTaskNotFound
+	}
+	if s.stopping() {
+		return nil, ErrSchedulerStopped
+	}
+
+	select {
+	case s.slots <- struct{}{}:
+	default:
+		return nil, ErrSchedulerBusy
+	}
+	run, err := s.begin(state, TriggerManual)
+	if err != nil {
+		<-s.slots
+		return nil, err
+	}
+
+	s.running.Add(1)
+	go func() {
+		defer s.running.Done()
+		defer func() { <-s.slots }()
+		s.execute(state, run)
 	}()
+
+	snapshot := *run
+	return &snapshot, nil
 }
 
-// Stop detiene el scheduler
-func (s *Scheduler) Stop() {
-	s.ticker.Stop()
-	s.stopChan <- true
+// RunNow ejecuta una tarea de forma síncrona (CLI) y devuelve el registro final
+func (s *Scheduler) RunNow(id string) (*Run, error) {
+	state, ok := s.byID[id]
+	if !ok {
+		return nil, ErrTaskNotFound
+	}
+
+	s.slots <- struct{}{}
+	defer func() { <-s.slots }()
+
+	run, err := s.begin(state, TriggerManual)
+	if err != nil {
+		return nil, err
+	}
+	s.execute(state, run)
+	return run, nil
 }
 
-// runAllTasks ejecuta todas las tareas registradas
-func (s *Scheduler) runAllTasks() {
-	for _, task := range s.tasks {
-		log.Printf("[Scheduler] Ejecutando tarea: %s", task.Name)
-		if err := task.Execute(); err != nil {
-			log.Printf("[Scheduler] Error en tarea %s: %v", task.Name, err)
+// loop espera a la siguiente ejecución de la tarea y la lanza, hasta que se detenga el scheduler.
+// Una tarea nunca se solapa consigo misma: la siguiente se calcula al terminar la anterior.
+func (s *Scheduler) loop(state *taskState) {
+	defer s.running.Done()
+
+	now := time.Now()
+	next := now // Las tareas por intervalo se ejecutan al arrancar (salvo que el historial diga otra cosa)
+	if state.cron != nil {
+		next = state.cron.Next(now.In(s.location))
+	}
+
+	for {
+		if next.IsZero() {
+			log.Printf("[Scheduler] %s: la expresión cron no tiene próximas ejecuciones", state.task.ID)
+			return
+		}
+		state.setNextRun(next)
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-s.stopChan:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		// Solo la líder lanza las ejecuciones programadas; las demás vuelven a mirar más tarde
+		if !s.isLeader() {
+			next = s.followerNext(state)
+			continue
+		}
+		// Otra instancia (o esta antes de reiniciar) ya ejecutó este tick
+		if due, ran := s.alreadyRan(state, next); ran {
+			next = due
+			continue
+		}
+
+		// Esperar hueco en el semáforo de concurrencia
+		select {
+		case s.slots <- struct{}{}:
+		case <-s.stopChan:
+			return
+		}
+		if run, err := s.begin(state, TriggerSchedule); err != nil {
+			log.Printf("[Scheduler] %s omitida: %v", state.task.Name, err)
+		} else {
+			s.execute(state, run)
+		}
+		<-s.slots
+
+		if state.cron != nil {
+			next = state.cron.Next(time.Now().In(s.location))
 		} else {
-			log.Printf("[Scheduler] Tarea %s completada exitosamente", task.Name)
+			next = time.Now().Add(state.task.Interval)
+		}
+	}
+}
+
+// followerNext es cuándo vuelve a comprobar el liderazgo una instancia que no es líder: las tareas
+// por intervalo, en la próxima renovación del lease (para tomar el relevo cuanto antes); las cron,
+// en su siguiente tick
+func (s *Scheduler) followerNext(state *taskState) time.Time {
+	if state.cron != nil {
+		return state.cron.Next(time.Now().In(s.location))
+	}
+	wait := state.task.Interval
+	if s.elector != nil && s.elector.RenewInterval() < wait {
+		wait = s.elector.RenewInterval()
+	}
+	return time.Now().Add(wait)
+}
+
+// alreadyRan consulta la última ejecución registrada de la tarea. Una tarea cron ya se ejecutó si
+// empezó en o después del tick; una por intervalo, si empezó hace menos de un intervalo, y en ese
+// caso le toca cuando se cumpla. Devuelve la próxima ejecución si hay que saltar este tick.
+func (s *Scheduler) alreadyRan(state *taskState, tick time.Time) (time.Time, bool) {
+	if s.store == nil {
+		return time.Time{}, false
+	}
+	runs, err := s.store.List(state.task.ID, 1)
+	if err != nil {
+		log.Printf("[Scheduler] Error consultando el historial de %s: %v", state.task.ID, err)
+		return time.Time{}, false
+	}
+	if len(runs) == 0 {
+		return time.Time{}, false
+	}
+	lastStart := runs[0].StartedAt
+
+	if state.cron != nil {
+		if lastStart.Before(tick) {
+			return time.Time{}, false
+		}
+		return state.cron.Next(time.Now().In(s.location)), true
+	}
+
+	due := lastStart.Add(state.task.Interval)
+	if due.After(time.Now()) {
+		return due, true
+	}
+	return time.Time{}, false
+}
+
+// campaign renueva el liderazgo periódicamente hasta resign
+func (s *Scheduler) campaign() {
+	defer close(s.electionDone)
+
+	ticker := time.NewTicker(s.elector.RenewInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.electionStop:
+			return
+		case <-ticker.C:
+			s.elector.Campaign()
+		}
+	}
+}
+
+// resign detiene la campaña y libera el liderazgo. Sin campaña (sin elector o sin Start)
+// no hay nada que esperar ni liberar.
+func (s *Scheduler) resign() {
+	s.resignOnce.Do(func() {
+		close(s.electionStop)
+		if !s.campaigning {
+			return
+		}
+		<-s.electionDone
+		s.elector.Resign()
+	})
+}
+
+func (s *Scheduler) isLeader() bool {
+	return s.elector == nil || s.elector.IsLeader()
+}
+
+// begin marca la tarea en curso (en esta instancia y, con Locker, en todas) y registra el inicio
+// de la ejecución
+func (s *Scheduler) begin(state *taskState, trigger string) (*Run, error) {
+	state.mu.Lock()
+	if state.running {
+		state.mu.Unlock()
+		return nil, ErrTaskRunning
+	}
+	state.running = true
+	state.mu.Unlock()
+
+	unlock := func() {}
+	if s.locker != nil {
+		var err error
+		if unlock, err = s.locker.TryLock(state.task.ID); err != nil {
+			state.mu.Lock()
+			state.running = false
+			state.mu.Unlock()
+			return nil, err
 		}
 	}
+	state.mu.Lock()
+	state.unlock = unlock
+	state.mu.Unlock()
+
+	run := &Run{
+		TaskID:    state.task.ID,
+		TaskName:  state.task.Name,
+		Trigger:   trigger,
+		Status:    RunStatusRunning,
+		Instance:  s.instance,
+		StartedAt: time.Now(),
+	}
+	if s.store != nil {
+		if err := s.store.Start(run); err != nil {
+			log.Printf("[Scheduler] Error registrando el inicio de %s: %v", state.task.ID, err)
+		}
+	}
+	return run, nil
+}
+
+// execute ejecuta la tarea con su timeout y registra el resultado. Si vence el timeout, la
+// ejecución queda como TIMEOUT pero execute no vuelve hasta que la tarea termine de verdad.
+func (s *Scheduler) execute(state *taskState, run *Run) {
+	task := state.task
+	timeout := task.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTaskTimeout
+	}
+	ctx, cancel := context.WithTimeout(s.ctx, timeout)
+	defer cancel()
+
+	log.Printf("[Scheduler] Ejecutando tarea: %s", task.Name)
+
+	type result struct {
+		affected int
+		err      error
+	}
+	done := make(chan result, 1)
+	go func() {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				done <- result{err: fmt.Errorf("panic: %v", recovered)}
+			}
+		}()
+		affected, err := task.Execute(ctx)
+		done <- result{affected, err}
+	}()
+
+	var res result
+	timedOut := false
+	select {
+	case res = <-done:
+	case <-ctx.Done():
+		// La ejecución se registra como TIMEOUT ya, aunque la tarea siga terminando
+		res = result{err: ctx.Err()}
+		timedOut = true
+	}
+
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	run.AffectedRows = res.affected
+	switch {
+	case res.err == nil:
+		run.Status = RunStatusSuccess
+		log.Printf("[Scheduler] Tarea %s completada (%d filas, %v)", task.Name, res.affected, run.Duration())
+	case errors.Is(res.err, context.DeadlineExceeded) || errors.Is(res.err, context.Canceled):
+		run.Status = RunStatusTimeout
+		message := res.err.Error()
+		run.Error = &message
+		log.Printf("[Scheduler] Tarea %s cancelada por timeout (%v)", task.Name, timeout)
+	default:
+		run.Status = RunStatusFailed
+		message := res.err.Error()
+		run.Error = &message
+		log.Printf("[Scheduler] Error en tarea %s: %v", task.Name, res.err)
+	}
+
+	if s.store != nil {
+		if err := s.store.Finish(run); err != nil {
+			log.Printf("[Scheduler] Error registrando el resultado de %s: %v", task.ID, err)
+		}
+	}
+	finished := *run
+	state.mu.Lock()
+	state.lastRun = &finished
+	state.mu.Unlock()
+
+	if timedOut {
+		// Hasta que Execute vuelva la tarea sigue en curso: se conservan el lock, la marca de
+		// ejecución y el hueco del semáforo para que nada se solape con ella
+		<-done
+		log.Printf("[Scheduler] Tarea %s terminó %v después del timeout", task.Name, time.Since(finishedAt).Round(time.Millisecond))
+	}
+
+	state.mu.Lock()
+	unlock := state.unlock
+	state.unlock = nil
+	state.mu.Unlock()
+	unlock() // Antes de marcarla libre: una nueva ejecución local necesita el lock
+
+	state.mu.Lock()
+	state.running = false
+	state.mu.Unlock()
+}
+
+func (s *Scheduler) stopping() bool {
+	select {
+	case <-s.stopChan:
+		return true
+	default:
+		return false
+	}
+}
+
+func (t *taskState) schedule() string {
+	if t.cron != nil {
+		return "cron " + t.cron.String()
+	}
+	return fmt.Sprintf("cada %v", t.task.Interval)
+}
+
+func (t *taskState) setNextRun(next time.Time) {
+	t.mu.Lock()
+	t.nextRun = next
+	t.mu.Unlock()
+}
+
+func (t *taskState) info() TaskInfo {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	timeout := t.task.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTaskTimeout
+	}
+	info := TaskInfo{
+		ID:       t.task.ID,
+		Name:     t.task.Name,
+		Schedule: t.schedule(),
+		Timeout:  timeout,
+		Running:  t.running,
+		LastRun:  t.lastRun,
+	}
+	if !t.nextRun.IsZero() {
+		next := t.nextRun
+		info.NextRun = &next
+	}
+	return info
 }
@@ -0,0 +1,68 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql/driver"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// taskLockClass es el primer entero de los advisory locks de tareas (el segundo es el hash del ID).
+// Distinto del lock de migraciones, que usa la variante de un solo bigint.
+const taskLockClass = 7_261_002
+
+// TaskLocker impide que una tarea se ejecute a la vez en dos instancias
+type TaskLocker interface {
+	// TryLock adquiere el lock de la tarea sin esperar. Devuelve ErrTaskLocked si otra instancia
+	// lo tiene; unlock debe llamarse al terminar la ejecución.
+	TryLock(taskID string) (unlock func(), err error)
+}
+
+// advisoryTaskLocker usa pg_try_advisory_lock en una conexión reservada durante la ejecución.
+// Si la instancia muere, PostgreSQL cierra su sesión y libera el lock.
+type advisoryTaskLocker struct {
+	db *gorm.DB
+}
+
+// NewAdvisoryTaskLocker crea un TaskLocker sobre PostgreSQL
+func NewAdvisoryTaskLocker(db *gorm.DB) TaskLocker {
+	return &advisoryTaskLocker{db: db}
+}
+
+func (l *advisoryTaskLocker) TryLock(taskID string) (func(), error) {
+	sqlDB, err := l.db.DB()
+	if err != nil {
+		return nil, err
+	}
+	conn, err := sqlDB.Conn(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	var acquired bool
+	if err := conn.QueryRowContext(context.Background(),
+		"SELECT pg_try_advisory_lock($1, hashtext($2))", taskLockClass, taskID,
+	).Scan(&acquired); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if !acquired {
+		conn.Close()
+		return nil, ErrTaskLocked
+	}
+
+	unlock := func() {
+		var released bool
+		err := conn.QueryRowContext(context.Background(),
+			"SELECT pg_advisory_unlock($1, hashtext($2))", taskLockClass, taskID,
+		).Scan(&released)
+		if err != nil || !released {
+			log.Printf("[Scheduler] ⚠️  No se pudo liberar el lock de %s: descartando la conexión", taskID)
+			// Una conexión con el lock aún tomado no puede volver al pool
+			_ = conn.Raw(func(interface{}) error { return driver.ErrBadConn })
+		}
+		conn.Close()
+	}
+	return unlock, nil
+}
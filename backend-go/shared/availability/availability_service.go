@@ -2,7 +2,9 @@ package availability
 
 import (
 	"backend-go/shared/database"
+	"database/sql"
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -10,13 +12,38 @@ import (
 )
 
 type AvailabilityService struct {
-	db *gorm.DB
+	db       *gorm.DB
+	schedule ScheduleProvider // Opcional: horario de apertura, cierres y bloqueos
 }
 
 func NewAvailabilityService(db *gorm.DB) *AvailabilityService {
 	return &AvailabilityService{db: db}
 }
 
+// maxSerializableRetries número de reintentos ante fallos de serialización (SQLSTATE 40001)
+const maxSerializableRetries = 3
+
+// RunSerializable ejecuta fn en una transacción SERIALIZABLE. Si Postgres aborta la
+// transacción por un conflicto con otra concurrente (40001/40P01) se reintenta, de modo
+// que de dos peticiones que compiten por el mismo hueco solo una pueda confirmarse.
+func (s *AvailabilityService) RunSerializable(fn func(tx *gorm.DB) error) error {
+	var err error
+	for attempt := 0; attempt <= maxSerializableRetries; attempt++ {
+		err = s.db.Transaction(fn, &sql.TxOptions{Isolation: sql.LevelSerializable})
+		if err == nil || !isSerializationFailure(err) {
+			return err
+		}
+		time.Sleep(time.Duration(attempt+1) * 20 * time.Millisecond)
+	}
+	return errors.New("la pista está siendo reservada por otra petición, inténtalo de nuevo")
+}
+
+// isSerializationFailure detecta los errores de Postgres que indican que la transacción se puede reintentar
+func isSerializationFailure(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "40001") || strings.Contains(msg, "40P01")
+}
+
 // CheckPistaAvailable verifica que una pista esté disponible en un rango de tiempo
 // excluyendo opcionalmente un booking o clase específica (para ediciones)
 func (s *AvailabilityService) CheckPistaAvailable(
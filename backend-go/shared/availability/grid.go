@@ -0,0 +1,233 @@
+package availability
+
+import (
+	"backend-go/shared/database"
+	"errors"
+	"time"
+)
+
+// Estados de un hueco de la rejilla de disponibilidad
+const (
+	SlotFree   = "FREE"
+	SlotBooked = "BOOKED"
+	SlotClass  = "CLASS"
+	SlotPast   = "PAST"
+	SlotClosed = "CLOSED" // Bloqueo puntual (mantenimiento, eventos...)
+)
+
+// Límites de la rejilla
+const (
+	DefaultSlotMinutes = 30
+	MinSlotMinutes     = 15
+	MaxSlotMinutes     = 180
+	MaxGridDays        = 31
+)
+
+var (
+	ErrInvalidGridRange = errors.New("rango de fechas inválido (la fecha final debe ser igual o posterior a la inicial)")
+	ErrGridRangeTooLong = errors.New("el rango de fechas no puede superar los 31 días")
+	ErrInvalidSlotSize  = errors.New("tamaño de hueco inválido (entre 15 y 180 minutos, divisor de 60 o múltiplo de 60)")
+)
+
+// GridQuery define los parámetros de la rejilla de disponibilidad.
+// From y To son días (se ignora la hora) y ambos se incluyen en el resultado.
+type GridQuery struct {
+	PistaIDs    []int // Vacío = todas las pistas activas
+	From        time.Time
+	To          time.Time
+	SlotMinutes int
+}
+
+// GridSlot representa un hueco de la rejilla con su estado. El último hueco de cada día puede
+// ser más corto que SlotMinutes si el horario de apertura no es múltiplo del tamaño del hueco.
+type GridSlot struct {
+	StartTime time.Time
+	EndTime   time.Time
+	Status    string
+	BookingID *uint // Solo si Status == BOOKED
+	ClassID   *uint // Solo si Status == CLASS
+}
+
+// PistaGrid agrupa los huecos de una pista en el rango solicitado
+type PistaGrid struct {
+	PistaID   uint
+	PistaName string
+	PistaType string
+	Slots     []GridSlot
+}
+
+// Validate normaliza y valida los parámetros de la rejilla
+func (q *GridQuery) Validate() error {
+	if q.SlotMinutes == 0 {
+		q.SlotMinutes = DefaultSlotMinutes
+	}
+	if q.SlotMinutes < MinSlotMinutes || q.SlotMinutes > MaxSlotMinutes {
+		return ErrInvalidSlotSize
+	}
+	if 60%q.SlotMinutes != 0 && q.SlotMinutes%60 != 0 {
+		return ErrInvalidSlotSize
+	}
+
+	q.From = truncateDay(q.From)
+	q.To = truncateDay(q.To)
+	if q.To.Before(q.From) {
+		return ErrInvalidGridRange
+	}
+	if q.To.Sub(q.From) >= MaxGridDays*24*time.Hour {
+		return ErrGridRangeTooLong
+	}
+	return nil
+}
+
+// GetAvailabilityGrid construye la rejilla libre/ocupado de una o varias pistas,
+// combinando reservas y clases en una única consulta por tipo. Los huecos se generan
+// dentro del horario de apertura de cada pista (los días de cierre no tienen huecos).
+func (s *AvailabilityService) GetAvailabilityGrid(query GridQuery) ([]PistaGrid, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	var pistas []database.Pista
+	pistaQuery := s.db.Where("is_active = ?", true).Order("id ASC")
+	if len(query.PistaIDs) > 0 {
+		pistaQuery = pistaQuery.Where("id IN ?", query.PistaIDs)
+	}
+	if err := pistaQuery.Find(&pistas).Error; err != nil {
+		return nil, err
+	}
+	if len(pistas) == 0 {
+		return []PistaGrid{}, nil
+	}
+
+	pistaIDs := make([]uint, len(pistas))
+	for i, pista := range pistas {
+		pistaIDs[i] = pista.ID
+	}
+
+	rangeStart := query.From
+	rangeEnd := query.To.AddDate(0, 0, 1)
+
+	var bookings []database.Booking
+	if err := s.db.Where("pista_id IN ?", pistaIDs).
+		Where("status != ?", "CANCELLED").
+		Where("deleted_at IS NULL").
+		Where("NOT (end_time <= ? OR start_time >= ?)", rangeStart, rangeEnd).
+		Order("start_time ASC").
+		Find(&bookings).Error; err != nil {
+		return nil, err
+	}
+
+	var classes []database.Class
+	if err := s.db.Where("pista_id IN ?", pistaIDs).
+		Where("status != ?", "CANCELLED").
+		Where("deleted_at IS NULL").
+		Where("NOT (end_time <= ? OR start_time >= ?)", rangeStart, rangeEnd).
+		Order("start_time ASC").
+		Find(&classes).Error; err != nil {
+		return nil, err
+	}
+
+	bookingsByPista := map[uint][]database.Booking{}
+	for _, booking := range bookings {
+		bookingsByPista[booking.PistaID] = append(bookingsByPista[booking.PistaID], booking)
+	}
+	classesByPista := map[uint][]database.Class{}
+	for _, class := range classes {
+		classesByPista[class.PistaID] = append(classesByPista[class.PistaID], class)
+	}
+
+	now := time.Now()
+	slot := time.Duration(query.SlotMinutes) * time.Minute
+	grids := make([]PistaGrid, len(pistas))
+
+	for i, pista := range pistas {
+		grid := PistaGrid{
+			PistaID:   pista.ID,
+			PistaName: pista.Name,
+			PistaType: pista.Type,
+			Slots:     []GridSlot{},
+		}
+
+		schedules, err := s.daySchedules(int(pista.ID), rangeStart, query.To)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, schedule := range schedules {
+			if schedule.Closed {
+				continue
+			}
+
+			// Si el horario no es múltiplo del hueco, el último hueco del día se acorta hasta el cierre
+			for start := schedule.Open; start.Before(schedule.Close); start = start.Add(slot) {
+				end := start.Add(slot)
+				if end.After(schedule.Close) {
+					end = schedule.Close
+				}
+				grid.Slots = append(grid.Slots, resolveSlot(
+					start, end, now, schedule.Blackouts, bookingsByPista[pista.ID], classesByPista[pista.ID],
+				))
+			}
+		}
+
+		grids[i] = grid
+	}
+
+	return grids, nil
+}
+
+// daySchedules obtiene el horario de cada día del rango; sin proveedor de horarios
+// se considera la pista abierta todo el día
+func (s *AvailabilityService) daySchedules(pistaID int, from, to time.Time) ([]DaySchedule, error) {
+	if s.schedule != nil {
+		return s.schedule.GetDaySchedules(pistaID, from, to)
+	}
+
+	schedules := []DaySchedule{}
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		schedules = append(schedules, DaySchedule{Date: day, Open: day, Close: day.AddDate(0, 0, 1)})
+	}
+	return schedules, nil
+}
+
+// resolveSlot calcula el estado de un hueco (bloqueos > clases > reservas)
+func resolveSlot(start, end, now time.Time, blackouts []TimeWindow, bookings []database.Booking, classes []database.Class) GridSlot {
+	slot := GridSlot{StartTime: start, EndTime: end, Status: SlotFree}
+
+	for _, blackout := range blackouts {
+		if blackout.StartTime.Before(end) && blackout.EndTime.After(start) {
+			slot.Status = SlotClosed
+			return slot
+		}
+	}
+
+	for _, class := range classes {
+		if class.StartTime.Before(end) && class.EndTime.After(start) {
+			classID := class.ID
+			slot.Status = SlotClass
+			slot.ClassID = &classID
+			return slot
+		}
+	}
+
+	for _, booking := range bookings {
+		if booking.StartTime.Before(end) && booking.EndTime.After(start) {
+			bookingID := booking.ID
+			slot.Status = SlotBooked
+			slot.BookingID = &bookingID
+			return slot
+		}
+	}
+
+	if start.Before(now) {
+		slot.Status = SlotPast
+	}
+
+	return slot
+}
+
+// truncateDay devuelve el inicio del día (UTC) de la fecha dada
+func truncateDay(date time.Time) time.Time {
+	date = date.UTC()
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,43 @@
+package availability
+
+import "time"
+
+// TimeWindow representa una franja horaria bloqueada (ej. mantenimiento)
+type TimeWindow struct {
+	StartTime time.Time
+	EndTime   time.Time
+	Reason    string
+}
+
+// DaySchedule es el horario efectivo de una pista en un día concreto
+type DaySchedule struct {
+	Date      time.Time
+	Closed    bool
+	Reason    string
+	Open      time.Time
+	Close     time.Time
+	Blackouts []TimeWindow
+}
+
+// ScheduleProvider resuelve horarios de apertura, cierres y bloqueos.
+// Lo implementa el módulo schedules (ver schedules/application/schedule_provider.go).
+type ScheduleProvider interface {
+	// GetDaySchedules devuelve un horario por día entre from y to (ambos incluidos)
+	GetDaySchedules(pistaID int, from, to time.Time) ([]DaySchedule, error)
+	// CheckSlot verifica que la franja esté dentro del horario de la pista
+	CheckSlot(pistaID int, startTime, endTime time.Time) error
+}
+
+// WithScheduleProvider devuelve una copia del servicio que consulta el horario de apertura
+func (s *AvailabilityService) WithScheduleProvider(schedule ScheduleProvider) *AvailabilityService {
+	return &AvailabilityService{db: s.db, schedule: schedule}
+}
+
+// CheckSchedule verifica que la franja esté dentro del horario de apertura de la pista,
+// que no sea un día de cierre y que no coincida con un bloqueo
+func (s *AvailabilityService) CheckSchedule(pistaID int, startTime, endTime time.Time) error {
+	if s.schedule == nil {
+		return nil
+	}
+	return s.schedule.CheckSlot(pistaID, startTime, endTime)
+}
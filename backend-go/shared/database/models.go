@@ -30,6 +30,7 @@ type User struct {
 	DNI              *string        `gorm:"type:varchar(20)"`
 	AvatarURL        *string        `gorm:"type:text"`
 	StripeCustomerID *string        `gorm:"type:varchar(255)"`
+	SkillLevel       *float64       `gorm:"type:numeric(2,1)"` // Nivel de juego (1.0 - 7.0) para partidos abiertos
 	IsMember         bool           `gorm:"default:false"`
 	IsActive         bool           `gorm:"default:true"`
 	SessionVersion   int            `gorm:"default:1;not null"` // V2: Global Logout Switch
@@ -99,17 +100,170 @@ type Booking struct {
 	StartTime          time.Time      `gorm:"type:timestamptz;not null;index:idx_bookings_dates;uniqueIndex:idx_booking_overlap,where:status != 'CANCELLED' AND deleted_at IS NULL"`
 	EndTime            time.Time      `gorm:"type:timestamptz;not null;index:idx_bookings_dates;check:end_time > start_time"`
 	PriceSnapshotCents int            `gorm:"not null"`
+	DiscountCents      int            `gorm:"not null;default:0"` // Descuento del código promocional aplicado al pagar
+	PromoCode          *string        `gorm:"type:varchar(50)"`
 	Status             string         `gorm:"type:varchar(50);default:'PENDING'"`
 	PaymentStatus      string         `gorm:"type:varchar(50);default:'UNPAID'"`
 	Notes              *string        `gorm:"type:text"`
+	SeriesID           *uint          `gorm:"index"`                        // Serie recurrente a la que pertenece (opcional)
+	SplitDeadline      *time.Time     `gorm:"type:timestamptz;index"`       // Pago dividido: plazo para completar el pago (nil = sin dividir)
+	IsOpenMatch        bool           `gorm:"not null;default:false;index"` // Partido abierto: otros jugadores pueden unirse hasta completarlo
+	MinLevel           *float64       `gorm:"type:numeric(2,1)"`            // Partido abierto: nivel mínimo (nil = sin mínimo)
+	MaxLevel           *float64       `gorm:"type:numeric(2,1);check:chk_bookings_level_range,min_level IS NULL OR max_level IS NULL OR min_level <= max_level"`
 	CreatedAt          time.Time      `gorm:"type:timestamptz;default:NOW()"`
 	UpdatedAt          time.Time      `gorm:"type:timestamptz;default:NOW()"`
 	DeletedAt          gorm.DeletedAt `gorm:"index"`
 
 	// Relaciones
-	User    User     `gorm:"foreignKey:UserID"`
-	Pista   Pista    `gorm:"foreignKey:PistaID"`
-	Payment *Payment `gorm:"foreignKey:BookingID"`
+	User         User                 `gorm:"foreignKey:UserID"`
+	Pista        Pista                `gorm:"foreignKey:PistaID"`
+	Payment      *Payment             `gorm:"foreignKey:BookingID"`
+	Series       *BookingSeries       `gorm:"foreignKey:SeriesID"`
+	Shares       []BookingShare       `gorm:"foreignKey:BookingID"`
+	Participants []BookingParticipant `gorm:"foreignKey:BookingID"`
+}
+
+// BookingParticipant es un jugador invitado por el titular a una reserva. Solo los invitados
+// que aceptan (ACCEPTED) cuentan como participantes; los pendientes ocupan plaza hasta que
+// responden.
+type BookingParticipant struct {
+	ID          uint       `gorm:"primaryKey"`
+	BookingID   uint       `gorm:"not null;uniqueIndex:idx_booking_participants_user"`
+	UserID      uuid.UUID  `gorm:"type:uuid;not null;index;uniqueIndex:idx_booking_participants_user"` // Una invitación por jugador y reserva
+	InvitedByID uuid.UUID  `gorm:"type:uuid;not null"`
+	Status      string     `gorm:"type:varchar(20);not null;index"` // PENDING, ACCEPTED, DECLINED
+	RespondedAt *time.Time `gorm:"type:timestamptz"`
+	CreatedAt   time.Time  `gorm:"type:timestamptz;default:NOW()"`
+	UpdatedAt   time.Time  `gorm:"type:timestamptz;default:NOW()"`
+
+	// Relaciones
+	Booking   Booking `gorm:"foreignKey:BookingID"`
+	User      User    `gorm:"foreignKey:UserID"`
+	InvitedBy User    `gorm:"foreignKey:InvitedByID"`
+}
+
+// BookingSeries agrupa reservas recurrentes (semanal/quincenal) de un mismo usuario y pista
+type BookingSeries struct {
+	ID          uint       `gorm:"primaryKey"`
+	UserID      uuid.UUID  `gorm:"type:uuid;not null;index"`
+	PistaID     uint       `gorm:"not null;index"`
+	Frequency   string     `gorm:"type:varchar(20);not null"` // WEEKLY, BIWEEKLY
+	StartTime   time.Time  `gorm:"type:timestamptz;not null"` // Inicio de la primera ocurrencia
+	EndTime     time.Time  `gorm:"type:timestamptz;not null;check:chk_booking_series_end,end_time > start_time"`
+	UntilDate   *time.Time `gorm:"type:timestamptz"` // Fin de la recurrencia (inclusive)
+	Occurrences *int       // Número de ocurrencias (alternativa a UntilDate)
+	Status      string     `gorm:"type:varchar(50);default:'ACTIVE'"`
+	Notes       *string    `gorm:"type:text"`
+	CreatedAt   time.Time  `gorm:"type:timestamptz;default:NOW()"`
+	UpdatedAt   time.Time  `gorm:"type:timestamptz;default:NOW()"`
+
+	// Relaciones
+	User     User      `gorm:"foreignKey:UserID"`
+	Pista    Pista     `gorm:"foreignKey:PistaID"`
+	Bookings []Booking `gorm:"foreignKey:SeriesID"`
+}
+
+// OpeningHours define el horario semanal de apertura, global (PistaID nil) o de una pista.
+// ValidFrom/ValidUntil permiten horarios de temporada (ej. pistas exteriores en invierno).
+type OpeningHours struct {
+	ID          uint       `gorm:"primaryKey"`
+	PistaID     *uint      `gorm:"index"`
+	Weekday     int        `gorm:"not null;check:chk_opening_hours_weekday,weekday BETWEEN 0 AND 6"` // 0 = domingo
+	OpenMinute  int        `gorm:"not null"`                                                         // Minutos desde medianoche (UTC)
+	CloseMinute int        `gorm:"not null;check:chk_opening_hours_range,close_minute > open_minute AND close_minute <= 1440"`
+	ValidFrom   *time.Time `gorm:"type:date"`
+	ValidUntil  *time.Time `gorm:"type:date"`
+	CreatedAt   time.Time  `gorm:"type:timestamptz;default:NOW()"`
+	UpdatedAt   time.Time  `gorm:"type:timestamptz;default:NOW()"`
+
+	// Relaciones
+	Pista *Pista `gorm:"foreignKey:PistaID"`
+}
+
+// ScheduleClosure representa un día festivo o de cierre, global (PistaID nil) o de una pista
+type ScheduleClosure struct {
+	ID        uint      `gorm:"primaryKey"`
+	PistaID   *uint     `gorm:"index"`
+	Date      time.Time `gorm:"type:date;not null;index"`
+	Reason    string    `gorm:"type:varchar(255)"`
+	CreatedAt time.Time `gorm:"type:timestamptz;default:NOW()"`
+
+	// Relaciones
+	Pista *Pista `gorm:"foreignKey:PistaID"`
+}
+
+// ScheduleBlackout representa un bloqueo puntual (ej. mantenimiento), global o de una pista
+type ScheduleBlackout struct {
+	ID        uint      `gorm:"primaryKey"`
+	PistaID   *uint     `gorm:"index"`
+	StartTime time.Time `gorm:"type:timestamptz;not null;index"`
+	EndTime   time.Time `gorm:"type:timestamptz;not null;check:chk_schedule_blackout_end,end_time > start_time"`
+	Reason    string    `gorm:"type:varchar(255)"`
+	CreatedAt time.Time `gorm:"type:timestamptz;default:NOW()"`
+
+	// Relaciones
+	Pista *Pista `gorm:"foreignKey:PistaID"`
+}
+
+// PricingRule ajusta el precio por hora de las pistas (franjas punta/valle, fin de semana,
+// descuentos de socio y promociones). PercentAdjustment positivo = recargo, negativo = descuento.
+type PricingRule struct {
+	ID                uint       `gorm:"primaryKey"`
+	Name              string     `gorm:"type:varchar(100);not null"`
+	Type              string     `gorm:"type:varchar(50);not null;index"` // TIME_BAND, WEEKEND, MEMBER_DISCOUNT, PROMO
+	PistaID           *uint      `gorm:"index"`                           // nil = todas las pistas
+	PistaType         *string    `gorm:"type:varchar(50)"`                // nil = todos los tipos
+	Weekdays          string     `gorm:"type:varchar(20)"`                // "1,2,3" (0 = domingo); vacío = todos
+	StartMinute       *int       // Inicio de la franja (minutos desde medianoche)
+	EndMinute         *int       // Fin de la franja
+	ValidFrom         *time.Time `gorm:"type:timestamptz"`
+	ValidUntil        *time.Time `gorm:"type:timestamptz"`
+	PercentAdjustment int        `gorm:"not null;check:chk_pricing_rule_percent,percent_adjustment BETWEEN -100 AND 500"`
+	IsActive          bool       `gorm:"default:true"`
+	CreatedAt         time.Time  `gorm:"type:timestamptz;default:NOW()"`
+	UpdatedAt         time.Time  `gorm:"type:timestamptz;default:NOW()"`
+
+	// Relaciones
+	Pista *Pista `gorm:"foreignKey:PistaID"`
+}
+
+// PromoCode es un código promocional ("VERANO20") con descuento porcentual o de importe fijo
+// que el cliente introduce al pagar pistas, clases o cuotas de club
+type PromoCode struct {
+	ID                    uint       `gorm:"primaryKey"`
+	Code                  string     `gorm:"type:varchar(50);not null;uniqueIndex"` // Siempre en mayúsculas
+	Description           *string    `gorm:"type:text"`
+	DiscountType          string     `gorm:"type:varchar(20);not null"` // PERCENT, FIXED
+	PercentOff            int        `gorm:"not null;default:0;check:chk_promo_code_percent,percent_off BETWEEN 0 AND 100"`
+	AmountOffCents        int        `gorm:"not null;default:0;check:chk_promo_code_amount,amount_off_cents >= 0"`
+	ResourceTypes         string     `gorm:"type:varchar(50);not null"` // "PISTA,CLASS,CLUB"
+	ValidFrom             *time.Time `gorm:"type:timestamptz"`
+	ValidUntil            *time.Time `gorm:"type:timestamptz"`
+	MaxRedemptions        *int       // Límite global de usos (nil = sin límite)
+	MaxRedemptionsPerUser *int       // Límite de usos por usuario (nil = sin límite)
+	IsActive              bool       `gorm:"default:true"`
+	CreatedAt             time.Time  `gorm:"type:timestamptz;default:NOW()"`
+	UpdatedAt             time.Time  `gorm:"type:timestamptz;default:NOW()"`
+
+	// Relaciones
+	Redemptions []PromoRedemption `gorm:"foreignKey:PromoCodeID"`
+}
+
+// PromoRedemption es un uso de un código promocional. Se libera (released_at) si el cobro
+// al que se aplicó no llega a realizarse, y entonces deja de contar para los límites.
+type PromoRedemption struct {
+	ID            uint       `gorm:"primaryKey"`
+	PromoCodeID   uint       `gorm:"not null;index"`
+	UserID        uuid.UUID  `gorm:"type:uuid;not null;index"`
+	ResourceType  string     `gorm:"type:varchar(20);not null"`
+	AmountCents   int        `gorm:"not null"` // Importe antes del descuento
+	DiscountCents int        `gorm:"not null;check:chk_promo_redemption_discount,discount_cents >= 0"`
+	ReleasedAt    *time.Time `gorm:"type:timestamptz"`
+	CreatedAt     time.Time  `gorm:"type:timestamptz;default:NOW()"`
+
+	// Relaciones
+	PromoCode PromoCode `gorm:"foreignKey:PromoCodeID"`
+	User      User      `gorm:"foreignKey:UserID"`
 }
 
 // ======================================================================================
@@ -124,11 +278,12 @@ type Class struct {
 	InstructorID uuid.UUID      `gorm:"type:uuid;not null"`
 	Title        string         `gorm:"type:varchar(100);not null"`
 	Description  *string        `gorm:"type:text"`
-	StartTime    time.Time      `gorm:"type:timestamptz;not null"`
+	StartTime    time.Time      `gorm:"type:timestamptz;not null;uniqueIndex:idx_classes_template_session"`
 	EndTime      time.Time      `gorm:"type:timestamptz;not null"`
 	Capacity     int            `gorm:"default:4"`
 	PriceCents   int            `gorm:"not null"`
 	Status       string         `gorm:"type:varchar(50);default:'OPEN'"`
+	TemplateID   *uint          `gorm:"uniqueIndex:idx_classes_template_session"` // Sesión generada por una plantilla
 	CreatedAt    time.Time      `gorm:"type:timestamptz;default:NOW()"`
 	UpdatedAt    time.Time      `gorm:"type:timestamptz;default:NOW()"`
 	DeletedAt    gorm.DeletedAt `gorm:"index"`
@@ -139,13 +294,55 @@ type Class struct {
 	Enrollments []ClassEnrollment `gorm:"foreignKey:ClassID"`
 }
 
+// ClassTemplate define una clase recurrente (ej. "Pádel iniciación" lunes y miércoles)
+// a partir de la cual el scheduler genera las sesiones con antelación
+type ClassTemplate struct {
+	ID              uint       `gorm:"primaryKey"`
+	Title           string     `gorm:"type:varchar(100);not null"`
+	Description     *string    `gorm:"type:text"`
+	PistaID         uint       `gorm:"not null;index"`
+	InstructorID    uuid.UUID  `gorm:"type:uuid;not null;index"`
+	Capacity        int        `gorm:"not null"`
+	PriceCents      int        `gorm:"not null"`
+	Weekdays        string     `gorm:"type:varchar(20);not null"` // "1,3" (0 = domingo)
+	StartMinute     int        `gorm:"not null"`                  // Hora de inicio (minutos desde medianoche, hora local del club)
+	DurationMinutes int        `gorm:"not null"`
+	ValidFrom       time.Time  `gorm:"type:timestamptz;not null"`
+	ValidUntil      *time.Time `gorm:"type:timestamptz"`
+	WeeksAhead      int        `gorm:"not null;default:4"` // Semanas de antelación con las que se generan sesiones
+	IsActive        bool       `gorm:"default:true"`
+	LastGeneratedAt *time.Time `gorm:"type:timestamptz"`
+	CreatedAt       time.Time  `gorm:"type:timestamptz;default:NOW()"`
+	UpdatedAt       time.Time  `gorm:"type:timestamptz;default:NOW()"`
+
+	// Relaciones
+	Pista      Pista                   `gorm:"foreignKey:PistaID"`
+	Instructor User                    `gorm:"foreignKey:InstructorID"`
+	Conflicts  []ClassTemplateConflict `gorm:"foreignKey:TemplateID"`
+}
+
+// ClassTemplateConflict registra una sesión de plantilla que no se pudo generar o actualizar
+// en la última ejecución (pista ocupada, fuera de horario, plazas ocupadas...)
+type ClassTemplateConflict struct {
+	ID         uint      `gorm:"primaryKey"`
+	TemplateID uint      `gorm:"not null;index"`
+	ClassID    *uint     // Solo en sesiones ya existentes
+	StartTime  time.Time `gorm:"type:timestamptz;not null"`
+	EndTime    time.Time `gorm:"type:timestamptz;not null"`
+	Reason     string    `gorm:"type:text;not null"`
+	DetectedAt time.Time `gorm:"type:timestamptz;default:NOW()"`
+}
+
 // ClassEnrollment representa la inscripción de un usuario a una clase
 type ClassEnrollment struct {
 	ID           uint      `gorm:"primaryKey"`
 	ClassID      uint      `gorm:"not null;uniqueIndex:uq_class_user"`
 	UserID       uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:uq_class_user"`
 	Status       string    `gorm:"type:varchar(50);default:'CONFIRMED'"`
-	RegisteredAt time.Time `gorm:"type:timestamptz;default:NOW()"`
+	RegisteredAt time.Time `gorm:"type:timestamptz;default:NOW()"` // También ordena la lista de espera
+
+	// Lista de espera: límite para confirmar una plaza ofertada (estado OFFERED)
+	OfferExpiresAt *time.Time `gorm:"type:timestamptz;index"`
 
 	// Relaciones
 	Class    Class     `gorm:"foreignKey:ClassID"`
@@ -179,17 +376,22 @@ type Club struct {
 
 // ClubMembership representa la membresía de un usuario a un club
 type ClubMembership struct {
-	ID              uint       `gorm:"primaryKey"`
-	ClubID          uint       `gorm:"not null;index:idx_club_user,unique"`
-	UserID          uuid.UUID  `gorm:"type:uuid;not null;index:idx_club_user,unique"`
-	Status          string     `gorm:"type:varchar(50);default:'ACTIVE'"`
-	StartDate       time.Time  `gorm:"type:timestamptz;default:NOW()"`
-	EndDate         *time.Time `gorm:"type:timestamptz"`
-	NextBillingDate *time.Time `gorm:"type:timestamptz"`
-	PaymentStatus   string     `gorm:"type:varchar(50);default:'UP_TO_DATE'"`
-	IsActive        bool       `gorm:"default:true"`
-	CreatedAt       time.Time  `gorm:"type:timestamptz;default:NOW()"`
-	UpdatedAt       time.Time  `gorm:"type:timestamptz;default:NOW()"`
+	ID               uint       `gorm:"primaryKey"`
+	ClubID           uint       `gorm:"not null;index:idx_club_user,unique"`
+	UserID           uuid.UUID  `gorm:"type:uuid;not null;index:idx_club_user,unique"`
+	Status           string     `gorm:"type:varchar(50);default:'ACTIVE'"`
+	StartDate        time.Time  `gorm:"type:timestamptz;default:NOW()"`
+	EndDate          *time.Time `gorm:"type:timestamptz"`
+	NextBillingDate  *time.Time `gorm:"type:timestamptz;index"`
+	PaymentStatus    string     `gorm:"type:varchar(50);default:'UP_TO_DATE'"`
+	LastPaymentID    *uint      // Último cobro de renovación
+	PendingPaymentID *uint      // Cobro de renovación pendiente de confirmar por webhook
+	RenewalAttempts  int        `gorm:"not null;default:0"` // Intentos fallidos de cobro consecutivos
+	NextRetryAt      *time.Time `gorm:"type:timestamptz"`   // Próximo reintento de cobro (backoff)
+	PastDueSince     *time.Time `gorm:"type:timestamptz"`   // Inicio del periodo de gracia por impago
+	IsActive         bool       `gorm:"default:true"`
+	CreatedAt        time.Time  `gorm:"type:timestamptz;default:NOW()"`
+	UpdatedAt        time.Time  `gorm:"type:timestamptz;default:NOW()"`
 
 	// Relaciones
 	Club     Club      `gorm:"foreignKey:ClubID"`
@@ -206,15 +408,25 @@ type Payment struct {
 	ID                    uint      `gorm:"primaryKey"`
 	UserID                uuid.UUID `gorm:"type:uuid;not null"`
 	AmountCents           int       `gorm:"not null"`
+	RefundedCents         int       `gorm:"not null;default:0"` // Suma de reembolsos (parciales o total)
 	Currency              string    `gorm:"type:varchar(3);default:'EUR'"`
 	Status                string    `gorm:"type:varchar(50);not null"`
 	Provider              string    `gorm:"type:varchar(50);default:'STRIPE'"`
 	StripePaymentIntentID *string   `gorm:"type:varchar(255);uniqueIndex"`
 
 	// Exclusive Arc - Solo uno puede ser NOT NULL
-	BookingID         *uint `gorm:"index"`
-	ClassEnrollmentID *uint `gorm:"index"`
+	BookingID         *uint `gorm:"index;uniqueIndex:idx_payments_booking_active,where:status != 'FAILED' AND status != 'REFUNDED' AND booking_share_id IS NULL"` // Un único pago vigente por reserva (sin contar las partes de un pago dividido)
+	ClassEnrollmentID *uint `gorm:"index;uniqueIndex:idx_payments_enrollment_active,where:status != 'FAILED' AND status != 'REFUNDED'"`
 	ClubMembershipID  *uint `gorm:"index"` // Nuevo: pagos de membresías
+	PassID            *uint `gorm:"index"` // Compra de bonos
+
+	// Pago dividido: parte de la reserva que paga un jugador (BookingID también se rellena)
+	BookingShareID *uint `gorm:"uniqueIndex:idx_payments_share_active,where:status != 'FAILED' AND status != 'REFUNDED'"` // Un único pago vigente por parte
+
+	// Código promocional: AmountCents es lo cobrado, ya descontado DiscountCents
+	DiscountCents     int     `gorm:"not null;default:0"`
+	PromoCode         *string `gorm:"type:varchar(50)"`
+	PromoRedemptionID *uint   `gorm:"index"`
 
 	CreatedAt time.Time `gorm:"type:timestamptz;default:NOW()"`
 	UpdatedAt time.Time `gorm:"type:timestamptz;default:NOW()"`
@@ -224,16 +436,269 @@ type Payment struct {
 	Booking         *Booking         `gorm:"foreignKey:BookingID"`
 	ClassEnrollment *ClassEnrollment `gorm:"foreignKey:ClassEnrollmentID"`
 	ClubMembership  *ClubMembership  `gorm:"foreignKey:ClubMembershipID"`
+	Pass            *Pass            `gorm:"foreignKey:PassID"`
+	PromoRedemption *PromoRedemption `gorm:"foreignKey:PromoRedemptionID"`
+	BookingShare    *BookingShare    `gorm:"foreignKey:BookingShareID"`
+	Refunds         []Refund         `gorm:"foreignKey:PaymentID"`
+}
+
+// BookingShare es la parte del precio de una reserva que paga cada jugador cuando el organizador
+// divide el pago. La reserva se confirma cuando no quedan partes pendientes (pagadas por cada
+// jugador o cubiertas por el organizador); si vence split_deadline antes, se cancela.
+type BookingShare struct {
+	ID          uint       `gorm:"primaryKey"`
+	BookingID   uint       `gorm:"not null;uniqueIndex:idx_booking_shares_user"`
+	UserID      uuid.UUID  `gorm:"type:uuid;not null;index;uniqueIndex:idx_booking_shares_user"` // Una parte por jugador y reserva
+	AmountCents int        `gorm:"not null;check:chk_booking_share_amount,amount_cents > 0"`
+	Status      string     `gorm:"type:varchar(20);not null;index"` // PENDING, PAID, COVERED, REFUNDED, CANCELLED
+	PaymentID   *uint      // Pago del jugador o, si la cubrió, del organizador
+	PaidAt      *time.Time `gorm:"type:timestamptz"`
+	CreatedAt   time.Time  `gorm:"type:timestamptz;default:NOW()"`
+	UpdatedAt   time.Time  `gorm:"type:timestamptz;default:NOW()"`
+
+	// Relaciones
+	Booking Booking `gorm:"foreignKey:BookingID"`
+	User    User    `gorm:"foreignKey:UserID"`
+}
+
+// Refund representa un reembolso (total o parcial) de un pago
+type Refund struct {
+	ID               uint      `gorm:"primaryKey"`
+	PaymentID        uint      `gorm:"not null;index"`
+	AmountCents      int       `gorm:"not null;check:amount_cents > 0"`
+	Reason           string    `gorm:"type:varchar(50);not null"` // CANCELLATION, MANUAL, PROVIDER
+	ProviderRefundID string    `gorm:"type:varchar(255)"`
+	Destination      string    `gorm:"type:varchar(20);not null;default:'ORIGINAL'"` // ORIGINAL (medio de pago) o WALLET (monedero)
+	CreatedAt        time.Time `gorm:"type:timestamptz;default:NOW()"`
+
+	// Relaciones
+	Payment Payment `gorm:"foreignKey:PaymentID"`
+}
+
+// PaymentEvent registra cada webhook recibido de un proveedor de pagos.
+// El índice único (provider, event_id) hace idempotente la recepción de reenvíos.
+type PaymentEvent struct {
+	ID              uint       `gorm:"primaryKey"`
+	Provider        string     `gorm:"type:varchar(50);not null;uniqueIndex:idx_payment_events_provider_event"`
+	EventID         string     `gorm:"type:varchar(255);not null;uniqueIndex:idx_payment_events_provider_event"`
+	Type            string     `gorm:"type:varchar(100);not null"` // Tipo original (payment_intent.succeeded...)
+	PaymentIntentID *string    `gorm:"type:varchar(255);index"`
+	PaymentID       *uint      `gorm:"index"`
+	Payload         string     `gorm:"type:jsonb;not null"`
+	Status          string     `gorm:"type:varchar(50);not null;default:'RECEIVED'"` // RECEIVED, PROCESSED, IGNORED, FAILED
+	Error           *string    `gorm:"type:text"`
+	ReceivedAt      time.Time  `gorm:"type:timestamptz;default:NOW()"`
+	ProcessedAt     *time.Time `gorm:"type:timestamptz"`
+
+	// Relaciones
+	Payment *Payment `gorm:"foreignKey:PaymentID"`
+}
+
+// IdempotencyKey guarda la respuesta de una petición que mueve dinero para repetirla ante
+// reintentos del cliente con la misma cabecera Idempotency-Key (única por usuario)
+type IdempotencyKey struct {
+	ID           uint      `gorm:"primaryKey"`
+	Scope        string    `gorm:"type:varchar(64);not null;uniqueIndex:idx_idempotency_scope_key"` // Usuario que hizo la petición
+	Key          string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_idempotency_scope_key"`
+	Method       string    `gorm:"type:varchar(10);not null"`
+	Path         string    `gorm:"type:varchar(255);not null"`
+	RequestHash  string    `gorm:"type:char(64);not null"`                          // SHA-256 de método, ruta y cuerpo
+	Status       string    `gorm:"type:varchar(20);not null;default:'IN_PROGRESS'"` // IN_PROGRESS, COMPLETED
+	StatusCode   int       `gorm:"not null;default:0"`
+	ContentType  string    `gorm:"type:varchar(100)"`
+	ResponseBody []byte    `gorm:"type:bytea"`
+	LockedUntil  time.Time `gorm:"type:timestamptz;not null"` // Una petición en curso caducada se puede retomar
+	CreatedAt    time.Time `gorm:"type:timestamptz;default:NOW()"`
+	ExpiresAt    time.Time `gorm:"type:timestamptz;not null;index"`
+}
+
+// WalletEntry es un movimiento del monedero (saldo prepago) de un usuario. El libro es de solo
+// inserción: el saldo es la suma de amount_cents y balance_after_cents nunca puede ser negativo.
+type WalletEntry struct {
+	ID                uint       `gorm:"primaryKey"`
+	UserID            uuid.UUID  `gorm:"type:uuid;not null;index;uniqueIndex:idx_wallet_entries_idempotency"`
+	Type              string     `gorm:"type:varchar(20);not null"` // TOP_UP, DEBIT, REFUND
+	AmountCents       int        `gorm:"not null;check:amount_cents <> 0"`
+	BalanceAfterCents int        `gorm:"not null;check:balance_after_cents >= 0"`
+	Reference         string     `gorm:"type:varchar(60);not null;uniqueIndex"` // wal_...: PaymentIntentID de los pagos con saldo
+	RelatedEntryID    *uint      `gorm:"index"`                                 // REFUND de un DEBIT
+	PaymentID         *uint      `gorm:"index"`                                 // REFUND al monedero de un pago con tarjeta
+	IdempotencyKey    *string    `gorm:"type:varchar(255);uniqueIndex:idx_wallet_entries_idempotency"`
+	Description       string     `gorm:"type:varchar(255)"`
+	CreatedBy         *uuid.UUID `gorm:"type:uuid"` // ADMIN/GESTOR que registró la recarga
+	CreatedAt         time.Time  `gorm:"type:timestamptz;default:NOW()"`
+
+	// Relaciones
+	User User `gorm:"foreignKey:UserID"`
+}
+
+// Invoice es una factura (serie F) o factura rectificativa (serie R) emitida para un pago.
+// Emisor y cliente se copian al emitirla; los importes de las rectificativas son negativos.
+type Invoice struct {
+	ID              uint      `gorm:"primaryKey"`
+	PaymentID       uint      `gorm:"not null;index;uniqueIndex:idx_invoices_payment_ordinary,where:type = 'ORDINARY'"` // Una única factura ordinaria por pago
+	RefundID        *uint     `gorm:"uniqueIndex"`                                                                      // Rectificativas: una por reembolso
+	UserID          uuid.UUID `gorm:"type:uuid;not null;index"`
+	Type            string    `gorm:"type:varchar(20);not null"` // ORDINARY, RECTIFYING
+	Series          string    `gorm:"type:varchar(10);not null;uniqueIndex:idx_invoices_series_year_sequence"`
+	FiscalYear      int       `gorm:"not null;uniqueIndex:idx_invoices_series_year_sequence"`
+	Sequence        int       `gorm:"not null;uniqueIndex:idx_invoices_series_year_sequence"`
+	Number          string    `gorm:"type:varchar(30);not null;uniqueIndex"`
+	RectifiesID     *uint     `gorm:"index"`
+	RectifiesNumber string    `gorm:"type:varchar(30)"`
+	Reason          string    `gorm:"type:text"`
+	IssuerName      string    `gorm:"type:varchar(255);not null"`
+	IssuerTaxID     string    `gorm:"type:varchar(20)"`
+	IssuerAddress   string    `gorm:"type:text"`
+	CustomerName    string    `gorm:"type:varchar(255);not null"`
+	CustomerEmail   string    `gorm:"type:varchar(255)"`
+	CustomerTaxID   string    `gorm:"type:varchar(20)"`
+	Currency        string    `gorm:"type:varchar(3);default:'EUR'"`
+	BaseCents       int       `gorm:"not null"`
+	VATCents        int       `gorm:"not null"`
+	TotalCents      int       `gorm:"not null"`
+	IssuedAt        time.Time `gorm:"type:timestamptz;not null"`
+	CreatedAt       time.Time `gorm:"type:timestamptz;default:NOW()"`
+
+	// Relaciones
+	Payment Payment       `gorm:"foreignKey:PaymentID"`
+	Lines   []InvoiceLine `gorm:"foreignKey:InvoiceID"`
+}
+
+// InvoiceLine es una línea de factura con su desglose de IVA (precios con IVA incluido)
+type InvoiceLine struct {
+	ID             uint   `gorm:"primaryKey"`
+	InvoiceID      uint   `gorm:"not null;index"`
+	Description    string `gorm:"type:varchar(255);not null"`
+	Quantity       int    `gorm:"not null;default:1"`
+	UnitPriceCents int    `gorm:"not null"`
+	BaseCents      int    `gorm:"not null"`
+	VATRatePercent int    `gorm:"not null"`
+	VATCents       int    `gorm:"not null"`
+	TotalCents     int    `gorm:"not null"`
+}
+
+// InvoiceSequence guarda el último correlativo de cada serie y ejercicio fiscal. La fila queda
+// bloqueada hasta el commit de la factura, así que la numeración no tiene huecos.
+type InvoiceSequence struct {
+	Series     string `gorm:"type:varchar(10);primaryKey"`
+	FiscalYear int    `gorm:"primaryKey;autoIncrement:false"`
+	LastNumber int    `gorm:"not null;default:0"`
+}
+
+// ======================================================================================
+// MÓDULO 6: BONOS (packs de clases y horas de pista)
+// ======================================================================================
+
+// PassProduct es un bono a la venta (catálogo): créditos, validez y recursos a los que se aplica
+type PassProduct struct {
+	ID            uint      `gorm:"primaryKey"`
+	Name          string    `gorm:"type:varchar(100);not null"`
+	Description   *string   `gorm:"type:text"`
+	Credits       int       `gorm:"not null;check:chk_pass_product_credits,credits > 0"`
+	ValidityDays  int       `gorm:"not null;check:chk_pass_product_validity,validity_days > 0"`
+	PriceCents    int       `gorm:"not null;check:chk_pass_product_price,price_cents >= 0"`
+	ResourceTypes string    `gorm:"type:varchar(50);not null"` // "CLASS", "PISTA" o "CLASS,PISTA"
+	IsActive      bool      `gorm:"default:true"`
+	CreatedAt     time.Time `gorm:"type:timestamptz;default:NOW()"`
+	UpdatedAt     time.Time `gorm:"type:timestamptz;default:NOW()"`
+}
+
+// Pass es un bono comprado por un usuario. Nombre, recursos y créditos se copian del producto
+// al comprarlo; credits_remaining nunca puede ser negativo.
+type Pass struct {
+	ID               uint      `gorm:"primaryKey"`
+	UserID           uuid.UUID `gorm:"type:uuid;not null;index"`
+	ProductID        uint      `gorm:"not null;index"`
+	Name             string    `gorm:"type:varchar(100);not null"`
+	ResourceTypes    string    `gorm:"type:varchar(50);not null"`
+	CreditsTotal     int       `gorm:"not null"`
+	CreditsRemaining int       `gorm:"not null;check:chk_pass_credits_remaining,credits_remaining >= 0"`
+	PriceCents       int       `gorm:"not null"`
+	Status           string    `gorm:"type:varchar(20);not null;index"` // PENDING_PAYMENT, ACTIVE, CANCELLED
+	ExpiresAt        time.Time `gorm:"type:timestamptz;not null;index"`
+	CreatedAt        time.Time `gorm:"type:timestamptz;default:NOW()"`
+	UpdatedAt        time.Time `gorm:"type:timestamptz;default:NOW()"`
+
+	// Relaciones
+	User        User             `gorm:"foreignKey:UserID"`
+	Product     PassProduct      `gorm:"foreignKey:ProductID"`
+	Redemptions []PassRedemption `gorm:"foreignKey:PassID"`
+}
+
+// PassRedemption es el consumo de créditos de un bono por una reserva o una inscripción.
+// Al cancelar, restored_at marca que los créditos volvieron al bono.
+type PassRedemption struct {
+	ID           uint       `gorm:"primaryKey"`
+	PassID       uint       `gorm:"not null;index"`
+	ResourceType string     `gorm:"type:varchar(20);not null"`
+	BookingID    *uint      `gorm:"uniqueIndex:idx_pass_redemptions_booking,where:restored_at IS NULL"`    // Un consumo vigente por reserva
+	EnrollmentID *uint      `gorm:"uniqueIndex:idx_pass_redemptions_enrollment,where:restored_at IS NULL"` // Un consumo vigente por inscripción
+	Credits      int        `gorm:"not null;check:chk_pass_redemption_credits,credits > 0"`
+	RestoredAt   *time.Time `gorm:"type:timestamptz"`
+	CreatedAt    time.Time  `gorm:"type:timestamptz;default:NOW()"`
+
+	// Relaciones
+	Pass       Pass             `gorm:"foreignKey:PassID"`
+	Booking    *Booking         `gorm:"foreignKey:BookingID"`
+	Enrollment *ClassEnrollment `gorm:"foreignKey:EnrollmentID"`
+}
+
+// SchedulerRun registra cada ejecución de una tarea programada (historial del scheduler)
+type SchedulerRun struct {
+	ID           uint       `gorm:"primaryKey"`
+	TaskID       string     `gorm:"type:varchar(100);not null;index:idx_scheduler_runs_task"`
+	TaskName     string     `gorm:"type:varchar(255);not null"`
+	Trigger      string     `gorm:"type:varchar(20);not null"`       // SCHEDULE, MANUAL
+	Status       string     `gorm:"type:varchar(20);not null;index"` // RUNNING, SUCCESS, FAILED, TIMEOUT
+	Instance     string     `gorm:"type:varchar(255);not null"`      // Instancia que la ejecutó (hostname:pid)
+	StartedAt    time.Time  `gorm:"type:timestamptz;not null;index:idx_scheduler_runs_task"`
+	FinishedAt   *time.Time `gorm:"type:timestamptz"`
+	AffectedRows int        `gorm:"not null;default:0"`
+	Error        *string    `gorm:"type:text"`
+}
+
+// SchedulerLease es el lease de liderazgo del scheduler entre réplicas. Solo la réplica que lo
+// tiene vigente lanza las ejecuciones programadas; si deja de renovarlo, otra lo adquiere al caducar.
+type SchedulerLease struct {
+	Name       string    `gorm:"type:varchar(100);primaryKey"`
+	Holder     string    `gorm:"type:varchar(255);not null"` // Instancia que tiene el lease (hostname:pid)
+	AcquiredAt time.Time `gorm:"type:timestamptz;not null"`
+	RenewedAt  time.Time `gorm:"type:timestamptz;not null"`
+	ExpiresAt  time.Time `gorm:"type:timestamptz;not null"`
 }
 
 // TableName overrides
-func (Role) TableName() string            { return "roles" }
-func (User) TableName() string            { return "users" }
-func (RefreshSession) TableName() string  { return "refresh_sessions" }
-func (Pista) TableName() string           { return "pistas" }
-func (Booking) TableName() string         { return "bookings" }
-func (Class) TableName() string           { return "classes" }
-func (ClassEnrollment) TableName() string { return "class_enrollments" }
-func (Club) TableName() string            { return "clubs" }
-func (ClubMembership) TableName() string  { return "club_memberships" }
-func (Payment) TableName() string         { return "payments" }
+func (Role) TableName() string                  { return "roles" }
+func (User) TableName() string                  { return "users" }
+func (RefreshSession) TableName() string        { return "refresh_sessions" }
+func (Pista) TableName() string                 { return "pistas" }
+func (Booking) TableName() string               { return "bookings" }
+func (BookingSeries) TableName() string         { return "booking_series" }
+func (BookingParticipant) TableName() string    { return "booking_participants" }
+func (OpeningHours) TableName() string          { return "opening_hours" }
+func (ScheduleClosure) TableName() string       { return "schedule_closures" }
+func (ScheduleBlackout) TableName() string      { return "schedule_blackouts" }
+func (PricingRule) TableName() string           { return "pricing_rules" }
+func (PromoCode) TableName() string             { return "promo_codes" }
+func (PromoRedemption) TableName() string       { return "promo_redemptions" }
+func (Class) TableName() string                 { return "classes" }
+func (ClassEnrollment) TableName() string       { return "class_enrollments" }
+func (ClassTemplate) TableName() string         { return "class_templates" }
+func (ClassTemplateConflict) TableName() string { return "class_template_conflicts" }
+func (Club) TableName() string                  { return "clubs" }
+func (ClubMembership) TableName() string        { return "club_memberships" }
+func (Payment) TableName() string               { return "payments" }
+func (BookingShare) TableName() string          { return "booking_shares" }
+func (Refund) TableName() string                { return "refunds" }
+func (PaymentEvent) TableName() string          { return "payment_events" }
+func (IdempotencyKey) TableName() string        { return "idempotency_keys" }
+func (Invoice) TableName() string               { return "invoices" }
+func (InvoiceLine) TableName() string           { return "invoice_lines" }
+func (InvoiceSequence) TableName() string       { return "invoice_sequences" }
+func (WalletEntry) TableName() string           { return "wallet_entries" }
+func (PassProduct) TableName() string           { return "pass_products" }
+func (Pass) TableName() string                  { return "passes" }
+func (PassRedemption) TableName() string        { return "pass_redemptions" }
+func (SchedulerRun) TableName() string          { return "scheduler_runs" }
+func (SchedulerLease) TableName() string        { return "scheduler_leases" }
@@ -0,0 +1,83 @@
+package health
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// readinessTimeout limita el ping a la base de datos en la comprobación de readiness
+const readinessTimeout = 2 * time.Second
+
+// Checker expone el estado del proceso para los orquestadores (Docker, Kubernetes, balanceadores)
+type Checker struct {
+	db           *gorm.DB
+	shuttingDown atomic.Bool
+}
+
+// NewChecker crea el comprobador de salud sobre la conexión a la base de datos
+func NewChecker(db *gorm.DB) *Checker {
+	return &Checker{db: db}
+}
+
+// MarkShuttingDown marca el proceso como en parada: readiness pasa a 503 para que el
+// balanceador deje de enviar tráfico mientras se drenan las peticiones en curso
+func (h *Checker) MarkShuttingDown() {
+	h.shuttingDown.Store(true)
+}
+
+// ShuttingDown indica si el proceso está en parada
+func (h *Checker) ShuttingDown() bool {
+	return h.shuttingDown.Load()
+}
+
+// Live maneja GET /health/live
+// @Summary Liveness: el proceso está vivo y atiende peticiones
+// @Tags health
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Router /health/live [get]
+func (h *Checker) Live(c *fiber.Ctx) error {
+	status := "ok"
+	if h.ShuttingDown() {
+		status = "shutting_down"
+	}
+	return c.JSON(fiber.Map{"status": status})
+}
+
+// Ready maneja GET /health/ready
+// @Summary Readiness: el proceso acepta tráfico (no está en parada y la base de datos responde)
+// @Tags health
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /health/ready [get]
+func (h *Checker) Ready(c *fiber.Ctx) error {
+	if h.ShuttingDown() {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"status": "shutting_down"})
+	}
+
+	sqlDB, err := h.db.DB()
+	if err == nil {
+		ctx, cancel := context.WithTimeout(c.UserContext(), readinessTimeout)
+		defer cancel()
+		err = sqlDB.PingContext(ctx)
+	}
+	if err != nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"status":   "unavailable",
+			"database": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{"status": "ok", "database": "ok"})
+}
+
+// RegisterRoutes registra /health/live y /health/ready (públicas, sin JWT)
+func RegisterRoutes(app *fiber.App, checker *Checker) {
+	app.Get("/health/live", checker.Live)
+	app.Get("/health/ready", checker.Ready)
+}
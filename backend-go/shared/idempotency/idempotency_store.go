@@ -0,0 +1,173 @@
+package idempotency
+
+import (
+	"backend-go/shared/database"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Estados de una clave de idempotencia
+const (
+	StatusInProgress = "IN_PROGRESS"
+	StatusCompleted  = "COMPLETED"
+)
+
+const (
+	// KeyTTL es el tiempo durante el que se conserva la respuesta de una clave
+	KeyTTL = 24 * time.Hour
+	// LockTTL es el tiempo tras el que una petición en curso (p. ej. el servidor cayó) se puede retomar
+	LockTTL = 2 * time.Minute
+	// MaxKeyLength limita la clave del cliente (se reenvía al proveedor de pagos con el usuario como prefijo)
+	MaxKeyLength = 200
+)
+
+// Record es una clave de idempotencia con la petición que la reservó y su respuesta
+type Record struct {
+	ID           uint
+	Scope        string
+	Key          string
+	Method       string
+	Path         string
+	RequestHash  string
+	Status       string
+	StatusCode   int
+	ContentType  string
+	ResponseBody []byte
+	LockedUntil  time.Time
+	ExpiresAt    time.Time
+}
+
+// Store persiste las claves de idempotencia en la tabla idempotency_keys
+type Store struct {
+	db *gorm.DB
+}
+
+func NewStore(db *gorm.DB) *Store {
+	return &Store{db: db}
+}
+
+// HashRequest calcula la huella de la petición (método, ruta y cuerpo) para detectar una clave
+// reutilizada con otra petición
+func HashRequest(method, path string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method))
+	hash.Write([]byte{0})
+	hash.Write([]byte(path))
+	hash.Write([]byte{0})
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// Begin reserva la clave para la petición. Devuelve acquired = true si la petición debe
+// ejecutarse (clave nueva, caducada, o en curso con el bloqueo vencido y la misma petición);
+// si no, devuelve el registro existente para repetir su respuesta o rechazar la petición.
+func (s *Store) Begin(record *Record, now time.Time) (*Record, bool, error) {
+	var existing *Record
+	acquired := false
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		model := toModel(record, now)
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(model)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 1 {
+			record.ID = model.ID
+			acquired = true
+			return nil
+		}
+
+		var stored database.IdempotencyKey
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("scope = ? AND key = ?", record.Scope, record.Key).
+			First(&stored).Error; err != nil {
+			return err
+		}
+
+		switch {
+		case stored.ExpiresAt.Before(now):
+			// Clave caducada: se reutiliza como nueva
+			model.ID = stored.ID
+			if err := tx.Save(model).Error; err != nil {
+				return err
+			}
+			record.ID = stored.ID
+			acquired = true
+		case stored.RequestHash == record.RequestHash && stored.Status == StatusInProgress && stored.LockedUntil.Before(now):
+			// La petición original no terminó (caída del servidor): se retoma
+			if err := tx.Model(&stored).Update("locked_until", now.Add(LockTTL)).Error; err != nil {
+				return err
+			}
+			record.ID = stored.ID
+			acquired = true
+		default:
+			existing = toRecord(&stored)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	if acquired {
+		return record, true, nil
+	}
+	return existing, false, nil
+}
+
+// Complete guarda la respuesta de la petición para repetirla en los reintentos
+func (s *Store) Complete(id uint, statusCode int, contentType string, body []byte) error {
+	return s.db.Model(&database.IdempotencyKey{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":        StatusCompleted,
+		"status_code":   statusCode,
+		"content_type":  contentType,
+		"response_body": body,
+	}).Error
+}
+
+// Release libera la clave (la petición falló con un error del servidor y se puede reintentar)
+func (s *Store) Release(id uint) error {
+	return s.db.Delete(&database.IdempotencyKey{}, id).Error
+}
+
+// DeleteExpired elimina las claves caducadas. Se ejecuta desde el scheduler.
+func (s *Store) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	result := s.db.WithContext(ctx).Where("expires_at < ?", now).Delete(&database.IdempotencyKey{})
+	return result.RowsAffected, result.Error
+}
+
+func toModel(record *Record, now time.Time) *database.IdempotencyKey {
+	return &database.IdempotencyKey{
+		Scope:       record.Scope,
+		Key:         record.Key,
+		Method:      record.Method,
+		Path:        record.Path,
+		RequestHash: record.RequestHash,
+		Status:      StatusInProgress,
+		LockedUntil: now.Add(LockTTL),
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(KeyTTL),
+	}
+}
+
+func toRecord(model *database.IdempotencyKey) *Record {
+	return &Record{
+		ID:           model.ID,
+		Scope:        model.Scope,
+		Key:          model.Key,
+		Method:       model.Method,
+		Path:         model.Path,
+		RequestHash:  model.RequestHash,
+		Status:       model.Status,
+		StatusCode:   model.StatusCode,
+		ContentType:  model.ContentType,
+		ResponseBody: model.ResponseBody,
+		LockedUntil:  model.LockedUntil,
+		ExpiresAt:    model.ExpiresAt,
+	}
+}
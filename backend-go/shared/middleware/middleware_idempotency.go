@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"backend-go/shared/idempotency"
+	"log"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// ======================================================================================
+// MIDDLEWARE IDEMPOTENCY - Reintentos seguros en endpoints que mueven dinero
+// Con la cabecera Idempotency-Key, la primera respuesta se guarda y se repite en los
+// reintentos; reutilizar la clave con otra petición se rechaza. Debe ir tras JWTMiddleware.
+// ======================================================================================
+
+const (
+	// IdempotencyKeyHeader es la cabecera con la clave elegida por el cliente
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader marca las respuestas repetidas de una petición anterior
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+// Idempotency guarda la respuesta de las peticiones con Idempotency-Key (por usuario) durante
+// 24h. Las respuestas 5xx no se guardan para que el cliente pueda reintentar.
+func Idempotency(store *idempotency.Store) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Get(IdempotencyKeyHeader)
+		if key == "" {
+			return c.Next()
+		}
+		if len(key) > idempotency.MaxKeyLength {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Idempotency-Key demasiado larga (máximo 200 caracteres)",
+			})
+		}
+
+		scope := "anonymous"
+		if userID, ok := c.Locals("userID").(uuid.UUID); ok {
+			scope = userID.String()
+		}
+
+		record, acquired, err := store.Begin(&idempotency.Record{
+			Scope:       scope,
+			Key:         key,
+			Method:      c.Method(),
+			Path:        c.Path(),
+			RequestHash: idempotency.HashRequest(c.Method(), c.Path(), c.Body()),
+		}, time.Now())
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Error al registrar la Idempotency-Key",
+			})
+		}
+
+		if !acquired {
+			switch {
+			case record.RequestHash != idempotency.HashRequest(c.Method(), c.Path(), c.Body()):
+				return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+					"error": "La Idempotency-Key ya se usó con una petición distinta",
+				})
+			case record.Status == idempotency.StatusInProgress:
+				return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+					"error": "Hay una petición en curso con la misma Idempotency-Key",
+				})
+			}
+
+			c.Set(IdempotentReplayedHeader, "true")
+			if record.ContentType != "" {
+				c.Set(fiber.HeaderContentType, record.ContentType)
+			}
+			return c.Status(record.StatusCode).Send(record.ResponseBody)
+		}
+
+		// Clave con el usuario como prefijo: se reenvía al proveedor de pagos (Charge)
+		c.Locals("idempotencyKey", scope+":"+key)
+
+		if err := c.Next(); err != nil {
+			if releaseErr := store.Release(record.ID); releaseErr != nil {
+				log.Printf("⚠️  No se pudo liberar la Idempotency-Key %s: %v", key, releaseErr)
+			}
+			return err
+		}
+
+		status := c.Response().StatusCode()
+		if status >= fiber.StatusInternalServerError {
+			if err := store.Release(record.ID); err != nil {
+				log.Printf("⚠️  No se pudo liberar la Idempotency-Key %s: %v", key, err)
+			}
+			return nil
+		}
+
+		body := append([]byte(nil), c.Response().Body()...)
+		if err := store.Complete(record.ID, status, string(c.Response().Header.ContentType()), body); err != nil {
+			log.Printf("⚠️  No se pudo guardar la respuesta de la Idempotency-Key %s: %v", key, err)
+		}
+		return nil
+	}
+}
+
+// IdempotencyKey devuelve la clave de idempotencia de la petición (con el usuario como prefijo)
+// o "" si el cliente no envió Idempotency-Key
+func IdempotencyKey(c *fiber.Ctx) string {
+	key, _ := c.Locals("idempotencyKey").(string)
+	return key
+}